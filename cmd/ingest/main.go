@@ -0,0 +1,51 @@
+// ingest is the RTMP server broadcasters point OBS or ffmpeg at and the WHIP endpoint browsers
+// publish to. It shares the config file with the API server and takes the stream key as the
+// publish name, or over WHIP as the bearer token:
+//
+//	ffmpeg -re -i in.mp4 -c:v libx264 -c:a aac -f flv rtmp://localhost:1935/live/live_...
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/AlexMickh/twitch-clone/internal/app"
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+func main() {
+	cfg := config.MustLoad()
+
+	file, err := os.OpenFile(cfg.Env+"-ingest.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	log := logger.New(cfg.Env, io.MultiWriter(os.Stdout, file))
+
+	log.Info("logger is working", slog.String("env", cfg.Env))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = logger.ContextWithLogger(ctx, log)
+
+	ingest := app.NewIngest(ctx, cfg)
+	ingest.Run(ctx)
+	defer ingest.Close(ctx)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+
+	<-stop
+
+	close(stop)
+	logger.FromCtx(ctx).Info("ingest stopped")
+}
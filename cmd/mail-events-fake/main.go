@@ -0,0 +1,51 @@
+// mail-events-fake plays a mail provider for local testing: it posts a sample bounce or complaint
+// to the webhook of a running server.
+//
+//	go run ./cmd/mail-events-fake -provider ses -type complaint -email user@example.com
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/lib/mailevents"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:50070", "server address")
+	secret := flag.String("secret", os.Getenv("MAIL_EVENTS_SECRET"), "webhook secret, MAIL_EVENTS_SECRET by default")
+	provider := flag.String("provider", consts.MailProviderGeneric, strings.Join(mailevents.Providers(), ", "))
+	eventType := flag.String("type", consts.MailEventHardBounce, strings.Join([]string{
+		consts.MailEventHardBounce,
+		consts.MailEventSoftBounce,
+		consts.MailEventComplaint,
+	}, ", "))
+	email := flag.String("email", "", "address the event is about")
+	flag.Parse()
+
+	if *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// a tool run by hand, it does not need the config of the server for its logger
+	log := logger.New(consts.EnvLocal, os.Stdout)
+
+	body, err := mailevents.NewFake(*url, *secret).Post(context.Background(), *provider, *eventType, *email)
+	if err != nil {
+		log.Error("failed to post event", logger.Err(err))
+		os.Exit(1)
+	}
+
+	log.Info(
+		"event posted",
+		slog.String("provider", *provider),
+		slog.String("type", *eventType),
+		slog.String("response", string(body)),
+	)
+}
@@ -5,17 +5,48 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 
 	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/lib/captcha"
+	"github.com/AlexMickh/twitch-clone/internal/lib/dkim"
 	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/AlexMickh/twitch-clone/internal/lib/i18n"
+	"github.com/AlexMickh/twitch-clone/internal/lib/sms"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/AlexMickh/twitch-clone/internal/lib/unsubscribe"
+	audit_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/audit"
+	channel_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/channel"
+	device_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/device"
+	invite_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/invite"
+	mail_queue_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/mail_queue"
+	phone_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/phone"
 	token_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/token"
 	user_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/user"
+	broadcast_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/broadcast"
+	counter_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/counter"
+	device_auth_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/device_auth"
 	session_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/session"
+	verification_code_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/verification_code"
 	"github.com/AlexMickh/twitch-clone/internal/server"
+	admin_service "github.com/AlexMickh/twitch-clone/internal/services/admin"
+	audit_service "github.com/AlexMickh/twitch-clone/internal/services/audit"
 	auth_service "github.com/AlexMickh/twitch-clone/internal/services/auth"
+	broadcast_service "github.com/AlexMickh/twitch-clone/internal/services/broadcast"
+	channel_service "github.com/AlexMickh/twitch-clone/internal/services/channel"
+	device_service "github.com/AlexMickh/twitch-clone/internal/services/device"
+	device_auth_service "github.com/AlexMickh/twitch-clone/internal/services/device_auth"
+	guard_service "github.com/AlexMickh/twitch-clone/internal/services/guard"
+	invite_service "github.com/AlexMickh/twitch-clone/internal/services/invite"
+	mail_events_service "github.com/AlexMickh/twitch-clone/internal/services/mail_events"
+	mail_queue_service "github.com/AlexMickh/twitch-clone/internal/services/mail_queue"
+	notification_service "github.com/AlexMickh/twitch-clone/internal/services/notification"
+	phone_service "github.com/AlexMickh/twitch-clone/internal/services/phone"
 	session_service "github.com/AlexMickh/twitch-clone/internal/services/session"
 	token_service "github.com/AlexMickh/twitch-clone/internal/services/token"
 	user_service "github.com/AlexMickh/twitch-clone/internal/services/user"
+	verification_code_service "github.com/AlexMickh/twitch-clone/internal/services/verification_code"
 	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
 	redis_client "github.com/AlexMickh/twitch-clone/pkg/clients/redis"
 	"github.com/AlexMickh/twitch-clone/pkg/logger"
@@ -24,10 +55,11 @@ import (
 )
 
 type App struct {
-	cfg  *config.Config
-	db   *mongo.Client
-	cash *redis.Client
-	srv  *server.Server
+	cfg       *config.Config
+	db        *mongo.Client
+	cash      *redis.Client
+	srv       *server.Server
+	mailQueue *mail_queue_service.Service
 }
 
 func New(ctx context.Context, cfg *config.Config) *App {
@@ -59,6 +91,50 @@ func New(ctx context.Context, cfg *config.Config) *App {
 		os.Exit(1)
 	}
 
+	auditRepository, err := audit_repository.New(
+		ctx,
+		db,
+		cfg.DB.Database,
+		cfg.DB.Collections["audit_events"],
+		cfg.Audit.TTL,
+	)
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	deviceRepository, err := device_repository.New(ctx, db, cfg.DB.Database, cfg.DB.Collections["devices"])
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	inviteRepository, err := invite_repository.New(ctx, db, cfg.DB.Database, cfg.DB.Collections["invites"])
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	phoneRepository := phone_repository.New(db, cfg.DB.Database, cfg.DB.Collections["phones"])
+
+	channelRepository, err := channel_repository.New(ctx, db, cfg.DB.Database, cfg.DB.Collections["channels"])
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	mailQueueRepository, err := mail_queue_repository.New(
+		ctx,
+		db,
+		cfg.DB.Database,
+		cfg.DB.Collections["mail_queue"],
+		cfg.DB.Collections["mail_dead_letters"],
+	)
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
 	log.Info("initing redis")
 	cash, err := redis_client.New(
 		ctx,
@@ -72,23 +148,160 @@ func New(ctx context.Context, cfg *config.Config) *App {
 		os.Exit(1)
 	}
 	sessionRepository := session_repository.New(cash, cfg.Redis.Expiration)
+	migrated, err := sessionRepository.MigrateLegacySessions(ctx)
+	if err != nil {
+		log.Error("failed to migrate sessions", logger.Err(err))
+		os.Exit(1)
+	}
+	if migrated > 0 {
+		log.Info("legacy sessions migrated", slog.Int("count", migrated))
+	}
+	counterRepository := counter_repository.New(cash, "counter")
+	deviceAuthRepository := device_auth_repository.New(cash)
+	verificationCodeRepository := verification_code_repository.New(cash, "verify_code")
+	phoneCodeRepository := verification_code_repository.New(cash, "phone_code")
+	broadcastRepository := broadcast_repository.New(cash, cfg.StreamKey.KickPrefix)
 
-	mailService := email.New(cfg.Mail)
+	catalog, err := i18n.Load()
+	if err != nil {
+		log.Error("failed to load message catalogs", logger.Err(err))
+		os.Exit(1)
+	}
+
+	smsSender, err := newSMSSender(cfg.Phone.Provider)
+	if err != nil {
+		log.Error("failed to init sms sender", logger.Err(err))
+		os.Exit(1)
+	}
 
 	log.Info("initing service layer")
-	tokenService := token_service.New(tokenRepository)
-	userService := user_service.New(userRepository, tokenService)
-	sessionService := session_service.New(sessionRepository)
-	authService := auth_service.New(userService, mailService, tokenService, sessionService)
+	auditService := audit_service.New(auditRepository)
+	tokenService := token_service.New(tokenRepository, cfg.Token)
+	verificationCodeService := verification_code_service.New(verificationCodeRepository, cfg.VerificationCode)
+	streamKeyring, err := streamkey.NewKeyring(cfg.StreamKey.Secret)
+	if err != nil {
+		log.Error("failed to init stream keys", logger.Err(err))
+		os.Exit(1)
+	}
+	channelService := channel_service.New(channelRepository, streamKeyring, broadcastRepository, auditService)
+	userService := user_service.New(userRepository, tokenService, verificationCodeService, auditService, channelService)
+	broadcastService := broadcast_service.New(
+		channelService,
+		channelRepository,
+		userService,
+		streamKeyring,
+		cfg.IngestCallbacks,
+	)
+	mailTransport, err := newMailTransport(cfg.Mail)
+	if err != nil {
+		log.Error("failed to init mail transport", logger.Err(err))
+		os.Exit(1)
+	}
+	var devMailbox server.Mailbox
+	if memory, ok := mailTransport.(*email.MemoryTransport); ok && (cfg.Env == consts.EnvLocal || cfg.Env == consts.EnvDev) {
+		devMailbox = memory
+	}
+	dkimSigner, err := dkim.New(cfg.Mail.DKIM, cfg.Mail.FromAddr)
+	if err != nil {
+		log.Error("failed to load dkim keys", logger.Err(err))
+		os.Exit(1)
+	}
+	if dkimSigner != nil {
+		log.Info("signing mail with dkim", slog.String("selector", dkimSigner.Selector()))
+		mailTransport = email.NewDKIMTransport(mailTransport, dkimSigner)
+	}
+	mailQueueService := mail_queue_service.New(mailQueueRepository, mailTransport, cfg.MailQueue)
+	unsubscribeSigner := unsubscribe.NewSigner(cfg.Mail.UnsubscribeSecret)
+	notificationService := notification_service.New(userService, unsubscribeSigner)
+	mailService, err := email.New(cfg.Mail, mailQueueService, catalog, notificationService, unsubscribeSigner)
+	if err != nil {
+		log.Error("failed to init mail templates", logger.Err(err))
+		os.Exit(1)
+	}
+	sessionService := session_service.New(sessionRepository, auditService, cfg.SessionLimit, cfg.SessionSecurity)
+	deviceService := device_service.New(deviceRepository)
+	if !slices.Contains(
+		[]string{consts.RegistrationModeOpen, consts.RegistrationModeInviteOnly, consts.RegistrationModeClosed},
+		cfg.Registration.Mode,
+	) {
+		log.Error("unknown registration mode", slog.String("mode", cfg.Registration.Mode))
+		os.Exit(1)
+	}
+	inviteService := invite_service.New(inviteRepository, cfg.Registration.InviteQuota)
+	registrationGuard, err := newRegistrationGuard(cfg.Registration, counterRepository)
+	if err != nil {
+		log.Error("failed to init registration guard", logger.Err(err))
+		os.Exit(1)
+	}
+	authService := auth_service.New(
+		userService,
+		mailService,
+		mailService,
+		mailService,
+		mailService,
+		tokenService,
+		verificationCodeService,
+		sessionService,
+		deviceService,
+		registrationGuard,
+		inviteService,
+		auditService,
+		cfg.Registration.Mode,
+		cfg.Auth.Hardened,
+	)
+	adminService := admin_service.New(userService, sessionService, authService, auditService)
+	deviceAuthService := device_auth_service.New(
+		deviceAuthRepository,
+		userService,
+		sessionService,
+		auditService,
+		cfg.DeviceAuth,
+	)
+	mailEventsService := mail_events_service.New(userService, auditService)
+	phoneCodeService := verification_code_service.New(phoneCodeRepository, config.VerificationCodeConfig{
+		TTL:         cfg.Phone.CodeTTL,
+		MaxAttempts: cfg.Phone.MaxAttempts,
+	})
+	phoneService := phone_service.New(
+		userService,
+		phoneCodeService,
+		phoneRepository,
+		counterRepository,
+		smsSender,
+		auditService,
+		cfg.Phone,
+	)
 
 	log.Info("initing server")
-	srv := server.New(ctx, cfg.Server, authService, userService, sessionService)
+	srv := server.New(
+		ctx,
+		cfg.Server,
+		cfg.SessionSecurity,
+		cfg.MailEvents,
+		cfg.IngestCallbacks,
+		catalog,
+		authService,
+		userService,
+		sessionService,
+		adminService,
+		auditService,
+		inviteService,
+		deviceAuthService,
+		phoneService,
+		mailQueueService,
+		mailEventsService,
+		notificationService,
+		channelService,
+		broadcastService,
+		devMailbox,
+	)
 
 	return &App{
-		cfg:  cfg,
-		db:   db,
-		srv:  srv,
-		cash: cash,
+		cfg:       cfg,
+		db:        db,
+		srv:       srv,
+		cash:      cash,
+		mailQueue: mailQueueService,
 	}
 }
 
@@ -96,6 +309,9 @@ func (a *App) Run(ctx context.Context) {
 	const op = "app.Run"
 	log := logger.FromCtx(ctx).With(slog.String("op", op))
 
+	a.mailQueue.Start(ctx)
+	log.Info("mail queue started", slog.Int("workers", a.cfg.MailQueue.Workers))
+
 	log.Info("server started", slog.String("addr", a.cfg.Server.Addr))
 
 	go func() {
@@ -108,6 +324,86 @@ func (a *App) Run(ctx context.Context) {
 
 func (a *App) Close(ctx context.Context) {
 	_ = a.srv.GracefulStop(ctx)
+	a.mailQueue.Stop()
 	_ = a.db.Disconnect(ctx)
 	_ = a.cash.Close()
 }
+
+// newRegistrationGuard builds the registration pipeline: local checks first, then the ones hitting redis or network.
+func newRegistrationGuard(cfg config.RegistrationConfig, counter guard_service.Counter) (*guard_service.Service, error) {
+	const op = "app.newRegistrationGuard"
+
+	var checkers []guard_service.Checker
+
+	if cfg.DisposableDomainsFile != "" {
+		domains, err := guard_service.ReadList(cfg.DisposableDomainsFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		checkers = append(checkers, guard_service.NewDisposableDomains(domains))
+	}
+
+	var blocked []string
+	if cfg.BlockedLoginsFile != "" {
+		var err error
+		blocked, err = guard_service.ReadList(cfg.BlockedLoginsFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	reserved := append(slices.Clone(guard_service.DefaultReservedLogins), cfg.ReservedLogins...)
+	checkers = append(checkers, guard_service.NewLogins(reserved, blocked))
+
+	checkers = append(checkers, guard_service.NewSignupVelocity(counter, cfg.MaxSignupsPerIP, cfg.SignupWindow))
+
+	switch cfg.Captcha.Provider {
+	case consts.CaptchaProviderNone, "":
+	case consts.CaptchaProviderHTTP:
+		verifier := captcha.NewHTTPVerifier(cfg.Captcha.VerifyURL, cfg.Captcha.Secret, cfg.Captcha.Timeout)
+		checkers = append(checkers, guard_service.NewCaptcha(verifier))
+	case consts.CaptchaProviderFake:
+		checkers = append(checkers, guard_service.NewCaptcha(captcha.NewFake(cfg.Captcha.FakeToken)))
+	default:
+		return nil, fmt.Errorf("%s: unknown captcha provider %q", op, cfg.Captcha.Provider)
+	}
+
+	return guard_service.New(checkers...), nil
+}
+
+func newMailTransport(cfg config.MailConfig) (email.Transport, error) {
+	const op = "app.newMailTransport"
+
+	switch cfg.Transport {
+	case consts.MailTransportSMTP, "":
+		if cfg.Host == "" {
+			return nil, fmt.Errorf("%s: mail host is required for the smtp transport", op)
+		}
+		if !slices.Contains([]string{consts.MailTLSNone, consts.MailTLSStartTLS, consts.MailTLSImplicit}, cfg.TLS) {
+			return nil, fmt.Errorf("%s: unknown mail tls mode %q", op, cfg.TLS)
+		}
+		return email.NewSMTPTransport(cfg.Host, cfg.Port, cfg.FromAddr, cfg.Password, cfg.TLS, cfg.Timeout), nil
+	case consts.MailTransportDir:
+		transport, err := email.NewDirTransport(cfg.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return transport, nil
+	case consts.MailTransportMemory:
+		return email.NewMemoryTransport(cfg.MailboxSize), nil
+	default:
+		return nil, fmt.Errorf("%s: unknown mail transport %q", op, cfg.Transport)
+	}
+}
+
+func newSMSSender(provider string) (phone_service.SMSSender, error) {
+	const op = "app.newSMSSender"
+
+	switch provider {
+	case consts.SMSProviderLog, "":
+		return sms.NewLogSender(), nil
+	case consts.SMSProviderMemory:
+		return sms.NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("%s: unknown sms provider %q", op, provider)
+	}
+}
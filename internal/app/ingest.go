@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/AlexMickh/twitch-clone/internal/lib/rtmp"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/AlexMickh/twitch-clone/internal/lib/whip"
+	audit_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/audit"
+	channel_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/channel"
+	user_repository "github.com/AlexMickh/twitch-clone/internal/repository/mongo/user"
+	broadcast_repository "github.com/AlexMickh/twitch-clone/internal/repository/redis/broadcast"
+	audit_service "github.com/AlexMickh/twitch-clone/internal/services/audit"
+	broadcast_service "github.com/AlexMickh/twitch-clone/internal/services/broadcast"
+	channel_service "github.com/AlexMickh/twitch-clone/internal/services/channel"
+	ingest_service "github.com/AlexMickh/twitch-clone/internal/services/ingest"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	redis_client "github.com/AlexMickh/twitch-clone/pkg/clients/redis"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/cors"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Ingest is the app of cmd/ingest, the server broadcasters stream to.
+type Ingest struct {
+	cfg     *config.Config
+	db      *mongo.Client
+	cash    *redis.Client
+	service *ingest_service.Service
+	rtmp    *rtmp.Server
+	whip    *whip.Server
+	srv     *http.Server
+}
+
+func NewIngest(ctx context.Context, cfg *config.Config) *Ingest {
+	const op = "app.NewIngest"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	log.Info("initing mongo")
+	db, err := mongodb.New(
+		ctx,
+		cfg.DB.Host,
+		cfg.DB.Port,
+		cfg.DB.User,
+		cfg.DB.Password,
+	)
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	auditRepository, err := audit_repository.New(
+		ctx,
+		db,
+		cfg.DB.Database,
+		cfg.DB.Collections["audit_events"],
+		cfg.Audit.TTL,
+	)
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	channelRepository, err := channel_repository.New(ctx, db, cfg.DB.Database, cfg.DB.Collections["channels"])
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	userRepository, err := user_repository.New(ctx, db, cfg.DB.Database, cfg.DB.Collections["users"])
+	if err != nil {
+		log.Error("failed to init mongo", logger.Err(err))
+		os.Exit(1)
+	}
+
+	log.Info("initing redis")
+	cash, err := redis_client.New(
+		ctx,
+		fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		cfg.Redis.User,
+		cfg.Redis.Password,
+		cfg.Redis.DB,
+	)
+	if err != nil {
+		log.Error("failed to init redis", logger.Err(err))
+		os.Exit(1)
+	}
+	broadcastRepository := broadcast_repository.New(cash, cfg.StreamKey.KickPrefix)
+
+	sink, err := newMediaSink(cfg.Ingest)
+	if err != nil {
+		log.Error("failed to init media sink", logger.Err(err))
+		os.Exit(1)
+	}
+
+	log.Info("initing service layer")
+	auditService := audit_service.New(auditRepository)
+	streamKeyring, err := streamkey.NewKeyring(cfg.StreamKey.Secret)
+	if err != nil {
+		log.Error("failed to init stream keys", logger.Err(err))
+		os.Exit(1)
+	}
+	channelService := channel_service.New(channelRepository, streamKeyring, broadcastRepository, auditService)
+	// the owner is looked up straight from the repository, the user service is not needed here
+	broadcastService := broadcast_service.New(
+		channelService,
+		channelRepository,
+		userRepository,
+		streamKeyring,
+		cfg.IngestCallbacks,
+	)
+	ingestService := ingest_service.New(broadcastService, broadcastRepository, sink, cfg.Ingest)
+
+	whipServer, err := whip.NewServer(
+		ingestService.WHIP(),
+		cfg.Ingest.WHIPUDPPort,
+		cfg.Ingest.WHIPPublicIPs,
+		cfg.Ingest.Timeout,
+	)
+	if err != nil {
+		log.Error("failed to init whip server", logger.Err(err))
+		os.Exit(1)
+	}
+
+	// browsers publish from the site, which is on another origin
+	handler := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodPost, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{"Authorization", "Content-Type", "If-Match"},
+		ExposedHeaders: []string{"Location", "ETag", "Link"},
+	}).Handler(whipServer)
+
+	return &Ingest{
+		cfg:     cfg,
+		db:      db,
+		cash:    cash,
+		service: ingestService,
+		rtmp:    rtmp.NewServer(ingestService, cfg.Ingest.ChunkSize, cfg.Ingest.Timeout),
+		whip:    whipServer,
+		srv: &http.Server{
+			Addr:    cfg.Ingest.WHIPAddr,
+			Handler: handler,
+			BaseContext: func(net.Listener) context.Context {
+				return ctx
+			},
+			ReadHeaderTimeout: cfg.Ingest.Timeout,
+		},
+	}
+}
+
+func (a *Ingest) Run(ctx context.Context) {
+	const op = "app.Ingest.Run"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	if err := a.service.WatchKicks(ctx); err != nil {
+		log.Error("failed to subscribe to kicks", logger.Err(err))
+		os.Exit(1)
+	}
+
+	log.Info("rtmp server started", slog.String("addr", a.cfg.Ingest.Addr))
+
+	go func() {
+		if err := a.rtmp.ListenAndServe(ctx, a.cfg.Ingest.Addr); err != nil && !errors.Is(err, rtmp.ErrServerClosed) {
+			log.Error("failed to start rtmp server", logger.Err(err))
+			os.Exit(1)
+		}
+	}()
+
+	log.Info("whip server started", slog.String("addr", a.cfg.Ingest.WHIPAddr))
+
+	go func() {
+		if err := a.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start whip server", logger.Err(err))
+			os.Exit(1)
+		}
+	}()
+}
+
+func (a *Ingest) Close(ctx context.Context) {
+	_ = a.rtmp.Shutdown(ctx)
+	_ = a.srv.Shutdown(ctx)
+	_ = a.whip.Shutdown(ctx)
+	_ = a.db.Disconnect(ctx)
+	_ = a.cash.Close()
+}
+
+func newMediaSink(cfg config.IngestConfig) (media.Sink, error) {
+	const op = "app.newMediaSink"
+
+	switch cfg.Sink {
+	case consts.IngestSinkDiscard, "":
+		return media.Discard, nil
+	case consts.IngestSinkFLV:
+		sink, err := flv.NewDirSink(cfg.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return sink, nil
+	default:
+		return nil, fmt.Errorf("%s: unknown media sink %q", op, cfg.Sink)
+	}
+}
@@ -10,11 +10,25 @@ import (
 )
 
 type Config struct {
-	Env    string       `yaml:"env" env-default:"prod"`
-	Server ServerConfig `yaml:"server"`
-	DB     DBConfig     `yaml:"db"`
-	Redis  RedisConfig  `yaml:"redis"`
-	Mail   MailConfig   `yaml:"mail"`
+	Env              string                 `yaml:"env" env-default:"prod"`
+	Server           ServerConfig           `yaml:"server"`
+	DB               DBConfig               `yaml:"db"`
+	Redis            RedisConfig            `yaml:"redis"`
+	Token            TokenConfig            `yaml:"token"`
+	Mail             MailConfig             `yaml:"mail"`
+	MailQueue        MailQueueConfig        `yaml:"mail_queue"`
+	MailEvents       MailEventsConfig       `yaml:"mail_events"`
+	Audit            AuditConfig            `yaml:"audit"`
+	SessionLimit     SessionLimitConfig     `yaml:"session_limit"`
+	SessionSecurity  SessionSecurityConfig  `yaml:"session_security"`
+	DeviceAuth       DeviceAuthConfig       `yaml:"device_auth"`
+	VerificationCode VerificationCodeConfig `yaml:"verification_code"`
+	Phone            PhoneConfig            `yaml:"phone"`
+	Registration     RegistrationConfig     `yaml:"registration"`
+	Auth             AuthConfig             `yaml:"auth"`
+	StreamKey        StreamKeyConfig        `yaml:"stream_key"`
+	Ingest           IngestConfig           `yaml:"ingest"`
+	IngestCallbacks  IngestCallbacksConfig  `yaml:"ingest_callbacks"`
 }
 
 type ServerConfig struct {
@@ -22,6 +36,8 @@ type ServerConfig struct {
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	Session     SessionConfig `yaml:"session"`
+	// TrustedProxies lists addresses or CIDRs whose X-Forwarded-For header is honored.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES" env-separator:","`
 }
 
 type DBConfig struct {
@@ -42,11 +58,191 @@ type RedisConfig struct {
 	Expiration time.Duration `env:"REDIS_EXPIRATION" yaml:"expire_time" env-default:"24h"`
 }
 
+// TokenConfig sets how long emailed links stay valid, sign up verification links never expire.
+type TokenConfig struct {
+	ResetPasswordTTL time.Duration `yaml:"reset_password_ttl" env:"TOKEN_RESET_PASSWORD_TTL" env-default:"1h"`
+	NotMeTTL         time.Duration `yaml:"not_me_ttl" env:"TOKEN_NOT_ME_TTL" env-default:"72h"`
+	ChangeEmailTTL   time.Duration `yaml:"change_email_ttl" env:"TOKEN_CHANGE_EMAIL_TTL" env-default:"24h"`
+}
+
+// MailConfig selects how mail leaves the service and how it looks. Host, Port, Password and TLS
+// only matter for the smtp transport, Dir for the dir one and MailboxSize for the in-memory capture.
 type MailConfig struct {
-	Host     string `env:"MAIL_HOST" yaml:"host" env-required:"true"`
-	Port     int    `env:"MAIL_PORT" yaml:"port" env-required:"true"`
-	FromAddr string `env:"MAIL_FROM_ADDR" yaml:"from_addr" env-required:"true"`
-	Password string `env:"MAIL_PASSWORD" yaml:"password" env-required:"true"`
+	// Transport is one of smtp, dir or memory
+	Transport string `env:"MAIL_TRANSPORT" yaml:"transport" env-default:"smtp"`
+	Host      string `env:"MAIL_HOST" yaml:"host"`
+	Port      int    `env:"MAIL_PORT" yaml:"port" env-default:"587"`
+	FromAddr  string `env:"MAIL_FROM_ADDR" yaml:"from_addr" env-required:"true"`
+	FromName  string `env:"MAIL_FROM_NAME" yaml:"from_name" env-default:"Twitch Clone"`
+	Password  string `env:"MAIL_PASSWORD" yaml:"password"`
+	// TLS is one of none, starttls or tls (implicit TLS, usually on port 465)
+	TLS         string        `env:"MAIL_TLS" yaml:"tls" env-default:"starttls"`
+	Timeout     time.Duration `env:"MAIL_TIMEOUT" yaml:"timeout" env-default:"10s"`
+	Dir         string        `env:"MAIL_DIR" yaml:"dir" env-default:"./mail"`
+	MailboxSize int           `env:"MAIL_MAILBOX_SIZE" yaml:"mailbox_size" env-default:"100"`
+	// BaseURL is the public address links in emails point to
+	BaseURL string `env:"MAIL_BASE_URL" yaml:"base_url" env-default:"http://localhost:8000"`
+	// TemplatesDir optionally overrides the embedded templates file by file
+	TemplatesDir string `env:"MAIL_TEMPLATES_DIR" yaml:"templates_dir"`
+	// UnsubscribeSecret signs the one-click unsubscribe links of non-critical mail
+	UnsubscribeSecret string     `env:"MAIL_UNSUBSCRIBE_SECRET" yaml:"unsubscribe_secret" env-required:"true"`
+	DKIM              DKIMConfig `yaml:"dkim"`
+}
+
+// DKIMConfig signs outgoing mail, signing is off while there is no key. The main key is
+// the one in use, Keys take over at their ActiveFrom, so a rotated key can be configured
+// ahead of time and its selector published in DNS before it starts signing.
+type DKIMConfig struct {
+	// Domain is the signing domain, the domain of FromAddr when empty
+	Domain         string          `env:"MAIL_DKIM_DOMAIN" yaml:"domain"`
+	Selector       string          `env:"MAIL_DKIM_SELECTOR" yaml:"selector"`
+	PrivateKey     string          `env:"MAIL_DKIM_PRIVATE_KEY" yaml:"private_key"`
+	PrivateKeyFile string          `env:"MAIL_DKIM_PRIVATE_KEY_FILE" yaml:"private_key_file"`
+	Keys           []DKIMKeyConfig `yaml:"keys"`
+}
+
+// DKIMKeyConfig is a PEM encoded RSA or Ed25519 key, given inline or as a file.
+type DKIMKeyConfig struct {
+	Selector       string    `yaml:"selector"`
+	PrivateKey     string    `yaml:"private_key"`
+	PrivateKeyFile string    `yaml:"private_key_file"`
+	ActiveFrom     time.Time `yaml:"active_from"`
+}
+
+// MailQueueConfig tunes the outbox workers. A failed message is retried with exponential
+// backoff from BaseBackoff up to MaxBackoff and dead-lettered after MaxAttempts.
+// Lease is how long a worker owns a message before another one may pick it up,
+// it has to be longer than the mail timeout.
+type MailQueueConfig struct {
+	Workers      int           `yaml:"workers" env:"MAIL_QUEUE_WORKERS" env-default:"4"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"MAIL_QUEUE_POLL_INTERVAL" env-default:"1s"`
+	Lease        time.Duration `yaml:"lease" env:"MAIL_QUEUE_LEASE" env-default:"1m"`
+	MaxAttempts  int           `yaml:"max_attempts" env:"MAIL_QUEUE_MAX_ATTEMPTS" env-default:"8"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env:"MAIL_QUEUE_BASE_BACKOFF" env-default:"30s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env:"MAIL_QUEUE_MAX_BACKOFF" env-default:"1h"`
+}
+
+// MailEventsConfig protects the bounce and complaint webhook. Providers pass Secret
+// as a bearer token or as the token query parameter, the webhook is off while it is empty.
+type MailEventsConfig struct {
+	Secret string `yaml:"secret" env:"MAIL_EVENTS_SECRET"`
+}
+
+type AuditConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"AUDIT_TTL" env-default:"2160h"`
+}
+
+// SessionLimitConfig caps the number of live sessions per user.
+// MaxSessions <= 0 means unlimited, RoleOverrides replace it for the given roles.
+type SessionLimitConfig struct {
+	MaxSessions   int            `yaml:"max_sessions" env:"SESSION_MAX_SESSIONS" env-default:"0"`
+	Policy        string         `yaml:"policy" env:"SESSION_LIMIT_POLICY" env-default:"evict_oldest"`
+	RoleOverrides map[string]int `yaml:"role_overrides"`
+}
+
+// SessionSecurityConfig enables the strict mode, where a session is rejected
+// once its user agent family differs from the one it was created with.
+// ImpersonationTTL is the lifetime of sessions support staff open on behalf of a user,
+// ReauthTTL is how long a password re-check unlocks sensitive actions.
+type SessionSecurityConfig struct {
+	Strict           bool          `yaml:"strict" env:"SESSION_STRICT" env-default:"false"`
+	ImpersonationTTL time.Duration `yaml:"impersonation_ttl" env:"SESSION_IMPERSONATION_TTL" env-default:"30m"`
+	ReauthTTL        time.Duration `yaml:"reauth_ttl" env:"SESSION_REAUTH_TTL" env-default:"10m"`
+}
+
+// DeviceAuthConfig tunes the RFC 8628 device flow used by TV and console apps.
+// VerificationURI is the /activate page shown to the user on the device screen.
+type DeviceAuthConfig struct {
+	CodeTTL         time.Duration `yaml:"code_ttl" env:"DEVICE_AUTH_CODE_TTL" env-default:"10m"`
+	Interval        time.Duration `yaml:"interval" env:"DEVICE_AUTH_INTERVAL" env-default:"5s"`
+	VerificationURI string        `yaml:"verification_uri" env:"DEVICE_AUTH_VERIFICATION_URI" env-default:"http://localhost:8000/activate"`
+}
+
+// VerificationCodeConfig covers the six-digit email verification codes used by mobile apps.
+type VerificationCodeConfig struct {
+	TTL         time.Duration `yaml:"ttl" env:"VERIFICATION_CODE_TTL" env-default:"15m"`
+	MaxAttempts int64         `yaml:"max_attempts" env:"VERIFICATION_CODE_MAX_ATTEMPTS" env-default:"5"`
+}
+
+// PhoneConfig covers phone number verification by SMS. Sends are limited per number and per user
+// within SendWindow, MaxAccountsPerNumber caps how many accounts may verify the same number.
+type PhoneConfig struct {
+	// Provider is one of log or memory
+	Provider             string        `yaml:"provider" env:"PHONE_SMS_PROVIDER" env-default:"log"`
+	CodeTTL              time.Duration `yaml:"code_ttl" env:"PHONE_CODE_TTL" env-default:"10m"`
+	MaxAttempts          int64         `yaml:"max_attempts" env:"PHONE_MAX_ATTEMPTS" env-default:"5"`
+	MaxSendsPerNumber    int64         `yaml:"max_sends_per_number" env:"PHONE_MAX_SENDS_PER_NUMBER" env-default:"3"`
+	MaxSendsPerUser      int64         `yaml:"max_sends_per_user" env:"PHONE_MAX_SENDS_PER_USER" env-default:"5"`
+	SendWindow           time.Duration `yaml:"send_window" env:"PHONE_SEND_WINDOW" env-default:"1h"`
+	MaxAccountsPerNumber int64         `yaml:"max_accounts_per_number" env:"PHONE_MAX_ACCOUNTS_PER_NUMBER" env-default:"1"`
+}
+
+type RegistrationConfig struct {
+	// Mode is one of open, invite-only or closed
+	Mode string `yaml:"mode" env:"REGISTRATION_MODE" env-default:"open"`
+	// InviteQuota is the number of invite codes a regular user may create
+	InviteQuota int64 `yaml:"invite_quota" env:"REGISTRATION_INVITE_QUOTA" env-default:"3"`
+	// DisposableDomainsFile and BlockedLoginsFile are optional word lists, one entry per line
+	DisposableDomainsFile string        `yaml:"disposable_domains_file" env:"REGISTRATION_DISPOSABLE_DOMAINS_FILE"`
+	BlockedLoginsFile     string        `yaml:"blocked_logins_file" env:"REGISTRATION_BLOCKED_LOGINS_FILE"`
+	ReservedLogins        []string      `yaml:"reserved_logins"`
+	MaxSignupsPerIP       int64         `yaml:"max_signups_per_ip" env:"REGISTRATION_MAX_SIGNUPS_PER_IP" env-default:"5"`
+	SignupWindow          time.Duration `yaml:"signup_window" env:"REGISTRATION_SIGNUP_WINDOW" env-default:"1h"`
+	Captcha               CaptchaConfig `yaml:"captcha"`
+}
+
+type CaptchaConfig struct {
+	Provider  string        `yaml:"provider" env:"CAPTCHA_PROVIDER" env-default:"none"`
+	VerifyURL string        `yaml:"verify_url" env:"CAPTCHA_VERIFY_URL"`
+	Secret    string        `yaml:"secret" env:"CAPTCHA_SECRET"`
+	FakeToken string        `yaml:"fake_token" env:"CAPTCHA_FAKE_TOKEN"`
+	Timeout   time.Duration `yaml:"timeout" env:"CAPTCHA_TIMEOUT" env-default:"5s"`
+}
+
+// AuthConfig.Hardened makes login and registration responses identical
+// whether or not an account exists for the email.
+type AuthConfig struct {
+	Hardened bool `yaml:"hardened" env:"AUTH_HARDENED" env-default:"false"`
+}
+
+// StreamKeyConfig protects the stored stream keys. Changing Secret invalidates every key,
+// broadcasters have to reset theirs.
+type StreamKeyConfig struct {
+	Secret string `yaml:"secret" env:"STREAM_KEY_SECRET" env-required:"true"`
+	// KickPrefix is the redis pub/sub channel that tells ingest servers to drop a broadcast
+	KickPrefix string `yaml:"kick_prefix" env:"STREAM_KEY_KICK_PREFIX" env-default:"broadcast"`
+}
+
+// IngestConfig is read by cmd/ingest. Sink is where the media goes: discard, or flv to record
+// every broadcast into Dir.
+type IngestConfig struct {
+	Addr string `yaml:"addr" env:"INGEST_ADDR" env-default:"0.0.0.0:1935"`
+	// App is the first path segment of the URL broadcasters are given, rtmp://host/live
+	App       string        `yaml:"app" env:"INGEST_APP" env-default:"live"`
+	ChunkSize int           `yaml:"chunk_size" env-default:"4096"`
+	Timeout   time.Duration `yaml:"timeout" env-default:"30s"`
+	Sink      string        `yaml:"sink" env:"INGEST_SINK" env-default:"discard"`
+	Dir       string        `yaml:"dir" env:"INGEST_DIR" env-default:"./recordings"`
+	// WHIPAddr serves WebRTC ingestion, browsers publish to http://<addr>/whip
+	WHIPAddr string `yaml:"whip_addr" env:"INGEST_WHIP_ADDR" env-default:"0.0.0.0:8088"`
+	// WHIPUDPPort takes the media of every WebRTC broadcast, 0 picks a random port per broadcast
+	WHIPUDPPort int `yaml:"whip_udp_port" env:"INGEST_WHIP_UDP_PORT" env-default:"8189"`
+	// WHIPPublicIPs are announced to browsers instead of the local addresses behind NAT
+	WHIPPublicIPs []string `yaml:"whip_public_ips" env:"INGEST_WHIP_PUBLIC_IPS" env-separator:","`
+	// UpdateInterval is how often a broadcast is confirmed, like the on_update of nginx-rtmp.
+	// It has to stay below IngestCallbacksConfig.StaleAfter.
+	UpdateInterval time.Duration `yaml:"update_interval" env-default:"30s"`
+}
+
+// IngestCallbacksConfig guards the nginx-rtmp and SRS callbacks. They stay off until Secret or
+// AllowedIPs is set, with both a request has to pass both.
+type IngestCallbacksConfig struct {
+	Secret string `yaml:"secret" env:"INGEST_CALLBACKS_SECRET"`
+	// AllowedIPs lists addresses or CIDRs of the ingest servers
+	AllowedIPs []string `yaml:"allowed_ips" env:"INGEST_CALLBACKS_ALLOWED_IPS" env-separator:","`
+	// StaleAfter lets a new publish take over a broadcast that got no on_update for that long,
+	// e.g. when the ingest server crashed before on_publish_done
+	StaleAfter time.Duration `yaml:"stale_after" env-default:"2m"`
 }
 
 type SessionConfig struct {
@@ -1,6 +1,110 @@
 package consts
 
 const (
-	TokenTypeVerifyEmail = "verify email"
-	ContextUserId        = "user_id"
+	TokenTypeVerifyEmail   = "verify email"
+	TokenTypeResetPassword = "reset password"
+	TokenTypeNotMe         = "not me"
+	TokenTypeChangeEmail   = "change email"
+	ContextUserId          = "user_id"
+	ContextUserRole        = "user_role"
+	ContextSessionId       = "session_id"
+	ContextClientIP        = "client_ip"
+	ContextUserAgent       = "user_agent"
+	ContextImpersonatorId  = "impersonator_id"
+	ContextElevatedUntil   = "elevated_until"
+	ContextLocale          = "locale"
+
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+
+	SuspensionTypeSuspend = "suspend"
+	SuspensionTypeBan     = "ban"
+
+	SessionLimitPolicyEvictOldest = "evict_oldest"
+	SessionLimitPolicyReject      = "reject"
+
+	RegistrationModeOpen       = "open"
+	RegistrationModeInviteOnly = "invite-only"
+	RegistrationModeClosed     = "closed"
+
+	DeviceGrantType             = "urn:ietf:params:oauth:grant-type:device_code"
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+
+	VerificationMethodLink = "link"
+	VerificationMethodCode = "code"
+	VerificationMethodBoth = "both"
+
+	CaptchaProviderNone = "none"
+	CaptchaProviderHTTP = "http"
+	CaptchaProviderFake = "fake"
+
+	SMSProviderLog    = "log"
+	SMSProviderMemory = "memory"
+
+	MailTransportSMTP   = "smtp"
+	MailTransportDir    = "dir"
+	MailTransportMemory = "memory"
+
+	IngestSinkDiscard = "discard"
+	IngestSinkFLV     = "flv"
+
+	IngestProtocolRTMP = "rtmp"
+	IngestProtocolWHIP = "whip"
+
+	IngestServerNginx = "nginx-rtmp"
+	IngestServerSRS   = "srs"
+	// IngestServerNative is cmd/ingest itself
+	IngestServerNative = "native"
+
+	MailTLSNone     = "none"
+	MailTLSStartTLS = "starttls"
+	MailTLSImplicit = "tls"
+
+	EmailCategorySecurity  = "security"
+	EmailCategoryMarketing = "marketing"
+	EmailCategoryGoLive    = "go_live"
+	EmailCategoryDigest    = "digest"
+
+	MailEventHardBounce = "hard_bounce"
+	MailEventSoftBounce = "soft_bounce"
+	MailEventComplaint  = "complaint"
+
+	MailProviderGeneric  = "generic"
+	MailProviderSES      = "ses"
+	MailProviderSendGrid = "sendgrid"
+	MailProviderMailgun  = "mailgun"
+
+	EnvLocal = "local"
+	EnvDev   = "dev"
+
+	AuditEventRegister           = "register"
+	AuditEventVerifyEmail        = "verify_email"
+	AuditEventLoginSuccess       = "login_success"
+	AuditEventLoginFailure       = "login_failure"
+	AuditEventLogout             = "logout"
+	AuditEventPasswordReset      = "password_reset_requested"
+	AuditEventPasswordChange     = "password_change"
+	AuditEventEmailChange        = "email_change"
+	AuditEventSessionRevoked     = "session_revoked"
+	AuditEventAdminAction        = "admin_action"
+	AuditEventNewDevice          = "new_device_login"
+	AuditEventSessionHijack      = "session_hijack_suspected"
+	AuditEventImpersonationStart = "impersonation_start"
+	AuditEventReauth             = "reauth"
+	AuditEventReauthFailure      = "reauth_failure"
+	AuditEventDeviceAuthorized   = "device_authorized"
+	AuditEventDeviceDenied       = "device_denied"
+	AuditEventPhoneVerified      = "phone_verified"
+	AuditEventEmailSuppressed    = "email_suppressed"
+	AuditEventStreamKeyReveal    = "stream_key_reveal"
+	AuditEventStreamKeyReset     = "stream_key_reset"
+
+	AuditReasonUserNotFound    = "user_not_found"
+	AuditReasonInvalidPassword = "invalid_password"
+	AuditReasonEmailNotVerify  = "email_not_verified"
+	AuditReasonUserSuspended   = "user_suspended"
+	AuditReasonNotMe           = "not_me"
+	AuditReasonSessionLimit    = "session_limit"
 )
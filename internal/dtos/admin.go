@@ -0,0 +1,144 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+type SearchUsersRequest struct {
+	Query string `validate:"max=100"`
+	Page  int    `validate:"min=1"`
+	Limit int    `validate:"min=1,max=100"`
+}
+
+type SuspendUserRequest struct {
+	Type      string     `json:"type" validate:"required,oneof=suspend ban"`
+	Reason    string     `json:"reason" validate:"required,max=500"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type SuspensionResponse struct {
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	IssuedBy  string     `json:"issued_by"`
+	IssuedAt  time.Time  `json:"issued_at"`
+}
+
+type AdminUserResponse struct {
+	ID              string              `json:"id"`
+	Login           string              `json:"login"`
+	Email           string              `json:"email"`
+	Role            string              `json:"role"`
+	Locale          string              `json:"locale,omitempty"`
+	IsEmailVerified bool                `json:"is_email_verified"`
+	Phone           string              `json:"phone,omitempty"`
+	IsPhoneVerified bool                `json:"is_phone_verified"`
+	IsSuspended     bool                `json:"is_suspended"`
+	EmailIssue      string              `json:"email_issue,omitempty"`
+	Suspension      *SuspensionResponse `json:"suspension,omitempty"`
+}
+
+type SearchUsersResponse struct {
+	Users []AdminUserResponse `json:"users"`
+	Total int64               `json:"total"`
+	Page  int                 `json:"page"`
+	Limit int                 `json:"limit"`
+}
+
+type AdminUserDetailsResponse struct {
+	AdminUserResponse
+	Sessions []CurrentSessionResponse `json:"sessions"`
+}
+
+type ImpersonationResponse struct {
+	SessionId string    `json:"session_id"`
+	UserId    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s SearchUsersRequest) Validate() error {
+	const op = "dtos.admin.SearchUsersRequest.Validate"
+
+	if err := validator.New().Struct(&s); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s SuspendUserRequest) Validate() error {
+	const op = "dtos.admin.SuspendUserRequest.Validate"
+
+	if err := validator.New().Struct(&s); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if s.ExpiresAt != nil && !s.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%s: expires_at must be in the future", op)
+	}
+
+	return nil
+}
+
+func ToAdminUserResponse(user entities.User) AdminUserResponse {
+	resp := AdminUserResponse{
+		ID:              user.ID.String(),
+		Login:           user.Login,
+		Email:           user.Email,
+		Role:            user.Role,
+		Locale:          user.Locale,
+		IsEmailVerified: user.IsEmailVerified,
+		Phone:           user.Phone,
+		IsPhoneVerified: user.IsPhoneVerified,
+		EmailIssue:      emailIssue(user),
+		IsSuspended:     user.IsSuspended(time.Now()),
+	}
+	if user.Suspension != nil {
+		resp.Suspension = &SuspensionResponse{
+			Type:      user.Suspension.Type,
+			Reason:    user.Suspension.Reason,
+			ExpiresAt: user.Suspension.ExpiresAt,
+			IssuedBy:  user.Suspension.IssuedBy.String(),
+			IssuedAt:  user.Suspension.IssuedAt,
+		}
+	}
+
+	return resp
+}
+
+func ToSearchUsersResponse(users []entities.User, total int64, page, limit int) SearchUsersResponse {
+	resp := SearchUsersResponse{
+		Users: make([]AdminUserResponse, 0, len(users)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, ToAdminUserResponse(user))
+	}
+
+	return resp
+}
+
+func ToAdminUserDetailsResponse(user entities.User, sessions []entities.Session) AdminUserDetailsResponse {
+	resp := AdminUserDetailsResponse{
+		AdminUserResponse: ToAdminUserResponse(user),
+		Sessions:          make([]CurrentSessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, ToCurrentSessionResponse(session))
+	}
+
+	return resp
+}
+
+func emailIssue(user entities.User) string {
+	if user.EmailSuppression == nil {
+		return ""
+	}
+
+	return user.EmailSuppression.Type
+}
@@ -0,0 +1,91 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+type PageRequest struct {
+	Page  int `validate:"min=1"`
+	Limit int `validate:"min=1,max=100"`
+}
+
+type SearchAuditEventsRequest struct {
+	PageRequest
+	UserId  string `validate:"omitempty,uuid"`
+	ActorId string `validate:"omitempty,uuid"`
+	Type    string `validate:"max=64"`
+	From    *time.Time
+	To      *time.Time
+}
+
+type AuditEventResponse struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	UserId    string            `json:"user_id"`
+	ActorId   string            `json:"actor_id"`
+	Reason    string            `json:"reason,omitempty"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	RequestId string            `json:"request_id"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type AuditEventsResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Total  int64                `json:"total"`
+	Page   int                  `json:"page"`
+	Limit  int                  `json:"limit"`
+}
+
+func (p PageRequest) Validate() error {
+	const op = "dtos.audit.PageRequest.Validate"
+
+	if err := validator.New().Struct(&p); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s SearchAuditEventsRequest) Validate() error {
+	const op = "dtos.audit.SearchAuditEventsRequest.Validate"
+
+	if err := validator.New().Struct(&s); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if s.From != nil && s.To != nil && !s.From.Before(*s.To) {
+		return fmt.Errorf("%s: from must be before to", op)
+	}
+
+	return nil
+}
+
+func ToAuditEventsResponse(events []entities.AuditEvent, total int64, page, limit int) AuditEventsResponse {
+	resp := AuditEventsResponse{
+		Events: make([]AuditEventResponse, 0, len(events)),
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, AuditEventResponse{
+			ID:        event.ID.String(),
+			Type:      event.Type,
+			UserId:    event.UserId.String(),
+			ActorId:   event.ActorId.String(),
+			Reason:    event.Reason,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			RequestId: event.RequestId,
+			Metadata:  event.Metadata,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,79 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+// UpdateChannelRequest changes the given channel settings, the omitted ones keep their values.
+// An empty offline_banner removes the banner.
+type UpdateChannelRequest struct {
+	Title    *string `json:"title,omitempty" validate:"omitempty,max=140"`
+	Category *string `json:"category,omitempty" validate:"omitempty,max=64"`
+	// Language is a BCP 47 tag, e.g. en or pt-BR
+	Language      *string   `json:"language,omitempty" validate:"omitempty,bcp47_language_tag"`
+	Tags          *[]string `json:"tags,omitempty" validate:"omitempty,max=10,unique,dive,min=1,max=25,alphanumunicode"`
+	Mature        *bool     `json:"mature,omitempty"`
+	Description   *string   `json:"description,omitempty" validate:"omitempty,max=300"`
+	OfflineBanner *string   `json:"offline_banner,omitempty" validate:"omitempty,max=2048,eq=|http_url"`
+}
+
+func (u UpdateChannelRequest) Validate() error {
+	const op = "dtos.channel.UpdateChannelRequest.Validate"
+
+	if err := validator.New().Struct(&u); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type ChannelResponse struct {
+	Login         string   `json:"login"`
+	DisplayName   string   `json:"display_name"`
+	Title         string   `json:"title"`
+	Category      string   `json:"category"`
+	Language      string   `json:"language"`
+	Tags          []string `json:"tags"`
+	Mature        bool     `json:"mature"`
+	Description   string   `json:"description"`
+	OfflineBanner string   `json:"offline_banner,omitempty"`
+	Live          bool     `json:"live"`
+	// LiveSince is when the current broadcast started, it is absent while offline
+	LiveSince *time.Time `json:"live_since,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func ToChannelResponse(channel entities.Channel) ChannelResponse {
+	tags := channel.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var liveSince *time.Time
+	if channel.Broadcast != nil {
+		liveSince = &channel.Broadcast.StartedAt
+	}
+
+	return ChannelResponse{
+		Login:         channel.Login,
+		DisplayName:   channel.DisplayName,
+		Title:         channel.Title,
+		Category:      channel.Category,
+		Language:      channel.Language,
+		Tags:          tags,
+		Mature:        channel.Mature,
+		Description:   channel.Description,
+		OfflineBanner: channel.OfflineBanner,
+		Live:          channel.Broadcast != nil,
+		LiveSince:     liveSince,
+		CreatedAt:     channel.CreatedAt,
+	}
+}
+
+type StreamKeyResponse struct {
+	StreamKey string `json:"stream_key"`
+}
@@ -1,17 +1,37 @@
 package dtos
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+)
 
 type CurrentSessionResponse struct {
-	ID        string `json:"id"`
-	UserId    string `json:"user_id"`
-	UserAgent string `json:"user_agent"`
+	ID         string    `json:"id"`
+	UserId     string    `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	Browser    string    `json:"browser"`
+	OS         string    `json:"os"`
+	DeviceType string    `json:"device_type"`
+	IP         string    `json:"ip"`
+	LastSeen   time.Time `json:"last_seen"`
+	// Impersonated marks a session opened by support staff on behalf of the user
+	Impersonated   bool   `json:"impersonated"`
+	ImpersonatorId string `json:"impersonator_id,omitempty"`
 }
 
-func ToCurrentSessionResponse(id, userId uuid.UUID, userAgent string) CurrentSessionResponse {
+func ToCurrentSessionResponse(session entities.Session) CurrentSessionResponse {
 	return CurrentSessionResponse{
-		ID:        id.String(),
-		UserId:    userId.String(),
-		UserAgent: userAgent,
+		ID:         session.ID.String(),
+		UserId:     session.UserId.String(),
+		UserAgent:  session.UserAgent,
+		Browser:    session.Browser,
+		OS:         session.OS,
+		DeviceType: session.DeviceType,
+		IP:         session.IP,
+		LastSeen:   session.LastSeen,
+
+		Impersonated:   session.ImpersonatorId != "",
+		ImpersonatorId: session.ImpersonatorId,
 	}
 }
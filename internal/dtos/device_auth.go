@@ -0,0 +1,85 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+type DeviceCodeRequest struct {
+	ClientId string `validate:"required,max=100"`
+}
+
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type DeviceTokenRequest struct {
+	GrantType  string `validate:"required"`
+	DeviceCode string `validate:"required"`
+	ClientId   string `validate:"required"`
+}
+
+// DeviceTokenResponse carries the id of the session created for the device,
+// it is sent back in the session cookie like after a regular login.
+type DeviceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type ActivateDeviceRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=20"`
+	Deny     bool   `json:"deny"`
+}
+
+func (d DeviceCodeRequest) Validate() error {
+	const op = "dtos.device_auth.DeviceCodeRequest.Validate"
+
+	if err := validator.New().Struct(&d); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (d DeviceTokenRequest) Validate() error {
+	const op = "dtos.device_auth.DeviceTokenRequest.Validate"
+
+	if err := validator.New().Struct(&d); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a ActivateDeviceRequest) Validate() error {
+	const op = "dtos.device_auth.ActivateDeviceRequest.Validate"
+
+	if err := validator.New().Struct(&a); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func ToDeviceCodeResponse(
+	auth entities.DeviceAuthorization,
+	verificationURI, verificationURIComplete string,
+) DeviceCodeResponse {
+	return DeviceCodeResponse{
+		DeviceCode:              auth.DeviceCode,
+		UserCode:                auth.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURIComplete,
+		ExpiresIn:               int(time.Until(auth.ExpiresAt).Seconds()),
+		Interval:                int(auth.Interval.Seconds()),
+	}
+}
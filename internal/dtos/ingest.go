@@ -0,0 +1,51 @@
+package dtos
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// IngestCallbackRequest is a callback of nginx-rtmp or SRS brought to one shape, see
+// lib/ingesthook.Parse.
+type IngestCallbackRequest struct {
+	// Server is nginx-rtmp or srs
+	Server string `validate:"required,oneof=nginx-rtmp srs"`
+	// Call is named as in nginx-rtmp: publish, publish_done, play, play_done, update_publish or update_play
+	Call string `validate:"required,oneof=publish publish_done play play_done update_publish update_play"`
+	// ClientId is the id the ingest server gave the connection
+	ClientId string `validate:"required,max=64"`
+	Addr     string `validate:"max=64"`
+	App      string `validate:"max=64"`
+	// Name is the stream name, the stream key or the channel login
+	Name string `validate:"required,max=128"`
+	// Key is the key query parameter of the publish URL, if any
+	Key string `validate:"max=128"`
+}
+
+func (i IngestCallbackRequest) Validate() error {
+	const op = "dtos.ingest.IngestCallbackRequest.Validate"
+
+	if err := validator.New().Struct(&i); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SRSCallbackRequest is the JSON body of the SRS http hooks.
+type SRSCallbackRequest struct {
+	Action   string `json:"action"`
+	ClientId string `json:"client_id"`
+	IP       string `json:"ip"`
+	Vhost    string `json:"vhost"`
+	App      string `json:"app"`
+	Stream   string `json:"stream"`
+	// Param is the query string of the publish or play URL, e.g. ?key=live_...
+	Param string `json:"param"`
+}
+
+// IngestCallbackResponse is what SRS expects on success, nginx-rtmp only looks at the status.
+type IngestCallbackResponse struct {
+	Code int `json:"code"`
+}
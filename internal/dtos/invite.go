@@ -0,0 +1,72 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateInviteRequest struct {
+	MaxUses   int       `json:"max_uses" validate:"min=1,max=100"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required,gt"`
+}
+
+type InviteRedemptionResponse struct {
+	UserId     string    `json:"user_id"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+type InviteResponse struct {
+	Code        string                     `json:"code"`
+	MaxUses     int                        `json:"max_uses"`
+	Uses        int                        `json:"uses"`
+	ExpiresAt   time.Time                  `json:"expires_at"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	Redemptions []InviteRedemptionResponse `json:"redemptions"`
+}
+
+type InvitesResponse struct {
+	Invites []InviteResponse `json:"invites"`
+}
+
+func (c CreateInviteRequest) Validate() error {
+	const op = "dtos.invite.CreateInviteRequest.Validate"
+
+	if err := validator.New().Struct(&c); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func ToInviteResponse(invite entities.Invite) InviteResponse {
+	redemptions := make([]InviteRedemptionResponse, 0, len(invite.Redemptions))
+	for _, redemption := range invite.Redemptions {
+		redemptions = append(redemptions, InviteRedemptionResponse{
+			UserId:     redemption.UserId.String(),
+			RedeemedAt: redemption.RedeemedAt,
+		})
+	}
+
+	return InviteResponse{
+		Code:        invite.Code,
+		MaxUses:     invite.MaxUses,
+		Uses:        invite.Uses,
+		ExpiresAt:   invite.ExpiresAt,
+		CreatedAt:   invite.CreatedAt,
+		Redemptions: redemptions,
+	}
+}
+
+func ToInvitesResponse(invites []entities.Invite) InvitesResponse {
+	resp := InvitesResponse{
+		Invites: make([]InviteResponse, 0, len(invites)),
+	}
+	for _, invite := range invites {
+		resp.Invites = append(resp.Invites, ToInviteResponse(invite))
+	}
+
+	return resp
+}
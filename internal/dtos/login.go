@@ -3,6 +3,8 @@ package dtos
 import (
 	"fmt"
 
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,6 +13,25 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required,min=3"`
 }
 
+type LoginResponse struct {
+	// EmailUpdateRequired is set when mail to the account address bounced or was reported as spam,
+	// the client should ask the user for another address
+	EmailUpdateRequired bool `json:"email_update_required"`
+	// EmailIssue is hard_bounce or complaint
+	EmailIssue string `json:"email_issue,omitempty"`
+}
+
+func ToLoginResponse(user entities.User) LoginResponse {
+	if user.EmailSuppression == nil {
+		return LoginResponse{}
+	}
+
+	return LoginResponse{
+		EmailUpdateRequired: true,
+		EmailIssue:          user.EmailSuppression.Type,
+	}
+}
+
 func (l LoginRequest) Validate() error {
 	const op = "dtos.register.Validate"
 
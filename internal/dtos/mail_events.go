@@ -0,0 +1,40 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MailEventsRequest is the provider independent webhook format:
+//
+//	{"events": [{"type": "bounce", "bounce_type": "hard", "email": "user@example.com"}]}
+type MailEventsRequest struct {
+	Events []MailEventRequest `json:"events" validate:"required,min=1,dive"`
+}
+
+type MailEventRequest struct {
+	// Type is bounce or complaint
+	Type string `json:"type" validate:"required,oneof=bounce complaint"`
+	// BounceType is hard or soft, bounces without it are taken as hard
+	BounceType string    `json:"bounce_type,omitempty" validate:"omitempty,oneof=hard soft"`
+	Email      string    `json:"email" validate:"required,email"`
+	Reason     string    `json:"reason,omitempty"`
+	Timestamp  time.Time `json:"timestamp,omitzero"`
+}
+
+func (m MailEventsRequest) Validate() error {
+	const op = "dtos.mail_events.MailEventsRequest.Validate"
+
+	if err := validator.New().Struct(&m); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type MailEventsResponse struct {
+	Received   int `json:"received"`
+	Suppressed int `json:"suppressed"`
+}
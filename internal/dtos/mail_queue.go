@@ -0,0 +1,23 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+)
+
+type MailQueueStatsResponse struct {
+	Pending         int64      `json:"pending"`
+	Retrying        int64      `json:"retrying"`
+	DeadLetters     int64      `json:"dead_letters"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+}
+
+func ToMailQueueStatsResponse(stats entities.MailQueueStats) MailQueueStatsResponse {
+	return MailQueueStatsResponse{
+		Pending:         stats.Pending,
+		Retrying:        stats.Retrying,
+		DeadLetters:     stats.DeadLetters,
+		OldestPendingAt: stats.OldestPendingAt,
+	}
+}
@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+)
+
+type MailboxMessageResponse struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text"`
+	HTML    string    `json:"html"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+type MailboxResponse struct {
+	Messages []MailboxMessageResponse `json:"messages"`
+}
+
+func ToMailboxResponse(messages []email.CapturedMessage) MailboxResponse {
+	resp := MailboxResponse{
+		Messages: make([]MailboxMessageResponse, 0, len(messages)),
+	}
+	for _, msg := range messages {
+		resp.Messages = append(resp.Messages, MailboxMessageResponse{
+			From:    msg.From,
+			To:      msg.To,
+			Subject: msg.Subject,
+			Text:    msg.Text,
+			HTML:    msg.HTML,
+			SentAt:  msg.SentAt,
+		})
+	}
+
+	return resp
+}
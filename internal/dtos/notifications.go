@@ -0,0 +1,35 @@
+package dtos
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// UpdateNotificationPreferencesRequest changes the given email categories,
+// the omitted ones keep their values. Security email can't be turned off.
+type UpdateNotificationPreferencesRequest struct {
+	Marketing *bool `json:"marketing,omitempty"`
+	GoLive    *bool `json:"go_live,omitempty"`
+	Digest    *bool `json:"digest,omitempty"`
+}
+
+func (u UpdateNotificationPreferencesRequest) Validate() error {
+	const op = "dtos.notifications.UpdateNotificationPreferencesRequest.Validate"
+
+	if err := validator.New().Struct(&u); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if u.Marketing == nil && u.GoLive == nil && u.Digest == nil {
+		return fmt.Errorf("%s: nothing to update", op)
+	}
+
+	return nil
+}
+
+type NotificationPreferencesResponse struct {
+	Security  bool `json:"security"`
+	Marketing bool `json:"marketing"`
+	GoLive    bool `json:"go_live"`
+	Digest    bool `json:"digest"`
+}
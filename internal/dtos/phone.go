@@ -0,0 +1,36 @@
+package dtos
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type SendPhoneCodeRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+type VerifyPhoneRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,len=6,numeric"`
+}
+
+func (s SendPhoneCodeRequest) Validate() error {
+	const op = "dtos.phone.SendPhoneCodeRequest.Validate"
+
+	if err := validator.New().Struct(&s); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (v VerifyPhoneRequest) Validate() error {
+	const op = "dtos.phone.VerifyPhoneRequest.Validate"
+
+	if err := validator.New().Struct(&v); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
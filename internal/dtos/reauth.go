@@ -0,0 +1,26 @@
+package dtos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ReauthRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type ReauthResponse struct {
+	ElevatedUntil time.Time `json:"elevated_until"`
+}
+
+func (r ReauthRequest) Validate() error {
+	const op = "dtos.reauth.ReauthRequest.Validate"
+
+	if err := validator.New().Struct(&r); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
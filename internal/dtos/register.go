@@ -10,6 +10,13 @@ type RegisterRequest struct {
 	Login    string `json:"login" validate:"required,min=3"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=3"`
+	// CaptchaToken is the response token of the CAPTCHA widget
+	CaptchaToken string `json:"captcha_token"`
+	// InviteCode is required in the invite-only registration mode
+	InviteCode string `json:"invite_code" validate:"omitempty,max=64"`
+	// VerificationMethod is the client hint on how to verify the email:
+	// link (default, web), code (mobile apps) or both
+	VerificationMethod string `json:"verification_method" validate:"omitempty,oneof=link code both"`
 }
 
 type RegisterResponse struct {
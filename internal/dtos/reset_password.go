@@ -0,0 +1,50 @@
+package dtos
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,uuid4"`
+	Password string `json:"password" validate:"required,min=3"`
+}
+
+type ChangePasswordRequest struct {
+	Password string `json:"password" validate:"required,min=3"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (f ForgotPasswordRequest) Validate() error {
+	const op = "dtos.reset_password.ForgotPasswordRequest.Validate"
+
+	if err := validator.New().Struct(&f); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r ResetPasswordRequest) Validate() error {
+	const op = "dtos.reset_password.Validate"
+
+	if err := validator.New().Struct(&r); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c ChangePasswordRequest) Validate() error {
+	const op = "dtos.reset_password.ChangePasswordRequest.Validate"
+
+	if err := validator.New().Struct(&c); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
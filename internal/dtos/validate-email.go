@@ -10,6 +10,15 @@ type ValidateEmailRequest struct {
 	Token string `validate:"required,uuid4"`
 }
 
+type VerifyEmailCodeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Code  string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
 func (v ValidateEmailRequest) Validate() error {
 	const op = "dtos.register.Validate"
 
@@ -19,3 +28,23 @@ func (v ValidateEmailRequest) Validate() error {
 
 	return nil
 }
+
+func (v VerifyEmailCodeRequest) Validate() error {
+	const op = "dtos.validate_email.VerifyEmailCodeRequest.Validate"
+
+	if err := validator.New().Struct(&v); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c ChangeEmailRequest) Validate() error {
+	const op = "dtos.validate_email.ChangeEmailRequest.Validate"
+
+	if err := validator.New().Struct(&c); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
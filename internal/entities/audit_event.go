@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEvent struct {
+	ID        uuid.UUID         `bson:"_id"`
+	Type      string            `bson:"type"`
+	UserId    uuid.UUID         `bson:"user_id"`
+	ActorId   uuid.UUID         `bson:"actor_id"`
+	Reason    string            `bson:"reason,omitempty"`
+	IP        string            `bson:"ip"`
+	UserAgent string            `bson:"user_agent"`
+	RequestId string            `bson:"request_id"`
+	Metadata  map[string]string `bson:"metadata,omitempty"`
+	CreatedAt time.Time         `bson:"created_at"`
+}
+
+type AuditEventFilter struct {
+	UserId  *uuid.UUID
+	ActorId *uuid.UUID
+	Type    string
+	From    *time.Time
+	To      *time.Time
+	Offset  int64
+	Limit   int64
+}
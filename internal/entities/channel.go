@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel is where a user streams, every user gets one once their email is verified.
+type Channel struct {
+	ID     uuid.UUID `bson:"_id"`
+	UserId uuid.UUID `bson:"user_id"`
+	// Login is the lowercased user login, it names the channel in URLs
+	Login string `bson:"login"`
+	// DisplayName is the login as the user typed it
+	DisplayName string   `bson:"display_name"`
+	Title       string   `bson:"title"`
+	Category    string   `bson:"category"`
+	Language    string   `bson:"language"`
+	Tags        []string `bson:"tags"`
+	Mature      bool     `bson:"mature"`
+	Description string   `bson:"description"`
+	// OfflineBanner is the URL of the image shown while the channel is not live
+	OfflineBanner string     `bson:"offline_banner,omitempty"`
+	StreamKey     *StreamKey `bson:"stream_key,omitempty"`
+	// Broadcast is set while the channel is live
+	Broadcast *Broadcast `bson:"broadcast,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+}
+
+// StreamKey is the secret broadcasters put in OBS. The key itself is never stored in the clear.
+type StreamKey struct {
+	// Hash finds the channel of a key, see lib/streamkey
+	Hash string `bson:"hash"`
+	// Sealed is the encrypted key, so the owner can see it again
+	Sealed    []byte    `bson:"sealed"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// Broadcast is a live stream, going through cmd/ingest or an external ingest server.
+type Broadcast struct {
+	// Ingest is the server software, see consts.IngestServer*
+	Ingest string `bson:"ingest"`
+	// ClientId is the id the ingest server gave the connection of the broadcaster
+	ClientId string `bson:"client_id"`
+	// KeyHash is the hash of the stream key the broadcast started with, resetting the key ends it
+	KeyHash   string    `bson:"key_hash"`
+	StartedAt time.Time `bson:"started_at"`
+	// UpdatedAt is bumped by every on_update, a broadcast that stopped getting them is stale
+	UpdatedAt time.Time `bson:"updated_at"`
+}
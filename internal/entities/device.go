@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Device struct {
+	ID        uuid.UUID `bson:"_id"`
+	UserId    uuid.UUID `bson:"user_id"`
+	Browser   string    `bson:"browser"`
+	OS        string    `bson:"os"`
+	IPPrefix  string    `bson:"ip_prefix"`
+	FirstSeen time.Time `bson:"first_seen"`
+	LastSeen  time.Time `bson:"last_seen"`
+}
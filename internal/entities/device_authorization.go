@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// DeviceAuthorization is a pending RFC 8628 device flow grant.
+// DeviceCode is the secret the device polls with, UserCode is what the user types at /activate.
+type DeviceAuthorization struct {
+	DeviceCode   string        `redis:"-"`
+	UserCode     string        `redis:"user_code"`
+	ClientId     string        `redis:"client_id"`
+	Status       string        `redis:"status"`
+	UserId       string        `redis:"user_id"`
+	Interval     time.Duration `redis:"interval"`
+	LastPolledAt time.Time     `redis:"last_polled_at"`
+	ExpiresAt    time.Time     `redis:"expires_at"`
+}
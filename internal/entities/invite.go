@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Invite struct {
+	Code        string             `bson:"_id"`
+	CreatedBy   uuid.UUID          `bson:"created_by"`
+	MaxUses     int                `bson:"max_uses"`
+	Uses        int                `bson:"uses"`
+	Redemptions []InviteRedemption `bson:"redemptions"`
+	ExpiresAt   time.Time          `bson:"expires_at"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+type InviteRedemption struct {
+	UserId     uuid.UUID `bson:"user_id"`
+	RedeemedAt time.Time `bson:"redeemed_at"`
+}
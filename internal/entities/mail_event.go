@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// MailEvent is a delivery report from the mail provider, normalized by a provider adapter.
+type MailEvent struct {
+	Type       string
+	Email      string
+	Reason     string
+	OccurredAt time.Time
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MailJob is an outgoing email waiting in the queue. LockedUntil is the lease of the worker
+// sending it, a job whose lease ran out is picked up again.
+type MailJob struct {
+	ID        uuid.UUID `bson:"_id"`
+	MessageId string    `bson:"message_id"`
+	Date      time.Time `bson:"date"`
+	From      string    `bson:"from"`
+	FromName  string    `bson:"from_name"`
+	To        string    `bson:"to"`
+	Subject   string    `bson:"subject"`
+	Text      string    `bson:"text"`
+	HTML      string    `bson:"html"`
+	// ListUnsubscribe is the one-click unsubscribe URL of non-critical mail
+	ListUnsubscribe string     `bson:"list_unsubscribe,omitempty"`
+	Attempts        int        `bson:"attempts"`
+	NextAttemptAt   time.Time  `bson:"next_attempt_at"`
+	LockedUntil     time.Time  `bson:"locked_until"`
+	LastError       string     `bson:"last_error,omitempty"`
+	CreatedAt       time.Time  `bson:"created_at"`
+	FailedAt        *time.Time `bson:"failed_at,omitempty"`
+}
+
+type MailQueueStats struct {
+	Pending         int64
+	Retrying        int64
+	DeadLetters     int64
+	OldestPendingAt *time.Time
+}
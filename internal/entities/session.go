@@ -7,8 +7,16 @@ import (
 )
 
 type Session struct {
-	ID        uuid.UUID `redis:"-"`
-	UserId    uuid.UUID `redis:"-"`
-	UserAgent string    `redis:"user_agent"`
-	LastSeen  time.Time `redis:"last_seen"`
+	ID         uuid.UUID `redis:"-"`
+	UserId     uuid.UUID `redis:"-"`
+	UserAgent  string    `redis:"user_agent"`
+	Browser    string    `redis:"browser"`
+	OS         string    `redis:"os"`
+	DeviceType string    `redis:"device_type"`
+	IP         string    `redis:"ip"`
+	LastSeen   time.Time `redis:"last_seen"`
+	// ImpersonatorId is the staff member acting as the user, empty for regular sessions
+	ImpersonatorId string `redis:"impersonator_id"`
+	// ElevatedUntil is set by a recent password check and unlocks sensitive actions
+	ElevatedUntil time.Time `redis:"elevated_until"`
 }
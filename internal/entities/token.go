@@ -1,9 +1,16 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Token struct {
-	Token  string    `bson:"token"`
-	UserId uuid.UUID `bson:"user_id"`
-	Type   string    `bson:"type"`
+	Token   string    `bson:"token"`
+	UserId  uuid.UUID `bson:"user_id"`
+	Type    string    `bson:"type"`
+	Payload string    `bson:"payload,omitempty"`
+	// ExpiresAt is zero for tokens that never expire
+	ExpiresAt time.Time `bson:"expires_at,omitempty"`
 }
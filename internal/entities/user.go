@@ -1,11 +1,78 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/google/uuid"
+)
 
 type User struct {
-	ID              uuid.UUID `bson:"_id"`
-	Login           string    `bson:"login"`
-	Email           string    `bson:"email"`
-	Password        string    `bson:"password"`
-	IsEmailVerified bool      `bson:"is_email_verified"`
+	ID    uuid.UUID `bson:"_id"`
+	Login string    `bson:"login"`
+	// LoginKey is the lowercased login. It is unique like the channel login it becomes,
+	// users registered before it was introduced do not have it.
+	LoginKey        string      `bson:"login_key,omitempty"`
+	Email           string      `bson:"email"`
+	Password        string      `bson:"password"`
+	IsEmailVerified bool        `bson:"is_email_verified"`
+	Phone           string      `bson:"phone,omitempty"`
+	IsPhoneVerified bool        `bson:"is_phone_verified"`
+	Role            string      `bson:"role,omitempty"`
+	Locale          string      `bson:"locale,omitempty"`
+	Suspension      *Suspension `bson:"suspension,omitempty"`
+	// EmailSuppression is set once the address hard-bounced or the user complained about our mail
+	EmailSuppression *EmailSuppression `bson:"email_suppression,omitempty"`
+	// NotificationPreferences maps an email category to whether the user wants it,
+	// categories missing here use DefaultNotificationPreferences
+	NotificationPreferences map[string]bool `bson:"notification_preferences,omitempty"`
+}
+
+// DefaultNotificationPreferences apply until the user changes them. Marketing is opt-in,
+// security mail can not be turned off at all.
+var DefaultNotificationPreferences = map[string]bool{
+	consts.EmailCategorySecurity:  true,
+	consts.EmailCategoryMarketing: false,
+	consts.EmailCategoryGoLive:    true,
+	consts.EmailCategoryDigest:    true,
+}
+
+type Suspension struct {
+	Type      string     `bson:"type"`
+	Reason    string     `bson:"reason"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+	IssuedBy  uuid.UUID  `bson:"issued_by"`
+	IssuedAt  time.Time  `bson:"issued_at"`
+}
+
+type EmailSuppression struct {
+	// Type is a hard bounce or a complaint, see the MailEvent consts
+	Type   string    `bson:"type"`
+	Reason string    `bson:"reason,omitempty"`
+	At     time.Time `bson:"at"`
+}
+
+// WantsEmail reports whether the user gets mail of the category.
+func (u User) WantsEmail(category string) bool {
+	if category == consts.EmailCategorySecurity {
+		return true
+	}
+	if wants, ok := u.NotificationPreferences[category]; ok {
+		return wants
+	}
+
+	return DefaultNotificationPreferences[category]
+}
+
+// IsSuspended reports whether the user has a suspension or ban that is still in force at now.
+// Suspensions without an expiry never end on their own.
+func (u User) IsSuspended(now time.Time) bool {
+	if u.Suspension == nil {
+		return false
+	}
+	if u.Suspension.ExpiresAt == nil {
+		return true
+	}
+
+	return now.Before(*u.Suspension.ExpiresAt)
 }
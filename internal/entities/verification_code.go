@@ -0,0 +1,9 @@
+package entities
+
+// VerificationCode is a short numeric code sent to an email or phone number, only its hash is stored.
+type VerificationCode struct {
+	Recipient string `redis:"-"`
+	UserId    string `redis:"user_id"`
+	CodeHash  string `redis:"code_hash"`
+	Attempts  int64  `redis:"attempts"`
+}
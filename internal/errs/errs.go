@@ -4,8 +4,51 @@ import "errors"
 
 var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrLoginTaken         = errors.New("login_taken")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrUserEmailNotVerify = errors.New("user email not verify")
+	ErrUserSuspended      = errors.New("user suspended")
 	ErrTokenNotFound      = errors.New("token not found")
 	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionRevoked     = errors.New("session_revoked")
+	ErrSessionLimit       = errors.New("session limit exceeded")
+	ErrSessionHijack      = errors.New("session user agent mismatch")
+	ErrForbidden          = errors.New("forbidden")
+	ErrDisposableEmail    = errors.New("disposable_email")
+	ErrReservedLogin      = errors.New("reserved_login")
+	ErrSignupVelocity     = errors.New("signup_rate_limited")
+	ErrCaptchaInvalid     = errors.New("captcha_invalid")
+	ErrRegistrationClosed = errors.New("registration_closed")
+	ErrInviteInvalid      = errors.New("invite_invalid")
+	ErrInviteQuota        = errors.New("invite quota exceeded")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrImpersonation      = errors.New("impersonation_forbidden")
+	ErrReauthRequired     = errors.New("reauth_required")
+	ErrDeviceCodeNotFound = errors.New("device code not found")
+	ErrUserCodeTaken      = errors.New("user code already taken")
+	ErrCodeInvalid        = errors.New("invalid_code")
+	ErrCodeAttempts       = errors.New("code_attempts_exceeded")
+	ErrPhoneTaken         = errors.New("phone_taken")
+	ErrPhoneRateLimited   = errors.New("phone_rate_limited")
+	ErrMailQueueEmpty     = errors.New("mail queue empty")
+	ErrMailProvider       = errors.New("unknown_mail_provider")
+	ErrMailEventsInvalid  = errors.New("invalid_mail_events")
+	ErrUnsubscribeToken   = errors.New("invalid_unsubscribe_token")
+	ErrChannelNotFound    = errors.New("channel_not_found")
+	ErrChannelLoginTaken  = errors.New("channel_login_taken")
+	ErrStreamKeyInvalid   = errors.New("invalid_stream_key")
+	ErrStreamKeyChanged   = errors.New("stream key changed")
+	ErrAlreadyLive        = errors.New("channel_already_live")
+	ErrChannelOffline     = errors.New("channel_offline")
+	ErrBroadcastNotFound  = errors.New("broadcast_not_found")
+	ErrStreamNameInvalid  = errors.New("invalid_stream_name")
+	ErrIngestCallback     = errors.New("invalid_ingest_callback")
+
+	// device flow token errors, named as in RFC 8628
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrAccessDenied         = errors.New("access_denied")
+	ErrExpiredToken         = errors.New("expired_token")
+	ErrUnsupportedGrantType = errors.New("unsupported_grant_type")
+	ErrInvalidGrant         = errors.New("invalid_grant")
 )
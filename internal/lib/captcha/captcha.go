@@ -0,0 +1,97 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+)
+
+type verifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// HTTPVerifier checks tokens against an hCaptcha or Cloudflare Turnstile style siteverify endpoint.
+type HTTPVerifier struct {
+	client    *http.Client
+	verifyURL string
+	secret    string
+}
+
+func NewHTTPVerifier(verifyURL, secret string, timeout time.Duration) *HTTPVerifier {
+	return &HTTPVerifier{
+		client:    &http.Client{Timeout: timeout},
+		verifyURL: verifyURL,
+		secret:    secret,
+	}
+}
+
+func (v *HTTPVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	const op = "lib.captcha.HTTPVerifier.Verify"
+
+	if token == "" {
+		return fmt.Errorf("%s: %w", op, errs.ErrCaptchaInvalid)
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	var result verifyResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%s: %w: %s", op, errs.ErrCaptchaInvalid, strings.Join(result.ErrorCodes, ","))
+	}
+
+	return nil
+}
+
+// Fake accepts only its configured token. It is meant for tests and local development.
+type Fake struct {
+	Token string
+}
+
+func NewFake(token string) *Fake {
+	return &Fake{
+		Token: token,
+	}
+}
+
+func (f *Fake) Verify(ctx context.Context, token, remoteIP string) error {
+	const op = "lib.captcha.Fake.Verify"
+
+	if token == "" || token != f.Token {
+		return fmt.Errorf("%s: %w", op, errs.ErrCaptchaInvalid)
+	}
+
+	return nil
+}
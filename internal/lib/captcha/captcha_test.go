@@ -0,0 +1,62 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPVerifier_Verify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "secret", r.PostForm.Get("secret"))
+
+		resp := verifyResponse{Success: r.PostForm.Get("response") == "good"}
+		if !resp.Success {
+			resp.ErrorCodes = []string{"invalid-input-response"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "good case",
+			token: "good",
+		},
+		{
+			name:    "invalid token case",
+			token:   "bad",
+			wantErr: errs.ErrCaptchaInvalid,
+		},
+		{
+			name:    "empty token case",
+			token:   "",
+			wantErr: errs.ErrCaptchaInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewHTTPVerifier(srv.URL, "secret", time.Second)
+			err := v.Verify(context.Background(), tt.token, "203.0.113.7")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFake_Verify(t *testing.T) {
+	f := NewFake("pass")
+
+	require.NoError(t, f.Verify(context.Background(), "pass", ""))
+	require.ErrorIs(t, f.Verify(context.Background(), "fail", ""), errs.ErrCaptchaInvalid)
+}
@@ -0,0 +1,151 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver finds the real client address of a request.
+// Forwarding headers are honored only when the direct peer is a trusted proxy,
+// otherwise any client could spoof its address.
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// New accepts CIDRs ("10.0.0.0/8") and plain addresses ("127.0.0.1").
+func New(trustedProxies []string) (*Resolver, error) {
+	const op = "lib.clientip.New"
+
+	trusted, err := parseNetworks(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Resolver{
+		trusted: trusted,
+	}, nil
+}
+
+// MustNew is like New but panics on invalid configuration.
+func MustNew(trustedProxies []string) *Resolver {
+	resolver, err := New(trustedProxies)
+	if err != nil {
+		panic(err)
+	}
+	return resolver
+}
+
+// ClientIP walks X-Forwarded-For from right to left, skipping trusted proxies,
+// and returns the first untrusted hop. X-Real-IP is used when X-Forwarded-For is absent.
+func (r *Resolver) ClientIP(req *http.Request) string {
+	remote, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remote = req.RemoteAddr
+	}
+	if !r.isTrusted(remote) {
+		return remote
+	}
+
+	forwarded := req.Header.Values("X-Forwarded-For")
+	hops := make([]string, 0)
+	for _, value := range forwarded {
+		for hop := range strings.SplitSeq(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			break
+		}
+		if !r.isTrusted(hops[i]) {
+			return hops[i]
+		}
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remote
+}
+
+func (r *Resolver) isTrusted(ip string) bool {
+	return contains(r.trusted, ip)
+}
+
+// Allowlist admits clients by address, e.g. servers calling internal endpoints.
+type Allowlist struct {
+	allowed []*net.IPNet
+}
+
+// NewAllowlist accepts the same CIDRs and plain addresses as New.
+func NewAllowlist(allowed []string) (*Allowlist, error) {
+	const op = "lib.clientip.NewAllowlist"
+
+	networks, err := parseNetworks(allowed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Allowlist{
+		allowed: networks,
+	}, nil
+}
+
+// MustNewAllowlist is like NewAllowlist but panics on invalid configuration.
+func MustNewAllowlist(allowed []string) *Allowlist {
+	allowlist, err := NewAllowlist(allowed)
+	if err != nil {
+		panic(err)
+	}
+	return allowlist
+}
+
+// Allows reports whether ip is in the list. An empty list allows nobody.
+func (a *Allowlist) Allows(ip string) bool {
+	return contains(a.allowed, ip)
+}
+
+func parseNetworks(list []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(list))
+	for _, entry := range list {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+func contains(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_ClientIP(t *testing.T) {
+	resolver, err := New([]string{"10.0.0.0/8", "127.0.0.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		want         string
+	}{
+		{
+			name:       "direct client case",
+			remoteAddr: "203.0.113.7:5555",
+			want:       "203.0.113.7",
+		},
+		{
+			name:         "untrusted peer spoofing header case",
+			remoteAddr:   "203.0.113.7:5555",
+			forwardedFor: "198.51.100.1",
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy case",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: "198.51.100.1",
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "proxy chain case",
+			remoteAddr:   "127.0.0.1:5555",
+			forwardedFor: "1.1.1.1, 198.51.100.1, 10.0.0.3",
+			want:         "198.51.100.1",
+		},
+		{
+			name:       "real ip header case",
+			remoteAddr: "10.0.0.2:5555",
+			realIP:     "198.51.100.9",
+			want:       "198.51.100.9",
+		},
+		{
+			name:         "garbage header case",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: "not an ip",
+			want:         "10.0.0.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			require.Equal(t, tt.want, resolver.ClientIP(req))
+		})
+	}
+}
+
+func TestNew_InvalidProxy(t *testing.T) {
+	_, err := New([]string{"not a network"})
+	require.Error(t, err)
+}
+
+func TestAllowlist_Allows(t *testing.T) {
+	allowlist, err := NewAllowlist([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+
+	require.True(t, allowlist.Allows("10.1.2.3"))
+	require.True(t, allowlist.Allows("::1"))
+	require.False(t, allowlist.Allows("127.0.0.1"))
+	require.False(t, allowlist.Allows("not an ip"))
+
+	empty, err := NewAllowlist(nil)
+	require.NoError(t, err)
+	require.False(t, empty.Allows("10.1.2.3"))
+
+	_, err = NewAllowlist([]string{"10.0.0.0/99"})
+	require.Error(t, err)
+}
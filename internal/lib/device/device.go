@@ -0,0 +1,77 @@
+package device
+
+import (
+	"net"
+	"strings"
+
+	"github.com/mssola/useragent"
+)
+
+const (
+	TypeDesktop = "desktop"
+	TypeMobile  = "mobile"
+	TypeTablet  = "tablet"
+	TypeBot     = "bot"
+	TypeUnknown = "unknown"
+
+	unknown = "unknown"
+)
+
+type Info struct {
+	Browser    string
+	OS         string
+	DeviceType string
+}
+
+// ParseUserAgent extracts the browser family, OS family and device type.
+// Versions are dropped on purpose, so browser updates do not look like a new device.
+func ParseUserAgent(userAgent string) Info {
+	if userAgent == "" {
+		return Info{Browser: unknown, OS: unknown, DeviceType: TypeUnknown}
+	}
+
+	ua := useragent.New(userAgent)
+
+	browser, _ := ua.Browser()
+	if browser == "" {
+		browser = unknown
+	}
+	os := ua.OSInfo().Name
+	if strings.Contains(userAgent, "iPad") {
+		os = "iPadOS"
+	}
+	if os == "" {
+		os = unknown
+	}
+
+	deviceType := TypeDesktop
+	switch {
+	case ua.Bot():
+		deviceType = TypeBot
+	case strings.Contains(userAgent, "iPad") || (strings.Contains(userAgent, "Android") && !ua.Mobile()):
+		deviceType = TypeTablet
+	case ua.Mobile():
+		deviceType = TypeMobile
+	}
+
+	return Info{
+		Browser:    browser,
+		OS:         os,
+		DeviceType: deviceType,
+	}
+}
+
+// IPPrefix returns the /24 network of an IPv4 address or the /48 network of an IPv6 one.
+// Unparsable input is returned as is.
+func IPPrefix(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
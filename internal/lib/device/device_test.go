@@ -0,0 +1,75 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      Info
+	}{
+		{
+			name:      "desktop chrome case",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want:      Info{Browser: "Chrome", OS: "Windows", DeviceType: TypeDesktop},
+		},
+		{
+			name:      "mobile safari case",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			want:      Info{Browser: "Safari", OS: "iPhone OS", DeviceType: TypeMobile},
+		},
+		{
+			name:      "tablet case",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			want:      Info{Browser: "Safari", OS: "iPadOS", DeviceType: TypeTablet},
+		},
+		{
+			name:      "bot case",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want:      Info{Browser: "Googlebot", OS: "unknown", DeviceType: TypeBot},
+		},
+		{
+			name:      "empty case",
+			userAgent: "",
+			want:      Info{Browser: "unknown", OS: "unknown", DeviceType: TypeUnknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseUserAgent(tt.userAgent))
+		})
+	}
+}
+
+func TestIPPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{
+			name: "ipv4 case",
+			ip:   "192.168.10.42",
+			want: "192.168.10.0/24",
+		},
+		{
+			name: "ipv6 case",
+			ip:   "2001:db8:abcd:12::1",
+			want: "2001:db8:abcd::/48",
+		},
+		{
+			name: "invalid case",
+			ip:   "not ip",
+			want: "not ip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IPPrefix(tt.ip))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package dkim
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/emersion/go-msgauth/dkim"
+)
+
+// headerKeys are the signed header fields. RFC 8058 wants List-Unsubscribe and
+// List-Unsubscribe-Post covered by the signature; fields a message lacks are signed
+// as absent, so nobody can add them on the way.
+var headerKeys = []string{
+	"From",
+	"To",
+	"Subject",
+	"Date",
+	"Message-ID",
+	"MIME-Version",
+	"Content-Type",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+}
+
+type signingKey struct {
+	selector   string
+	signer     crypto.Signer
+	activeFrom time.Time
+}
+
+// Signer adds DKIM signatures with relaxed/relaxed canonicalization, which survives
+// relays refolding headers and trimming whitespace. Of the configured keys it signs
+// with the newest one that is already active, that is how keys are rotated.
+type Signer struct {
+	domain string
+	keys   []signingKey
+	now    func() time.Time
+}
+
+// New loads the keys of cfg, it returns nil when no key is configured and signing is off.
+// fromAddr gives the signing domain when cfg has none.
+func New(cfg config.DKIMConfig, fromAddr string) (*Signer, error) {
+	const op = "lib.dkim.New"
+
+	keyConfigs := cfg.Keys
+	if cfg.Selector != "" || cfg.PrivateKey != "" || cfg.PrivateKeyFile != "" {
+		keyConfigs = append([]config.DKIMKeyConfig{{
+			Selector:       cfg.Selector,
+			PrivateKey:     cfg.PrivateKey,
+			PrivateKeyFile: cfg.PrivateKeyFile,
+		}}, keyConfigs...)
+	}
+	if len(keyConfigs) == 0 {
+		return nil, nil
+	}
+
+	domain := cfg.Domain
+	if domain == "" {
+		if at := strings.LastIndex(fromAddr, "@"); at != -1 {
+			domain = fromAddr[at+1:]
+		}
+	}
+	if domain == "" {
+		return nil, fmt.Errorf("%s: no signing domain", op)
+	}
+
+	keys := make([]signingKey, 0, len(keyConfigs))
+	for _, keyConfig := range keyConfigs {
+		if keyConfig.Selector == "" {
+			return nil, fmt.Errorf("%s: key without a selector", op)
+		}
+
+		signer, err := loadKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%s: selector %s: %w", op, keyConfig.Selector, err)
+		}
+
+		keys = append(keys, signingKey{
+			selector:   keyConfig.Selector,
+			signer:     signer,
+			activeFrom: keyConfig.ActiveFrom,
+		})
+	}
+	// newest first, so the first active key is the one to sign with
+	slices.SortStableFunc(keys, func(a, b signingKey) int {
+		return b.activeFrom.Compare(a.activeFrom)
+	})
+
+	signer := &Signer{
+		domain: domain,
+		keys:   keys,
+		now:    time.Now,
+	}
+	if _, ok := signer.activeKey(); !ok {
+		return nil, fmt.Errorf("%s: none of the keys is active yet", op)
+	}
+
+	return signer, nil
+}
+
+// Sign returns the DKIM-Signature header field for the raw message, including the trailing CRLF,
+// to be put in front of the other headers.
+func (s *Signer) Sign(message []byte) (string, error) {
+	const op = "lib.dkim.Signer.Sign"
+
+	key, ok := s.activeKey()
+	if !ok {
+		return "", fmt.Errorf("%s: no active key", op)
+	}
+
+	signer, err := dkim.NewSigner(&dkim.SignOptions{
+		Domain:                 s.domain,
+		Selector:               key.selector,
+		Signer:                 key.signer,
+		Hash:                   crypto.SHA256,
+		HeaderCanonicalization: dkim.CanonicalizationRelaxed,
+		BodyCanonicalization:   dkim.CanonicalizationRelaxed,
+		HeaderKeys:             headerKeys,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = signer.Write(message); err != nil {
+		_ = signer.Close()
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err = signer.Close(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return signer.Signature(), nil
+}
+
+// Selector returns the selector messages are signed with right now.
+func (s *Signer) Selector() string {
+	key, _ := s.activeKey()
+	return key.selector
+}
+
+func (s *Signer) activeKey() (signingKey, bool) {
+	now := s.now()
+	for _, key := range s.keys {
+		if !key.activeFrom.After(now) {
+			return key, true
+		}
+	}
+
+	return signingKey{}, false
+}
+
+// PublicKeyRecord returns the DNS TXT record to publish at <selector>._domainkey.<domain>.
+func PublicKeyRecord(signer crypto.Signer) (string, error) {
+	const op = "lib.dkim.PublicKeyRecord"
+
+	switch public := signer.Public().(type) {
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(public)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(public), nil
+	default:
+		return "", fmt.Errorf("%s: unsupported key type %T", op, public)
+	}
+}
+
+// ParsePrivateKey reads a PEM encoded PKCS#1 RSA key or a PKCS#8 RSA or Ed25519 key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	const op = "lib.dkim.ParsePrivateKey"
+
+	block, _ := pem.Decode(bytes.TrimSpace(data))
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block", op)
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported key type %T", op, key)
+	}
+}
+
+func loadKey(cfg config.DKIMKeyConfig) (crypto.Signer, error) {
+	data := []byte(cfg.PrivateKey)
+	if cfg.PrivateKeyFile != "" {
+		var err error
+		data, err = os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.New("no private key")
+	}
+
+	return ParsePrivateKey(data)
+}
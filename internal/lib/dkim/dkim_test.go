@@ -0,0 +1,220 @@
+package dkim
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/emersion/go-msgauth/dkim"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = email.Message{
+	ID:              "id@example.com",
+	Date:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	From:            "noreply@example.com",
+	FromName:        "Twitch Clone",
+	To:              "user@test.com",
+	Subject:         "Привет",
+	Text:            "plain text",
+	HTML:            "<p>html</p>",
+	ListUnsubscribe: "https://clone.example.com/unsubscribe/token",
+}
+
+func TestSigner_Sign(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  crypto.Signer
+		pem  string
+	}{
+		{
+			name: "rsa pkcs1 case",
+			key:  rsaKey,
+			pem:  pemKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+		},
+		{
+			name: "rsa pkcs8 case",
+			key:  rsaKey,
+			pem:  pemKey(t, "PRIVATE KEY", pkcs8(t, rsaKey)),
+		},
+		{
+			name: "ed25519 case",
+			key:  edKey,
+			pem:  pemKey(t, "PRIVATE KEY", pkcs8(t, edKey)),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			signer, err := New(config.DKIMConfig{
+				Selector:   "mail",
+				PrivateKey: tt.pem,
+			}, "noreply@example.com")
+			require.NoError(t, err)
+
+			raw := signed(t, signer, testMessage)
+			verifications := verify(t, raw, map[string]crypto.Signer{"mail": tt.key})
+			require.Len(t, verifications, 1)
+			require.NoError(t, verifications[0].Err)
+			require.Equal(t, "example.com", verifications[0].Domain)
+			for _, header := range []string{"From", "Subject", "List-Unsubscribe", "List-Unsubscribe-Post"} {
+				require.Contains(t, verifications[0].HeaderKeys, header)
+			}
+		})
+	}
+}
+
+func TestSigner_Relaxed(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer, err := New(config.DKIMConfig{
+		Domain:     "mail.example.com",
+		Selector:   "mail",
+		PrivateKey: pemKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+	}, "noreply@example.com")
+	require.NoError(t, err)
+
+	raw := signed(t, signer, testMessage)
+
+	// relays may refold headers and change whitespace, relaxed canonicalization ignores that
+	relayed := strings.Replace(string(raw), "\r\nSubject: ", "\r\nSubject:  \r\n\t", 1)
+	relayed = strings.Replace(relayed, "plain text", "plain  text \t", 1)
+	verifications := verify(t, []byte(relayed), map[string]crypto.Signer{"mail": key})
+	require.NoError(t, verifications[0].Err)
+	require.Equal(t, "mail.example.com", verifications[0].Domain)
+
+	tampered := strings.Replace(string(raw), "plain text", "plain test", 1)
+	verifications = verify(t, []byte(tampered), map[string]crypto.Signer{"mail": key})
+	require.Error(t, verifications[0].Err)
+
+	// headers signed as absent can't be added later
+	msg := testMessage
+	msg.ListUnsubscribe = ""
+	added := strings.Replace(string(signed(t, signer, msg)), "\r\nMIME-Version", "\r\nList-Unsubscribe: <https://evil.example.com>\r\nMIME-Version", 1)
+	verifications = verify(t, []byte(added), map[string]crypto.Signer{"mail": key})
+	require.Error(t, verifications[0].Err)
+}
+
+func TestSigner_Rotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	newKeyFile := filepath.Join(dir, "mail2027.pem")
+	require.NoError(t, os.WriteFile(newKeyFile, []byte(pemKey(t, "PRIVATE KEY", pkcs8(t, newKey))), 0o600))
+
+	rotation := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer, err := New(config.DKIMConfig{
+		Selector:   "mail2026",
+		PrivateKey: pemKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(oldKey)),
+		Keys: []config.DKIMKeyConfig{{
+			Selector:       "mail2027",
+			PrivateKeyFile: newKeyFile,
+			ActiveFrom:     rotation,
+		}},
+	}, "noreply@example.com")
+	require.NoError(t, err)
+	keys := map[string]crypto.Signer{"mail2026": oldKey, "mail2027": newKey}
+
+	signer.now = func() time.Time { return rotation.Add(-time.Second) }
+	require.Equal(t, "mail2026", signer.Selector())
+	raw := signed(t, signer, testMessage)
+	require.Contains(t, string(raw), "s=mail2026;")
+	verifications := verify(t, raw, keys)
+	require.NoError(t, verifications[0].Err)
+
+	signer.now = func() time.Time { return rotation }
+	require.Equal(t, "mail2027", signer.Selector())
+	raw = signed(t, signer, testMessage)
+	require.Contains(t, string(raw), "s=mail2027;")
+	verifications = verify(t, raw, keys)
+	require.NoError(t, verifications[0].Err)
+}
+
+func TestNew(t *testing.T) {
+	signer, err := New(config.DKIMConfig{}, "noreply@example.com")
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	_, err = New(config.DKIMConfig{Selector: "mail", PrivateKey: "not a key"}, "noreply@example.com")
+	require.Error(t, err)
+
+	_, err = New(config.DKIMConfig{Keys: []config.DKIMKeyConfig{{
+		Selector:   "mail",
+		PrivateKey: pemKey(t, "PRIVATE KEY", pkcs8(t, ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))),
+		ActiveFrom: time.Now().Add(time.Hour),
+	}}}, "noreply@example.com")
+	require.Error(t, err)
+}
+
+// signed puts the signature in front of the message, as email.DKIMTransport does.
+func signed(t *testing.T, signer *Signer, msg email.Message) []byte {
+	t.Helper()
+
+	signature, err := signer.Sign(msg.Bytes())
+	require.NoError(t, err)
+	msg.DKIMSignature = signature
+
+	return msg.Bytes()
+}
+
+// verify checks the signatures against the given selectors instead of DNS.
+func verify(t *testing.T, raw []byte, keys map[string]crypto.Signer) []*dkim.Verification {
+	t.Helper()
+
+	verifications, err := dkim.VerifyWithOptions(bytes.NewReader(raw), &dkim.VerifyOptions{
+		LookupTXT: func(domain string) ([]string, error) {
+			selector, _, _ := strings.Cut(domain, "._domainkey.")
+			key, ok := keys[selector]
+			if !ok {
+				return nil, fmt.Errorf("no record for %s", domain)
+			}
+			record, err := PublicKeyRecord(key)
+			if err != nil {
+				return nil, err
+			}
+			return []string{record}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, verifications)
+
+	return verifications
+}
+
+func pemKey(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func pkcs8(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return der
+}
@@ -1,60 +1,204 @@
 package email
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"html/template"
-	"net/smtp"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
 )
 
 type VerificationEmailVars struct {
 	Login string
 	Token string
+	Code  string
 }
 
+type PasswordResetEmailVars struct {
+	Login string
+	Token string
+}
+
+type AccountExistsEmailVars struct {
+	Email string
+}
+
+type NewDeviceEmailVars struct {
+	Login     string
+	Token     string
+	Browser   string
+	OS        string
+	IPPrefix  string
+	FirstSeen string
+}
+
+type GoLiveEmailVars struct {
+	Login   string
+	Channel string
+	Title   string
+}
+
+// Recipients decides whether an address gets non-critical mail of the category:
+// the user preferences, bounces and complaints are taken into account.
+type Recipients interface {
+	AcceptsEmail(ctx context.Context, email, category string) (bool, error)
+}
+
+// UnsubscribeSigner issues the tokens of List-Unsubscribe links, see lib/unsubscribe.
+type UnsubscribeSigner interface {
+	Token(email, category string) string
+}
+
+const recipientsCheckTimeout = 5 * time.Second
+
 type Email struct {
-	cfg  config.MailConfig
-	auth smtp.Auth
+	cfg         config.MailConfig
+	transport   Transport
+	templates   *Templates
+	recipients  Recipients
+	unsubscribe UnsubscribeSigner
+	link        func(path string, segments ...string) string
 }
 
-func New(cfg config.MailConfig) *Email {
-	return &Email{
-		cfg:  cfg,
-		auth: smtp.PlainAuth("", cfg.FromAddr, cfg.Password, cfg.Host),
+func New(
+	cfg config.MailConfig,
+	transport Transport,
+	translator Translator,
+	recipients Recipients,
+	unsubscribe UnsubscribeSigner,
+) (*Email, error) {
+	const op = "lib.email.New"
+
+	templates, err := LoadTemplates(cfg.TemplatesDir, cfg.BaseURL, translator)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+
+	return &Email{
+		cfg:         cfg,
+		transport:   transport,
+		templates:   templates,
+		recipients:  recipients,
+		unsubscribe: unsubscribe,
+		link:        linkFunc(cfg.BaseURL),
+	}, nil
 }
 
-func (e *Email) SendVerification(to string, token, login string) error {
-	const op = "lib.email.Send"
+func (e *Email) SendVerification(to, locale string, token, code, login string) error {
+	const op = "lib.email.SendVerification"
 
-	tmpl, err := template.ParseFiles("./internal/lib/email/templates/verify-email.html")
-	if err != nil {
+	vars := VerificationEmailVars{
+		Login: login,
+		Token: token,
+		Code:  code,
+	}
+	if err := e.send(to, locale, TemplateVerifyEmail, vars); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	rendered := new(bytes.Buffer)
-	vars := VerificationEmailVars{
+	return nil
+}
+
+func (e *Email) SendPasswordReset(to, locale string, token, login string) error {
+	const op = "lib.email.SendPasswordReset"
+
+	vars := PasswordResetEmailVars{
 		Login: login,
 		Token: token,
 	}
-	if err = tmpl.Execute(rendered, vars); err != nil {
+	if err := e.send(to, locale, TemplatePasswordReset, vars); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	headers := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";"
+	return nil
+}
 
-	err = smtp.SendMail(
-		fmt.Sprintf("%s:%d", e.cfg.Host, e.cfg.Port),
-		e.auth,
-		e.cfg.FromAddr,
-		[]string{to},
-		fmt.Appendf(nil, "Subject: Email\n%s\n\n%s", headers, rendered.String()),
-	)
-	if err != nil {
+func (e *Email) SendAccountExists(to, locale string) error {
+	const op = "lib.email.SendAccountExists"
+
+	vars := AccountExistsEmailVars{
+		Email: to,
+	}
+	if err := e.send(to, locale, TemplateAccountExists, vars); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (e *Email) SendNewDeviceAlert(to, locale string, token, login string, device entities.Device) error {
+	const op = "lib.email.SendNewDeviceAlert"
+
+	vars := NewDeviceEmailVars{
+		Login:     login,
+		Token:     token,
+		Browser:   device.Browser,
+		OS:        device.OS,
+		IPPrefix:  device.IPPrefix,
+		FirstSeen: device.FirstSeen.UTC().Format(time.RFC1123),
+	}
+	if err := e.send(to, locale, TemplateNewDevice, vars); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// SendGoLive tells a follower the channel started streaming.
+func (e *Email) SendGoLive(to, locale string, vars GoLiveEmailVars) error {
+	const op = "lib.email.SendGoLive"
+
+	if err := e.send(to, locale, TemplateGoLive, vars); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (e *Email) send(to, locale, templateName string, vars any) error {
+	category := templateCategories[templateName]
+
+	var listUnsubscribe string
+	if category != consts.EmailCategorySecurity {
+		accepts, err := e.acceptsEmail(to, category)
+		if err != nil {
+			return err
+		}
+		if !accepts {
+			return nil
+		}
+		if e.unsubscribe != nil {
+			listUnsubscribe = e.link("/unsubscribe", e.unsubscribe.Token(to, category))
+		}
+	}
+
+	subject, text, html, err := e.templates.Render(templateName, locale, vars)
+	if err != nil {
+		return err
+	}
+
+	return e.transport.Send(Message{
+		ID:              newMessageId(e.cfg.FromAddr),
+		Date:            time.Now(),
+		From:            e.cfg.FromAddr,
+		FromName:        e.cfg.FromName,
+		To:              to,
+		Subject:         subject,
+		Text:            text,
+		HTML:            html,
+		ListUnsubscribe: listUnsubscribe,
+	})
+}
+
+func (e *Email) acceptsEmail(to, category string) (bool, error) {
+	if e.recipients == nil {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), recipientsCheckTimeout)
+	defer cancel()
+
+	return e.recipients.AcceptsEmail(ctx, to, category)
+}
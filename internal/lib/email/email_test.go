@@ -0,0 +1,223 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/lib/i18n"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.MailConfig{
+	FromAddr: "noreply@example.com",
+	FromName: "Twitch Clone",
+	BaseURL:  "https://clone.example.com/",
+}
+
+func TestEmail_Send(t *testing.T) {
+	transport := NewMemoryTransport(0)
+	e, err := New(testCfg, transport, testCatalog(t), nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, e.SendVerification("user@test.com", "en", "token-1", "123456", "login"))
+	require.NoError(t, e.SendPasswordReset("user@test.com", "", "token-2", "login"))
+	require.NoError(t, e.SendAccountExists("user@test.com", "ru"))
+	require.NoError(t, e.SendNewDeviceAlert("user@test.com", "en", "token-3", "login", entities.Device{
+		Browser:   "Firefox",
+		OS:        "Linux",
+		IPPrefix:  "10.0.0.0/24",
+		FirstSeen: time.Now(),
+	}))
+
+	messages := transport.Messages("")
+	require.Len(t, messages, 4)
+	for _, msg := range messages {
+		require.NotEmpty(t, msg.Subject)
+		require.NotEmpty(t, msg.Text)
+		require.NotEmpty(t, msg.HTML)
+		require.NotContains(t, msg.HTML, "localhost")
+		require.True(t, strings.HasSuffix(msg.ID, "@example.com"))
+	}
+
+	verification := messages[3]
+	require.Equal(t, "Verify your email", verification.Subject)
+	require.Contains(t, verification.Text, "https://clone.example.com/user/verify-email/token-1")
+	require.Contains(t, verification.HTML, `href="https://clone.example.com/user/verify-email/token-1"`)
+	require.Contains(t, verification.Text, "123456")
+
+	newDevice := messages[0]
+	require.Contains(t, newDevice.HTML, "https://clone.example.com/auth/not-me/token-3")
+
+	accountExists := messages[1]
+	require.Equal(t, "Попытка регистрации", accountExists.Subject)
+	require.Contains(t, accountExists.HTML, `<html lang="ru">`)
+	require.Contains(t, accountExists.Text, "user@test.com")
+}
+
+type recipients map[string]bool
+
+func (r recipients) AcceptsEmail(_ context.Context, email, category string) (bool, error) {
+	accepts, ok := r[email+"/"+category]
+	return accepts || !ok, nil
+}
+
+type unsubscribeSigner struct{}
+
+func (unsubscribeSigner) Token(email, category string) string {
+	return email + "." + category
+}
+
+func TestEmail_Preferences(t *testing.T) {
+	transport := NewMemoryTransport(0)
+	e, err := New(testCfg, transport, testCatalog(t), recipients{
+		"off@test.com/" + consts.EmailCategoryGoLive:   false,
+		"off@test.com/" + consts.EmailCategorySecurity: false,
+	}, unsubscribeSigner{})
+	require.NoError(t, err)
+
+	vars := GoLiveEmailVars{Login: "login", Channel: "streamer", Title: "speedrun"}
+	require.NoError(t, e.SendGoLive("off@test.com", "en", vars))
+	require.Empty(t, transport.Messages("off@test.com"))
+
+	// security mail skips the preferences and carries no unsubscribe link
+	require.NoError(t, e.SendPasswordReset("off@test.com", "en", "token", "login"))
+	messages := transport.Messages("off@test.com")
+	require.Len(t, messages, 1)
+	require.Empty(t, messages[0].ListUnsubscribe)
+
+	require.NoError(t, e.SendGoLive("on@test.com", "en", vars))
+	messages = transport.Messages("on@test.com")
+	require.Len(t, messages, 1)
+	require.Equal(t, "https://clone.example.com/unsubscribe/on@test.com.go_live", messages[0].ListUnsubscribe)
+	require.Contains(t, messages[0].HTML, "https://clone.example.com/channels/streamer")
+}
+
+// TestTemplates_Translated renders every email in every locale, a key left in the output
+// means the catalog misses it.
+func TestTemplates_Translated(t *testing.T) {
+	catalog := testCatalog(t)
+	templates, err := LoadTemplates("", "http://localhost:8000", catalog)
+	require.NoError(t, err)
+
+	vars := map[string]any{
+		TemplateVerifyEmail:   VerificationEmailVars{Login: "login", Token: "token", Code: "123456"},
+		TemplatePasswordReset: PasswordResetEmailVars{Login: "login", Token: "token"},
+		TemplateAccountExists: AccountExistsEmailVars{Email: "user@test.com"},
+		TemplateNewDevice:     NewDeviceEmailVars{Login: "login", Token: "token", Browser: "Firefox"},
+		TemplateGoLive:        GoLiveEmailVars{Login: "login", Channel: "streamer", Title: "speedrun"},
+	}
+	for _, locale := range catalog.Locales() {
+		for _, name := range templateNames {
+			subject, text, html, err := templates.Render(name, locale, vars[name])
+			require.NoError(t, err)
+			for _, part := range []string{subject, text, html} {
+				require.NotRegexp(t, `email\.[a-z_]+`, part, "%s/%s has an untranslated key", locale, name)
+				require.NotContains(t, part, "%!", "%s/%s has a broken format", locale, name)
+			}
+		}
+	}
+}
+
+func testCatalog(t *testing.T) *i18n.Catalog {
+	t.Helper()
+
+	catalog, err := i18n.Load()
+	require.NoError(t, err)
+
+	return catalog
+}
+
+func TestLoadTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(dir, TemplateAccountExists+".txt"),
+		[]byte(`{{define "subject"}}Custom subject{{end}}custom body {{.Email}}`),
+		0o644,
+	)
+	require.NoError(t, err)
+
+	templates, err := LoadTemplates(dir, "http://localhost:8000", testCatalog(t))
+	require.NoError(t, err)
+
+	subject, text, html, err := templates.Render(TemplateAccountExists, "en", AccountExistsEmailVars{Email: "a@test.com"})
+	require.NoError(t, err)
+	require.Equal(t, "Custom subject", subject)
+	require.Equal(t, "custom body a@test.com", text)
+	require.Contains(t, html, "a@test.com")
+
+	err = os.WriteFile(filepath.Join(dir, TemplateNewDevice+".txt"), []byte("no subject"), 0o644)
+	require.NoError(t, err)
+	_, err = LoadTemplates(dir, "http://localhost:8000", testCatalog(t))
+	require.Error(t, err)
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := Message{
+		ID:       newMessageId("noreply@example.com"),
+		Date:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		From:     "noreply@example.com",
+		FromName: "Twitch Clone",
+		To:       "user@test.com",
+		Subject:  "Привет",
+		Text:     "plain = text",
+		HTML:     "<p>html</p>",
+	}
+	require.Equal(t, msg.Bytes(), msg.Bytes())
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg.Bytes())))
+	require.NoError(t, err)
+
+	require.Equal(t, "<"+msg.ID+">", parsed.Header.Get("Message-ID"))
+	date, err := parsed.Header.Date()
+	require.NoError(t, err)
+	require.True(t, date.Equal(msg.Date))
+
+	from, err := mail.ParseAddress(parsed.Header.Get("From"))
+	require.NoError(t, err)
+	require.Equal(t, "Twitch Clone", from.Name)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Привет", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		parts = append(parts, part.Header.Get("Content-Type")+"|"+string(content))
+	}
+	require.Equal(t, []string{
+		"text/plain; charset=UTF-8|plain = text",
+		"text/html; charset=UTF-8|<p>html</p>",
+	}, parts)
+	require.Empty(t, parsed.Header.Get("List-Unsubscribe"))
+
+	msg.ListUnsubscribe = "https://clone.example.com/unsubscribe/token"
+	parsed, err = mail.ReadMessage(strings.NewReader(string(msg.Bytes())))
+	require.NoError(t, err)
+	require.Equal(t, "<https://clone.example.com/unsubscribe/token>", parsed.Header.Get("List-Unsubscribe"))
+	require.Equal(t, "List-Unsubscribe=One-Click", parsed.Header.Get("List-Unsubscribe-Post"))
+}
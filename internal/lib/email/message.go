@@ -0,0 +1,101 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a rendered email. ID is the Message-ID without angle brackets.
+type Message struct {
+	ID       string
+	Date     time.Time
+	From     string
+	FromName string
+	To       string
+	Subject  string
+	Text     string
+	HTML     string
+	// ListUnsubscribe is the RFC 8058 one-click unsubscribe URL, empty for critical mail
+	ListUnsubscribe string
+	// DKIMSignature is the whole DKIM-Signature header field, DKIMTransport sets it right before delivery
+	DKIMSignature string
+}
+
+// Bytes renders the message as it is put on the wire: a multipart/alternative body
+// with the plain text part first, as RFC 2046 orders them from the simplest.
+func (m Message) Bytes() []byte {
+	buf := new(bytes.Buffer)
+
+	body := multipart.NewWriter(buf)
+	_ = body.SetBoundary(boundary(m.ID))
+
+	header := func(key, value string) {
+		fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+	}
+	buf.WriteString(m.DKIMSignature)
+	header("Date", m.Date.Format(time.RFC1123Z))
+	header("Message-ID", "<"+m.ID+">")
+	header("From", (&mail.Address{Name: m.FromName, Address: m.From}).String())
+	header("To", (&mail.Address{Address: m.To}).String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	if m.ListUnsubscribe != "" {
+		header("List-Unsubscribe", "<"+m.ListUnsubscribe+">")
+		header("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", body.Boundary()))
+	buf.WriteString("\r\n")
+
+	writePart(body, "text/plain", m.Text)
+	writePart(body, "text/html", m.HTML)
+	_ = body.Close()
+
+	return buf.Bytes()
+}
+
+func writePart(body *multipart.Writer, contentType, content string) {
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	_, _ = qp.Write([]byte(content))
+	_ = qp.Close()
+}
+
+// newMessageId uses the sender domain, as RFC 5322 recommends a domain the sender controls.
+func newMessageId(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at != -1 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+
+	return uuid.NewString() + "@" + domain
+}
+
+// boundary is derived from the message id, so rendering the same message twice gives the same bytes.
+func boundary(id string) string {
+	var b strings.Builder
+	b.WriteString("alt-")
+	for _, r := range id {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+
+	// RFC 2046 limits boundaries to 70 characters
+	return b.String()[:min(b.Len(), 70)]
+}
@@ -0,0 +1,199 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+const (
+	TemplateVerifyEmail   = "verify-email"
+	TemplatePasswordReset = "reset-password"
+	TemplateAccountExists = "account-exists"
+	TemplateNewDevice     = "new-device"
+	TemplateGoLive        = "go-live"
+)
+
+var templateNames = []string{
+	TemplateVerifyEmail,
+	TemplatePasswordReset,
+	TemplateAccountExists,
+	TemplateNewDevice,
+	TemplateGoLive,
+}
+
+// templateCategories decide who gets an email. Security mail is critical: it always goes out,
+// the other categories follow the recipient preferences and carry an unsubscribe link.
+var templateCategories = map[string]string{
+	TemplateVerifyEmail:   consts.EmailCategorySecurity,
+	TemplatePasswordReset: consts.EmailCategorySecurity,
+	TemplateAccountExists: consts.EmailCategorySecurity,
+	TemplateNewDevice:     consts.EmailCategorySecurity,
+	TemplateGoLive:        consts.EmailCategoryGoLive,
+}
+
+// mailTemplate is one email: <name>.txt holds the plain text body and defines the "subject"
+// template, <name>.html holds the HTML body.
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Translator looks up localized strings, see lib/i18n.
+type Translator interface {
+	Translate(locale, key string, args ...any) string
+}
+
+// Templates are parsed once. Files found in the override directory replace the embedded defaults
+// one by one, so an operator can restyle a single email without copying the rest.
+// Text is localized with {{t "email.key" args}}, {{locale}} gives the locale the email is rendered in.
+type Templates struct {
+	templates  map[string]mailTemplate
+	translator Translator
+}
+
+func LoadTemplates(overrideDir, baseURL string, translator Translator) (*Templates, error) {
+	const op = "lib.email.LoadTemplates"
+
+	// t and locale are bound to the recipient locale in Render, these are parse time stubs
+	funcs := map[string]any{
+		"link":   linkFunc(baseURL),
+		"t":      func(key string, args ...any) string { return key },
+		"locale": func() string { return "" },
+	}
+
+	t := &Templates{
+		templates:  make(map[string]mailTemplate, len(templateNames)),
+		translator: translator,
+	}
+	for _, name := range templateNames {
+		textSrc, err := readTemplate(overrideDir, name+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		text, err := texttemplate.New(name).Funcs(funcs).Parse(textSrc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, name, err)
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s: %s.txt does not define a subject", op, name)
+		}
+
+		htmlSrc, err := readTemplate(overrideDir, name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		html, err := htmltemplate.New(name).Funcs(funcs).Parse(htmlSrc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, name, err)
+		}
+
+		t.templates[name] = mailTemplate{
+			text: text,
+			html: html,
+		}
+	}
+
+	return t, nil
+}
+
+// Render returns the subject, the plain text and the HTML body of the named email in locale.
+func (t *Templates) Render(name, locale string, vars any) (string, string, string, error) {
+	const op = "lib.email.Templates.Render"
+
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return "", "", "", fmt.Errorf("%s: unknown template %q", op, name)
+	}
+	tmpl, err := tmpl.localize(t.translator, locale)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	subject := new(bytes.Buffer)
+	if err := tmpl.text.ExecuteTemplate(subject, "subject", vars); err != nil {
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+	text := new(bytes.Buffer)
+	if err := tmpl.text.Execute(text, vars); err != nil {
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+	html := new(bytes.Buffer)
+	if err := tmpl.html.Execute(html, vars); err != nil {
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(text.String()), html.String(), nil
+}
+
+// localize clones the parsed templates with t and locale bound to locale.
+// The parsed ones are never executed, html/template refuses to clone a template after that.
+func (m mailTemplate) localize(translator Translator, locale string) (mailTemplate, error) {
+	funcs := map[string]any{
+		"t": func(key string, args ...any) string {
+			return translator.Translate(locale, key, args...)
+		},
+		"locale": func() string { return locale },
+	}
+
+	text, err := m.text.Clone()
+	if err != nil {
+		return mailTemplate{}, err
+	}
+	html, err := m.html.Clone()
+	if err != nil {
+		return mailTemplate{}, err
+	}
+
+	return mailTemplate{
+		text: text.Funcs(funcs),
+		html: html.Funcs(funcs),
+	}, nil
+}
+
+func readTemplate(overrideDir, file string) (string, error) {
+	if overrideDir != "" {
+		data, err := os.ReadFile(filepath.Join(overrideDir, file))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	data, err := defaultTemplates.ReadFile("templates/" + file)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// linkFunc builds absolute links to the public site, path segments are escaped.
+// In a template: {{link "/user/verify-email" .Token}}.
+func linkFunc(baseURL string) func(path string, segments ...string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return func(path string, segments ...string) string {
+		link := baseURL + path
+		for _, segment := range segments {
+			link += "/" + url.PathEscape(segment)
+		}
+
+		return link
+	}
+}
@@ -0,0 +1,250 @@
+package email
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/google/uuid"
+)
+
+// Transport delivers a rendered message, Email does not care whether it goes
+// to an SMTP server, a directory or memory.
+type Transport interface {
+	Send(msg Message) error
+}
+
+// DKIMSigner returns the DKIM-Signature header field of a raw message, see lib/dkim.
+type DKIMSigner interface {
+	Sign(message []byte) (string, error)
+}
+
+// DKIMTransport signs messages before handing them to the next transport. Signing happens
+// on delivery rather than when the message is queued, so a rotated key applies to retries too.
+type DKIMTransport struct {
+	next   Transport
+	signer DKIMSigner
+}
+
+func NewDKIMTransport(next Transport, signer DKIMSigner) *DKIMTransport {
+	return &DKIMTransport{
+		next:   next,
+		signer: signer,
+	}
+}
+
+func (t *DKIMTransport) Send(msg Message) error {
+	const op = "lib.email.DKIMTransport.Send"
+
+	// Bytes renders the same message to the same bytes, so the signature stays valid
+	msg.DKIMSignature = ""
+	signature, err := t.signer.Sign(msg.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	msg.DKIMSignature = signature
+
+	if err = t.next.Send(msg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SMTPTransport sends mail through an SMTP server. In starttls mode the
+// server has to support STARTTLS, the connection is never downgraded.
+type SMTPTransport struct {
+	addr      string
+	host      string
+	tlsMode   string
+	auth      smtp.Auth
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+func NewSMTPTransport(host string, port int, username, password, tlsMode string, timeout time.Duration) *SMTPTransport {
+	var auth smtp.Auth
+	if password != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPTransport{
+		addr:      net.JoinHostPort(host, fmt.Sprint(port)),
+		host:      host,
+		tlsMode:   tlsMode,
+		auth:      auth,
+		timeout:   timeout,
+		tlsConfig: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+	}
+}
+
+func (t *SMTPTransport) Send(msg Message) error {
+	const op = "lib.email.SMTPTransport.Send"
+
+	client, err := t.dial()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if t.tlsMode == consts.MailTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s: server does not support STARTTLS", op)
+		}
+		if err = client.StartTLS(t.tlsConfig); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if t.auth != nil {
+		if err = client.Auth(t.auth); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = client.Mail(msg.From); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = w.Write(msg.Bytes()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = client.Quit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (t *SMTPTransport) dial() (*smtp.Client, error) {
+	dialer := &net.Dialer{Timeout: t.timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if t.tlsMode == consts.MailTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", t.addr, t.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", t.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the dialer timeout covers only the connect, a server that stalls
+	// later would block the worker past its lease
+	if t.timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// DirTransport writes every message to its own .eml file, which any mail client can open.
+type DirTransport struct {
+	dir string
+}
+
+func NewDirTransport(dir string) (*DirTransport, error) {
+	const op = "lib.email.NewDirTransport"
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &DirTransport{
+		dir: dir,
+	}, nil
+}
+
+func (t *DirTransport) Send(msg Message) error {
+	const op = "lib.email.DirTransport.Send"
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+	err := os.WriteFile(filepath.Join(t.dir, name), msg.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type CapturedMessage struct {
+	Message
+	SentAt time.Time
+}
+
+// MemoryTransport keeps messages in memory for tests and the dev mailbox,
+// only the latest limit messages are kept.
+type MemoryTransport struct {
+	mu       sync.Mutex
+	limit    int
+	messages []CapturedMessage
+}
+
+func NewMemoryTransport(limit int) *MemoryTransport {
+	return &MemoryTransport{
+		limit: limit,
+	}
+}
+
+func (t *MemoryTransport) Send(msg Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = append(t.messages, CapturedMessage{
+		Message: msg,
+		SentAt:  time.Now(),
+	})
+	if t.limit > 0 && len(t.messages) > t.limit {
+		t.messages = slices.Delete(t.messages, 0, len(t.messages)-t.limit)
+	}
+
+	return nil
+}
+
+// Messages returns the captured messages newest first, filtered by recipient when to is not empty.
+func (t *MemoryTransport) Messages(to string) []CapturedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	messages := make([]CapturedMessage, 0, len(t.messages))
+	for i := len(t.messages) - 1; i >= 0; i-- {
+		if to == "" || strings.EqualFold(t.messages[i].To, to) {
+			messages = append(messages, t.messages[i])
+		}
+	}
+
+	return messages
+}
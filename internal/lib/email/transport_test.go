@@ -0,0 +1,223 @@
+package email
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = Message{
+	From:    "noreply@test.com",
+	To:      "user@test.com",
+	Subject: "Hello",
+	HTML:    "<p>hi</p>",
+}
+
+func TestMemoryTransport_Messages(t *testing.T) {
+	transport := NewMemoryTransport(2)
+
+	for _, to := range []string{"a@test.com", "b@test.com", "A@test.com"} {
+		msg := testMessage
+		msg.To = to
+		require.NoError(t, transport.Send(msg))
+	}
+
+	all := transport.Messages("")
+	require.Len(t, all, 2)
+	require.Equal(t, "A@test.com", all[0].To)
+	require.Equal(t, "b@test.com", all[1].To)
+
+	filtered := transport.Messages("a@test.com")
+	require.Len(t, filtered, 1)
+	require.Equal(t, "A@test.com", filtered[0].To)
+}
+
+func TestDirTransport_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	transport, err := NewDirTransport(dir)
+	require.NoError(t, err)
+	require.NoError(t, transport.Send(testMessage))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Equal(t, testMessage.Bytes(), data)
+}
+
+type prefixSigner struct{}
+
+func (prefixSigner) Sign(message []byte) (string, error) {
+	return fmt.Sprintf("DKIM-Signature: l=%d\r\n", len(message)), nil
+}
+
+func TestDKIMTransport_Send(t *testing.T) {
+	memory := NewMemoryTransport(0)
+	transport := NewDKIMTransport(memory, prefixSigner{})
+
+	require.NoError(t, transport.Send(testMessage))
+
+	messages := memory.Messages("")
+	require.Len(t, messages, 1)
+	unsigned := testMessage.Bytes()
+	require.Equal(t, fmt.Sprintf("DKIM-Signature: l=%d\r\n", len(unsigned)), messages[0].DKIMSignature)
+	require.Equal(t, append([]byte(messages[0].DKIMSignature), unsigned...), messages[0].Bytes())
+
+	// a retried message is signed again, not twice
+	require.NoError(t, transport.Send(messages[0].Message))
+	require.Equal(t, messages[0].DKIMSignature, memory.Messages("")[0].DKIMSignature)
+}
+
+func TestSMTPTransport_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		tlsMode  string
+		wantErr  bool
+		wantData bool
+	}{
+		{
+			name:     "plain case",
+			tlsMode:  consts.MailTLSNone,
+			wantData: true,
+		},
+		{
+			name:    "starttls not supported case",
+			tlsMode: consts.MailTLSStartTLS,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, received := fakeSMTPServer(t)
+			host, portStr, err := net.SplitHostPort(addr)
+			require.NoError(t, err)
+			port, err := strconv.Atoi(portStr)
+			require.NoError(t, err)
+
+			transport := NewSMTPTransport(host, port, "noreply@test.com", "secret", tt.tlsMode, time.Second)
+			err = transport.Send(testMessage)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			data := <-received
+			require.Contains(t, data, "Subject: Hello")
+			require.Contains(t, data, "<p>hi</p>")
+		})
+	}
+}
+
+func TestSMTPTransport_SendStalled(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+
+	// accepts the connection but never greets
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+	}()
+
+	host, portStr, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	transport := NewSMTPTransport(host, port, "noreply@test.com", "secret", consts.MailTLSNone, 200*time.Millisecond)
+
+	start := time.Now()
+	err = transport.Send(testMessage)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 2*time.Second)
+}
+
+// fakeSMTPServer accepts one session without STARTTLS and sends the DATA it received.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		r := bufio.NewReader(conn)
+		write := func(line string) {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+
+		write("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				write("250-localhost")
+				write("250 AUTH PLAIN")
+			case strings.HasPrefix(cmd, "AUTH"):
+				write("235 ok")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				write("250 ok")
+			case cmd == "DATA":
+				write("354 go ahead")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				write("250 ok")
+			case cmd == "QUIT":
+				write("221 bye")
+				return
+			default:
+				write("250 ok")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), received
+}
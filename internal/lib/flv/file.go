@@ -0,0 +1,188 @@
+package flv
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+)
+
+const (
+	headerSize    = 9
+	tagHeaderSize = 11
+	flagAudio     = 0x04
+	flagVideo     = 0x01
+)
+
+// Writer writes an FLV file: the header and then tags, each followed by its size.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) (*Writer, error) {
+	const op = "lib.flv.NewWriter"
+
+	header := []byte{'F', 'L', 'V', 1, flagAudio | flagVideo, 0, 0, 0, headerSize, 0, 0, 0, 0}
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Writer{
+		w: w,
+	}, nil
+}
+
+func (w *Writer) WriteTag(tag Tag) error {
+	const op = "lib.flv.Writer.WriteTag"
+
+	size := len(tag.Data)
+	buf := make([]byte, 0, tagHeaderSize+size+4)
+	buf = append(buf, tag.Type, byte(size>>16), byte(size>>8), byte(size))
+	// the timestamp is 24 bits plus an extension byte holding the upper 8
+	buf = append(buf, byte(tag.Timestamp>>16), byte(tag.Timestamp>>8), byte(tag.Timestamp), byte(tag.Timestamp>>24))
+	buf = append(buf, 0, 0, 0)
+	buf = append(buf, tag.Data...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(tagHeaderSize+size))
+
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Reader reads back what Writer wrote.
+type Reader struct {
+	r io.Reader
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	const op = "lib.flv.NewReader"
+
+	header := make([]byte, headerSize+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if string(header[:3]) != "FLV" {
+		return nil, fmt.Errorf("%s: %w", op, ErrMalformed)
+	}
+	if skip := int64(binary.BigEndian.Uint32(header[5:9])) - headerSize; skip > 0 {
+		if _, err := io.CopyN(io.Discard, r, skip); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return &Reader{
+		r: r,
+	}, nil
+}
+
+// ReadTag returns io.EOF after the last tag.
+func (r *Reader) ReadTag() (Tag, error) {
+	const op = "lib.flv.Reader.ReadTag"
+
+	header := make([]byte, tagHeaderSize)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Tag{}, io.EOF
+		}
+		return Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+	data := make([]byte, size+4)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return Tag{
+		Type:      header[0],
+		Timestamp: uint32(header[7])<<24 | uint32(header[4])<<16 | uint32(header[5])<<8 | uint32(header[6]),
+		Data:      data[:size],
+	}, nil
+}
+
+// DirSink records every broadcast to its own .flv file, which ffplay or VLC can open.
+// Opus from WebRTC broadcasts has no place in FLV, recordings of those have no sound.
+type DirSink struct {
+	dir string
+}
+
+func NewDirSink(dir string) (*DirSink, error) {
+	const op = "lib.flv.NewDirSink"
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &DirSink{
+		dir: dir,
+	}, nil
+}
+
+func (s *DirSink) Open(_ context.Context, stream media.Stream) (media.Writer, error) {
+	const op = "lib.flv.DirSink.Open"
+
+	name := fmt.Sprintf("%s-%s.flv", stream.Login, stream.StartedAt.UTC().Format("20060102T150405"))
+	file, err := os.Create(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	buf := bufio.NewWriter(file)
+	w, err := NewWriter(buf)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &fileWriter{
+		file: file,
+		buf:  buf,
+		w:    w,
+	}, nil
+}
+
+type fileWriter struct {
+	file *os.File
+	buf  *bufio.Writer
+	w    *Writer
+}
+
+func (w *fileWriter) WritePacket(packet media.Packet) error {
+	const op = "lib.flv.fileWriter.WritePacket"
+
+	tag, err := EncodeTag(packet)
+	if errors.Is(err, ErrUnsupportedCodec) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := w.w.WriteTag(tag); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (w *fileWriter) Close() error {
+	const op = "lib.flv.fileWriter.Close"
+
+	err := w.buf.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
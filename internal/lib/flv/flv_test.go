@@ -0,0 +1,172 @@
+package flv
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        Tag
+		wantPacket media.Packet
+		wantOk     bool
+		wantErr    error
+	}{
+		{
+			name: "avc sequence header case",
+			tag:  Tag{Type: TagVideo, Data: []byte{0x17, 0, 0, 0, 0, 1, 0x64}},
+			wantPacket: media.Packet{
+				Codec:    media.CodecH264,
+				Keyframe: true,
+				Config:   true,
+				Data:     []byte{1, 0x64},
+			},
+			wantOk: true,
+		},
+		{
+			name: "b-frame case",
+			tag:  Tag{Type: TagVideo, Timestamp: 40, Data: []byte{0x27, 1, 0xff, 0xff, 0xd8, 0, 0, 0, 1, 9}},
+			wantPacket: media.Packet{
+				Codec:             media.CodecH264,
+				Time:              40 * time.Millisecond,
+				CompositionOffset: -40 * time.Millisecond,
+				Data:              []byte{0, 0, 0, 1, 9},
+			},
+			wantOk: true,
+		},
+		{
+			name: "aac case",
+			tag:  Tag{Type: TagAudio, Timestamp: 23, Data: []byte{0xaf, 1, 'x'}},
+			wantPacket: media.Packet{
+				Codec: media.CodecAAC,
+				Time:  23 * time.Millisecond,
+				Data:  []byte{'x'},
+			},
+			wantOk: true,
+		},
+		{
+			name: "script data case",
+			tag:  Tag{Type: TagScript, Data: []byte{2, 0, 0}},
+		},
+		{
+			name: "end of sequence case",
+			tag:  Tag{Type: TagVideo, Data: []byte{0x17, 2, 0, 0, 0}},
+		},
+		{
+			name:    "hevc case",
+			tag:     Tag{Type: TagVideo, Data: []byte{0x1c, 1, 0, 0, 0}},
+			wantErr: ErrUnsupportedCodec,
+		},
+		{
+			name:    "mp3 case",
+			tag:     Tag{Type: TagAudio, Data: []byte{0x2f, 0}},
+			wantErr: ErrUnsupportedCodec,
+		},
+		{
+			name:    "short video case",
+			tag:     Tag{Type: TagVideo, Data: []byte{0x17, 1}},
+			wantErr: ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			packet, ok, err := ParseTag(tt.tag)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOk, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tt.wantPacket, packet)
+
+			tag, err := EncodeTag(packet)
+			require.NoError(t, err)
+			require.Equal(t, tt.tag.Timestamp, tag.Timestamp)
+			reparsed, _, err := ParseTag(tag)
+			require.NoError(t, err)
+			require.Equal(t, packet, reparsed)
+		})
+	}
+}
+
+func TestWriter_WriteTag(t *testing.T) {
+	t.Parallel()
+
+	tags := []Tag{
+		{Type: TagVideo, Timestamp: 0, Data: []byte{0x17, 0, 0, 0, 0, 1}},
+		{Type: TagAudio, Timestamp: 0x01000020, Data: []byte{0xaf, 1, 'x'}},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	for _, tag := range tags {
+		require.NoError(t, w.WriteTag(tag))
+	}
+
+	r, err := NewReader(&buf)
+	require.NoError(t, err)
+	for _, tag := range tags {
+		got, err := r.ReadTag()
+		require.NoError(t, err)
+		require.Equal(t, tag, got)
+	}
+	_, err = r.ReadTag()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestDirSink_Open(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, err := NewDirSink(dir)
+	require.NoError(t, err)
+
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	w, err := sink.Open(context.Background(), media.Stream{
+		ChannelId: uuid.New(),
+		Login:     "streamer",
+		Protocol:  "rtmp",
+		StartedAt: startedAt,
+	})
+	require.NoError(t, err)
+
+	packet := media.Packet{Codec: media.CodecAAC, Time: time.Second, Data: []byte{'x'}}
+	require.NoError(t, w.WritePacket(packet))
+	// opus is dropped, FLV cannot hold it
+	require.NoError(t, w.WritePacket(media.Packet{Codec: media.CodecOpus, Data: []byte{'y'}}))
+	require.NoError(t, w.Close())
+
+	file, err := os.Open(filepath.Join(dir, "streamer-20260102T030405.flv"))
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	r, err := NewReader(file)
+	require.NoError(t, err)
+	tag, err := r.ReadTag()
+	require.NoError(t, err)
+	got, ok, err := ParseTag(tag)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, packet, got)
+	_, err = r.ReadTag()
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,159 @@
+// Package flv reads and writes FLV tags, the unit RTMP carries media in.
+package flv
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+)
+
+// Tag types, the same numbers are the RTMP message types of the tags.
+const (
+	TagAudio  uint8 = 8
+	TagVideo  uint8 = 9
+	TagScript uint8 = 18
+)
+
+const (
+	videoKeyframe   = 1
+	videoInterframe = 2
+	codecAVC        = 7
+	soundFormatAAC  = 10
+	// aacSoundFlags is 44 kHz, 16 bit, stereo: FLV requires them for AAC, the real values are in
+	// the AudioSpecificConfig
+	aacSoundFlags = 0x0f
+
+	avcSequenceHeader = 0
+	avcNALU           = 1
+	aacSequenceHeader = 0
+	aacRaw            = 1
+)
+
+var (
+	ErrMalformed        = errors.New("malformed flv tag")
+	ErrUnsupportedCodec = errors.New("unsupported codec")
+)
+
+// Tag is an FLV tag body with its timestamp in milliseconds.
+type Tag struct {
+	Type      uint8
+	Timestamp uint32
+	Data      []byte
+}
+
+// ParseTag turns an audio or video tag into a packet. ok is false for tags that carry no media:
+// script data, end of sequence markers and video info frames.
+func ParseTag(tag Tag) (packet media.Packet, ok bool, err error) {
+	const op = "lib.flv.ParseTag"
+
+	switch tag.Type {
+	case TagVideo:
+		packet, ok, err = parseVideo(tag)
+	case TagAudio:
+		packet, ok, err = parseAudio(tag)
+	default:
+		return media.Packet{}, false, nil
+	}
+	if err != nil {
+		return media.Packet{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return packet, ok, nil
+}
+
+func parseVideo(tag Tag) (media.Packet, bool, error) {
+	if len(tag.Data) < 1 {
+		return media.Packet{}, false, ErrMalformed
+	}
+	frameType, codec := tag.Data[0]>>4, tag.Data[0]&0x0f
+	if frameType != videoKeyframe && frameType != videoInterframe {
+		return media.Packet{}, false, nil
+	}
+	// the enhanced RTMP header flag shares the bit with frame types above 7
+	if codec != codecAVC || tag.Data[0]&0x80 != 0 {
+		return media.Packet{}, false, fmt.Errorf("%w: video codec %d", ErrUnsupportedCodec, codec)
+	}
+	if len(tag.Data) < 5 {
+		return media.Packet{}, false, ErrMalformed
+	}
+
+	packetType := tag.Data[1]
+	if packetType != avcSequenceHeader && packetType != avcNALU {
+		return media.Packet{}, false, nil
+	}
+
+	// composition time is a signed 24 bit number
+	offset := int32(uint32(tag.Data[2])<<16|uint32(tag.Data[3])<<8|uint32(tag.Data[4])) << 8 >> 8
+
+	return media.Packet{
+		Codec:             media.CodecH264,
+		Time:              time.Duration(tag.Timestamp) * time.Millisecond,
+		CompositionOffset: time.Duration(offset) * time.Millisecond,
+		Keyframe:          frameType == videoKeyframe,
+		Config:            packetType == avcSequenceHeader,
+		Data:              tag.Data[5:],
+	}, true, nil
+}
+
+func parseAudio(tag Tag) (media.Packet, bool, error) {
+	if len(tag.Data) < 1 {
+		return media.Packet{}, false, ErrMalformed
+	}
+	format := tag.Data[0] >> 4
+	if format != soundFormatAAC {
+		return media.Packet{}, false, fmt.Errorf("%w: sound format %d", ErrUnsupportedCodec, format)
+	}
+	if len(tag.Data) < 2 {
+		return media.Packet{}, false, ErrMalformed
+	}
+
+	return media.Packet{
+		Codec:  media.CodecAAC,
+		Time:   time.Duration(tag.Timestamp) * time.Millisecond,
+		Config: tag.Data[1] == aacSequenceHeader,
+		Data:   tag.Data[2:],
+	}, true, nil
+}
+
+// EncodeTag is the inverse of ParseTag. FLV has no place for Opus, it is ErrUnsupportedCodec.
+func EncodeTag(packet media.Packet) (Tag, error) {
+	const op = "lib.flv.EncodeTag"
+
+	tag := Tag{
+		Timestamp: uint32(packet.Time / time.Millisecond),
+	}
+
+	switch packet.Codec {
+	case media.CodecH264:
+		frameType := byte(videoInterframe)
+		if packet.Keyframe {
+			frameType = videoKeyframe
+		}
+		packetType := byte(avcNALU)
+		if packet.Config {
+			packetType = avcSequenceHeader
+		}
+		offset := int32(packet.CompositionOffset / time.Millisecond)
+
+		tag.Type = TagVideo
+		tag.Data = make([]byte, 0, 5+len(packet.Data))
+		tag.Data = append(tag.Data, frameType<<4|codecAVC, packetType, byte(offset>>16), byte(offset>>8), byte(offset))
+		tag.Data = append(tag.Data, packet.Data...)
+	case media.CodecAAC:
+		packetType := byte(aacRaw)
+		if packet.Config {
+			packetType = aacSequenceHeader
+		}
+
+		tag.Type = TagAudio
+		tag.Data = make([]byte, 0, 2+len(packet.Data))
+		tag.Data = append(tag.Data, soundFormatAAC<<4|aacSoundFlags, packetType)
+		tag.Data = append(tag.Data, packet.Data...)
+	default:
+		return Tag{}, fmt.Errorf("%s: %w: %s", op, ErrUnsupportedCodec, packet.Codec)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the fallback for unsupported locales and missing keys, its catalog must be complete.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+// Catalog holds a flat key to message map per locale, loaded from locales/<locale>.json.
+// Messages may contain fmt verbs, filled from the Translate arguments.
+type Catalog struct {
+	messages map[string]map[string]string
+	locales  []string
+	matcher  language.Matcher
+}
+
+func Load() (*Catalog, error) {
+	const op = "lib.i18n.Load"
+
+	files, err := catalogs.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c := &Catalog{
+		messages: make(map[string]map[string]string, len(files)),
+		locales:  []string{DefaultLocale},
+	}
+	for _, file := range files {
+		locale := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+
+		data, err := catalogs.ReadFile("locales/" + file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		messages := make(map[string]string)
+		if err = json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, file.Name(), err)
+		}
+
+		c.messages[locale] = messages
+		if locale != DefaultLocale {
+			c.locales = append(c.locales, locale)
+		}
+	}
+	if _, ok := c.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("%s: no catalog for the default locale %q", op, DefaultLocale)
+	}
+
+	// the default locale goes first, the matcher falls back to it
+	tags := make([]language.Tag, 0, len(c.locales))
+	for _, locale := range c.locales {
+		tags = append(tags, language.Make(locale))
+	}
+	c.matcher = language.NewMatcher(tags)
+
+	return c, nil
+}
+
+// Locales lists the supported locales, the default one first.
+func (c *Catalog) Locales() []string {
+	return c.locales
+}
+
+// Negotiate picks the supported locale that fits the first acceptable preference.
+// Each preference is an Accept-Language header or a plain locale such as "ru".
+func (c *Catalog) Negotiate(preferences ...string) string {
+	_, index := language.MatchStrings(c.matcher, preferences...)
+
+	return c.locales[index]
+}
+
+// Supported reports whether locale has its own catalog.
+func (c *Catalog) Supported(locale string) bool {
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// Translate looks key up in the locale, then in the default locale.
+// An unknown key is returned as is, so a missing translation never breaks a response.
+func (c *Catalog) Translate(locale, key string, args ...any) string {
+	message, ok := c.messages[locale][key]
+	if !ok {
+		message, ok = c.messages[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// Keys returns the keys of the locale catalog, used to check the catalogs are complete.
+func (c *Catalog) Keys(locale string) []string {
+	keys := make([]string, 0, len(c.messages[locale]))
+	for key := range c.messages[locale] {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,197 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sourceRoots are scanned for the error messages the API can answer with.
+var sourceRoots = []string{"../../../internal", "../../../pkg"}
+
+var verbRegexp = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalog_Complete(t *testing.T) {
+	c, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, DefaultLocale, c.Locales()[0])
+
+	defaultKeys := c.Keys(DefaultLocale)
+	slices.Sort(defaultKeys)
+	for _, locale := range c.Locales()[1:] {
+		keys := c.Keys(locale)
+		slices.Sort(keys)
+		require.Equal(t, defaultKeys, keys, "%s catalog has missing or extra keys", locale)
+
+		for _, key := range keys {
+			require.Equal(t,
+				verbRegexp.FindAllString(c.Translate(DefaultLocale, key), -1),
+				verbRegexp.FindAllString(c.Translate(locale, key), -1),
+				"%s: %s has other format verbs", locale, key,
+			)
+		}
+	}
+}
+
+// TestCatalog_ErrorMessages makes sure every error the API responds with can be translated:
+// string literals passed to api.Error and api.WriteError, and every error declared in errs.
+func TestCatalog_ErrorMessages(t *testing.T) {
+	c, err := Load()
+	require.NoError(t, err)
+
+	messages := errsMessages(t)
+	for _, root := range sourceRoots {
+		messages = append(messages, apiErrorLiterals(t, root)...)
+	}
+	require.NotEmpty(t, messages)
+
+	for _, msg := range messages {
+		key := "error." + msg
+		require.NotEqual(t, key, c.Translate(DefaultLocale, key), "no catalog entry for %q", msg)
+	}
+}
+
+func TestCatalog_Negotiate(t *testing.T) {
+	c, err := Load()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		preferences []string
+		want        string
+	}{
+		{
+			name:        "empty case",
+			preferences: []string{""},
+			want:        DefaultLocale,
+		},
+		{
+			name:        "region case",
+			preferences: []string{"ru-RU,ru;q=0.9,en;q=0.8"},
+			want:        "ru",
+		},
+		{
+			name:        "quality case",
+			preferences: []string{"ru;q=0.5,en;q=0.9"},
+			want:        "en",
+		},
+		{
+			name:        "unsupported case",
+			preferences: []string{"ja-JP"},
+			want:        DefaultLocale,
+		},
+		{
+			name:        "fallback preference case",
+			preferences: []string{"ja", "ru"},
+			want:        "ru",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, c.Negotiate(tt.preferences...))
+		})
+	}
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	c := &Catalog{
+		messages: map[string]map[string]string{
+			DefaultLocale: {"greeting": "Hello, %s", "bye": "Bye"},
+			"ru":          {"greeting": "Привет, %s"},
+		},
+	}
+
+	require.Equal(t, "Привет, login", c.Translate("ru", "greeting", "login"))
+	require.Equal(t, "Bye", c.Translate("ru", "bye"))
+	require.Equal(t, "Hello, login", c.Translate("de", "greeting", "login"))
+	require.Equal(t, "unknown", c.Translate("ru", "unknown"))
+	require.True(t, c.Supported("ru"))
+	require.False(t, c.Supported("de"))
+}
+
+func errsMessages(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../../errs/errs.go", nil, 0)
+	require.NoError(t, err)
+
+	var messages []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if ok && isCall(call, "errors", "New") {
+			if msg, ok := stringLit(call.Args[0]); ok {
+				messages = append(messages, msg)
+			}
+		}
+		return true
+	})
+
+	return messages
+}
+
+func apiErrorLiterals(t *testing.T, root string) []string {
+	t.Helper()
+
+	var messages []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch {
+			case isCall(call, "api", "Error") && len(call.Args) == 2:
+				if msg, ok := stringLit(call.Args[0]); ok {
+					messages = append(messages, msg)
+				}
+			case isCall(call, "api", "WriteError") && len(call.Args) == 4:
+				if msg, ok := stringLit(call.Args[2]); ok {
+					messages = append(messages, msg)
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	return messages
+}
+
+func isCall(call *ast.CallExpr, pkg, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
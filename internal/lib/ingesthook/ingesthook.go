@@ -0,0 +1,102 @@
+// Package ingesthook reads the http callbacks of the ingest servers broadcasters can go
+// through instead of cmd/ingest. nginx-rtmp posts a form, SRS posts JSON.
+package ingesthook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+)
+
+// maxBodySize caps a callback, both servers send a few hundred bytes
+const maxBodySize = 64 << 10
+
+// srsActions maps the SRS hooks to the nginx-rtmp calls
+var srsActions = map[string]string{
+	"on_publish":   "publish",
+	"on_unpublish": "publish_done",
+	"on_play":      "play",
+	"on_stop":      "play_done",
+}
+
+// Parse reads the callback of r. Errors wrap errs.ErrIngestCallback.
+func Parse(r *http.Request) (dtos.IngestCallbackRequest, error) {
+	const op = "lib.ingesthook.Parse"
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBodySize)
+
+	var (
+		req dtos.IngestCallbackRequest
+		err error
+	)
+	switch mediaType {
+	case "application/json":
+		req, err = parseSRS(r.Body)
+	case "application/x-www-form-urlencoded":
+		req, err = parseNginx(r)
+	default:
+		err = fmt.Errorf("unsupported content type %q", mediaType)
+	}
+	if err != nil {
+		return dtos.IngestCallbackRequest{}, fmt.Errorf("%s: %w: %w", op, errs.ErrIngestCallback, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return dtos.IngestCallbackRequest{}, fmt.Errorf("%s: %w: %w", op, errs.ErrIngestCallback, err)
+	}
+
+	return req, nil
+}
+
+func parseNginx(r *http.Request) (dtos.IngestCallbackRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return dtos.IngestCallbackRequest{}, err
+	}
+
+	// the query parameters of the publish URL come as form values too, the key is one of them
+	return dtos.IngestCallbackRequest{
+		Server:   consts.IngestServerNginx,
+		Call:     r.PostForm.Get("call"),
+		ClientId: r.PostForm.Get("clientid"),
+		Addr:     r.PostForm.Get("addr"),
+		App:      r.PostForm.Get("app"),
+		Name:     r.PostForm.Get("name"),
+		Key:      r.PostForm.Get("key"),
+	}, nil
+}
+
+func parseSRS(body io.Reader) (dtos.IngestCallbackRequest, error) {
+	var callback dtos.SRSCallbackRequest
+	if err := json.NewDecoder(body).Decode(&callback); err != nil {
+		return dtos.IngestCallbackRequest{}, err
+	}
+
+	call, ok := srsActions[callback.Action]
+	if !ok {
+		return dtos.IngestCallbackRequest{}, fmt.Errorf("unknown action %q", callback.Action)
+	}
+
+	params, err := url.ParseQuery(strings.TrimPrefix(callback.Param, "?"))
+	if err != nil {
+		return dtos.IngestCallbackRequest{}, err
+	}
+
+	return dtos.IngestCallbackRequest{
+		Server:   consts.IngestServerSRS,
+		Call:     call,
+		ClientId: callback.ClientId,
+		Addr:     callback.IP,
+		App:      callback.App,
+		Name:     callback.Stream,
+		Key:      params.Get("key"),
+	}, nil
+}
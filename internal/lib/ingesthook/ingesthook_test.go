@@ -0,0 +1,91 @@
+package ingesthook
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        dtos.IngestCallbackRequest
+		wantErr     bool
+	}{
+		{
+			name:        "nginx case",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "call=publish&addr=10.0.0.5&clientid=7&app=live&flashVer=FMLE&name=live_key&type=live",
+			want: dtos.IngestCallbackRequest{
+				Server:   "nginx-rtmp",
+				Call:     "publish",
+				ClientId: "7",
+				Addr:     "10.0.0.5",
+				App:      "live",
+				Name:     "live_key",
+			},
+		},
+		{
+			name:        "srs case",
+			contentType: "application/json; charset=utf-8",
+			body: `{"server_id":"vid-1","action":"on_unpublish","client_id":"341w361a","ip":"10.0.0.5",` +
+				`"vhost":"__defaultVhost__","app":"live","stream":"streamer","param":"?key=live_key"}`,
+			want: dtos.IngestCallbackRequest{
+				Server:   "srs",
+				Call:     "publish_done",
+				ClientId: "341w361a",
+				Addr:     "10.0.0.5",
+				App:      "live",
+				Name:     "streamer",
+				Key:      "live_key",
+			},
+		},
+		{
+			name:        "unknown srs action case",
+			contentType: "application/json",
+			body:        `{"action":"on_dvr","client_id":"1","stream":"streamer"}`,
+			wantErr:     true,
+		},
+		{
+			name:        "unknown nginx call case",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "call=record_done&clientid=7&name=live_key",
+			wantErr:     true,
+		},
+		{
+			name:        "missing name case",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "call=publish&clientid=7",
+			wantErr:     true,
+		},
+		{
+			name:        "content type case",
+			contentType: "text/plain",
+			body:        "publish",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest("POST", "/ingest/callbacks/on_publish", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			got, err := Parse(r)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errs.ErrIngestCallback)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package mailevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+)
+
+// Sample builds a webhook body the provider would send for one event of eventType,
+// a hard bounce, a soft bounce or a complaint.
+func Sample(provider, eventType, email string) ([]byte, error) {
+	const op = "lib.mailevents.Sample"
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	var payload any
+	switch provider {
+	case consts.MailProviderGeneric:
+		event := dtos.MailEventRequest{Type: "bounce", BounceType: "hard", Email: email, Reason: "550 5.1.1 user unknown", Timestamp: now}
+		switch eventType {
+		case consts.MailEventSoftBounce:
+			event.BounceType = "soft"
+			event.Reason = "452 4.2.2 mailbox full"
+		case consts.MailEventComplaint:
+			event = dtos.MailEventRequest{Type: "complaint", Email: email, Reason: "abuse", Timestamp: now}
+		}
+		payload = dtos.MailEventsRequest{Events: []dtos.MailEventRequest{event}}
+	case consts.MailProviderSES:
+		notification := map[string]any{
+			"notificationType": "Bounce",
+			"bounce": map[string]any{
+				"bounceType":        "Permanent",
+				"bouncedRecipients": []map[string]any{{"emailAddress": email, "diagnosticCode": "smtp; 550 5.1.1 user unknown"}},
+				"timestamp":         now,
+			},
+		}
+		switch eventType {
+		case consts.MailEventSoftBounce:
+			notification["bounce"].(map[string]any)["bounceType"] = "Transient"
+		case consts.MailEventComplaint:
+			notification = map[string]any{
+				"notificationType": "Complaint",
+				"complaint": map[string]any{
+					"complainedRecipients":  []map[string]any{{"emailAddress": email}},
+					"complaintFeedbackType": "abuse",
+					"timestamp":             now,
+				},
+			}
+		}
+		message, err := json.Marshal(notification)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		payload = map[string]any{"Type": "Notification", "Message": string(message)}
+	case consts.MailProviderSendGrid:
+		event := map[string]any{"email": email, "event": "bounce", "type": "bounce", "reason": "550 5.1.1 user unknown", "timestamp": now.Unix()}
+		switch eventType {
+		case consts.MailEventSoftBounce:
+			event["type"] = "blocked"
+		case consts.MailEventComplaint:
+			event = map[string]any{"email": email, "event": "spamreport", "timestamp": now.Unix()}
+		}
+		payload = []map[string]any{event}
+	case consts.MailProviderMailgun:
+		data := map[string]any{
+			"event":           "failed",
+			"severity":        "permanent",
+			"recipient":       email,
+			"timestamp":       float64(now.Unix()),
+			"delivery-status": map[string]any{"description": "550 5.1.1 user unknown"},
+		}
+		switch eventType {
+		case consts.MailEventSoftBounce:
+			data["severity"] = "temporary"
+		case consts.MailEventComplaint:
+			data = map[string]any{"event": "complained", "recipient": email, "timestamp": float64(now.Unix())}
+		}
+		payload = map[string]any{"event-data": data}
+	default:
+		return nil, fmt.Errorf("%s: unknown provider %q", op, provider)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return body, nil
+}
+
+// Fake plays a mail provider: it posts sample events to the webhook of a running server,
+// see cmd/mail-events-fake.
+type Fake struct {
+	baseURL string
+	secret  string
+	client  *http.Client
+}
+
+func NewFake(baseURL, secret string) *Fake {
+	return &Fake{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Post sends one sample event and returns the response body.
+func (f *Fake) Post(ctx context.Context, provider, eventType, email string) ([]byte, error) {
+	const op = "lib.mailevents.Fake.Post"
+
+	body, err := Sample(provider, eventType, email)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/webhooks/mail/"+provider, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+f.secret)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: webhook answered %d: %s", op, resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,256 @@
+// Package mailevents turns bounce and complaint notifications of mail providers into
+// entities.MailEvent. Every provider has an adapter, the generic format (dtos.MailEventsRequest)
+// is for providers without one and for tests.
+package mailevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+)
+
+// Adapter parses the webhook body of one provider. Events the service does not care about,
+// e.g. deliveries or opens, are skipped.
+type Adapter func(body []byte) ([]entities.MailEvent, error)
+
+var adapters = map[string]Adapter{
+	consts.MailProviderGeneric:  parseGeneric,
+	consts.MailProviderSES:      parseSES,
+	consts.MailProviderSendGrid: parseSendGrid,
+	consts.MailProviderMailgun:  parseMailgun,
+}
+
+// Providers lists the providers with an adapter.
+func Providers() []string {
+	return []string{
+		consts.MailProviderGeneric,
+		consts.MailProviderSES,
+		consts.MailProviderSendGrid,
+		consts.MailProviderMailgun,
+	}
+}
+
+func Parse(provider string, body []byte) ([]entities.MailEvent, error) {
+	const op = "lib.mailevents.Parse"
+
+	adapter, ok := adapters[provider]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, errs.ErrMailProvider)
+	}
+
+	events, err := adapter(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w: %w", op, provider, errs.ErrMailEventsInvalid, err)
+	}
+
+	return events, nil
+}
+
+func parseGeneric(body []byte) ([]entities.MailEvent, error) {
+	var payload dtos.MailEventsRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	events := make([]entities.MailEvent, 0, len(payload.Events))
+	for _, event := range payload.Events {
+		eventType := consts.MailEventComplaint
+		if event.Type == "bounce" {
+			eventType = consts.MailEventHardBounce
+			if event.BounceType == "soft" {
+				eventType = consts.MailEventSoftBounce
+			}
+		}
+
+		events = append(events, entities.MailEvent{
+			Type:       eventType,
+			Email:      event.Email,
+			Reason:     event.Reason,
+			OccurredAt: event.Timestamp,
+		})
+	}
+
+	return events, nil
+}
+
+// sesNotification is an Amazon SES notification. It comes wrapped into an SNS message
+// when the webhook is an SNS HTTP subscription.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Bounce           struct {
+		BounceType        string `json:"bounceType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+		ComplaintFeedbackType string    `json:"complaintFeedbackType"`
+		Timestamp             time.Time `json:"timestamp"`
+	} `json:"complaint"`
+}
+
+type snsMessage struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+func parseSES(body []byte) ([]entities.MailEvent, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	switch envelope.Type {
+	case "":
+		// raw notification, e.g. delivered by a queue consumer
+	case "Notification":
+		body = []byte(envelope.Message)
+	default:
+		// subscription confirmations are done by the operator in the AWS console
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, err
+	}
+
+	var events []entities.MailEvent
+	switch notification.NotificationType {
+	case "Bounce":
+		eventType := consts.MailEventSoftBounce
+		if notification.Bounce.BounceType == "Permanent" {
+			eventType = consts.MailEventHardBounce
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			events = append(events, entities.MailEvent{
+				Type:       eventType,
+				Email:      recipient.EmailAddress,
+				Reason:     recipient.DiagnosticCode,
+				OccurredAt: notification.Bounce.Timestamp,
+			})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			events = append(events, entities.MailEvent{
+				Type:       consts.MailEventComplaint,
+				Email:      recipient.EmailAddress,
+				Reason:     notification.Complaint.ComplaintFeedbackType,
+				OccurredAt: notification.Complaint.Timestamp,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// sendGridEvent is one entry of the SendGrid event webhook batch.
+type sendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func parseSendGrid(body []byte) ([]entities.MailEvent, error) {
+	var batch []sendGridEvent
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, err
+	}
+
+	var events []entities.MailEvent
+	for _, event := range batch {
+		var eventType string
+		switch event.Event {
+		case "bounce":
+			// "blocked" bounces are temporary rejections
+			eventType = consts.MailEventHardBounce
+			if event.Type == "blocked" {
+				eventType = consts.MailEventSoftBounce
+			}
+		case "spamreport":
+			eventType = consts.MailEventComplaint
+		default:
+			continue
+		}
+
+		events = append(events, entities.MailEvent{
+			Type:       eventType,
+			Email:      event.Email,
+			Reason:     event.Reason,
+			OccurredAt: unixTime(float64(event.Timestamp)),
+		})
+	}
+
+	return events, nil
+}
+
+// mailgunPayload is a Mailgun webhook. The signature is not checked,
+// the webhook is protected by the shared secret like for the other providers.
+type mailgunPayload struct {
+	EventData struct {
+		Event          string  `json:"event"`
+		Severity       string  `json:"severity"`
+		Recipient      string  `json:"recipient"`
+		Timestamp      float64 `json:"timestamp"`
+		DeliveryStatus struct {
+			Description string `json:"description"`
+			Message     string `json:"message"`
+		} `json:"delivery-status"`
+	} `json:"event-data"`
+}
+
+func parseMailgun(body []byte) ([]entities.MailEvent, error) {
+	var payload mailgunPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	data := payload.EventData
+
+	var eventType string
+	switch data.Event {
+	case "failed":
+		eventType = consts.MailEventSoftBounce
+		if data.Severity == "permanent" {
+			eventType = consts.MailEventHardBounce
+		}
+	case "complained":
+		eventType = consts.MailEventComplaint
+	default:
+		return nil, nil
+	}
+
+	reason := data.DeliveryStatus.Description
+	if reason == "" {
+		reason = data.DeliveryStatus.Message
+	}
+
+	return []entities.MailEvent{{
+		Type:       eventType,
+		Email:      data.Recipient,
+		Reason:     strings.TrimSpace(reason),
+		OccurredAt: unixTime(data.Timestamp),
+	}}, nil
+}
+
+func unixTime(seconds float64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(int64(seconds * 1000)).UTC()
+}
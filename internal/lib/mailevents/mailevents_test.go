@@ -0,0 +1,129 @@
+package mailevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse_Samples runs every sample through the adapter of its provider.
+func TestParse_Samples(t *testing.T) {
+	eventTypes := []string{consts.MailEventHardBounce, consts.MailEventSoftBounce, consts.MailEventComplaint}
+
+	for _, provider := range Providers() {
+		for _, eventType := range eventTypes {
+			t.Run(provider+" "+eventType, func(t *testing.T) {
+				body, err := Sample(provider, eventType, "user@test.com")
+				require.NoError(t, err)
+
+				events, err := Parse(provider, body)
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, eventType, events[0].Type)
+				require.Equal(t, "user@test.com", events[0].Email)
+				require.False(t, events[0].OccurredAt.IsZero())
+			})
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		body       string
+		wantEvents int
+		wantErr    error
+	}{
+		{
+			name:     "unknown provider case",
+			provider: "postmark",
+			body:     `{}`,
+			wantErr:  errs.ErrMailProvider,
+		},
+		{
+			name:     "malformed body case",
+			provider: consts.MailProviderSendGrid,
+			body:     `{`,
+			wantErr:  errs.ErrMailEventsInvalid,
+		},
+		{
+			name:     "generic without events case",
+			provider: consts.MailProviderGeneric,
+			body:     `{"events": []}`,
+			wantErr:  errs.ErrMailEventsInvalid,
+		},
+		{
+			name:     "generic bad email case",
+			provider: consts.MailProviderGeneric,
+			body:     `{"events": [{"type": "bounce", "email": "nope"}]}`,
+			wantErr:  errs.ErrMailEventsInvalid,
+		},
+		{
+			name:       "generic bounce defaults to hard case",
+			provider:   consts.MailProviderGeneric,
+			body:       `{"events": [{"type": "bounce", "email": "a@test.com"}, {"type": "complaint", "email": "b@test.com"}]}`,
+			wantEvents: 2,
+		},
+		{
+			name:       "sendgrid skips other events case",
+			provider:   consts.MailProviderSendGrid,
+			body:       `[{"email": "a@test.com", "event": "delivered"}, {"email": "a@test.com", "event": "open"}]`,
+			wantEvents: 0,
+		},
+		{
+			name:       "ses subscription confirmation case",
+			provider:   consts.MailProviderSES,
+			body:       `{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://sns.example.com"}`,
+			wantEvents: 0,
+		},
+		{
+			name:       "ses raw notification case",
+			provider:   consts.MailProviderSES,
+			body:       `{"notificationType": "Bounce", "bounce": {"bounceType": "Permanent", "bouncedRecipients": [{"emailAddress": "a@test.com"}, {"emailAddress": "b@test.com"}]}}`,
+			wantEvents: 2,
+		},
+		{
+			name:       "mailgun delivered case",
+			provider:   consts.MailProviderMailgun,
+			body:       `{"event-data": {"event": "delivered", "recipient": "a@test.com"}}`,
+			wantEvents: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events, err := Parse(tt.provider, []byte(tt.body))
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, events, tt.wantEvents)
+		})
+	}
+}
+
+func TestFake_Post(t *testing.T) {
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if gotAuth != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"received": 1}`))
+	}))
+	defer srv.Close()
+
+	body, err := NewFake(srv.URL+"/", "secret").Post(t.Context(), consts.MailProviderSES, consts.MailEventComplaint, "user@test.com")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"received": 1}`, string(body))
+	require.Equal(t, "/webhooks/mail/ses", gotPath)
+
+	_, err = NewFake(srv.URL, "wrong").Post(t.Context(), consts.MailProviderSES, consts.MailEventComplaint, "user@test.com")
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+// Package media is the common ground of the ingest protocols: whatever a broadcaster sends over
+// RTMP or WebRTC ends up as Packets written to a Sink.
+package media
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Codec string
+
+const (
+	CodecH264 Codec = "h264"
+	CodecAAC  Codec = "aac"
+	CodecOpus Codec = "opus"
+)
+
+func (c Codec) IsVideo() bool {
+	return c == CodecH264
+}
+
+// Packet is one video frame or one frame of audio samples.
+type Packet struct {
+	Codec Codec
+	// Time is the decode time since the start of the broadcast
+	Time time.Duration
+	// CompositionOffset is how much later than Time the frame is presented, B-frames need it
+	CompositionOffset time.Duration
+	Keyframe          bool
+	// Config marks decoder configuration instead of media: the AVCDecoderConfigurationRecord
+	// for H.264 or the AudioSpecificConfig for AAC
+	Config bool
+	// Data of H.264 frames is NAL units with 4 byte length prefixes, as in MP4 and FLV
+	Data []byte
+}
+
+// Stream describes a broadcast to the sink.
+type Stream struct {
+	ChannelId uuid.UUID
+	Login     string
+	// Protocol the broadcaster uses, rtmp or whip
+	Protocol  string
+	StartedAt time.Time
+}
+
+// Sink takes the media of every broadcast, it is where a recorder or a transcoder plugs in.
+type Sink interface {
+	Open(ctx context.Context, stream Stream) (Writer, error)
+}
+
+// Writer gets the packets of one broadcast. Close is called once when the broadcast ends.
+type Writer interface {
+	WritePacket(packet Packet) error
+	Close() error
+}
+
+// Discard is a Sink that drops everything, ingest then only authenticates broadcasters.
+var Discard Sink = discard{}
+
+type discard struct{}
+
+func (discard) Open(context.Context, Stream) (Writer, error) {
+	return discard{}, nil
+}
+
+func (discard) WritePacket(Packet) error {
+	return nil
+}
+
+func (discard) Close() error {
+	return nil
+}
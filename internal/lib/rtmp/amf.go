@@ -0,0 +1,246 @@
+package rtmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// AMF0 type markers. AMF3 is never negotiated, connect answers with objectEncoding 0.
+const (
+	amfNumber      = 0x00
+	amfBoolean     = 0x01
+	amfString      = 0x02
+	amfObject      = 0x03
+	amfNull        = 0x05
+	amfUndefined   = 0x06
+	amfECMAArray   = 0x08
+	amfObjectEnd   = 0x09
+	amfStrictArray = 0x0a
+	amfDate        = 0x0b
+	amfLongString  = 0x0c
+)
+
+// maxAMFDepth stops objects nested deep enough to exhaust the stack.
+const maxAMFDepth = 16
+
+var errAMF = errors.New("malformed amf0 value")
+
+// Object is an AMF0 object or ECMA array.
+type Object map[string]any
+
+// encodeAMF writes values as AMF0. It knows float64, int, bool, string, Object, nil and []any.
+func encodeAMF(values ...any) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, value := range values {
+		if err := encodeAMFValue(&buf, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeAMFValue(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(amfNull)
+	case float64:
+		buf.WriteByte(amfNumber)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case int:
+		return encodeAMFValue(buf, float64(v))
+	case bool:
+		buf.WriteByte(amfBoolean)
+		if v {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case string:
+		if len(v) > math.MaxUint16 {
+			buf.WriteByte(amfLongString)
+			_ = binary.Write(buf, binary.BigEndian, uint32(len(v)))
+			buf.WriteString(v)
+			return nil
+		}
+		buf.WriteByte(amfString)
+		writeAMFKey(buf, v)
+	case Object:
+		buf.WriteByte(amfObject)
+		// sorted, so the same object always encodes the same
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			writeAMFKey(buf, key)
+			if err := encodeAMFValue(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.Write([]byte{0, 0, amfObjectEnd})
+	case []any:
+		buf.WriteByte(amfStrictArray)
+		_ = binary.Write(buf, binary.BigEndian, uint32(len(v)))
+		for _, item := range v {
+			if err := encodeAMFValue(buf, item); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("amf0 cannot encode %T", value)
+	}
+
+	return nil
+}
+
+func writeAMFKey(buf *bytes.Buffer, key string) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(key)))
+	buf.WriteString(key)
+}
+
+// decodeAMF reads every value in data. Numbers and dates come back as float64, undefined as nil.
+func decodeAMF(data []byte) ([]any, error) {
+	d := amfDecoder{data: data}
+
+	var values []any
+	for d.pos < len(d.data) {
+		value, err := d.value(0)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+type amfDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *amfDecoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errAMF
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *amfDecoder) value(depth int) (any, error) {
+	if depth > maxAMFDepth {
+		return nil, errAMF
+	}
+
+	marker, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch marker[0] {
+	case amfNumber:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case amfBoolean:
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case amfString:
+		return d.key()
+	case amfLongString:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.next(int(binary.BigEndian.Uint32(b)))
+		if err != nil {
+			return nil, err
+		}
+		return string(s), nil
+	case amfObject:
+		return d.object(depth)
+	case amfECMAArray:
+		// the count is only a hint, the array ends like an object
+		if _, err := d.next(4); err != nil {
+			return nil, err
+		}
+		return d.object(depth)
+	case amfStrictArray:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		count := int(binary.BigEndian.Uint32(b))
+		if count > len(d.data)-d.pos {
+			return nil, errAMF
+		}
+		items := make([]any, 0, count)
+		for range count {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case amfDate:
+		b, err := d.next(10)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case amfNull, amfUndefined:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: marker %#x", errAMF, marker[0])
+	}
+}
+
+func (d *amfDecoder) key() (string, error) {
+	b, err := d.next(2)
+	if err != nil {
+		return "", err
+	}
+	s, err := d.next(int(binary.BigEndian.Uint16(b)))
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+func (d *amfDecoder) object(depth int) (Object, error) {
+	obj := Object{}
+	for {
+		key, err := d.key()
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			end, err := d.next(1)
+			if err != nil {
+				return nil, err
+			}
+			if end[0] != amfObjectEnd {
+				return nil, errAMF
+			}
+			return obj, nil
+		}
+		value, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+	}
+}
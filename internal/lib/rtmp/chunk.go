@@ -0,0 +1,278 @@
+package rtmp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// message types
+const (
+	typeSetChunkSize     = 1
+	typeAbort            = 2
+	typeAck              = 3
+	typeUserControl      = 4
+	typeWindowAckSize    = 5
+	typeSetPeerBandwidth = 6
+	typeAudio            = 8
+	typeVideo            = 9
+	typeDataAMF3         = 15
+	typeCommandAMF3      = 17
+	typeDataAMF0         = 18
+	typeCommandAMF0      = 20
+)
+
+// chunk streams messages are sent on, only the control one is fixed by the spec
+const (
+	csidControl = 2
+	csidCommand = 3
+	csidAudio   = 4
+	csidData    = 5
+	csidVideo   = 6
+)
+
+const (
+	defaultChunkSize = 128
+	maxChunkSize     = 0xffffff
+	// maxChunkStreams bounds the partly read messages a peer can make us hold
+	maxChunkStreams = 64
+	extendedTime    = 0xffffff
+)
+
+var errProtocol = errors.New("rtmp protocol violation")
+
+type message struct {
+	typeID    uint8
+	streamID  uint32
+	timestamp uint32
+	payload   []byte
+}
+
+// chunkStream is what the last header on a chunk stream said, later headers only carry changes.
+type chunkStream struct {
+	timestamp uint32
+	delta     uint32
+	length    uint32
+	typeID    uint8
+	streamID  uint32
+	extended  bool
+	reading   bool
+	payload   []byte
+}
+
+// chunkReader reassembles messages from the chunks of all chunk streams.
+type chunkReader struct {
+	r         *bufio.Reader
+	chunkSize uint32
+	streams   map[uint32]*chunkStream
+}
+
+func newChunkReader(r io.Reader) *chunkReader {
+	return &chunkReader{
+		r:         bufio.NewReader(r),
+		chunkSize: defaultChunkSize,
+		streams:   make(map[uint32]*chunkStream),
+	}
+}
+
+func (r *chunkReader) readMessage() (message, error) {
+	for {
+		cs, err := r.readHeader()
+		if err != nil {
+			return message{}, err
+		}
+
+		n := min(r.chunkSize, cs.length-uint32(len(cs.payload)))
+		start := len(cs.payload)
+		cs.payload = append(cs.payload, make([]byte, n)...)
+		if _, err := io.ReadFull(r.r, cs.payload[start:]); err != nil {
+			return message{}, err
+		}
+
+		if uint32(len(cs.payload)) == cs.length {
+			msg := message{
+				typeID:    cs.typeID,
+				streamID:  cs.streamID,
+				timestamp: cs.timestamp,
+				payload:   cs.payload,
+			}
+			cs.reading = false
+			cs.payload = nil
+			return msg, nil
+		}
+	}
+}
+
+// readHeader reads a chunk header and returns the state of its chunk stream updated by it.
+func (r *chunkReader) readHeader() (*chunkStream, error) {
+	b, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	format := b >> 6
+	csid := uint32(b & 0x3f)
+	switch csid {
+	case 0:
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		csid = 64 + uint32(b)
+	case 1:
+		var buf [2]byte
+		if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+			return nil, err
+		}
+		csid = 64 + uint32(buf[0]) + uint32(buf[1])*256
+	}
+
+	cs, ok := r.streams[csid]
+	if !ok {
+		if len(r.streams) >= maxChunkStreams {
+			return nil, fmt.Errorf("%w: too many chunk streams", errProtocol)
+		}
+		cs = &chunkStream{}
+		r.streams[csid] = cs
+	}
+
+	if format == 3 {
+		if cs.extended {
+			// repeats the extended timestamp of the header this chunk continues
+			if _, err := r.readUint(4); err != nil {
+				return nil, err
+			}
+		}
+		if !cs.reading {
+			cs.timestamp += cs.delta
+			cs.reading = true
+		}
+		return cs, nil
+	}
+
+	field, err := r.readUint(3)
+	if err != nil {
+		return nil, err
+	}
+	if format <= 1 {
+		length, err := r.readUint(3)
+		if err != nil {
+			return nil, err
+		}
+		typeID, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		cs.length, cs.typeID = length, typeID
+	}
+	if format == 0 {
+		var buf [4]byte
+		if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+			return nil, err
+		}
+		cs.streamID = binary.LittleEndian.Uint32(buf[:])
+	}
+	cs.extended = field == extendedTime
+	if cs.extended {
+		if field, err = r.readUint(4); err != nil {
+			return nil, err
+		}
+	}
+
+	// a full header always starts a new message, what was read of the last one is dropped
+	if format == 0 {
+		cs.timestamp = field
+	} else {
+		cs.timestamp += field
+	}
+	cs.delta = field
+	cs.reading = true
+	cs.payload = nil
+
+	return cs, nil
+}
+
+func (r *chunkReader) readUint(n int) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r.r, buf[4-n:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(buf[:]), nil
+}
+
+// abort drops the partly read message of a chunk stream.
+func (r *chunkReader) abort(csid uint32) {
+	if cs, ok := r.streams[csid]; ok {
+		cs.reading = false
+		cs.payload = nil
+	}
+}
+
+// chunkWriter splits messages into chunks. Every message gets a full header, which costs a few
+// bytes but keeps no state about what the peer last saw.
+type chunkWriter struct {
+	w         *bufio.Writer
+	chunkSize uint32
+}
+
+func newChunkWriter(w io.Writer) *chunkWriter {
+	return &chunkWriter{
+		w:         bufio.NewWriter(w),
+		chunkSize: defaultChunkSize,
+	}
+}
+
+func (w *chunkWriter) writeMessage(csid uint32, msg message) error {
+	extended := msg.timestamp >= extendedTime
+	timestamp := msg.timestamp
+	if extended {
+		timestamp = extendedTime
+	}
+
+	header := make([]byte, 0, 16)
+	header = append(header, byte(csid))
+	header = append(header, byte(timestamp>>16), byte(timestamp>>8), byte(timestamp))
+	length := len(msg.payload)
+	header = append(header, byte(length>>16), byte(length>>8), byte(length), msg.typeID)
+	header = binary.LittleEndian.AppendUint32(header, msg.streamID)
+	if extended {
+		header = binary.BigEndian.AppendUint32(header, msg.timestamp)
+	}
+	if _, err := w.w.Write(header); err != nil {
+		return err
+	}
+
+	payload := msg.payload
+	for {
+		n := min(int(w.chunkSize), len(payload))
+		if _, err := w.w.Write(payload[:n]); err != nil {
+			return err
+		}
+		payload = payload[n:]
+		if len(payload) == 0 {
+			break
+		}
+
+		continuation := []byte{3<<6 | byte(csid)}
+		if extended {
+			continuation = binary.BigEndian.AppendUint32(continuation, msg.timestamp)
+		}
+		if _, err := w.w.Write(continuation); err != nil {
+			return err
+		}
+	}
+
+	return w.w.Flush()
+}
+
+func controlMessage(typeID uint8, values ...uint32) message {
+	payload := make([]byte, 0, 4*len(values))
+	for _, value := range values {
+		payload = binary.BigEndian.AppendUint32(payload, value)
+	}
+	return message{
+		typeID:  typeID,
+		payload: payload,
+	}
+}
@@ -0,0 +1,262 @@
+package rtmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+)
+
+const (
+	defaultPort     = "1935"
+	clientChunkSize = 4096
+)
+
+// StatusError is an onStatus of level error, such as NetStream.Publish.BadName.
+type StatusError struct {
+	Code        string
+	Description string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("rtmp: %s: %s", e.Code, e.Description)
+}
+
+// Client publishes to an RTMP server the way OBS does. It exists for tests and tools.
+type Client struct {
+	nc       net.Conn
+	r        *chunkReader
+	w        *chunkWriter
+	streamID uint32
+	txn      float64
+	done     chan struct{}
+}
+
+// Dial connects to rtmp://host[:port]/app.
+func Dial(ctx context.Context, rawURL string) (*Client, error) {
+	const op = "lib.rtmp.Dial"
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if u.Scheme != "rtmp" {
+		return nil, fmt.Errorf("%s: unsupported scheme %q", op, u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	var dialer net.Dialer
+	nc, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = nc.SetDeadline(deadline)
+	}
+
+	c := &Client{
+		nc:   nc,
+		r:    newChunkReader(nc),
+		w:    newChunkWriter(nc),
+		done: make(chan struct{}),
+	}
+	if err := c.connect(strings.Trim(u.Path, "/"), rawURL); err != nil {
+		_ = nc.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+func (c *Client) connect(app, tcURL string) error {
+	if err := clientHandshake(c.nc); err != nil {
+		return err
+	}
+
+	if err := c.w.writeMessage(csidControl, controlMessage(typeSetChunkSize, clientChunkSize)); err != nil {
+		return err
+	}
+	c.w.chunkSize = clientChunkSize
+
+	txn, err := c.writeCommand(0, "connect", Object{
+		"app":      app,
+		"tcUrl":    tcURL,
+		"flashVer": "FMLE/3.0 (compatible; twitch-clone)",
+		"type":     "nonprivate",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.await(txn)
+	return err
+}
+
+// Publish starts publishing under name and returns once the server accepted it.
+// A refusal is a *StatusError.
+func (c *Client) Publish(ctx context.Context, name string) error {
+	const op = "lib.rtmp.Client.Publish"
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.nc.SetDeadline(deadline)
+	}
+
+	txn, err := c.writeCommand(0, "createStream", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	values, err := c.await(txn)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(values) < 4 {
+		return fmt.Errorf("%s: %w: createStream without a stream id", op, errProtocol)
+	}
+	streamID, _ := values[3].(float64)
+	c.streamID = uint32(streamID)
+
+	payload, err := encodeAMF("publish", 0, nil, name, "live")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	err = c.w.writeMessage(csidData, message{typeID: typeCommandAMF0, streamID: c.streamID, payload: payload})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for {
+		values, err := c.readCommand()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if command, _ := values[0].(string); command != "onStatus" || len(values) < 4 {
+			continue
+		}
+		info, _ := values[3].(Object)
+		code, _ := info["code"].(string)
+		if level, _ := info["level"].(string); level == "error" {
+			description, _ := info["description"].(string)
+			return fmt.Errorf("%s: %w", op, &StatusError{Code: code, Description: description})
+		}
+		if code == "NetStream.Publish.Start" {
+			break
+		}
+	}
+
+	_ = c.nc.SetDeadline(time.Time{})
+	go c.drain()
+
+	return nil
+}
+
+// WriteTag sends an audio, video or script data tag of the published stream.
+func (c *Client) WriteTag(tag flv.Tag) error {
+	const op = "lib.rtmp.Client.WriteTag"
+
+	csid := uint32(csidData)
+	switch tag.Type {
+	case flv.TagAudio:
+		csid = csidAudio
+	case flv.TagVideo:
+		csid = csidVideo
+	}
+
+	err := c.w.writeMessage(csid, message{
+		typeID:    tag.Type,
+		streamID:  c.streamID,
+		timestamp: tag.Timestamp,
+		payload:   tag.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Done is closed when the server drops the connection after Publish.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close unpublishes and disconnects.
+func (c *Client) Close() error {
+	if c.streamID != 0 {
+		_, _ = c.writeCommand(0, "deleteStream", nil, float64(c.streamID))
+	}
+	return c.nc.Close()
+}
+
+func (c *Client) writeCommand(streamID uint32, name string, values ...any) (float64, error) {
+	c.txn++
+	payload, err := encodeAMF(append([]any{name, c.txn}, values...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.w.writeMessage(csidCommand, message{typeID: typeCommandAMF0, streamID: streamID, payload: payload})
+	return c.txn, err
+}
+
+// await reads until the _result of the transaction.
+func (c *Client) await(txn float64) ([]any, error) {
+	for {
+		values, err := c.readCommand()
+		if err != nil {
+			return nil, err
+		}
+		if id, _ := values[1].(float64); id != txn {
+			continue
+		}
+		switch values[0] {
+		case "_result":
+			return values, nil
+		case "_error":
+			return nil, errors.New("rtmp: command failed")
+		}
+	}
+}
+
+// readCommand reads until the next command, applying control messages on the way.
+func (c *Client) readCommand() ([]any, error) {
+	for {
+		msg, err := c.r.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		if msg.typeID == typeSetChunkSize && len(msg.payload) >= 4 {
+			c.r.chunkSize = max(min(binary.BigEndian.Uint32(msg.payload)&0x7fffffff, maxChunkSize), 1)
+			continue
+		}
+		if msg.typeID != typeCommandAMF0 {
+			continue
+		}
+
+		values, err := decodeAMF(msg.payload)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) >= 2 {
+			return values, nil
+		}
+	}
+}
+
+// drain reads what the server sends while publishing, only to notice it hang up.
+func (c *Client) drain() {
+	defer close(c.done)
+	for {
+		if _, err := c.r.readMessage(); err != nil {
+			return
+		}
+	}
+}
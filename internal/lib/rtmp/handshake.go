@@ -0,0 +1,97 @@
+package rtmp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	version       = 3
+	handshakeSize = 1536
+)
+
+// serverHandshake runs the plain handshake. The digest variant of Flash Player 9 is not needed
+// for publishing, OBS and ffmpeg fall back to the plain one when S1 has a zero version field.
+func serverHandshake(rw io.ReadWriter) error {
+	const op = "lib.rtmp.serverHandshake"
+
+	c0c1 := make([]byte, 1+handshakeSize)
+	if _, err := io.ReadFull(rw, c0c1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if c0c1[0] != version {
+		return fmt.Errorf("%s: unsupported version %d", op, c0c1[0])
+	}
+
+	s1, err := handshakeChunk()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// S2 echoes C1 with the time it was read
+	s2 := bytes.Clone(c0c1[1:])
+	binary.BigEndian.PutUint32(s2[4:8], binary.BigEndian.Uint32(s1[:4]))
+
+	out := make([]byte, 0, 1+2*handshakeSize)
+	out = append(out, version)
+	out = append(out, s1...)
+	out = append(out, s2...)
+	if _, err := rw.Write(out); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c2 := make([]byte, handshakeSize)
+	if _, err := io.ReadFull(rw, c2); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func clientHandshake(rw io.ReadWriter) error {
+	const op = "lib.rtmp.clientHandshake"
+
+	c1, err := handshakeChunk()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := rw.Write(append([]byte{version}, c1...)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s0s1 := make([]byte, 1+handshakeSize)
+	if _, err := io.ReadFull(rw, s0s1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if s0s1[0] != version {
+		return fmt.Errorf("%s: unsupported version %d", op, s0s1[0])
+	}
+
+	if _, err := rw.Write(s0s1[1:]); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s2 := make([]byte, handshakeSize)
+	if _, err := io.ReadFull(rw, s2); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !bytes.Equal(s2[8:], c1[8:]) {
+		return fmt.Errorf("%s: S2 does not echo C1", op)
+	}
+
+	return nil
+}
+
+// handshakeChunk is C1 or S1: time, four zero bytes and random filler.
+func handshakeChunk() ([]byte, error) {
+	chunk := make([]byte, handshakeSize)
+	binary.BigEndian.PutUint32(chunk[:4], uint32(time.Now().UnixMilli()))
+	if _, err := rand.Read(chunk[8:]); err != nil {
+		return nil, err
+	}
+
+	return chunk, nil
+}
@@ -0,0 +1,253 @@
+package rtmp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAMF_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	values := []any{
+		"connect",
+		float64(1),
+		Object{
+			"app":    "live",
+			"nested": Object{"ok": true},
+			"list":   []any{float64(1), "two", nil},
+		},
+		nil,
+		false,
+	}
+
+	data, err := encodeAMF(values...)
+	require.NoError(t, err)
+
+	got, err := decodeAMF(data)
+	require.NoError(t, err)
+	require.Equal(t, values, got)
+
+	_, err = decodeAMF(data[:len(data)-4])
+	require.Error(t, err)
+}
+
+func TestChunkReader_ReadMessage(t *testing.T) {
+	t.Parallel()
+
+	// the audio example of the spec: a full header, a delta, and a type 3 header repeating it
+	var stream bytes.Buffer
+	stream.Write([]byte{0x04, 0, 0x03, 0xe8, 0, 0, 2, typeAudio, 1, 0, 0, 0, 'a', 'b'})
+	stream.Write([]byte{0x84, 0, 0, 20, 'c', 'd'})
+	stream.Write([]byte{0xc4, 'e', 'f'})
+	// a message of 3 chunks with an extended timestamp repeated in each continuation
+	var w bytes.Buffer
+	writer := newChunkWriter(&w)
+	writer.chunkSize = 2
+	require.NoError(t, writer.writeMessage(csidVideo, message{
+		typeID:    typeVideo,
+		streamID:  1,
+		timestamp: 0x01000000,
+		payload:   []byte("12345"),
+	}))
+	stream.Write(w.Bytes())
+
+	r := newChunkReader(&stream)
+	r.chunkSize = 2
+
+	want := []message{
+		{typeID: typeAudio, streamID: 1, timestamp: 1000, payload: []byte("ab")},
+		{typeID: typeAudio, streamID: 1, timestamp: 1020, payload: []byte("cd")},
+		{typeID: typeAudio, streamID: 1, timestamp: 1040, payload: []byte("ef")},
+		{typeID: typeVideo, streamID: 1, timestamp: 0x01000000, payload: []byte("12345")},
+	}
+	for _, msg := range want {
+		got, err := r.readMessage()
+		require.NoError(t, err)
+		require.Equal(t, msg, got)
+	}
+}
+
+type testHandler struct {
+	mu       sync.Mutex
+	err      error
+	app      string
+	name     string
+	tags     []flv.Tag
+	closed   chan struct{}
+	received chan struct{}
+}
+
+func (h *testHandler) Publish(_ context.Context, _ *Conn, app, name string) (Stream, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.err != nil {
+		return nil, h.err
+	}
+	h.app, h.name = app, name
+	return h, nil
+}
+
+func (h *testHandler) WriteTag(tag flv.Tag) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tags = append(h.tags, tag)
+	h.received <- struct{}{}
+	return nil
+}
+
+func (h *testHandler) Close() error {
+	close(h.closed)
+	return nil
+}
+
+func TestServer_Publish(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{
+			name: "good case",
+		},
+		{
+			name:     "name in use case",
+			err:      ErrBadName,
+			wantCode: "NetStream.Publish.BadName",
+		},
+		{
+			name:     "denied case",
+			err:      ErrDenied,
+			wantCode: "NetStream.Publish.Denied",
+		},
+		{
+			name:     "handler error case",
+			err:      errors.New("some error"),
+			wantCode: "NetStream.Publish.Failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := &testHandler{
+				err:      tt.err,
+				closed:   make(chan struct{}),
+				received: make(chan struct{}, 3),
+			}
+			addr := startServer(t, handler)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := Dial(ctx, "rtmp://"+addr+"/live")
+			require.NoError(t, err)
+			defer func() {
+				_ = client.Close()
+			}()
+
+			err = client.Publish(ctx, "live_key")
+			if tt.wantCode != "" {
+				var status *StatusError
+				require.ErrorAs(t, err, &status)
+				require.Equal(t, tt.wantCode, status.Code)
+				return
+			}
+			require.NoError(t, err)
+
+			// bigger than both chunk sizes, so it is split
+			frame := bytes.Repeat([]byte{0x17, 1, 0, 0, 0}, 2000)
+			tags := []flv.Tag{
+				{Type: flv.TagScript, Timestamp: 0, Data: []byte{amfNull}},
+				{Type: flv.TagVideo, Timestamp: 0, Data: frame},
+				{Type: flv.TagAudio, Timestamp: 23, Data: []byte{0xaf, 1, 'x'}},
+			}
+			for _, tag := range tags {
+				require.NoError(t, client.WriteTag(tag))
+			}
+			for range tags {
+				select {
+				case <-handler.received:
+				case <-ctx.Done():
+					t.Fatal("tags did not arrive")
+				}
+			}
+
+			handler.mu.Lock()
+			require.Equal(t, "live", handler.app)
+			require.Equal(t, "live_key", handler.name)
+			require.Equal(t, tags, handler.tags)
+			handler.mu.Unlock()
+
+			require.NoError(t, client.Close())
+			select {
+			case <-handler.closed:
+			case <-ctx.Done():
+				t.Fatal("stream was not closed")
+			}
+		})
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	handler := &testHandler{
+		closed:   make(chan struct{}),
+		received: make(chan struct{}, 1),
+	}
+	server := NewServer(handler, 4096, 5*time.Second)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(context.Background(), l)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, "rtmp://"+l.Addr().String()+"/live")
+	require.NoError(t, err)
+	require.NoError(t, client.Publish(ctx, "live_key"))
+
+	require.NoError(t, server.Shutdown(ctx))
+	require.ErrorIs(t, <-served, ErrServerClosed)
+
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+		t.Fatal("client was not dropped")
+	}
+	select {
+	case <-handler.closed:
+	default:
+		t.Fatal("stream was not closed")
+	}
+}
+
+func startServer(t *testing.T, handler Handler) string {
+	t.Helper()
+
+	server := NewServer(handler, 4096, 5*time.Second)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(context.Background(), l)
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	return l.Addr().String()
+}
@@ -0,0 +1,442 @@
+// Package rtmp is the part of RTMP that OBS and ffmpeg need to publish H.264 and AAC: the plain
+// handshake, the chunk stream and the connect, createStream and publish commands. Playback is
+// not served, viewers get the media from elsewhere.
+package rtmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+// windowAckSize is how many bytes a peer may send before it has to hear an acknowledgement.
+const windowAckSize = 2500000
+
+var (
+	ErrServerClosed = errors.New("rtmp: server closed")
+	// ErrBadName refuses a publish with NetStream.Publish.BadName, the name is already in use
+	ErrBadName = errors.New("rtmp: stream name in use")
+	// ErrDenied refuses a publish with NetStream.Publish.Denied
+	ErrDenied = errors.New("rtmp: publish denied")
+
+	errUnsupported = errors.New("rtmp: not supported")
+)
+
+// Handler decides who may publish and takes what they send.
+type Handler interface {
+	// Publish is called for the publish command. The returned Stream gets the tags until it is
+	// closed, which happens once the broadcaster unpublishes or the connection drops.
+	// Errors wrapping ErrBadName or ErrDenied tell the broadcaster why, others are reported
+	// as NetStream.Publish.Failed.
+	Publish(ctx context.Context, conn *Conn, app, name string) (Stream, error)
+}
+
+type Stream interface {
+	WriteTag(tag flv.Tag) error
+	Close() error
+}
+
+type Server struct {
+	handler   Handler
+	chunkSize uint32
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	closed   bool
+	listener net.Listener
+	conns    map[*Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer makes a server that drops connections silent for longer than timeout and sends
+// chunks of chunkSize bytes.
+func NewServer(handler Handler, chunkSize int, timeout time.Duration) *Server {
+	return &Server{
+		handler:   handler,
+		chunkSize: uint32(min(max(chunkSize, defaultChunkSize), maxChunkSize)),
+		timeout:   timeout,
+		conns:     make(map[*Conn]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	const op = "lib.rtmp.Server.ListenAndServe"
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Serve accepts connections on l until Shutdown is called, then it returns ErrServerClosed.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	const op = "lib.rtmp.Server.Serve"
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		conn := newConn(s, nc)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = nc.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+			}()
+			conn.serve(ctx)
+		}()
+	}
+}
+
+// Shutdown stops accepting, drops every connection and waits for their streams to close.
+func (s *Server) Shutdown(ctx context.Context) error {
+	const op = "lib.rtmp.Server.Shutdown"
+
+	s.mu.Lock()
+	s.closed = true
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", op, ctx.Err())
+	}
+}
+
+// Conn is a connection of a broadcaster.
+type Conn struct {
+	server *Server
+	nc     net.Conn
+	count  *countingReader
+	r      *chunkReader
+
+	// mu guards w
+	mu sync.Mutex
+	w  *chunkWriter
+
+	app        string
+	streamID   uint32
+	stream     Stream
+	peerWindow uint32
+	acked      uint64
+}
+
+func newConn(server *Server, nc net.Conn) *Conn {
+	count := &countingReader{r: nc}
+	return &Conn{
+		server: server,
+		nc:     nc,
+		count:  count,
+		r:      newChunkReader(count),
+		w:      newChunkWriter(nc),
+	}
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.nc.RemoteAddr()
+}
+
+// Close drops the connection, the stream is closed once the connection noticed.
+func (c *Conn) Close() error {
+	return c.nc.Close()
+}
+
+func (c *Conn) serve(ctx context.Context) {
+	const op = "lib.rtmp.Conn.serve"
+	log := logger.FromCtx(ctx).With(slog.String("op", op), slog.String("remote_addr", c.nc.RemoteAddr().String()))
+
+	defer func() {
+		_ = c.nc.Close()
+	}()
+	defer c.unpublish(log)
+
+	_ = c.nc.SetDeadline(time.Now().Add(c.server.timeout))
+	if err := serverHandshake(c.nc); err != nil {
+		log.Debug("handshake failed", logger.Err(err))
+		return
+	}
+	_ = c.nc.SetWriteDeadline(time.Time{})
+
+	for {
+		_ = c.nc.SetReadDeadline(time.Now().Add(c.server.timeout))
+		msg, err := c.r.readMessage()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Info("connection dropped", logger.Err(err))
+			}
+			return
+		}
+
+		if err := c.acknowledge(); err != nil {
+			log.Info("failed to acknowledge", logger.Err(err))
+			return
+		}
+
+		if err := c.handle(ctx, msg); err != nil {
+			log.Info("closing connection", logger.Err(err))
+			return
+		}
+	}
+}
+
+func (c *Conn) unpublish(log *slog.Logger) {
+	if c.stream == nil {
+		return
+	}
+	if err := c.stream.Close(); err != nil {
+		log.Error("failed to close stream", logger.Err(err))
+	}
+	c.stream = nil
+}
+
+// acknowledge tells the peer how much was read once it sent a window worth of bytes.
+func (c *Conn) acknowledge() error {
+	if c.peerWindow == 0 || c.count.n-c.acked < uint64(c.peerWindow) {
+		return nil
+	}
+	c.acked = c.count.n
+
+	return c.write(csidControl, controlMessage(typeAck, uint32(c.count.n)))
+}
+
+func (c *Conn) handle(ctx context.Context, msg message) error {
+	switch msg.typeID {
+	case typeSetChunkSize:
+		if len(msg.payload) < 4 {
+			return errProtocol
+		}
+		size := binary.BigEndian.Uint32(msg.payload) & 0x7fffffff
+		if size == 0 {
+			return fmt.Errorf("%w: zero chunk size", errProtocol)
+		}
+		c.r.chunkSize = min(size, maxChunkSize)
+	case typeAbort:
+		if len(msg.payload) < 4 {
+			return errProtocol
+		}
+		c.r.abort(binary.BigEndian.Uint32(msg.payload))
+	case typeWindowAckSize:
+		if len(msg.payload) < 4 {
+			return errProtocol
+		}
+		c.peerWindow = binary.BigEndian.Uint32(msg.payload)
+	case typeCommandAMF0:
+		return c.command(ctx, msg)
+	case typeCommandAMF3:
+		// AMF3 commands are AMF0 behind a format byte
+		if len(msg.payload) < 1 {
+			return errProtocol
+		}
+		msg.payload = msg.payload[1:]
+		return c.command(ctx, msg)
+	case typeAudio, typeVideo, typeDataAMF0:
+		if c.stream == nil || msg.streamID != c.streamID {
+			return nil
+		}
+		return c.stream.WriteTag(flv.Tag{
+			Type:      msg.typeID,
+			Timestamp: msg.timestamp,
+			Data:      msg.payload,
+		})
+	}
+
+	return nil
+}
+
+func (c *Conn) command(ctx context.Context, msg message) error {
+	values, err := decodeAMF(msg.payload)
+	if err != nil {
+		return err
+	}
+	if len(values) < 2 {
+		return fmt.Errorf("%w: short command", errProtocol)
+	}
+	name, _ := values[0].(string)
+	txn, _ := values[1].(float64)
+
+	switch name {
+	case "connect":
+		return c.connect(txn, values)
+	case "createStream":
+		c.streamID = 1
+		return c.writeCommand(csidCommand, 0, "_result", txn, nil, float64(c.streamID))
+	case "FCPublish":
+		return c.writeCommand(csidCommand, 0, "onFCPublish", 0, nil, Object{
+			"code":        "NetStream.Publish.Start",
+			"description": "FCPublish",
+		})
+	case "publish":
+		return c.publish(ctx, msg.streamID, values)
+	case "FCUnpublish", "deleteStream", "closeStream":
+		c.unpublish(logger.FromCtx(ctx))
+	case "play":
+		_ = c.writeStatus(msg.streamID, "error", "NetStream.Play.Failed", "Playback is not supported.")
+		return fmt.Errorf("%w: play", errUnsupported)
+	}
+
+	return nil
+}
+
+func (c *Conn) connect(txn float64, values []any) error {
+	if len(values) < 3 {
+		return fmt.Errorf("%w: connect without command object", errProtocol)
+	}
+	params, _ := values[2].(Object)
+	app, _ := params["app"].(string)
+	c.app = strings.Trim(app, "/")
+
+	if err := c.write(csidControl, controlMessage(typeWindowAckSize, windowAckSize)); err != nil {
+		return err
+	}
+	bandwidth := controlMessage(typeSetPeerBandwidth, windowAckSize)
+	// dynamic limit
+	bandwidth.payload = append(bandwidth.payload, 2)
+	if err := c.write(csidControl, bandwidth); err != nil {
+		return err
+	}
+	if err := c.write(csidControl, controlMessage(typeSetChunkSize, c.server.chunkSize)); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.w.chunkSize = c.server.chunkSize
+	c.mu.Unlock()
+
+	return c.writeCommand(csidCommand, 0, "_result", txn, Object{
+		"fmsVer":       "FMS/3,0,1,123",
+		"capabilities": 31,
+	}, Object{
+		"level":          "status",
+		"code":           "NetConnection.Connect.Success",
+		"description":    "Connection succeeded.",
+		"objectEncoding": 0,
+	})
+}
+
+func (c *Conn) publish(ctx context.Context, streamID uint32, values []any) error {
+	if c.stream != nil {
+		_ = c.writeStatus(streamID, "error", "NetStream.Publish.BadName", "Already publishing.")
+		return fmt.Errorf("%w: second publish", errProtocol)
+	}
+	if len(values) < 4 {
+		return fmt.Errorf("%w: publish without a name", errProtocol)
+	}
+	name, _ := values[3].(string)
+
+	stream, err := c.server.handler.Publish(ctx, c, c.app, name)
+	if err != nil {
+		code, description := "NetStream.Publish.Failed", "Publishing failed."
+		switch {
+		case errors.Is(err, ErrBadName):
+			code, description = "NetStream.Publish.BadName", "The stream is already being published."
+		case errors.Is(err, ErrDenied):
+			code, description = "NetStream.Publish.Denied", "Publishing is not allowed."
+		}
+		_ = c.writeStatus(streamID, "error", code, description)
+		return err
+	}
+	c.stream = stream
+	c.streamID = streamID
+
+	// user control event 0 is stream begin
+	begin := message{typeID: typeUserControl, payload: binary.BigEndian.AppendUint32([]byte{0, 0}, streamID)}
+	if err := c.write(csidControl, begin); err != nil {
+		return err
+	}
+
+	// the name is the stream key, it must not be echoed back
+	return c.writeStatus(streamID, "status", "NetStream.Publish.Start", "Publishing started.")
+}
+
+func (c *Conn) writeStatus(streamID uint32, level, code, description string) error {
+	return c.writeCommand(csidData, streamID, "onStatus", 0, nil, Object{
+		"level":       level,
+		"code":        code,
+		"description": description,
+	})
+}
+
+func (c *Conn) writeCommand(csid, streamID uint32, values ...any) error {
+	payload, err := encodeAMF(values...)
+	if err != nil {
+		return err
+	}
+
+	return c.write(csid, message{
+		typeID:   typeCommandAMF0,
+		streamID: streamID,
+		payload:  payload,
+	})
+}
+
+func (c *Conn) write(csid uint32, msg message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.nc.SetWriteDeadline(time.Now().Add(c.server.timeout))
+	return c.w.writeMessage(csid, msg)
+}
+
+// countingReader counts the bytes read for acknowledgements.
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += uint64(n)
+	return n, err
+}
@@ -0,0 +1,75 @@
+package sms
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+// LogSender writes messages to the log instead of sending them. It is meant for local development.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) SendSMS(ctx context.Context, to, text string) error {
+	const op = "lib.sms.LogSender.SendSMS"
+
+	logger.FromCtx(ctx).Info("sms", slog.String("op", op), slog.String("to", to), slog.String("text", text))
+
+	return nil
+}
+
+type Message struct {
+	To   string
+	Text string
+}
+
+// Memory keeps sent messages so tests can read the codes back.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) SendSMS(ctx context.Context, to, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{
+		To:   to,
+		Text: text,
+	})
+
+	return nil
+}
+
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+
+	return messages
+}
+
+// Last returns the latest message sent to the number.
+func (m *Memory) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+
+	return Message{}, false
+}
@@ -0,0 +1,24 @@
+package sms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Last(t *testing.T) {
+	m := NewMemory()
+
+	_, ok := m.Last("+15550000001")
+	require.False(t, ok)
+
+	require.NoError(t, m.SendSMS(context.Background(), "+15550000001", "first"))
+	require.NoError(t, m.SendSMS(context.Background(), "+15550000002", "other"))
+	require.NoError(t, m.SendSMS(context.Background(), "+15550000001", "second"))
+
+	got, ok := m.Last("+15550000001")
+	require.True(t, ok)
+	require.Equal(t, "second", got.Text)
+	require.Len(t, m.Messages(), 3)
+}
@@ -0,0 +1,120 @@
+package streamkey
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks stream keys, so a leaked one is easy to recognize, e.g. by secret scanners.
+const Prefix = "live_"
+
+// keyBytes of randomness make a key, 256 bits can't be guessed
+const keyBytes = 32
+
+var ErrMalformed = errors.New("malformed stream key")
+
+// Keyring keeps stream keys twice: sealed with AES-GCM, so the owner can see the key
+// again, and as an HMAC, so the ingest can find the channel of a key without decrypting
+// every key there is. Both keys are derived from one secret.
+type Keyring struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+func NewKeyring(secret string) (*Keyring, error) {
+	const op = "lib.streamkey.NewKeyring"
+
+	if secret == "" {
+		return nil, fmt.Errorf("%s: empty secret", op)
+	}
+
+	block, err := aes.NewCipher(derive(secret, "seal"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Keyring{
+		aead:    aead,
+		hashKey: derive(secret, "hash"),
+	}, nil
+}
+
+// Generate returns a new random stream key.
+func (k *Keyring) Generate() (string, error) {
+	const op = "lib.streamkey.Keyring.Generate"
+
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return Prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the lookup hash of the key.
+func (k *Keyring) Hash(key string) string {
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write([]byte(key))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Seal encrypts the key. The owner id goes in as additional data, a sealed key
+// copied to another channel can't be opened there.
+func (k *Keyring) Seal(key string, owner []byte) ([]byte, error) {
+	const op = "lib.streamkey.Keyring.Seal"
+
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(key)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return k.aead.Seal(nonce, nonce, []byte(key), owner), nil
+}
+
+func (k *Keyring) Open(sealed []byte, owner []byte) (string, error) {
+	const op = "lib.streamkey.Keyring.Open"
+
+	if len(sealed) < k.aead.NonceSize() {
+		return "", fmt.Errorf("%s: %w", op, ErrMalformed)
+	}
+
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	key, err := k.aead.Open(nil, nonce, ciphertext, owner)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return string(key), nil
+}
+
+// Valid reports whether s looks like a stream key at all, so obvious garbage
+// is turned away before a database lookup.
+func Valid(s string) bool {
+	encoded, ok := strings.CutPrefix(s, Prefix)
+	if !ok {
+		return false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	return err == nil && len(b) == keyBytes
+}
+
+func derive(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("stream-key:" + purpose))
+
+	return mac.Sum(nil)
+}
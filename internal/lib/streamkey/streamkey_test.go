@@ -0,0 +1,69 @@
+package streamkey
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring, err := NewKeyring("secret")
+	require.NoError(t, err)
+
+	key, err := keyring.Generate()
+	require.NoError(t, err)
+	require.True(t, Valid(key))
+
+	other, err := keyring.Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+	require.NotEqual(t, keyring.Hash(key), keyring.Hash(other))
+	require.Equal(t, keyring.Hash(key), keyring.Hash(key))
+
+	owner := uuid.New()
+	sealed, err := keyring.Seal(key, owner[:])
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), key)
+
+	opened, err := keyring.Open(sealed, owner[:])
+	require.NoError(t, err)
+	require.Equal(t, key, opened)
+
+	stranger := uuid.New()
+	_, err = keyring.Open(sealed, stranger[:])
+	require.Error(t, err)
+
+	_, err = keyring.Open(sealed[:4], owner[:])
+	require.ErrorIs(t, err, ErrMalformed)
+
+	rotated, err := NewKeyring("other secret")
+	require.NoError(t, err)
+	_, err = rotated.Open(sealed, owner[:])
+	require.Error(t, err)
+	require.NotEqual(t, keyring.Hash(key), rotated.Hash(key))
+
+	_, err = NewKeyring("")
+	require.Error(t, err)
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "good case", key: "live_" + "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", want: true},
+		{name: "no prefix case", key: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+		{name: "short case", key: "live_AAAA"},
+		{name: "not base64 case", key: "live_" + "!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!"},
+		{name: "empty case", key: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Valid(tt.key))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package unsubscribe
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner("secret")
+
+	token := s.Token("user@test.com", consts.EmailCategoryGoLive)
+	email, category, err := s.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, "user@test.com", email)
+	require.Equal(t, consts.EmailCategoryGoLive, category)
+
+	payload, _, _ := strings.Cut(token, ".")
+	forged := NewSigner("other").Token("user@test.com", consts.EmailCategoryGoLive)
+	_, forgedSignature, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty case", token: ""},
+		{name: "no signature case", token: payload},
+		{name: "other secret case", token: forged},
+		{name: "swapped signature case", token: payload + "." + forgedSignature},
+		{name: "other payload case", token: s.Token("other@test.com", consts.EmailCategoryDigest)[:10] + token[10:]},
+		{name: "bad encoding case", token: "!!!." + forgedSignature},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := s.Parse(tt.token)
+			require.ErrorIs(t, err, errs.ErrUnsubscribeToken)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package whip
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/sdp/v3"
+	"github.com/pion/webrtc/v4"
+)
+
+// StatusError is a publish the server refused.
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("whip: server answered %d: %s", e.Status, e.Body)
+}
+
+// Client publishes to a WHIP endpoint the way browsers do: it posts the offer right away and
+// trickles its candidates afterwards.
+type Client struct {
+	pc       *webrtc.PeerConnection
+	location string
+	token    string
+
+	connected chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Publish sends tracks to endpoint, e.g. http://localhost:8088/whip. A refusal is a *StatusError.
+func Publish(ctx context.Context, endpoint, token string, tracks ...webrtc.TrackLocal) (*Client, error) {
+	const op = "lib.whip.Publish"
+
+	m := &webrtc.MediaEngine{}
+	if err := m.RegisterDefaultCodecs(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	settings := webrtc.SettingEngine{}
+	settings.SetIncludeLoopbackCandidate(true)
+	// pion does not hang up on a DTLS close, a server that ended the session is noticed once
+	// it stops answering connectivity checks
+	settings.SetICETimeouts(time.Second, 3*time.Second, 500*time.Millisecond)
+	api := webrtc.NewAPI(webrtc.WithMediaEngine(m), webrtc.WithSettingEngine(settings))
+
+	pc, err := api.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	c := &Client{
+		pc:        pc,
+		token:     token,
+		connected: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	var connectOnce, doneOnce sync.Once
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateConnected:
+			connectOnce.Do(func() {
+				close(c.connected)
+			})
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed:
+			doneOnce.Do(func() {
+				close(c.done)
+			})
+		}
+	})
+
+	for _, track := range tracks {
+		_, err := pc.AddTransceiverFromTrack(track, webrtc.RTPTransceiverInit{
+			Direction: webrtc.RTPTransceiverDirectionSendonly,
+		})
+		if err != nil {
+			_ = pc.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// candidates gathered before the session URL is known wait here
+	candidates := make(chan *webrtc.ICECandidate, 64)
+	pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+		select {
+		case candidates <- candidate:
+		default:
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := pc.SetLocalDescription(offer); err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	answer, err := c.post(ctx, endpoint, offer.SDP)
+	if err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer}); err != nil {
+		_ = c.Close(ctx)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	go c.trickle(context.WithoutCancel(ctx), offer.SDP, candidates)
+
+	return c, nil
+}
+
+func (c *Client) post(ctx context.Context, endpoint, offer string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(offer))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentTypeSDP)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", &StatusError{Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	location, err := base.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", err
+	}
+	c.location = location.String()
+
+	return string(body), nil
+}
+
+// trickle sends the candidates to the session. It is best effort, the server learns the
+// addresses of the client from the connectivity checks as well.
+func (c *Client) trickle(ctx context.Context, offer string, candidates <-chan *webrtc.ICECandidate) {
+	var desc sdp.SessionDescription
+	if err := desc.UnmarshalString(offer); err != nil || len(desc.MediaDescriptions) == 0 {
+		return
+	}
+	media := desc.MediaDescriptions[0]
+	pwd, _ := media.Attribute("ice-pwd")
+	mid, _ := media.Attribute("mid")
+	ufrag := iceUfrag(&desc)
+
+	for {
+		select {
+		case candidate := <-candidates:
+			_, _ = c.Trickle(ctx, sdpFrag(ufrag, pwd, mid, candidate))
+			if candidate == nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Trickle patches the session with an ICE fragment and returns the status of the answer.
+func (c *Client) Trickle(ctx context.Context, frag string) (int, error) {
+	const op = "lib.whip.Client.Trickle"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, c.location, strings.NewReader(frag))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", contentTypeSDPFrag)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// Location is the URL of the session.
+func (c *Client) Location() string {
+	return c.location
+}
+
+// Connected is closed once media flows.
+func (c *Client) Connected() <-chan struct{} {
+	return c.connected
+}
+
+// Done is closed once the connection is lost or closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close ends the session with DELETE and hangs up.
+func (c *Client) Close(ctx context.Context) error {
+	const op = "lib.whip.Client.Close"
+
+	var err error
+	c.closeOnce.Do(func() {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodDelete, c.location, nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+			var resp *http.Response
+			resp, err = http.DefaultClient.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}
+
+		if closeErr := c.pc.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package whip
+
+import (
+	"strings"
+
+	"github.com/pion/sdp/v3"
+	"github.com/pion/webrtc/v4"
+)
+
+// checkOffer makes sure the offer has media the server takes and returns its ICE username
+// fragment, trickled candidates have to come with the same one.
+func checkOffer(offer string) (string, error) {
+	var desc sdp.SessionDescription
+	if err := desc.UnmarshalString(offer); err != nil {
+		return "", err
+	}
+
+	hasMedia := false
+	for _, m := range desc.MediaDescriptions {
+		for _, attr := range m.Attributes {
+			if attr.Key != "rtpmap" {
+				continue
+			}
+			codec := strings.ToLower(attr.Value)
+			if strings.Contains(codec, " h264/90000") || strings.Contains(codec, " opus/48000") {
+				hasMedia = true
+			}
+		}
+	}
+	if !hasMedia {
+		return "", errNoMedia
+	}
+
+	return iceUfrag(&desc), nil
+}
+
+func iceUfrag(desc *sdp.SessionDescription) string {
+	if ufrag, ok := desc.Attribute("ice-ufrag"); ok {
+		return ufrag
+	}
+	for _, m := range desc.MediaDescriptions {
+		if ufrag, ok := m.Attribute("ice-ufrag"); ok {
+			return ufrag
+		}
+	}
+
+	return ""
+}
+
+// parseSDPFrag reads a trickle ICE fragment (RFC 8840): the ICE credentials, then the candidates
+// of every media section after its mid.
+func parseSDPFrag(frag string) (string, []webrtc.ICECandidateInit) {
+	var (
+		ufrag      string
+		mid        *string
+		candidates []webrtc.ICECandidateInit
+	)
+	for line := range strings.Lines(frag) {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "a=ice-ufrag:"):
+			ufrag = strings.TrimPrefix(line, "a=ice-ufrag:")
+		case strings.HasPrefix(line, "a=mid:"):
+			value := strings.TrimPrefix(line, "a=mid:")
+			mid = &value
+		case strings.HasPrefix(line, "a=candidate:"):
+			candidates = append(candidates, webrtc.ICECandidateInit{
+				Candidate: strings.TrimPrefix(line, "a="),
+				SDPMid:    mid,
+			})
+		}
+	}
+
+	return ufrag, candidates
+}
+
+// sdpFrag is the fragment a client trickles candidate in, a nil candidate ends the gathering.
+func sdpFrag(ufrag, pwd, mid string, candidate *webrtc.ICECandidate) string {
+	var b strings.Builder
+	b.WriteString("a=ice-ufrag:" + ufrag + "\r\n")
+	b.WriteString("a=ice-pwd:" + pwd + "\r\n")
+	b.WriteString("m=audio 9 UDP/TLS/RTP/SAVPF 0\r\n")
+	b.WriteString("a=mid:" + mid + "\r\n")
+	if candidate != nil {
+		b.WriteString("a=" + candidate.ToJSON().Candidate + "\r\n")
+	} else {
+		b.WriteString("a=end-of-candidates\r\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,492 @@
+// Package whip is WebRTC-HTTP ingestion (RFC 9725), how browsers broadcast without OBS. A POST
+// with an SDP offer starts a session, PATCH trickles ICE candidates and DELETE ends it. The
+// received H.264 and Opus come out as media.Packets, like RTMP does.
+package whip
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/pion/ice/v4"
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/intervalpli"
+	"github.com/pion/webrtc/v4"
+)
+
+// Path is where the server takes offers, sessions live under Path/<id>.
+const Path = "/whip"
+
+const (
+	contentTypeSDP     = "application/sdp"
+	contentTypeSDPFrag = "application/trickle-ice-sdpfrag"
+)
+
+// maxBodySize caps offers and candidate fragments, offers are a few kilobytes
+const maxBodySize = 64 << 10
+
+var (
+	// ErrUnauthorized refuses a publish with 401, the token is not a valid stream key
+	ErrUnauthorized = errors.New("whip: unauthorized")
+	// ErrForbidden refuses a publish with 403, the key is valid but its owner may not broadcast
+	ErrForbidden = errors.New("whip: forbidden")
+	// ErrConflict refuses a publish with 409, the channel is live already
+	ErrConflict = errors.New("whip: already publishing")
+
+	errNoMedia = errors.New("whip: offer has neither H.264 nor Opus")
+)
+
+// Handler decides who may publish and takes what they send.
+type Handler interface {
+	// Publish is called for every offer with the bearer token of the request. The returned
+	// Stream gets the packets until it is closed, which happens on DELETE or once the
+	// connection is lost. Errors wrapping ErrUnauthorized, ErrForbidden or ErrConflict are
+	// answered with their status, others with 500.
+	Publish(ctx context.Context, session *Session, token string) (Stream, error)
+}
+
+type Stream interface {
+	WritePacket(packet media.Packet) error
+	Close() error
+}
+
+type Server struct {
+	handler Handler
+	api     *webrtc.API
+	udpMux  ice.UDPMux
+	timeout time.Duration
+	mux     *http.ServeMux
+
+	mu       sync.Mutex
+	closed   bool
+	sessions map[string]*Session
+}
+
+// NewServer makes a server that takes ICE on udpPort, or on a random port per session when it is
+// 0, and drops sessions that do not connect within timeout. publicIPs replace the addresses in
+// the candidates of a server behind NAT.
+func NewServer(handler Handler, udpPort int, publicIPs []string, timeout time.Duration) (*Server, error) {
+	const op = "lib.whip.NewServer"
+
+	settings := webrtc.SettingEngine{}
+	// lets broadcasters on the same machine in, handy in development
+	settings.SetIncludeLoopbackCandidate(true)
+	settings.SetICETimeouts(5*time.Second, timeout, 2*time.Second)
+	if len(publicIPs) > 0 {
+		settings.SetNAT1To1IPs(publicIPs, webrtc.ICECandidateTypeHost)
+	}
+
+	var udpMux ice.UDPMux
+	if udpPort != 0 {
+		mux, err := ice.NewMultiUDPMuxFromPort(udpPort)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		settings.SetICEUDPMux(mux)
+		udpMux = mux
+	}
+
+	api, err := newAPI(settings)
+	if err != nil {
+		if udpMux != nil {
+			_ = udpMux.Close()
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s := &Server{
+		handler:  handler,
+		api:      api,
+		udpMux:   udpMux,
+		timeout:  timeout,
+		mux:      http.NewServeMux(),
+		sessions: make(map[string]*Session),
+	}
+	s.mux.HandleFunc("POST "+Path, s.offer)
+	s.mux.HandleFunc("PATCH "+Path+"/{id}", s.trickle)
+	s.mux.HandleFunc("DELETE "+Path+"/{id}", s.delete)
+
+	return s, nil
+}
+
+// newAPI accepts the codecs the media pipeline knows, H.264 and Opus, and asks for a keyframe
+// every few seconds, so recordings can be cut and the stream recovers from loss.
+func newAPI(settings webrtc.SettingEngine) (*webrtc.API, error) {
+	m := &webrtc.MediaEngine{}
+
+	feedback := []webrtc.RTCPFeedback{
+		{Type: webrtc.TypeRTCPFBNACK},
+		{Type: webrtc.TypeRTCPFBNACK, Parameter: "pli"},
+		{Type: webrtc.TypeRTCPFBCCM, Parameter: "fir"},
+	}
+	// browsers offer several profiles, all of them fit into FLV and MP4
+	profiles := []string{"42e01f", "42001f", "4d001f", "640c1f", "64001f"}
+	for i, profile := range profiles {
+		err := m.RegisterCodec(webrtc.RTPCodecParameters{
+			RTPCodecCapability: webrtc.RTPCodecCapability{
+				MimeType:     webrtc.MimeTypeH264,
+				ClockRate:    90000,
+				SDPFmtpLine:  "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=" + profile,
+				RTCPFeedback: feedback,
+			},
+			PayloadType: webrtc.PayloadType(102 + 2*i),
+		}, webrtc.RTPCodecTypeVideo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := m.RegisterCodec(webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{
+			MimeType:    webrtc.MimeTypeOpus,
+			ClockRate:   48000,
+			Channels:    2,
+			SDPFmtpLine: "minptime=10;useinbandfec=1",
+		},
+		PayloadType: 111,
+	}, webrtc.RTPCodecTypeAudio)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &interceptor.Registry{}
+	if err := webrtc.RegisterDefaultInterceptors(m, registry); err != nil {
+		return nil, err
+	}
+	pli, err := intervalpli.NewReceiverInterceptor()
+	if err != nil {
+		return nil, err
+	}
+	registry.Add(pli)
+
+	return webrtc.NewAPI(
+		webrtc.WithMediaEngine(m),
+		webrtc.WithInterceptorRegistry(registry),
+		webrtc.WithSettingEngine(settings),
+	), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Shutdown ends every session, new offers are refused from then on.
+func (s *Server) Shutdown(ctx context.Context) error {
+	const op = "lib.whip.Server.Shutdown"
+
+	s.mu.Lock()
+	s.closed = true
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		_ = session.Close()
+	}
+	if s.udpMux != nil {
+		if err := s.udpMux.Close(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *Server) offer(w http.ResponseWriter, r *http.Request) {
+	const op = "lib.whip.Server.offer"
+	log := logger.FromCtx(r.Context()).With(slog.String("op", op))
+
+	if !hasContentType(r, contentTypeSDP) {
+		http.Error(w, "offer must be "+contentTypeSDP, http.StatusUnsupportedMediaType)
+		return
+	}
+	offer, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read offer", http.StatusBadRequest)
+		return
+	}
+	ufrag, err := checkOffer(string(offer))
+	if err != nil {
+		log.Info("invalid offer", logger.Err(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pc, err := s.api.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		log.Error("failed to create peer connection", logger.Err(err))
+		http.Error(w, "failed to negotiate", http.StatusInternalServerError)
+		return
+	}
+	session := &Session{
+		server:    s,
+		id:        newSessionId(),
+		token:     bearerToken(r),
+		ufrag:     ufrag,
+		startedAt: time.Now(),
+		log:       log,
+		pc:        pc,
+	}
+
+	// the session outlives the request
+	stream, err := s.handler.Publish(context.WithoutCancel(r.Context()), session, session.token)
+	if err != nil {
+		_ = pc.Close()
+	}
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		log.Info("publish refused", logger.Err(err))
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrForbidden):
+		log.Info("publish refused", logger.Err(err))
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	case errors.Is(err, ErrConflict):
+		log.Info("publish refused", logger.Err(err))
+		http.Error(w, "already publishing", http.StatusConflict)
+		return
+	case err != nil:
+		log.Error("failed to publish", logger.Err(err))
+		http.Error(w, "failed to publish", http.StatusInternalServerError)
+		return
+	}
+	session.stream = stream
+
+	if !s.add(session) {
+		_ = session.Close()
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	answer, err := session.negotiate(string(offer))
+	if err != nil {
+		log.Error("failed to negotiate", logger.Err(err))
+		_ = session.Close()
+		http.Error(w, "failed to negotiate", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeSDP)
+	w.Header().Set("Location", Path+"/"+session.id)
+	w.WriteHeader(http.StatusCreated)
+	_, _ = io.WriteString(w, answer)
+}
+
+func (s *Server) trickle(w http.ResponseWriter, r *http.Request) {
+	const op = "lib.whip.Server.trickle"
+	log := logger.FromCtx(r.Context()).With(slog.String("op", op))
+
+	session, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	if !hasContentType(r, contentTypeSDPFrag) {
+		http.Error(w, "candidates must be "+contentTypeSDPFrag, http.StatusUnsupportedMediaType)
+		return
+	}
+	frag, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read candidates", http.StatusBadRequest)
+		return
+	}
+
+	ufrag, candidates := parseSDPFrag(string(frag))
+	if ufrag != "" && ufrag != session.ufrag {
+		http.Error(w, "ice restarts are not supported", http.StatusUnprocessableEntity)
+		return
+	}
+	for _, candidate := range candidates {
+		if err := session.pc.AddICECandidate(candidate); err != nil {
+			log.Info("invalid candidate", logger.Err(err))
+			http.Error(w, "invalid candidate", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	session, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	_ = session.Close()
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorize finds the session of the request, only the broadcaster who started it may change it.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) (*Session, bool) {
+	s.mu.Lock()
+	session, ok := s.sessions[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(session.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (s *Server) add(session *Session) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.sessions[session.id] = session
+	return true
+}
+
+func (s *Server) remove(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, session.id)
+}
+
+// Session is a broadcast over WebRTC, from the offer until DELETE or a lost connection.
+type Session struct {
+	server    *Server
+	id        string
+	token     string
+	ufrag     string
+	startedAt time.Time
+	log       *slog.Logger
+
+	pc     *webrtc.PeerConnection
+	stream Stream
+
+	connectTimer *time.Timer
+	writeMu      sync.Mutex
+	closed       bool
+	closeOnce    sync.Once
+}
+
+// ID is the last segment of the session URL.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// negotiate answers the offer once all candidates of the server are gathered, so clients that
+// can not trickle get them in the answer.
+func (s *Session) negotiate(offer string) (string, error) {
+	pc := s.pc
+
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		s.readTrack(track)
+	})
+
+	connected := make(chan struct{})
+	var connectOnce sync.Once
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateConnected:
+			connectOnce.Do(func() {
+				close(connected)
+			})
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed:
+			go func() {
+				_ = s.Close()
+			}()
+		}
+	})
+	s.connectTimer = time.AfterFunc(s.server.timeout, func() {
+		select {
+		case <-connected:
+		default:
+			s.log.Info("session did not connect", slog.String("session_id", s.id))
+			_ = s.Close()
+		}
+	})
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer}); err != nil {
+		return "", err
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return "", err
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		return "", err
+	}
+	<-gathered
+
+	return pc.LocalDescription().SDP, nil
+}
+
+func (s *Session) writePacket(packet media.Packet) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	return s.stream.WritePacket(packet)
+}
+
+// Close ends the session, calling it again does nothing.
+func (s *Session) Close() error {
+	const op = "lib.whip.Session.Close"
+
+	var err error
+	s.closeOnce.Do(func() {
+		s.server.remove(s)
+		_ = s.pc.Close()
+		if s.connectTimer != nil {
+			s.connectTimer.Stop()
+		}
+
+		s.writeMu.Lock()
+		s.closed = true
+		err = s.stream.Close()
+		s.writeMu.Unlock()
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func bearerToken(r *http.Request) string {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token
+}
+
+func hasContentType(r *http.Request, want string) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == want
+}
+
+func newSessionId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
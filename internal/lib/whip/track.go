@@ -0,0 +1,197 @@
+package whip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media/samplebuilder"
+)
+
+// maxLate is how many RTP packets a sample waits for the ones missing before it is dropped
+const maxLate = 256
+
+// H.264 NAL unit types
+const (
+	naluIDR = 5
+	naluSPS = 7
+	naluPPS = 8
+	naluAUD = 9
+)
+
+var errMalformedFrame = errors.New("whip: malformed H.264 frame")
+
+// readTrack turns the RTP of a track into packets until the peer connection closes. A packet the
+// stream refuses ends the session, like a tag does for RTMP.
+func (s *Session) readTrack(track *webrtc.TrackRemote) {
+	codec := track.Codec()
+
+	var (
+		depacketizer rtp.Depacketizer
+		convert      func(frame []byte, keyframeNeeded bool) []media.Packet
+	)
+	switch strings.ToLower(codec.MimeType) {
+	case strings.ToLower(webrtc.MimeTypeH264):
+		h264 := &h264Track{}
+		depacketizer = &codecs.H264Packet{IsAVC: true}
+		convert = h264.packets
+	case strings.ToLower(webrtc.MimeTypeOpus):
+		depacketizer = &codecs.OpusPacket{}
+		convert = func(frame []byte, _ bool) []media.Packet {
+			return []media.Packet{{Codec: media.CodecOpus, Data: frame}}
+		}
+	default:
+		return
+	}
+
+	builder := samplebuilder.New(maxLate, depacketizer, codec.ClockRate)
+	clock := trackClock{rate: codec.ClockRate}
+	keyframeNeeded := true
+	for {
+		packet, _, err := track.ReadRTP()
+		if err != nil {
+			return
+		}
+		builder.Push(packet)
+
+		for sample := builder.Pop(); sample != nil; sample = builder.Pop() {
+			// a frame after a gap can not be decoded until the next keyframe
+			if sample.PrevDroppedPackets > 0 {
+				keyframeNeeded = true
+			}
+			at := clock.time(sample.PacketTimestamp, time.Since(s.startedAt))
+
+			for _, packet := range convert(sample.Data, keyframeNeeded) {
+				if packet.Keyframe {
+					keyframeNeeded = false
+				}
+				packet.Time = at
+				if err := s.writePacket(packet); err != nil {
+					s.log.Error(
+						"failed to write packet",
+						slog.String("session_id", s.id),
+						slog.String("codec", string(packet.Codec)),
+						logger.Err(err),
+					)
+					go func() {
+						_ = s.Close()
+					}()
+					return
+				}
+			}
+		}
+	}
+}
+
+// trackClock turns RTP timestamps into time since the start of the session. The first sample of
+// a track is placed where it arrived, later ones follow the timestamps.
+type trackClock struct {
+	rate    uint32
+	started bool
+	last    uint32
+	ticks   int64
+	offset  time.Duration
+}
+
+func (c *trackClock) time(timestamp uint32, arrival time.Duration) time.Duration {
+	if !c.started {
+		c.started = true
+		c.last = timestamp
+		c.offset = arrival
+	}
+	// the difference as int32 survives the wrap around of the timestamp
+	c.ticks += int64(int32(timestamp - c.last))
+	c.last = timestamp
+
+	return c.offset + time.Duration(c.ticks)*time.Second/time.Duration(c.rate)
+}
+
+// h264Track takes the parameter sets out of the frames, WebRTC sends them in-band while the
+// media pipeline, like FLV and MP4, wants them as an AVCDecoderConfigurationRecord up front.
+type h264Track struct {
+	sps []byte
+	pps []byte
+}
+
+// packets turns an access unit of length prefixed NAL units into a frame, preceded by a config
+// packet when the parameter sets change. Frames are dropped while a keyframe is needed.
+func (t *h264Track) packets(frame []byte, keyframeNeeded bool) []media.Packet {
+	var (
+		data       []byte
+		keyframe   bool
+		configured = t.sps != nil && t.pps != nil
+		changed    bool
+	)
+	for len(frame) > 0 {
+		if len(frame) < 4 {
+			return nil
+		}
+		size := binary.BigEndian.Uint32(frame)
+		if size == 0 || uint64(size) > uint64(len(frame)-4) {
+			return nil
+		}
+		nalu := frame[4 : 4+size]
+		frame = frame[4+size:]
+
+		switch nalu[0] & 0x1f {
+		case naluSPS:
+			changed = changed || !bytes.Equal(nalu, t.sps)
+			t.sps = bytes.Clone(nalu)
+		case naluPPS:
+			changed = changed || !bytes.Equal(nalu, t.pps)
+			t.pps = bytes.Clone(nalu)
+		case naluAUD:
+		default:
+			if nalu[0]&0x1f == naluIDR {
+				keyframe = true
+			}
+			data = binary.BigEndian.AppendUint32(data, size)
+			data = append(data, nalu...)
+		}
+	}
+
+	packets := make([]media.Packet, 0, 2)
+	if changed && t.sps != nil && t.pps != nil {
+		config, err := avcConfig(t.sps, t.pps)
+		if err == nil {
+			configured = true
+			packets = append(packets, media.Packet{Codec: media.CodecH264, Keyframe: true, Config: true, Data: config})
+		}
+	}
+	if data == nil || !configured || (keyframeNeeded && !keyframe) {
+		return packets
+	}
+
+	return append(packets, media.Packet{Codec: media.CodecH264, Keyframe: keyframe, Data: data})
+}
+
+// avcConfig builds the AVCDecoderConfigurationRecord (ISO/IEC 14496-15) of one SPS and one PPS.
+func avcConfig(sps, pps []byte) ([]byte, error) {
+	if len(sps) < 4 || len(pps) == 0 {
+		return nil, errMalformedFrame
+	}
+
+	config := []byte{
+		1,      // version
+		sps[1], // profile
+		sps[2], // profile compatibility
+		sps[3], // level
+		0xff,   // 4 byte NAL unit lengths
+		0xe1,   // one SPS
+	}
+	config = binary.BigEndian.AppendUint16(config, uint16(len(sps)))
+	config = append(config, sps...)
+	config = append(config, 1) // one PPS
+	config = binary.BigEndian.AppendUint16(config, uint16(len(pps)))
+	config = append(config, pps...)
+
+	return config, nil
+}
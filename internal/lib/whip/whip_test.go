@@ -0,0 +1,377 @@
+package whip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/pion/webrtc/v4"
+	pionmedia "github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "live_key"
+
+var (
+	testSPS = []byte{0x67, 0x42, 0xe0, 0x1f, 0xda, 0x02}
+	testPPS = []byte{0x68, 0xce, 0x3c, 0x80}
+	testIDR = []byte{0x65, 0x88, 0x84, 0x00, 0x33}
+	testP   = []byte{0x41, 0x9a, 0x02}
+)
+
+// testHandler lets in testToken and records what arrives.
+type testHandler struct {
+	mu       sync.Mutex
+	sessions []*Session
+	packets  chan media.Packet
+	closed   chan struct{}
+	err      error
+}
+
+func newTestHandler() *testHandler {
+	return &testHandler{
+		packets: make(chan media.Packet, 1024),
+		closed:  make(chan struct{}, 4),
+	}
+}
+
+func (h *testHandler) Publish(_ context.Context, session *Session, token string) (Stream, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	if token != testToken {
+		return nil, ErrUnauthorized
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions = append(h.sessions, session)
+
+	return h, nil
+}
+
+func (h *testHandler) WritePacket(packet media.Packet) error {
+	select {
+	case h.packets <- packet:
+	default:
+	}
+	return nil
+}
+
+func (h *testHandler) Close() error {
+	h.closed <- struct{}{}
+	return nil
+}
+
+func TestServer_Loopback(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	endpoint := startServer(t, handler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	video, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264, ClockRate: 90000},
+		"video",
+		"broadcast",
+	)
+	require.NoError(t, err)
+	audio, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+		"audio",
+		"broadcast",
+	)
+	require.NoError(t, err)
+
+	client, err := Publish(ctx, endpoint, testToken, video, audio)
+	require.NoError(t, err)
+	defer func() {
+		_ = client.Close(context.Background())
+	}()
+
+	select {
+	case <-client.Connected():
+	case <-ctx.Done():
+		t.Fatal("client did not connect")
+	}
+
+	// samples are sent until the first ones came through, the packet of a frame
+	// is known to be complete once the next frame starts
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			frame := annexB(testP)
+			if i%25 == 0 {
+				frame = annexB(testSPS, testPPS, testIDR)
+			}
+			_ = video.WriteSample(pionmedia.Sample{Data: frame, Duration: 20 * time.Millisecond})
+			_ = audio.WriteSample(pionmedia.Sample{Data: []byte{0xfc, 0xff, 0xfe}, Duration: 20 * time.Millisecond})
+		}
+	}()
+
+	var (
+		videoPackets []media.Packet
+		gotAudio     bool
+	)
+	for len(videoPackets) < 3 || !gotAudio {
+		select {
+		case packet := <-handler.packets:
+			if packet.Codec == media.CodecOpus {
+				require.Equal(t, []byte{0xfc, 0xff, 0xfe}, packet.Data)
+				gotAudio = true
+				continue
+			}
+			videoPackets = append(videoPackets, packet)
+		case <-ctx.Done():
+			t.Fatal("media did not arrive")
+		}
+	}
+
+	// the parameter sets become the decoder config, the IDR frame comes without them
+	require.Equal(t, media.CodecH264, videoPackets[0].Codec)
+	require.True(t, videoPackets[0].Config)
+	config, err := avcConfig(testSPS, testPPS)
+	require.NoError(t, err)
+	require.Equal(t, config, videoPackets[0].Data)
+
+	require.True(t, videoPackets[1].Keyframe)
+	require.False(t, videoPackets[1].Config)
+	require.Equal(t, avcc(testIDR), videoPackets[1].Data)
+
+	require.False(t, videoPackets[2].Keyframe)
+	require.Equal(t, avcc(testP), videoPackets[2].Data)
+	require.GreaterOrEqual(t, videoPackets[2].Time, videoPackets[1].Time)
+
+	// trickled candidates must come with the ICE credentials of the offer
+	status, err := client.Trickle(ctx, "a=ice-ufrag:other\r\na=ice-pwd:secret\r\na=mid:0\r\n")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnprocessableEntity, status)
+
+	// only the broadcaster can end the session
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, client.Location(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer other")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	require.NoError(t, client.Close(ctx))
+	select {
+	case <-handler.closed:
+	case <-ctx.Done():
+		t.Fatal("stream was not closed")
+	}
+	status, err = client.Trickle(ctx, "a=end-of-candidates\r\n")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestServer_Kick(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	endpoint := startServer(t, handler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	audio, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+		"audio",
+		"broadcast",
+	)
+	require.NoError(t, err)
+
+	client, err := Publish(ctx, endpoint, testToken, audio)
+	require.NoError(t, err)
+	defer func() {
+		_ = client.Close(context.Background())
+	}()
+	select {
+	case <-client.Connected():
+	case <-ctx.Done():
+		t.Fatal("client did not connect")
+	}
+
+	handler.mu.Lock()
+	session := handler.sessions[0]
+	handler.mu.Unlock()
+	require.NoError(t, session.Close())
+
+	select {
+	case <-handler.closed:
+	case <-ctx.Done():
+		t.Fatal("stream was not closed")
+	}
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+		t.Fatal("client was not disconnected")
+	}
+}
+
+func TestServer_Refused(t *testing.T) {
+	audio, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+		"audio",
+		"broadcast",
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		token      string
+		handlerErr error
+		wantStatus int
+	}{
+		{
+			name:       "invalid token case",
+			token:      "other",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "forbidden case",
+			token:      testToken,
+			handlerErr: ErrForbidden,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "already live case",
+			token:      testToken,
+			handlerErr: ErrConflict,
+			wantStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newTestHandler()
+			handler.err = tt.handlerErr
+			endpoint := startServer(t, handler)
+
+			_, err := Publish(context.Background(), endpoint, tt.token, audio)
+			var status *StatusError
+			require.ErrorAs(t, err, &status)
+			require.Equal(t, tt.wantStatus, status.Status)
+		})
+	}
+}
+
+func TestServer_InvalidOffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{
+			name:        "content type case",
+			contentType: "application/json",
+			body:        "{}",
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "garbage case",
+			contentType: contentTypeSDP,
+			body:        "hello",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "no supported codec case",
+			contentType: contentTypeSDP,
+			body: "v=0\r\no=- 1 1 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n" +
+				"m=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=rtpmap:96 VP8/90000\r\n",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			endpoint := startServer(t, newTestHandler())
+
+			req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestH264Track_Packets(t *testing.T) {
+	track := &h264Track{}
+
+	// frames before the parameter sets can not be decoded
+	require.Empty(t, track.packets(avcc(testIDR), true))
+
+	packets := track.packets(avcc(testSPS, testPPS, testIDR), true)
+	require.Len(t, packets, 2)
+	require.True(t, packets[0].Config)
+	require.Equal(t, avcc(testIDR), packets[1].Data)
+
+	// unchanged parameter sets are not repeated
+	packets = track.packets(avcc(testSPS, testPPS, testP), false)
+	require.Len(t, packets, 1)
+	require.False(t, packets[0].Keyframe)
+
+	// after a loss everything up to the next keyframe is dropped
+	require.Empty(t, track.packets(avcc(testP), true))
+
+	// truncated frames are dropped
+	require.Empty(t, track.packets([]byte{0, 0, 0, 9, 0x41}, false))
+}
+
+func startServer(t *testing.T, handler Handler) string {
+	t.Helper()
+
+	server, err := NewServer(handler, 0, nil, 10*time.Second)
+	require.NoError(t, err)
+	srv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		srv.Close()
+		_ = server.Shutdown(context.Background())
+	})
+
+	return srv.URL + Path
+}
+
+func annexB(nalus ...[]byte) []byte {
+	var b []byte
+	for _, nalu := range nalus {
+		b = append(b, 0, 0, 0, 1)
+		b = append(b, nalu...)
+	}
+	return b
+}
+
+func avcc(nalus ...[]byte) []byte {
+	var b []byte
+	for _, nalu := range nalus {
+		b = append(b, 0, 0, 0, byte(len(nalu)))
+		b = append(b, nalu...)
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+package audit_repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Repository is append-only: events are never updated, and only the TTL index removes them.
+type Repository struct {
+	coll *mongo.Collection
+}
+
+func New(ctx context.Context, client *mongo.Client, db string, collection string, ttl time.Duration) (*Repository, error) {
+	const op = "repository.mongo.audit.New"
+
+	coll := client.Database(db).Collection(collection)
+
+	_, err := coll.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "created_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+			{
+				Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Repository{
+		coll: coll,
+	}, nil
+}
+
+func (r *Repository) SaveEvent(ctx context.Context, event entities.AuditEvent) error {
+	const op = "repository.mongo.audit.SaveEvent"
+
+	_, err := r.coll.InsertOne(ctx, event)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Events(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, int64, error) {
+	const op = "repository.mongo.audit.Events"
+
+	query := bson.D{}
+	if filter.UserId != nil {
+		query = append(query, bson.E{Key: "user_id", Value: *filter.UserId})
+	}
+	if filter.ActorId != nil {
+		query = append(query, bson.E{Key: "actor_id", Value: *filter.ActorId})
+	}
+	if filter.Type != "" {
+		query = append(query, bson.E{Key: "type", Value: filter.Type})
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := bson.D{}
+		if filter.From != nil {
+			createdAt = append(createdAt, bson.E{Key: "$gte", Value: *filter.From})
+		}
+		if filter.To != nil {
+			createdAt = append(createdAt, bson.E{Key: "$lt", Value: *filter.To})
+		}
+		query = append(query, bson.E{Key: "created_at", Value: createdAt})
+	}
+
+	total, err := r.coll.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(filter.Offset).
+		SetLimit(filter.Limit)
+	cursor, err := r.coll.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events := make([]entities.AuditEvent, 0, filter.Limit)
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,86 @@
+package audit_repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_Events(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	userId := uuid.New()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i, eventType := range []string{consts.AuditEventRegister, consts.AuditEventLoginFailure, consts.AuditEventLoginSuccess} {
+		err := r.SaveEvent(t.Context(), entities.AuditEvent{
+			ID:        uuid.New(),
+			Type:      eventType,
+			UserId:    userId,
+			ActorId:   userId,
+			IP:        "127.0.0.1",
+			UserAgent: "firefox",
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+		})
+		require.NoError(t, err)
+	}
+
+	events, total, err := r.Events(t.Context(), entities.AuditEventFilter{
+		UserId: &userId,
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+	require.Len(t, events, 2)
+	require.Equal(t, consts.AuditEventLoginSuccess, events[0].Type)
+
+	events, total, err = r.Events(t.Context(), entities.AuditEventFilter{
+		UserId: &userId,
+		Type:   consts.AuditEventLoginFailure,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, consts.AuditEventLoginFailure, events[0].Type)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	return client, client.Database("tests").Collection("audit_events")
+}
@@ -0,0 +1,266 @@
+package channel_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Repository struct {
+	coll *mongo.Collection
+}
+
+func New(ctx context.Context, client *mongo.Client, db string, collection string) (*Repository, error) {
+	const op = "repository.mongo.channel.New"
+
+	coll := client.Database(db).Collection(collection)
+
+	_, err := coll.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "login", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "stream_key.hash", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+			{
+				Keys:    bson.D{{Key: "broadcast.key_hash", Value: 1}},
+				Options: options.Index().SetSparse(true),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Repository{
+		coll: coll,
+	}, nil
+}
+
+// SaveChannel inserts the channel unless its user already has one, so it is safe to call twice.
+func (r *Repository) SaveChannel(ctx context.Context, channel entities.Channel) error {
+	const op = "repository.mongo.channel.SaveChannel"
+
+	_, err := r.coll.UpdateOne(
+		ctx,
+		bson.D{{Key: "user_id", Value: channel.UserId}},
+		bson.D{{Key: "$setOnInsert", Value: channel}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, errs.ErrChannelLoginTaken)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ChannelByLogin(ctx context.Context, login string) (entities.Channel, error) {
+	const op = "repository.mongo.channel.ChannelByLogin"
+
+	channel, err := r.channel(ctx, bson.D{{Key: "login", Value: strings.ToLower(login)}})
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+func (r *Repository) ChannelByUserId(ctx context.Context, userId uuid.UUID) (entities.Channel, error) {
+	const op = "repository.mongo.channel.ChannelByUserId"
+
+	channel, err := r.channel(ctx, bson.D{{Key: "user_id", Value: userId}})
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// UpdateSettings stores the settings the owner can change, the login and ids stay as they are.
+func (r *Repository) UpdateSettings(ctx context.Context, channel entities.Channel) error {
+	const op = "repository.mongo.channel.UpdateSettings"
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "title", Value: channel.Title},
+			{Key: "category", Value: channel.Category},
+			{Key: "language", Value: channel.Language},
+			{Key: "tags", Value: channel.Tags},
+			{Key: "mature", Value: channel.Mature},
+			{Key: "description", Value: channel.Description},
+			{Key: "offline_banner", Value: channel.OfflineBanner},
+			{Key: "updated_at", Value: channel.UpdatedAt},
+		}},
+	}
+	result, err := r.coll.UpdateOne(ctx, bson.D{{Key: "user_id", Value: channel.UserId}}, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrChannelNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) ChannelByStreamKeyHash(ctx context.Context, hash string) (entities.Channel, error) {
+	const op = "repository.mongo.channel.ChannelByStreamKeyHash"
+
+	channel, err := r.channel(ctx, bson.D{{Key: "stream_key.hash", Value: hash}})
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// SaveStreamKey replaces the stream key only while the stored one still has previousHash,
+// an empty previousHash means the channel has no key yet. Two concurrent resets can't both
+// win, the loser gets ErrStreamKeyChanged.
+func (r *Repository) SaveStreamKey(
+	ctx context.Context,
+	userId uuid.UUID,
+	key entities.StreamKey,
+	previousHash string,
+) (entities.Channel, error) {
+	const op = "repository.mongo.channel.SaveStreamKey"
+
+	filter := bson.D{{Key: "user_id", Value: userId}}
+	if previousHash == "" {
+		filter = append(filter, bson.E{Key: "stream_key", Value: bson.D{{Key: "$exists", Value: false}}})
+	} else {
+		filter = append(filter, bson.E{Key: "stream_key.hash", Value: previousHash})
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "stream_key", Value: key}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var channel entities.Channel
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&channel)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Channel{}, fmt.Errorf("%s: %w", op, errs.ErrStreamKeyChanged)
+		}
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// ChannelByBroadcastKeyHash finds the live channel whose broadcast started with the key, even
+// if the key was reset since.
+func (r *Repository) ChannelByBroadcastKeyHash(ctx context.Context, hash string) (entities.Channel, error) {
+	const op = "repository.mongo.channel.ChannelByBroadcastKeyHash"
+
+	channel, err := r.channel(ctx, bson.D{{Key: "broadcast.key_hash", Value: hash}})
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// StartBroadcast marks the channel live unless it already is. A broadcast not updated since
+// staleBefore counts as gone and is replaced.
+func (r *Repository) StartBroadcast(
+	ctx context.Context,
+	channelId uuid.UUID,
+	broadcast entities.Broadcast,
+	staleBefore time.Time,
+) error {
+	const op = "repository.mongo.channel.StartBroadcast"
+
+	filter := bson.D{
+		{Key: "_id", Value: channelId},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "broadcast", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "broadcast.updated_at", Value: bson.D{{Key: "$lt", Value: staleBefore}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "broadcast", Value: broadcast}}}}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrAlreadyLive)
+	}
+
+	return nil
+}
+
+// TouchBroadcast records that the broadcast of the client is still going.
+func (r *Repository) TouchBroadcast(ctx context.Context, channelId uuid.UUID, clientId string, now time.Time) error {
+	const op = "repository.mongo.channel.TouchBroadcast"
+
+	filter := bson.D{
+		{Key: "_id", Value: channelId},
+		{Key: "broadcast.client_id", Value: clientId},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "broadcast.updated_at", Value: now}}}}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+
+	return nil
+}
+
+// EndBroadcast marks the channel offline if the client is still the one broadcasting,
+// a late end of a replaced broadcast leaves the new one alone.
+func (r *Repository) EndBroadcast(ctx context.Context, channelId uuid.UUID, clientId string) error {
+	const op = "repository.mongo.channel.EndBroadcast"
+
+	filter := bson.D{
+		{Key: "_id", Value: channelId},
+		{Key: "broadcast.client_id", Value: clientId},
+	}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "broadcast", Value: ""}}}}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) channel(ctx context.Context, filter bson.D) (entities.Channel, error) {
+	var channel entities.Channel
+	err := r.coll.FindOne(ctx, filter).Decode(&channel)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Channel{}, errs.ErrChannelNotFound
+		}
+		return entities.Channel{}, err
+	}
+
+	return channel, nil
+}
@@ -0,0 +1,224 @@
+package channel_repository
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_Channel(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	displayName := gofakeit.Username() + "X"
+	channel := entities.Channel{
+		ID:          uuid.New(),
+		UserId:      uuid.New(),
+		Login:       strings.ToLower(displayName),
+		DisplayName: displayName,
+		Language:    "en",
+		Tags:        []string{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	require.NoError(t, r.SaveChannel(t.Context(), channel))
+	// saving again keeps the first channel
+	again := channel
+	again.ID = uuid.New()
+	require.NoError(t, r.SaveChannel(t.Context(), again))
+
+	got, err := r.ChannelByLogin(t.Context(), displayName)
+	require.NoError(t, err)
+	require.Equal(t, channel, got)
+
+	taken := channel
+	taken.ID = uuid.New()
+	taken.UserId = uuid.New()
+	require.ErrorIs(t, r.SaveChannel(t.Context(), taken), errs.ErrChannelLoginTaken)
+
+	channel.Title = "speedrun"
+	channel.Tags = []string{"english", "speedrun"}
+	channel.Mature = true
+	channel.UpdatedAt = now.Add(time.Minute)
+	require.NoError(t, r.UpdateSettings(t.Context(), channel))
+
+	got, err = r.ChannelByUserId(t.Context(), channel.UserId)
+	require.NoError(t, err)
+	require.Equal(t, channel, got)
+
+	_, err = r.ChannelByLogin(t.Context(), "missing-"+channel.Login)
+	require.ErrorIs(t, err, errs.ErrChannelNotFound)
+	require.ErrorIs(t, r.UpdateSettings(t.Context(), taken), errs.ErrChannelNotFound)
+}
+
+func TestRepository_SaveStreamKey(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	channel := entities.Channel{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		Login:     strings.ToLower(gofakeit.Username()) + "-" + uuid.NewString(),
+		Tags:      []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, r.SaveChannel(t.Context(), channel))
+
+	first := entities.StreamKey{Hash: uuid.NewString(), Sealed: []byte("first"), CreatedAt: now}
+	got, err := r.SaveStreamKey(t.Context(), channel.UserId, first, "")
+	require.NoError(t, err)
+	require.Equal(t, &first, got.StreamKey)
+
+	// the channel has a key already
+	_, err = r.SaveStreamKey(t.Context(), channel.UserId, first, "")
+	require.ErrorIs(t, err, errs.ErrStreamKeyChanged)
+
+	second := entities.StreamKey{Hash: uuid.NewString(), Sealed: []byte("second"), CreatedAt: now}
+	_, err = r.SaveStreamKey(t.Context(), channel.UserId, second, first.Hash)
+	require.NoError(t, err)
+	_, err = r.SaveStreamKey(t.Context(), channel.UserId, second, first.Hash)
+	require.ErrorIs(t, err, errs.ErrStreamKeyChanged)
+
+	got, err = r.ChannelByStreamKeyHash(t.Context(), second.Hash)
+	require.NoError(t, err)
+	require.Equal(t, channel.ID, got.ID)
+
+	_, err = r.ChannelByStreamKeyHash(t.Context(), first.Hash)
+	require.ErrorIs(t, err, errs.ErrChannelNotFound)
+}
+
+func TestRepository_Broadcast(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	channel := entities.Channel{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		Login:     strings.ToLower(gofakeit.Username()) + "-" + uuid.NewString(),
+		Tags:      []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, r.SaveChannel(t.Context(), channel))
+
+	first := entities.Broadcast{
+		Ingest:    "nginx-rtmp",
+		ClientId:  "1",
+		KeyHash:   uuid.NewString(),
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, r.StartBroadcast(t.Context(), channel.ID, first, now.Add(-time.Minute)))
+
+	got, err := r.ChannelByBroadcastKeyHash(t.Context(), first.KeyHash)
+	require.NoError(t, err)
+	require.Equal(t, &first, got.Broadcast)
+
+	// the channel is live already
+	second := first
+	second.ClientId = "2"
+	require.ErrorIs(t, r.StartBroadcast(t.Context(), channel.ID, second, now.Add(-time.Minute)), errs.ErrAlreadyLive)
+
+	require.NoError(t, r.TouchBroadcast(t.Context(), channel.ID, first.ClientId, now.Add(time.Minute)))
+	require.ErrorIs(t, r.TouchBroadcast(t.Context(), channel.ID, second.ClientId, now), errs.ErrBroadcastNotFound)
+
+	// the first broadcast went stale and the second takes over
+	require.NoError(t, r.StartBroadcast(t.Context(), channel.ID, second, now.Add(2*time.Minute)))
+	require.ErrorIs(t, r.EndBroadcast(t.Context(), channel.ID, first.ClientId), errs.ErrBroadcastNotFound)
+
+	require.NoError(t, r.EndBroadcast(t.Context(), channel.ID, second.ClientId))
+	got, err = r.ChannelByUserId(t.Context(), channel.UserId)
+	require.NoError(t, err)
+	require.Nil(t, got.Broadcast)
+	_, err = r.ChannelByBroadcastKeyHash(t.Context(), first.KeyHash)
+	require.ErrorIs(t, err, errs.ErrChannelNotFound)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	coll := client.Database("tests").Collection("channels")
+
+	_, err = coll.Indexes().CreateMany(
+		t.Context(),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "login", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "stream_key.hash", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+			{
+				Keys:    bson.D{{Key: "broadcast.key_hash", Value: 1}},
+				Options: options.Index().SetSparse(true),
+			},
+		},
+	)
+	require.NoError(t, err, fmt.Sprintf("failed to create index: %v", err))
+
+	return client, coll
+}
@@ -0,0 +1,81 @@
+package device_repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Repository struct {
+	coll *mongo.Collection
+}
+
+func New(ctx context.Context, client *mongo.Client, db string, collection string) (*Repository, error) {
+	const op = "repository.mongo.device.New"
+
+	coll := client.Database(db).Collection(collection)
+
+	_, err := coll.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "browser", Value: 1},
+				{Key: "os", Value: 1},
+				{Key: "ip_prefix", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Repository{
+		coll: coll,
+	}, nil
+}
+
+// TouchDevice bumps last_seen of a known device or inserts it, reporting whether it was inserted.
+func (r *Repository) TouchDevice(ctx context.Context, device entities.Device) (bool, error) {
+	const op = "repository.mongo.device.TouchDevice"
+
+	filter := bson.D{
+		{Key: "user_id", Value: device.UserId},
+		{Key: "browser", Value: device.Browser},
+		{Key: "os", Value: device.OS},
+		{Key: "ip_prefix", Value: device.IPPrefix},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "last_seen", Value: device.LastSeen},
+		}},
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "_id", Value: device.ID},
+			{Key: "first_seen", Value: device.FirstSeen},
+		}},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *Repository) CountDevices(ctx context.Context, userId uuid.UUID) (int64, error) {
+	const op = "repository.mongo.device.CountDevices"
+
+	count, err := r.coll.CountDocuments(ctx, bson.D{{Key: "user_id", Value: userId}})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
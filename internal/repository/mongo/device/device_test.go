@@ -0,0 +1,77 @@
+package device_repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_TouchDevice(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	device := entities.Device{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		Browser:   "Firefox",
+		OS:        "Linux x86_64",
+		IPPrefix:  "203.0.113.0/24",
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+
+	inserted, err := r.TouchDevice(t.Context(), device)
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	device.ID = uuid.New()
+	device.LastSeen = now.Add(time.Hour)
+	inserted, err = r.TouchDevice(t.Context(), device)
+	require.NoError(t, err)
+	require.False(t, inserted)
+
+	count, err := r.CountDevices(t.Context(), device.UserId)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	return client, client.Database("tests").Collection("devices")
+}
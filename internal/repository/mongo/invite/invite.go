@@ -0,0 +1,138 @@
+package invite_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Repository struct {
+	coll *mongo.Collection
+}
+
+func New(ctx context.Context, client *mongo.Client, db string, collection string) (*Repository, error) {
+	const op = "repository.mongo.invite.New"
+
+	coll := client.Database(db).Collection(collection)
+
+	_, err := coll.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Repository{
+		coll: coll,
+	}, nil
+}
+
+func (r *Repository) SaveInvite(ctx context.Context, invite entities.Invite) error {
+	const op = "repository.mongo.invite.SaveInvite"
+
+	_, err := r.coll.InsertOne(ctx, invite)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) InvitesByCreator(ctx context.Context, userId uuid.UUID) ([]entities.Invite, error) {
+	const op = "repository.mongo.invite.InvitesByCreator"
+
+	cursor, err := r.coll.Find(
+		ctx,
+		bson.D{{Key: "created_by", Value: userId}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invites := make([]entities.Invite, 0)
+	if err = cursor.All(ctx, &invites); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invites, nil
+}
+
+func (r *Repository) CountInvitesByCreator(ctx context.Context, userId uuid.UUID) (int64, error) {
+	const op = "repository.mongo.invite.CountInvitesByCreator"
+
+	count, err := r.coll.CountDocuments(ctx, bson.D{{Key: "created_by", Value: userId}})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// ConsumeInvite takes one use of the code in a single conditional update,
+// so concurrent registrations can not redeem more than max_uses.
+func (r *Repository) ConsumeInvite(ctx context.Context, code string, now time.Time) error {
+	const op = "repository.mongo.invite.ConsumeInvite"
+
+	filter := bson.D{
+		{Key: "_id", Value: code},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+		{Key: "$expr", Value: bson.D{{Key: "$lt", Value: bson.A{"$uses", "$max_uses"}}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "uses", Value: 1}}}}
+
+	err := r.coll.FindOneAndUpdate(ctx, filter, update).Err()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("%s: %w", op, errs.ErrInviteInvalid)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseInvite gives back a use taken by ConsumeInvite when the registration failed.
+func (r *Repository) ReleaseInvite(ctx context.Context, code string) error {
+	const op = "repository.mongo.invite.ReleaseInvite"
+
+	filter := bson.D{
+		{Key: "_id", Value: code},
+		{Key: "uses", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "uses", Value: -1}}}}
+
+	_, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SaveRedemption(ctx context.Context, code string, redemption entities.InviteRedemption) error {
+	const op = "repository.mongo.invite.SaveRedemption"
+
+	update := bson.D{{Key: "$push", Value: bson.D{{Key: "redemptions", Value: redemption}}}}
+
+	result, err := r.coll.UpdateByID(ctx, code, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrInviteInvalid)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package invite_repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_ConsumeInvite(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	invite := entities.Invite{
+		Code:        uuid.NewString(),
+		CreatedBy:   uuid.New(),
+		MaxUses:     2,
+		Redemptions: []entities.InviteRedemption{},
+		ExpiresAt:   now.Add(time.Hour),
+		CreatedAt:   now,
+	}
+	require.NoError(t, r.SaveInvite(t.Context(), invite))
+
+	require.NoError(t, r.ConsumeInvite(t.Context(), invite.Code, now))
+	require.NoError(t, r.ConsumeInvite(t.Context(), invite.Code, now))
+	require.ErrorIs(t, r.ConsumeInvite(t.Context(), invite.Code, now), errs.ErrInviteInvalid)
+
+	require.NoError(t, r.ReleaseInvite(t.Context(), invite.Code))
+	require.ErrorIs(t, r.ConsumeInvite(t.Context(), invite.Code, now.Add(2*time.Hour)), errs.ErrInviteInvalid)
+
+	userId := uuid.New()
+	require.NoError(t, r.SaveRedemption(t.Context(), invite.Code, entities.InviteRedemption{
+		UserId:     userId,
+		RedeemedAt: now,
+	}))
+
+	invites, err := r.InvitesByCreator(t.Context(), invite.CreatedBy)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.Equal(t, 1, invites[0].Uses)
+	require.Equal(t, userId, invites[0].Redemptions[0].UserId)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	return client, client.Database("tests").Collection("invites")
+}
@@ -0,0 +1,180 @@
+package mail_queue_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Repository struct {
+	queue       *mongo.Collection
+	deadLetters *mongo.Collection
+}
+
+func New(
+	ctx context.Context,
+	client *mongo.Client,
+	db string,
+	queueCollection string,
+	deadLetterCollection string,
+) (*Repository, error) {
+	const op = "repository.mongo.mail_queue.New"
+
+	queue := client.Database(db).Collection(queueCollection)
+	deadLetters := client.Database(db).Collection(deadLetterCollection)
+
+	_, err := queue.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "next_attempt_at", Value: 1}},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = deadLetters.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "failed_at", Value: -1}},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Repository{
+		queue:       queue,
+		deadLetters: deadLetters,
+	}, nil
+}
+
+func (r *Repository) Enqueue(ctx context.Context, job entities.MailJob) error {
+	const op = "repository.mongo.mail_queue.Enqueue"
+
+	_, err := r.queue.InsertOne(ctx, job)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Claim leases the due job that waits the longest, so no other worker sends it until lease runs out.
+func (r *Repository) Claim(ctx context.Context, now time.Time, lease time.Duration) (entities.MailJob, error) {
+	const op = "repository.mongo.mail_queue.Claim"
+
+	filter := bson.D{
+		{Key: "next_attempt_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		{Key: "locked_until", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "locked_until", Value: now.Add(lease)}}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job entities.MailJob
+	err := r.queue.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.MailJob{}, fmt.Errorf("%s: %w", op, errs.ErrMailQueueEmpty)
+		}
+		return entities.MailJob{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+func (r *Repository) Complete(ctx context.Context, id uuid.UUID) error {
+	const op = "repository.mongo.mail_queue.Complete"
+
+	_, err := r.queue.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Retry releases the job and schedules its next attempt.
+func (r *Repository) Retry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastErr string) error {
+	const op = "repository.mongo.mail_queue.Retry"
+
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+		{Key: "$set", Value: bson.D{
+			{Key: "next_attempt_at", Value: nextAttemptAt},
+			{Key: "locked_until", Value: time.Time{}},
+			{Key: "last_error", Value: lastErr},
+		}},
+	}
+	_, err := r.queue.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MoveToDeadLetter stores the job in the dead-letter collection and removes it from the queue.
+// A job left in both after a crash between the two steps is only dead-lettered once.
+func (r *Repository) MoveToDeadLetter(ctx context.Context, job entities.MailJob) error {
+	const op = "repository.mongo.mail_queue.MoveToDeadLetter"
+
+	_, err := r.deadLetters.InsertOne(ctx, job)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = r.queue.DeleteOne(ctx, bson.D{{Key: "_id", Value: job.ID}})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Stats(ctx context.Context) (entities.MailQueueStats, error) {
+	const op = "repository.mongo.mail_queue.Stats"
+
+	var stats entities.MailQueueStats
+	var err error
+
+	stats.Pending, err = r.queue.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return entities.MailQueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.Retrying, err = r.queue.CountDocuments(ctx, bson.D{{Key: "attempts", Value: bson.D{{Key: "$gt", Value: 0}}}})
+	if err != nil {
+		return entities.MailQueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.DeadLetters, err = r.deadLetters.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return entities.MailQueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var oldest entities.MailJob
+	err = r.queue.FindOne(
+		ctx,
+		bson.D{},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	).Decode(&oldest)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return entities.MailQueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err == nil {
+		stats.OldestPendingAt = &oldest.CreatedAt
+	}
+
+	return stats, nil
+}
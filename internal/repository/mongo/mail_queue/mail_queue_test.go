@@ -0,0 +1,93 @@
+package mail_queue_repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_Claim(t *testing.T) {
+	isSkip(t)
+
+	client, r := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	job := entities.MailJob{
+		ID:            uuid.New(),
+		To:            "user@test.com",
+		Subject:       "subject",
+		NextAttemptAt: now.Add(-time.Second),
+		CreatedAt:     now,
+	}
+	require.NoError(t, r.Enqueue(t.Context(), job))
+
+	got, err := r.Claim(t.Context(), now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, got.ID)
+	require.True(t, got.LockedUntil.After(now))
+
+	_, err = r.Claim(t.Context(), now, time.Minute)
+	require.ErrorIs(t, err, errs.ErrMailQueueEmpty)
+
+	err = r.Retry(t.Context(), job.ID, now.Add(-time.Millisecond), "temporary")
+	require.NoError(t, err)
+
+	got, err = r.Claim(t.Context(), now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.Attempts)
+	require.Equal(t, "temporary", got.LastError)
+
+	require.NoError(t, r.MoveToDeadLetter(t.Context(), got))
+	require.NoError(t, r.MoveToDeadLetter(t.Context(), got))
+
+	stats, err := r.Stats(t.Context())
+	require.NoError(t, err)
+	require.Zero(t, stats.Pending)
+	require.Equal(t, int64(1), stats.DeadLetters)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *Repository) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	r := &Repository{
+		queue:       client.Database("tests").Collection("mail_queue"),
+		deadLetters: client.Database("tests").Collection("mail_dead_letters"),
+	}
+	_, err = r.queue.DeleteMany(t.Context(), bson.D{})
+	require.NoError(t, err)
+	_, err = r.deadLetters.DeleteMany(t.Context(), bson.D{})
+	require.NoError(t, err)
+
+	return client, r
+}
@@ -0,0 +1,115 @@
+package phone_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Repository keeps one document per phone number with the ids of the accounts holding it,
+// so the per-number account limit is enforced by a single conditional update.
+type Repository struct {
+	coll *mongo.Collection
+}
+
+type phoneHolders struct {
+	Phone   string      `bson:"_id"`
+	UserIds []uuid.UUID `bson:"user_ids"`
+}
+
+func New(client *mongo.Client, db string, collection string) *Repository {
+	return &Repository{
+		coll: client.Database(db).Collection(collection),
+	}
+}
+
+// Reserve adds the user to the holders of the number unless limit accounts already hold it.
+// Reserving a number the user already holds is a no-op.
+func (r *Repository) Reserve(ctx context.Context, phone string, userId uuid.UUID, limit int64) error {
+	const op = "repository.mongo.phone.Reserve"
+
+	if limit < 1 {
+		return fmt.Errorf("%s: %w", op, errs.ErrPhoneTaken)
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: phone},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "user_ids", Value: userId}},
+			bson.D{{Key: "user_ids." + strconv.FormatInt(limit-1, 10), Value: bson.D{{Key: "$exists", Value: false}}}},
+		}},
+	}
+	update := bson.D{{Key: "$addToSet", Value: bson.D{{Key: "user_ids", Value: userId}}}}
+
+	_, err := r.coll.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err == nil {
+		return nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the upsert collides when the number is full or was inserted concurrently,
+	// the document exists now, so a plain update tells which one it was
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrPhoneTaken)
+	}
+
+	return nil
+}
+
+// Release removes the user from the holders of the number.
+func (r *Repository) Release(ctx context.Context, phone string, userId uuid.UUID) error {
+	const op = "repository.mongo.phone.Release"
+
+	filter := bson.D{{Key: "_id", Value: phone}}
+	update := bson.D{{Key: "$pull", Value: bson.D{{Key: "user_ids", Value: userId}}}}
+	_, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = r.coll.DeleteOne(ctx, bson.D{
+		{Key: "_id", Value: phone},
+		{Key: "user_ids", Value: bson.D{{Key: "$size", Value: 0}}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountHolders counts the accounts other than exceptId that hold the number.
+func (r *Repository) CountHolders(ctx context.Context, phone string, exceptId uuid.UUID) (int64, error) {
+	const op = "repository.mongo.phone.CountHolders"
+
+	var holders phoneHolders
+	err := r.coll.FindOne(ctx, bson.D{{Key: "_id", Value: phone}}).Decode(&holders)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int64
+	for _, id := range holders.UserIds {
+		if id != exceptId {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,87 @@
+package phone_repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestRepository_Reserve(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	phone := "+1555" + gofakeit.DigitN(7)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	err := r.Reserve(t.Context(), phone, first, 2)
+	require.NoError(t, err)
+
+	// reserving again does not take another slot
+	err = r.Reserve(t.Context(), phone, first, 2)
+	require.NoError(t, err)
+
+	err = r.Reserve(t.Context(), phone, second, 2)
+	require.NoError(t, err)
+
+	err = r.Reserve(t.Context(), phone, third, 2)
+	require.ErrorIs(t, err, errs.ErrPhoneTaken)
+
+	count, err := r.CountHolders(t.Context(), phone, first)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	err = r.Release(t.Context(), phone, second)
+	require.NoError(t, err)
+
+	err = r.Reserve(t.Context(), phone, third, 2)
+	require.NoError(t, err)
+
+	err = r.Release(t.Context(), phone, first)
+	require.NoError(t, err)
+	err = r.Release(t.Context(), phone, third)
+	require.NoError(t, err)
+
+	count, err = r.CountHolders(t.Context(), phone, uuid.New())
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
+	t.Helper()
+
+	connString := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/?authSource=admin",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(connString).SetRegistry(mongodb.UUIDRegistry))
+	require.NoError(t, err, fmt.Sprintf("failed to connect to db: %v", err))
+
+	return client, client.Database("tests").Collection("phones")
+}
@@ -3,9 +3,11 @@ package token_repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -20,11 +22,21 @@ func New(ctx context.Context, client *mongo.Client, db string, collection string
 
 	coll := client.Database(db).Collection(collection)
 
-	_, err := coll.Indexes().CreateOne(
+	_, err := coll.Indexes().CreateMany(
 		ctx,
-		mongo.IndexModel{
-			Keys:    bson.D{{Key: "token", Value: 1}},
-			Options: options.Index().SetUnique(true),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "token", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				// tokens without expires_at are not touched by the TTL index
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}},
+			},
 		},
 	)
 	if err != nil {
@@ -50,7 +62,11 @@ func (r *Repository) SaveToken(ctx context.Context, token entities.Token) error
 func (r *Repository) Token(ctx context.Context, token string) (entities.Token, error) {
 	const op = "repository.mongo.token.Token"
 
-	filter := bson.D{{Key: "token", Value: token}}
+	// the TTL monitor runs only once a minute, so expired tokens are filtered out here as well
+	filter := bson.D{
+		{Key: "token", Value: token},
+		{Key: "expires_at", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$lte", Value: time.Now()}}}}},
+	}
 	result := r.coll.FindOne(ctx, filter)
 	if result.Err() != nil {
 		return entities.Token{}, fmt.Errorf("%s: %w", op, errs.ErrTokenNotFound)
@@ -80,3 +96,16 @@ func (r *Repository) DeleteToken(ctx context.Context, token string) error {
 
 	return nil
 }
+
+// DeleteUserTokens deletes all tokens of the given type issued to the user.
+func (r *Repository) DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error {
+	const op = "repository.mongo.token.DeleteUserTokens"
+
+	filter := bson.D{{Key: "user_id", Value: userId}, {Key: "type", Value: tokenType}}
+	_, err := r.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
@@ -90,6 +91,17 @@ func TestRepository_Token(t *testing.T) {
 	_, err := coll.InsertOne(t.Context(), token)
 	require.NoError(t, err)
 
+	// not yet removed by the TTL monitor
+	expired := entities.Token{
+		Token:     uuid.NewString(),
+		UserId:    uuid.New(),
+		Type:      consts.TokenTypeResetPassword,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	_, err = coll.InsertOne(t.Context(), expired)
+	require.NoError(t, err)
+
 	tests := []struct {
 		name    string
 		fields  fields
@@ -121,6 +133,18 @@ func TestRepository_Token(t *testing.T) {
 			want:    entities.Token{},
 			wantErr: errs.ErrTokenNotFound,
 		},
+		{
+			name: "expired case",
+			fields: fields{
+				coll: coll,
+			},
+			args: args{
+				ctx:   t.Context(),
+				token: expired.Token,
+			},
+			want:    entities.Token{},
+			wantErr: errs.ErrTokenNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -200,6 +224,41 @@ func TestRepository_DeleteToken(t *testing.T) {
 	}
 }
 
+func TestRepository_DeleteUserTokens(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	userId := uuid.New()
+	tokens := []entities.Token{
+		{Token: uuid.NewString(), UserId: userId, Type: consts.TokenTypeResetPassword},
+		{Token: uuid.NewString(), UserId: userId, Type: consts.TokenTypeResetPassword},
+		{Token: uuid.NewString(), UserId: userId, Type: consts.TokenTypeVerifyEmail},
+	}
+	for _, token := range tokens {
+		_, err := coll.InsertOne(t.Context(), token)
+		require.NoError(t, err)
+	}
+
+	r := &Repository{
+		coll: coll,
+	}
+	err := r.DeleteUserTokens(t.Context(), userId, consts.TokenTypeResetPassword)
+	require.NoError(t, err)
+
+	for _, token := range tokens[:2] {
+		_, err = r.Token(t.Context(), token.Token)
+		require.ErrorIs(t, err, errs.ErrTokenNotFound)
+	}
+
+	// tokens of other types are kept
+	_, err = r.Token(t.Context(), tokens[2].Token)
+	require.NoError(t, err)
+}
+
 func isSkip(t *testing.T) {
 	t.Helper()
 	if os.Getenv("CI") != "" {
@@ -2,7 +2,10 @@ package user_repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
@@ -21,11 +24,17 @@ func New(ctx context.Context, client *mongo.Client, db string, collection string
 
 	coll := client.Database(db).Collection(collection)
 
-	_, err := coll.Indexes().CreateOne(
+	_, err := coll.Indexes().CreateMany(
 		ctx,
-		mongo.IndexModel{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "login_key", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
 		},
 	)
 	if err != nil {
@@ -44,6 +53,9 @@ func (r *Repository) SaveUser(ctx context.Context, user entities.User) error {
 	if err != nil {
 		if writeErr, ok := err.(mongo.WriteException); ok {
 			for _, e := range writeErr.WriteErrors {
+				if e.Code == 11000 && strings.Contains(e.Message, "login_key") {
+					return fmt.Errorf("%s: %w", op, errs.ErrLoginTaken)
+				}
 				if e.Code == 11000 {
 					return fmt.Errorf("%s: %w", op, errs.ErrUserAlreadyExists)
 				}
@@ -84,7 +96,186 @@ func (r *Repository) ValidateEmail(ctx context.Context, id uuid.UUID) error {
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// ChangeEmail replaces the email with an address the user just verified,
+// a suppression of the old address does not carry over.
+func (r *Repository) ChangeEmail(ctx context.Context, id uuid.UUID, email string) error {
+	const op = "repository.mongo.user.ChangeEmail"
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "email", Value: email},
+			{Key: "is_email_verified", Value: true},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "email_suppression", Value: ""},
+		}},
+	}
+	result, err := r.coll.UpdateByID(ctx, id, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, errs.ErrUserAlreadyExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) UserById(ctx context.Context, id uuid.UUID) (entities.User, error) {
+	const op = "repository.mongo.user.UserById"
+
+	result := r.coll.FindOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if result.Err() != nil {
+		return entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	var user entities.User
+	if err := result.Decode(&user); err != nil {
+		return entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+func (r *Repository) SearchUsers(ctx context.Context, query string, offset, limit int64) ([]entities.User, int64, error) {
+	const op = "repository.mongo.user.SearchUsers"
+
+	filter := bson.D{}
+	if query != "" {
+		pattern := bson.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter = bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "login", Value: pattern}},
+			bson.D{{Key: "email", Value: pattern}},
+		}}}
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "login", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	users := make([]entities.User, 0, limit)
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, total, nil
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	const op = "repository.mongo.user.UpdatePassword"
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "password", Value: password},
+		}},
+	}
+	result, err := r.coll.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetSuspension(ctx context.Context, id uuid.UUID, suspension *entities.Suspension) error {
+	const op = "repository.mongo.user.SetSuspension"
+
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "suspension", Value: ""}}}}
+	if suspension != nil {
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "suspension", Value: suspension}}}}
+	}
+
+	result, err := r.coll.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// SetPhone stores a verified phone number of the user.
+func (r *Repository) SetPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	const op = "repository.mongo.user.SetPhone"
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "phone", Value: phone},
+			{Key: "is_phone_verified", Value: true},
+		}},
+	}
+	result, err := r.coll.UpdateByID(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// SuppressEmail marks the address as hard-bounced or complained and returns the id of its owner.
+func (r *Repository) SuppressEmail(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error) {
+	const op = "repository.mongo.user.SuppressEmail"
+
+	filter := bson.D{{Key: "email", Value: email}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "email_suppression", Value: suppression}}}}
+	opts := options.FindOneAndUpdate().SetProjection(bson.D{{Key: "_id", Value: 1}})
+
+	var user entities.User
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+		}
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.ID, nil
+}
+
+func (r *Repository) SetNotificationPreferences(ctx context.Context, id uuid.UUID, preferences map[string]bool) error {
+	const op = "repository.mongo.user.SetNotificationPreferences"
+
+	set := make(bson.D, 0, len(preferences))
+	for category, enabled := range preferences {
+		set = append(set, bson.E{Key: "notification_preferences." + category, Value: enabled})
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	result, err := r.coll.UpdateByID(ctx, id, bson.D{{Key: "$set", Value: set}})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if result.MatchedCount == 0 {
 		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
 	}
 
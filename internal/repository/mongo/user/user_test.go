@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
 	"github.com/AlexMickh/twitch-clone/pkg/clients/mongodb"
@@ -33,9 +36,11 @@ func TestRepository_SaveUser(t *testing.T) {
 		_ = client.Disconnect(t.Context())
 	}()
 
+	login := gofakeit.Username()
 	existingUser := entities.User{
 		ID:       uuid.New(),
-		Login:    gofakeit.FirstName(),
+		Login:    login,
+		LoginKey: strings.ToLower(login),
 		Email:    gofakeit.Email(),
 		Password: "some password",
 	}
@@ -76,6 +81,23 @@ func TestRepository_SaveUser(t *testing.T) {
 			},
 			wantErr: errs.ErrUserAlreadyExists,
 		},
+		{
+			name: "login taken case",
+			fields: fields{
+				coll: coll,
+			},
+			args: args{
+				ctx: context.TODO(),
+				user: entities.User{
+					ID:       uuid.New(),
+					Login:    strings.ToUpper(login),
+					LoginKey: strings.ToLower(login),
+					Email:    gofakeit.Email(),
+					Password: "some password",
+				},
+			},
+			wantErr: errs.ErrLoginTaken,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -225,6 +247,248 @@ func TestRepository_ValidateEmail(t *testing.T) {
 	}
 }
 
+func TestRepository_SearchUsers(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	login := "search_" + uuid.NewString()
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    login,
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertOne(t.Context(), user)
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	got, total, err := r.SearchUsers(t.Context(), strings.ToUpper(login), 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, []entities.User{user}, got)
+
+	got, total, err = r.SearchUsers(t.Context(), "not existing (login", 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), total)
+	require.Empty(t, got)
+}
+
+func TestRepository_SetSuspension(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertOne(t.Context(), user)
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	err = r.SetSuspension(t.Context(), user.ID, &entities.Suspension{
+		Type:     consts.SuspensionTypeBan,
+		Reason:   "spam",
+		IssuedBy: uuid.New(),
+		IssuedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	got, err := r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.True(t, got.IsSuspended(time.Now()))
+
+	err = r.SetSuspension(t.Context(), user.ID, nil)
+	require.NoError(t, err)
+
+	got, err = r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.Suspension)
+
+	err = r.SetSuspension(t.Context(), uuid.New(), nil)
+	require.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
+func TestRepository_SetPhone(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	phone := "+1555" + gofakeit.DigitN(7)
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertOne(t.Context(), user)
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	err = r.SetPhone(t.Context(), user.ID, phone)
+	require.NoError(t, err)
+
+	got, err := r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, phone, got.Phone)
+	require.True(t, got.IsPhoneVerified)
+
+	err = r.SetPhone(t.Context(), uuid.New(), phone)
+	require.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
+func TestRepository_SuppressEmail(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertOne(t.Context(), user)
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	suppression := entities.EmailSuppression{
+		Type:   consts.MailEventHardBounce,
+		Reason: "mailbox does not exist",
+		At:     time.Now().UTC().Truncate(time.Millisecond),
+	}
+	id, err := r.SuppressEmail(t.Context(), user.Email, suppression)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, id)
+
+	got, err := r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.EmailSuppression)
+	require.Equal(t, suppression.Type, got.EmailSuppression.Type)
+	require.True(t, suppression.At.Equal(got.EmailSuppression.At))
+
+	_, err = r.SuppressEmail(t.Context(), gofakeit.Email(), suppression)
+	require.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
+func TestRepository_ChangeEmail(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+		EmailSuppression: &entities.EmailSuppression{
+			Type: consts.MailEventHardBounce,
+			At:   time.Now().UTC().Truncate(time.Millisecond),
+		},
+	}
+	other := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertMany(t.Context(), []entities.User{user, other})
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	email := gofakeit.Email()
+	err = r.ChangeEmail(t.Context(), user.ID, email)
+	require.NoError(t, err)
+
+	got, err := r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, email, got.Email)
+	require.True(t, got.IsEmailVerified)
+	require.Nil(t, got.EmailSuppression)
+
+	err = r.ChangeEmail(t.Context(), user.ID, other.Email)
+	require.ErrorIs(t, err, errs.ErrUserAlreadyExists)
+
+	err = r.ChangeEmail(t.Context(), uuid.New(), gofakeit.Email())
+	require.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
+func TestRepository_SetNotificationPreferences(t *testing.T) {
+	isSkip(t)
+
+	client, coll := initRepository(t)
+	defer func() {
+		_ = client.Disconnect(t.Context())
+	}()
+
+	user := entities.User{
+		ID:       uuid.New(),
+		Login:    gofakeit.FirstName(),
+		Email:    gofakeit.Email(),
+		Password: "some password",
+	}
+
+	_, err := coll.InsertOne(t.Context(), user)
+	require.NoError(t, err)
+
+	r := &Repository{
+		coll: coll,
+	}
+
+	err = r.SetNotificationPreferences(t.Context(), user.ID, map[string]bool{consts.EmailCategoryMarketing: true})
+	require.NoError(t, err)
+	err = r.SetNotificationPreferences(t.Context(), user.ID, map[string]bool{consts.EmailCategoryGoLive: false})
+	require.NoError(t, err)
+
+	got, err := r.UserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{
+		consts.EmailCategoryMarketing: true,
+		consts.EmailCategoryGoLive:    false,
+	}, got.NotificationPreferences)
+
+	err = r.SetNotificationPreferences(t.Context(), uuid.New(), map[string]bool{consts.EmailCategoryDigest: false})
+	require.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
 func isSkip(t *testing.T) {
 	t.Helper()
 	if os.Getenv("CI") != "" {
@@ -248,11 +512,17 @@ func initRepository(t *testing.T) (*mongo.Client, *mongo.Collection) {
 
 	coll := client.Database("tests").Collection("users")
 
-	_, err = coll.Indexes().CreateOne(
+	_, err = coll.Indexes().CreateMany(
 		t.Context(),
-		mongo.IndexModel{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "login_key", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
 		},
 	)
 	require.NoError(t, err, fmt.Sprintf("failed to create index: %v", err))
@@ -0,0 +1,79 @@
+package broadcast_repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Repository passes kick notices from the API to the ingest servers over redis pub/sub.
+// A notice is not stored, ingest servers that are down miss it, which is fine as they
+// have no broadcasts to drop then.
+type Repository struct {
+	rdb     *redis.Client
+	channel string
+}
+
+func New(rdb *redis.Client, prefix string) *Repository {
+	return &Repository{
+		rdb:     rdb,
+		channel: prefix + ":kick",
+	}
+}
+
+// Kick asks every ingest server to drop the broadcast of the channel.
+func (r *Repository) Kick(ctx context.Context, channelId uuid.UUID) error {
+	const op = "repository.redis.broadcast.Kick"
+
+	err := r.rdb.Publish(ctx, r.channel, channelId.String()).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Kicks delivers the channel ids of kick notices until ctx is done.
+func (r *Repository) Kicks(ctx context.Context) (<-chan uuid.UUID, error) {
+	const op = "repository.redis.broadcast.Kicks"
+
+	sub := r.rdb.Subscribe(ctx, r.channel)
+	// wait for the subscription, notices published before it would be lost silently
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	kicks := make(chan uuid.UUID)
+	go func() {
+		defer close(kicks)
+		defer func() {
+			_ = sub.Close()
+		}()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				channelId, err := uuid.Parse(msg.Payload)
+				if err != nil {
+					continue
+				}
+				select {
+				case kicks <- channelId:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return kicks, nil
+}
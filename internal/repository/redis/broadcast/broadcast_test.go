@@ -0,0 +1,68 @@
+package broadcast_repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Kicks(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := New(rdb, "test-"+uuid.NewString())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	kicks, err := r.Kicks(ctx)
+	require.NoError(t, err)
+
+	channelId := uuid.New()
+	require.NoError(t, r.Kick(t.Context(), channelId))
+
+	select {
+	case got := <-kicks:
+		require.Equal(t, channelId, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no kick received")
+	}
+
+	cancel()
+	_, ok := <-kicks
+	require.False(t, ok)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) *redis.Client {
+	t.Helper()
+
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	err = rdb.Ping(t.Context()).Err()
+	require.NoError(t, err)
+
+	return rdb
+}
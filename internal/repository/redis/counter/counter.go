@@ -0,0 +1,39 @@
+package counter_repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Repository struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+func New(rdb *redis.Client, prefix string) *Repository {
+	return &Repository{
+		rdb:    rdb,
+		prefix: prefix,
+	}
+}
+
+// Increment bumps the counter of key and returns its new value.
+// The window starts with the first increment, later ones do not extend it.
+func (r *Repository) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	const op = "repository.redis.counter.Increment"
+
+	fullKey := r.prefix + ":" + key
+	pipeline := r.rdb.TxPipeline()
+	count := pipeline.Incr(ctx, fullKey)
+	pipeline.ExpireNX(ctx, fullKey, window)
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count.Val(), nil
+}
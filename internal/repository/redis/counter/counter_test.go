@@ -0,0 +1,60 @@
+package counter_repository
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Increment(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := New(rdb, "test")
+	key := uuid.NewString()
+
+	for i := range 3 {
+		count, err := r.Increment(t.Context(), key, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, int64(i+1), count)
+	}
+
+	ttl, err := rdb.TTL(t.Context(), "test:"+key).Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, time.Duration(0))
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) *redis.Client {
+	t.Helper()
+
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	err = rdb.Ping(t.Context()).Err()
+	require.NoError(t, err)
+
+	return rdb
+}
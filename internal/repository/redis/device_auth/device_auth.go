@@ -0,0 +1,180 @@
+package device_auth_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+// decideScript moves a pending authorization to the given status.
+var decideScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "status") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[1], "status", ARGV[2], "user_id", ARGV[3])
+return 1
+`)
+
+// pollScript records a poll only if the authorization has not expired meanwhile.
+var pollScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "last_polled_at", ARGV[1], "interval", ARGV[2])
+return 1
+`)
+
+type Repository struct {
+	rdb *redis.Client
+}
+
+func New(rdb *redis.Client) *Repository {
+	return &Repository{
+		rdb: rdb,
+	}
+}
+
+// SaveAuthorization stores the authorization under both of its codes until it expires.
+// ErrUserCodeTaken is returned if another pending authorization holds the same user code.
+func (r *Repository) SaveAuthorization(ctx context.Context, auth entities.DeviceAuthorization) error {
+	const op = "repository.redis.device_auth.SaveAuthorization"
+
+	ttl := time.Until(auth.ExpiresAt)
+
+	ok, err := r.rdb.SetNX(ctx, userCodeKey(auth.UserCode), auth.DeviceCode, ttl).Result()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserCodeTaken)
+	}
+
+	key := deviceCodeKey(auth.DeviceCode)
+	pipeline := r.rdb.TxPipeline()
+	pipeline.HSet(ctx, key, auth)
+	pipeline.Expire(ctx, key, ttl)
+
+	_, err = pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AuthorizationByDeviceCode(
+	ctx context.Context,
+	deviceCode string,
+) (entities.DeviceAuthorization, error) {
+	const op = "repository.redis.device_auth.AuthorizationByDeviceCode"
+
+	res := r.rdb.HGetAll(ctx, deviceCodeKey(deviceCode))
+	if err := res.Err(); err != nil {
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(res.Val()) == 0 {
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, errs.ErrDeviceCodeNotFound)
+	}
+
+	var auth entities.DeviceAuthorization
+	err := res.Scan(&auth)
+	if err != nil {
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+	auth.DeviceCode = deviceCode
+
+	return auth, nil
+}
+
+func (r *Repository) AuthorizationByUserCode(
+	ctx context.Context,
+	userCode string,
+) (entities.DeviceAuthorization, error) {
+	const op = "repository.redis.device_auth.AuthorizationByUserCode"
+
+	deviceCode, err := r.rdb.Get(ctx, userCodeKey(userCode)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, errs.ErrDeviceCodeNotFound)
+		}
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	auth, err := r.AuthorizationByDeviceCode(ctx, deviceCode)
+	if err != nil {
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return auth, nil
+}
+
+// Decide approves or denies a pending authorization on behalf of userId.
+// An authorization that is gone or already decided yields ErrDeviceCodeNotFound.
+func (r *Repository) Decide(ctx context.Context, deviceCode, status, userId string) error {
+	const op = "repository.redis.device_auth.Decide"
+
+	updated, err := decideScript.Run(
+		ctx,
+		r.rdb,
+		[]string{deviceCodeKey(deviceCode)},
+		consts.DeviceAuthorizationPending,
+		status,
+		userId,
+	).Int()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrDeviceCodeNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) RecordPoll(ctx context.Context, deviceCode string, at time.Time, interval time.Duration) error {
+	const op = "repository.redis.device_auth.RecordPoll"
+
+	updated, err := pollScript.Run(ctx, r.rdb, []string{deviceCodeKey(deviceCode)}, at, interval).Int()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrDeviceCodeNotFound)
+	}
+
+	return nil
+}
+
+// DeleteAuthorization removes both codes. Only the caller that actually deleted
+// the device code gets nil, so an approved grant is exchanged at most once.
+func (r *Repository) DeleteAuthorization(ctx context.Context, auth entities.DeviceAuthorization) error {
+	const op = "repository.redis.device_auth.DeleteAuthorization"
+
+	pipeline := r.rdb.TxPipeline()
+	deleted := pipeline.Del(ctx, deviceCodeKey(auth.DeviceCode))
+	pipeline.Del(ctx, userCodeKey(auth.UserCode))
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if deleted.Val() == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrDeviceCodeNotFound)
+	}
+
+	return nil
+}
+
+func deviceCodeKey(deviceCode string) string {
+	return "device_code:" + deviceCode
+}
+
+func userCodeKey(userCode string) string {
+	return "user_code:" + userCode
+}
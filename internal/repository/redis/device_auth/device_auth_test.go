@@ -0,0 +1,96 @@
+package device_auth_repository
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_DeviceFlow(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := New(rdb)
+
+	auth := entities.DeviceAuthorization{
+		DeviceCode: uuid.NewString(),
+		UserCode:   uuid.NewString()[:8],
+		ClientId:   "tv",
+		Status:     consts.DeviceAuthorizationPending,
+		Interval:   5 * time.Second,
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+	err := r.SaveAuthorization(t.Context(), auth)
+	require.NoError(t, err)
+
+	err = r.SaveAuthorization(t.Context(), auth)
+	require.ErrorIs(t, err, errs.ErrUserCodeTaken)
+
+	got, err := r.AuthorizationByUserCode(t.Context(), auth.UserCode)
+	require.NoError(t, err)
+	require.Equal(t, auth.DeviceCode, got.DeviceCode)
+	require.Equal(t, auth.Interval, got.Interval)
+
+	err = r.RecordPoll(t.Context(), auth.DeviceCode, time.Now(), 10*time.Second)
+	require.NoError(t, err)
+
+	userId := uuid.NewString()
+	err = r.Decide(t.Context(), auth.DeviceCode, consts.DeviceAuthorizationApproved, userId)
+	require.NoError(t, err)
+
+	err = r.Decide(t.Context(), auth.DeviceCode, consts.DeviceAuthorizationDenied, userId)
+	require.ErrorIs(t, err, errs.ErrDeviceCodeNotFound)
+
+	got, err = r.AuthorizationByDeviceCode(t.Context(), auth.DeviceCode)
+	require.NoError(t, err)
+	require.Equal(t, consts.DeviceAuthorizationApproved, got.Status)
+	require.Equal(t, userId, got.UserId)
+	require.Equal(t, 10*time.Second, got.Interval)
+
+	err = r.DeleteAuthorization(t.Context(), got)
+	require.NoError(t, err)
+
+	err = r.DeleteAuthorization(t.Context(), got)
+	require.ErrorIs(t, err, errs.ErrDeviceCodeNotFound)
+
+	_, err = r.AuthorizationByUserCode(t.Context(), auth.UserCode)
+	require.ErrorIs(t, err, errs.ErrDeviceCodeNotFound)
+}
+
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) *redis.Client {
+	t.Helper()
+
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	err = rdb.Ping(t.Context()).Err()
+	require.NoError(t, err)
+
+	return rdb
+}
@@ -3,7 +3,6 @@ package session_repository
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
@@ -12,6 +11,46 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// A session is a hash under session:<id> that holds the id of its user as well,
+// the ids of the sessions of a user are kept in a set, so they are listed without a scan.
+
+var elevateScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "elevated_until", ARGV[1])
+return 1
+`)
+
+var touchScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "last_seen", ARGV[1])
+return 1
+`)
+
+// migrateScript moves a session from its legacy <id>:<user id> key. A legacy key without expiry
+// was written back by a touch after the session had gone, it is dropped.
+var migrateScript = redis.NewScript(`
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl == -2 then
+	return 0
+end
+if ttl == -1 then
+	redis.call("DEL", KEYS[1])
+	return 0
+end
+redis.call("RENAME", KEYS[1], KEYS[2])
+redis.call("HSET", KEYS[2], "user_id", ARGV[1])
+redis.call("SADD", KEYS[3], ARGV[2])
+redis.call("PEXPIRE", KEYS[3], ARGV[3])
+return 1
+`)
+
+// legacyPattern matches the <id>:<user id> keys sessions were stored under before.
+const legacyPattern = "????????-????-????-????-????????????:????????-????-????-????-????????????"
+
 type Repository struct {
 	rdb    *redis.Client
 	expire time.Duration
@@ -27,15 +66,44 @@ func New(rdb *redis.Client, expire time.Duration) *Repository {
 func (r *Repository) SaveSession(ctx context.Context, session entities.Session) error {
 	const op = "repository.redis.session.SaveSession"
 
-	key := genKey(session.ID, session.UserId)
-	pipeline := r.rdb.Pipeline()
+	err := r.SaveSessionTTL(ctx, session, r.expire)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveSessionTTL saves the session with a lifetime other than the default one.
+// The set of the user lives as long as its longest session.
+func (r *Repository) SaveSessionTTL(ctx context.Context, session entities.Session, ttl time.Duration) error {
+	const op = "repository.redis.session.SaveSessionTTL"
+
+	key := sessionKey(session.ID.String())
+	setKey := userKey(session.UserId)
+	pipeline := r.rdb.TxPipeline()
 
 	err := pipeline.HSet(ctx, key, session).Err()
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = pipeline.Expire(ctx, key, r.expire).Err()
+	err = pipeline.HSet(ctx, key, "user_id", session.UserId.String()).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = pipeline.Expire(ctx, key, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = pipeline.SAdd(ctx, setKey, session.ID.String()).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = pipeline.Expire(ctx, setKey, max(ttl, r.expire)).Err()
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -51,43 +119,245 @@ func (r *Repository) SaveSession(ctx context.Context, session entities.Session)
 func (r *Repository) SessionById(ctx context.Context, sessionId string) (entities.Session, error) {
 	const op = "repository.redis.session.SessionById"
 
-	var (
-		err    error
-		cursor uint64
-		keys   []string
-	)
-	//nolint
-	keys, cursor, err = r.rdb.Scan(ctx, cursor, sessionId+":*", 1).Result()
-	if err != nil {
-		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	cmd := r.rdb.HGetAll(ctx, sessionKey(sessionId))
+	if cmd.Err() != nil {
+		return entities.Session{}, fmt.Errorf("%s: %w", op, cmd.Err())
 	}
-	if len(keys) == 0 {
+	if len(cmd.Val()) == 0 {
+		revoked, err := r.rdb.Exists(ctx, revokedKey(sessionId)).Result()
+		if err != nil {
+			return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if revoked > 0 {
+			return entities.Session{}, fmt.Errorf("%s: %w", op, errs.ErrSessionRevoked)
+		}
 		return entities.Session{}, fmt.Errorf("%s: %w", op, errs.ErrSessionNotFound)
 	}
 
-	var session entities.Session
-	err = r.rdb.HGetAll(ctx, keys[0]).Scan(&session)
+	session, err := parseSession(sessionId, cmd)
 	if err != nil {
 		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	userId := strings.Split(keys[0], ":")[1]
+	return session, nil
+}
+
+// SessionsByUserId reads the sessions in the set of the user. Ids of sessions that expired
+// meanwhile are dropped from the set.
+func (r *Repository) SessionsByUserId(ctx context.Context, userId uuid.UUID) ([]entities.Session, error) {
+	const op = "repository.redis.session.SessionsByUserId"
 
-	id, err := uuid.Parse(sessionId)
+	ids, err := r.rdb.SMembers(ctx, userKey(userId)).Result()
 	if err != nil {
-		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(ids) == 0 {
+		return []entities.Session{}, nil
+	}
+
+	pipeline := r.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ids))
+	for _, id := range ids {
+		cmds = append(cmds, pipeline.HGetAll(ctx, sessionKey(id)))
 	}
-	uId, err := uuid.Parse(userId)
+	_, err = pipeline.Exec(ctx)
 	if err != nil {
-		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessions := make([]entities.Session, 0, len(ids))
+	var expired []any
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+
+		session, err := parseSession(ids[i], cmd)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		err = r.rdb.SRem(ctx, userKey(userId), expired...).Err()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	session.ID = id
-	session.UserId = uId
+	return sessions, nil
+}
+
+func (r *Repository) DeleteSessionsByUserId(ctx context.Context, userId uuid.UUID) error {
+	const op = "repository.redis.session.DeleteSessionsByUserId"
+
+	ids, err := r.rdb.SMembers(ctx, userKey(userId)).Result()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	keys = append(keys, userKey(userId))
+
+	err = r.rdb.Del(ctx, keys...).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteSession(ctx context.Context, id, userId uuid.UUID) error {
+	const op = "repository.redis.session.DeleteSession"
+
+	pipeline := r.rdb.TxPipeline()
+	deleted := pipeline.Del(ctx, sessionKey(id.String()))
+	pipeline.SRem(ctx, userKey(userId), id.String())
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if deleted.Val() == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// RevokeSession deletes the session and leaves a marker for its lifetime,
+// so the client gets session_revoked instead of a plain not found.
+func (r *Repository) RevokeSession(ctx context.Context, id, userId uuid.UUID) error {
+	const op = "repository.redis.session.RevokeSession"
+
+	pipeline := r.rdb.TxPipeline()
+	deleted := pipeline.Del(ctx, sessionKey(id.String()))
+	pipeline.SRem(ctx, userKey(userId), id.String())
+	pipeline.Set(ctx, revokedKey(id.String()), userId.String(), r.expire)
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if deleted.Val() == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// TouchSession updates when the session was last seen. It writes only to a session
+// that still exists, a touch racing with a logout must not bring the key back.
+func (r *Repository) TouchSession(ctx context.Context, id, userId uuid.UUID, lastSeen time.Time) error {
+	const op = "repository.redis.session.TouchSession"
+
+	updated, err := touchScript.Run(ctx, r.rdb, []string{sessionKey(id.String())}, lastSeen).Int()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// ElevateSession stamps the session with the time sensitive actions stay unlocked until.
+// The stamp is written only if the session still exists, so no key without expiry is left behind.
+func (r *Repository) ElevateSession(ctx context.Context, id, userId uuid.UUID, until time.Time) error {
+	const op = "repository.redis.session.ElevateSession"
+
+	updated, err := elevateScript.Run(ctx, r.rdb, []string{sessionKey(id.String())}, until).Int()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("%s: %w", op, errs.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// MigrateLegacySessions moves sessions stored under <id>:<user id> keys to the current layout,
+// so they survive the upgrade. It reports how many were moved.
+func (r *Repository) MigrateLegacySessions(ctx context.Context) (int, error) {
+	const op = "repository.redis.session.MigrateLegacySessions"
+
+	migrated := 0
+	iter := r.rdb.Scan(ctx, 0, legacyPattern, 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		id, userId, err := parseLegacyKey(key)
+		if err != nil {
+			continue
+		}
+
+		moved, err := migrateScript.Run(
+			ctx,
+			r.rdb,
+			[]string{key, sessionKey(id.String()), userKey(userId)},
+			userId.String(),
+			id.String(),
+			r.expire.Milliseconds(),
+		).Int()
+		if err != nil {
+			return migrated, fmt.Errorf("%s: %w", op, err)
+		}
+		migrated += moved
+	}
+	if err := iter.Err(); err != nil {
+		return migrated, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return migrated, nil
+}
+
+func parseSession(id string, cmd *redis.MapStringStringCmd) (entities.Session, error) {
+	var session entities.Session
+	err := cmd.Scan(&session)
+	if err != nil {
+		return entities.Session{}, err
+	}
+
+	session.ID, err = uuid.Parse(id)
+	if err != nil {
+		return entities.Session{}, err
+	}
+	session.UserId, err = uuid.Parse(cmd.Val()["user_id"])
+	if err != nil {
+		return entities.Session{}, err
+	}
 
 	return session, nil
 }
 
-func genKey(id uuid.UUID, userId uuid.UUID) string {
-	return id.String() + ":" + userId.String()
+func parseLegacyKey(key string) (uuid.UUID, uuid.UUID, error) {
+	id, err := uuid.Parse(key[:36])
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+	userId, err := uuid.Parse(key[37:])
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	return id, userId, nil
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userKey(userId uuid.UUID) string {
+	return "user_sessions:" + userId.String()
+}
+
+func revokedKey(sessionId string) string {
+	return "revoked:" + sessionId
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
@@ -65,82 +66,295 @@ func TestRepository_SaveSession(t *testing.T) {
 	}
 }
 
-// func TestRepository_SessionById(t *testing.T) {
-// 	type fields struct {
-// 		rdb    *redis.Client
-// 		expire time.Duration
-// 	}
-// 	type args struct {
-// 		ctx       context.Context
-// 		sessionId string
-// 	}
-
-// 	rdb := initRepository(t)
-// 	defer rdb.Close()
-
-// 	session := entities.Session{
-// 		ID:        uuid.New(),
-// 		UserId:    uuid.New(),
-// 		UserAgent: "firefox",
-// 		LastSeen:  time.Now(),
-// 	}
-
-// 	err := rdb.HSet(t.Context(), genKey(session.ID, session.UserId), session).Err()
-// 	require.NoError(t, err)
-
-// 	err = rdb.Expire(t.Context(), genKey(session.ID, session.UserId), 5*time.Minute).Err()
-// 	require.NoError(t, err)
-
-// 	time.Sleep(2 * time.Second)
-
-// 	tests := []struct {
-// 		name    string
-// 		fields  fields
-// 		args    args
-// 		want    entities.Session
-// 		wantErr error
-// 	}{
-// 		{
-// 			name: "good case",
-// 			fields: fields{
-// 				rdb:    rdb,
-// 				expire: 5 * time.Minute,
-// 			},
-// 			args: args{
-// 				ctx:       t.Context(),
-// 				sessionId: session.ID.String(),
-// 			},
-// 			want:    session,
-// 			wantErr: nil,
-// 		},
-// 		{
-// 			name: "not found case",
-// 			fields: fields{
-// 				rdb:    rdb,
-// 				expire: 5 * time.Minute,
-// 			},
-// 			args: args{
-// 				ctx:       t.Context(),
-// 				sessionId: "not existing id",
-// 			},
-// 			want:    entities.Session{},
-// 			wantErr: errs.ErrSessionNotFound,
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			r := &Repository{
-// 				rdb:    tt.fields.rdb,
-// 				expire: tt.fields.expire,
-// 			}
-// 			got, err := r.SessionById(tt.args.ctx, tt.args.sessionId)
-// 			require.ErrorIs(t, err, tt.wantErr)
-// 			if !reflect.DeepEqual(got, tt.want) {
-// 				t.Errorf("Repository.SessionById() = %v, want %v", got, tt.want)
-// 			}
-// 		})
-// 	}
-// }
+func TestRepository_DeleteSessionsByUserId(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	userId := uuid.New()
+	ids := make([]uuid.UUID, 0, 3)
+	for range 3 {
+		session := entities.Session{
+			ID:        uuid.New(),
+			UserId:    userId,
+			UserAgent: "chrome",
+			LastSeen:  time.Now(),
+		}
+		err := r.SaveSession(t.Context(), session)
+		require.NoError(t, err)
+		ids = append(ids, session.ID)
+	}
+
+	// other data keyed by the user is neither read nor deleted
+	otherKey := "counter:" + userId.String()
+	err := rdb.Set(t.Context(), otherKey, 1, time.Minute).Err()
+	require.NoError(t, err)
+
+	sessions, err := r.SessionsByUserId(t.Context(), userId)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	for _, session := range sessions {
+		require.Equal(t, userId, session.UserId)
+	}
+
+	// a session that expired is dropped from the set of the user
+	err = rdb.Del(t.Context(), sessionKey(ids[0].String())).Err()
+	require.NoError(t, err)
+
+	sessions, err = r.SessionsByUserId(t.Context(), userId)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	members, err := rdb.SCard(t.Context(), userKey(userId)).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, members)
+
+	err = r.DeleteSessionsByUserId(t.Context(), userId)
+	require.NoError(t, err)
+
+	sessions, err = r.SessionsByUserId(t.Context(), userId)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	exists, err := rdb.Exists(t.Context(), otherKey).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, exists)
+}
+
+func TestRepository_SessionById(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	// the lookup must not depend on how many other keys there are
+	for range 100 {
+		err := rdb.Set(t.Context(), "counter:"+uuid.NewString(), 1, time.Minute).Err()
+		require.NoError(t, err)
+	}
+
+	session := entities.Session{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		UserAgent: "chrome",
+		LastSeen:  time.Now().Truncate(time.Second),
+	}
+	err := r.SaveSession(t.Context(), session)
+	require.NoError(t, err)
+
+	got, err := r.SessionById(t.Context(), session.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, session.ID, got.ID)
+	require.Equal(t, session.UserId, got.UserId)
+	require.Equal(t, session.UserAgent, got.UserAgent)
+	require.True(t, session.LastSeen.Equal(got.LastSeen))
+}
+
+func TestRepository_RevokeSession(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	session := entities.Session{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		UserAgent: "chrome",
+		LastSeen:  time.Now(),
+	}
+	err := r.SaveSession(t.Context(), session)
+	require.NoError(t, err)
+
+	err = r.RevokeSession(t.Context(), session.ID, session.UserId)
+	require.NoError(t, err)
+
+	_, err = r.SessionById(t.Context(), session.ID.String())
+	require.ErrorIs(t, err, errs.ErrSessionRevoked)
+
+	_, err = r.SessionById(t.Context(), uuid.NewString())
+	require.ErrorIs(t, err, errs.ErrSessionNotFound)
+}
+
+func TestRepository_SaveSessionTTL(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	session := entities.Session{
+		ID:             uuid.New(),
+		UserId:         uuid.New(),
+		UserAgent:      "chrome",
+		LastSeen:       time.Now(),
+		ImpersonatorId: uuid.NewString(),
+	}
+	err := r.SaveSessionTTL(t.Context(), session, time.Minute)
+	require.NoError(t, err)
+
+	ttl, err := rdb.TTL(t.Context(), sessionKey(session.ID.String())).Result()
+	require.NoError(t, err)
+	require.LessOrEqual(t, ttl, time.Minute)
+
+	got, err := r.SessionById(t.Context(), session.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, session.ImpersonatorId, got.ImpersonatorId)
+}
+
+func TestRepository_ElevateSession(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	session := entities.Session{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		UserAgent: "chrome",
+		LastSeen:  time.Now(),
+	}
+	err := r.SaveSession(t.Context(), session)
+	require.NoError(t, err)
+
+	until := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	err = r.ElevateSession(t.Context(), session.ID, session.UserId, until)
+	require.NoError(t, err)
+
+	got, err := r.SessionById(t.Context(), session.ID.String())
+	require.NoError(t, err)
+	require.True(t, until.Equal(got.ElevatedUntil))
+
+	err = r.ElevateSession(t.Context(), uuid.New(), session.UserId, until)
+	require.ErrorIs(t, err, errs.ErrSessionNotFound)
+}
+
+func TestRepository_TouchSession(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	session := entities.Session{
+		ID:        uuid.New(),
+		UserId:    uuid.New(),
+		UserAgent: "chrome",
+		LastSeen:  time.Now().Add(-time.Hour),
+	}
+	err := r.SaveSession(t.Context(), session)
+	require.NoError(t, err)
+
+	lastSeen := time.Now().Truncate(time.Second)
+	err = r.TouchSession(t.Context(), session.ID, session.UserId, lastSeen)
+	require.NoError(t, err)
+
+	got, err := r.SessionById(t.Context(), session.ID.String())
+	require.NoError(t, err)
+	require.True(t, lastSeen.Equal(got.LastSeen))
+
+	ttl, err := rdb.TTL(t.Context(), sessionKey(session.ID.String())).Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, time.Duration(0))
+
+	// a deleted session is not brought back without an expiry
+	err = r.DeleteSession(t.Context(), session.ID, session.UserId)
+	require.NoError(t, err)
+
+	err = r.TouchSession(t.Context(), session.ID, session.UserId, lastSeen)
+	require.ErrorIs(t, err, errs.ErrSessionNotFound)
+
+	exists, err := rdb.Exists(t.Context(), sessionKey(session.ID.String())).Result()
+	require.NoError(t, err)
+	require.Zero(t, exists)
+}
+
+func TestRepository_MigrateLegacySessions(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := &Repository{
+		rdb:    rdb,
+		expire: 10 * time.Minute,
+	}
+
+	id, userId := uuid.New(), uuid.New()
+	legacyKey := id.String() + ":" + userId.String()
+	err := rdb.HSet(t.Context(), legacyKey, "user_agent", "chrome").Err()
+	require.NoError(t, err)
+	err = rdb.Expire(t.Context(), legacyKey, time.Minute).Err()
+	require.NoError(t, err)
+
+	// a key left behind by a touch after the session had gone has no expiry
+	staleKey := uuid.NewString() + ":" + userId.String()
+	err = rdb.HSet(t.Context(), staleKey, "last_seen", time.Now()).Err()
+	require.NoError(t, err)
+
+	migrated, err := r.MigrateLegacySessions(t.Context())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, migrated, 1)
+
+	got, err := r.SessionById(t.Context(), id.String())
+	require.NoError(t, err)
+	require.Equal(t, userId, got.UserId)
+	require.Equal(t, "chrome", got.UserAgent)
+
+	ttl, err := rdb.TTL(t.Context(), sessionKey(id.String())).Result()
+	require.NoError(t, err)
+	require.LessOrEqual(t, ttl, time.Minute)
+
+	sessions, err := r.SessionsByUserId(t.Context(), userId)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	exists, err := rdb.Exists(t.Context(), legacyKey, staleKey).Result()
+	require.NoError(t, err)
+	require.Zero(t, exists)
+}
 
 func isSkip(t *testing.T) {
 	t.Helper()
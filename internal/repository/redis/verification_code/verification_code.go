@@ -0,0 +1,94 @@
+package verification_code_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+// attemptScript counts a verification attempt and returns the code,
+// a missing or expired code is not recreated.
+var attemptScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+redis.call("HINCRBY", KEYS[1], "attempts", 1)
+return redis.call("HGETALL", KEYS[1])
+`)
+
+type Repository struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+func New(rdb *redis.Client, prefix string) *Repository {
+	return &Repository{
+		rdb:    rdb,
+		prefix: prefix,
+	}
+}
+
+// SaveCode replaces any previous code of the recipient and resets its attempts.
+func (r *Repository) SaveCode(ctx context.Context, code entities.VerificationCode, ttl time.Duration) error {
+	const op = "repository.redis.verification_code.SaveCode"
+
+	key := r.genKey(code.Recipient)
+	pipeline := r.rdb.TxPipeline()
+	pipeline.Del(ctx, key)
+	pipeline.HSet(ctx, key, code)
+	pipeline.Expire(ctx, key, ttl)
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RegisterAttempt increments the attempt counter of the recipient's code and returns the code.
+func (r *Repository) RegisterAttempt(ctx context.Context, recipient string) (entities.VerificationCode, error) {
+	const op = "repository.redis.verification_code.RegisterAttempt"
+
+	res, err := attemptScript.Run(ctx, r.rdb, []string{r.genKey(recipient)}).StringSlice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entities.VerificationCode{}, fmt.Errorf("%s: %w", op, errs.ErrCodeInvalid)
+		}
+		return entities.VerificationCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	fields := make(map[string]string, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		fields[res[i]] = res[i+1]
+	}
+
+	var code entities.VerificationCode
+	err = redis.NewMapStringStringResult(fields, nil).Scan(&code)
+	if err != nil {
+		return entities.VerificationCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+	code.Recipient = recipient
+
+	return code, nil
+}
+
+func (r *Repository) DeleteCode(ctx context.Context, recipient string) error {
+	const op = "repository.redis.verification_code.DeleteCode"
+
+	err := r.rdb.Del(ctx, r.genKey(recipient)).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) genKey(recipient string) string {
+	return r.prefix + ":" + recipient
+}
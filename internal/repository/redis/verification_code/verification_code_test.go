@@ -0,0 +1,82 @@
+package verification_code_repository
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_RegisterAttempt(t *testing.T) {
+	isSkip(t)
+
+	rdb := initRepository(t)
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	r := New(rdb, "verify_code")
+
+	code := entities.VerificationCode{
+		Recipient: uuid.NewString() + "@test.com",
+		UserId:    uuid.NewString(),
+		CodeHash:  "hash",
+	}
+	err := r.SaveCode(t.Context(), code, time.Minute)
+	require.NoError(t, err)
+
+	for i := range 2 {
+		got, err := r.RegisterAttempt(t.Context(), code.Recipient)
+		require.NoError(t, err)
+		require.Equal(t, code.UserId, got.UserId)
+		require.Equal(t, code.CodeHash, got.CodeHash)
+		require.Equal(t, int64(i+1), got.Attempts)
+	}
+
+	err = r.SaveCode(t.Context(), code, time.Minute)
+	require.NoError(t, err)
+	got, err := r.RegisterAttempt(t.Context(), code.Recipient)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.Attempts)
+
+	err = r.DeleteCode(t.Context(), code.Recipient)
+	require.NoError(t, err)
+
+	_, err = r.RegisterAttempt(t.Context(), code.Recipient)
+	require.ErrorIs(t, err, errs.ErrCodeInvalid)
+
+	exists, err := rdb.Exists(t.Context(), r.genKey(code.Recipient)).Result()
+	require.NoError(t, err)
+	require.Zero(t, exists)
+}
+func isSkip(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CI") != "" {
+		t.Skip("skiping in ci")
+	}
+}
+
+func initRepository(t *testing.T) *redis.Client {
+	t.Helper()
+
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	err = rdb.Ping(t.Context()).Err()
+	require.NoError(t, err)
+
+	return rdb
+}
@@ -0,0 +1,95 @@
+package impersonate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Impersonator interface {
+	Impersonate(ctx context.Context, userId, adminId uuid.UUID, userAgent string) (uuid.UUID, error)
+}
+
+// @Summary		impersonate user
+// @Description	open a short-lived session as the user to reproduce reported bugs, the session cookie replaces the current one
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path		string	true	"user id"
+// @Success		201	{object}	dtos.ImpersonationResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id}/impersonate [post]
+func New(impersonator Impersonator, sessionCfg config.SessionConfig, ttl time.Duration) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.impersonate.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		adminId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get admin id")
+			return api.Error("failed to get admin id", http.StatusUnauthorized)
+		}
+
+		sessionId, err := impersonator.Impersonate(ctx, userId, adminId, r.UserAgent())
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrUserSuspended) {
+				log.Error("user suspended", logger.Err(err))
+				return api.Error(errs.ErrUserSuspended.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrForbidden) {
+				log.Error("user can not be impersonated", logger.Err(err))
+				return api.Error("you can not impersonate yourself or another admin", http.StatusForbidden)
+			}
+
+			log.Error("failed to impersonate user", logger.Err(err))
+			return api.Error("failed to impersonate user", http.StatusInternalServerError)
+		}
+
+		cookie := &http.Cookie{
+			Name:     sessionCfg.Name,
+			Value:    sessionId.String(),
+			Path:     "/",
+			HttpOnly: sessionCfg.HttpOnly,
+			Secure:   sessionCfg.Secure,
+			SameSite: http.SameSiteStrictMode,
+			MaxAge:   int(ttl.Seconds()),
+		}
+		http.SetCookie(w, cookie)
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dtos.ImpersonationResponse{
+			SessionId: sessionId.String(),
+			UserId:    userId.String(),
+			ExpiresAt: time.Now().Add(ttl),
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package mail_queue
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type StatsProvider interface {
+	Stats(ctx context.Context) (entities.MailQueueStats, error)
+}
+
+// @Summary		mail queue stats
+// @Description	queue depth of outgoing mail, messages waiting for a retry and dead letters
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	dtos.MailQueueStatsResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/mail-queue [get]
+func New(statsProvider StatsProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.mail_queue.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		stats, err := statsProvider.Stats(ctx)
+		if err != nil {
+			log.Error("failed to get mail queue stats", logger.Err(err))
+			return api.Error("failed to get mail queue stats", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToMailQueueStatsResponse(stats))
+
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package reset_password
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type PasswordResetter interface {
+	TriggerPasswordReset(ctx context.Context, userId uuid.UUID) error
+}
+
+// @Summary		send password reset email to user
+// @Description	send password reset email to user
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path	string	true	"user id"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id}/reset-password [post]
+func New(passwordResetter PasswordResetter) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.reset_password.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		err = passwordResetter.TriggerPasswordReset(ctx, userId)
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to reset password", logger.Err(err))
+			return api.Error("failed to reset password", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package revoke_sessions
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type SessionRevoker interface {
+	RevokeSessions(ctx context.Context, userId uuid.UUID) error
+}
+
+// @Summary		revoke all user sessions
+// @Description	revoke all user sessions
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path	string	true	"user id"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id}/sessions [delete]
+func New(sessionRevoker SessionRevoker) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.revoke_sessions.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		err = sessionRevoker.RevokeSessions(ctx, userId)
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to revoke sessions", logger.Err(err))
+			return api.Error("failed to revoke sessions", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package search_users
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 20
+)
+
+type UserSearcher interface {
+	SearchUsers(ctx context.Context, req dtos.SearchUsersRequest) ([]entities.User, int64, error)
+}
+
+// @Summary		search users
+// @Description	search and paginate users by login or email
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			query	query		string	false	"part of login or email"
+// @Param			page	query		int		false	"page number, starts from 1"
+// @Param			limit	query		int		false	"page size, max 100"
+// @Success		200		{object}	dtos.SearchUsersResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		401		{object}	api.ErrorResponse
+// @Failure		403		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users [get]
+func New(userSearcher UserSearcher) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.search_users.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		req := dtos.SearchUsersRequest{
+			Query: r.URL.Query().Get("query"),
+		}
+
+		var err error
+		req.Page, err = api.QueryInt(r, "page", defaultPage)
+		if err != nil {
+			log.Error("failed to parse page", logger.Err(err))
+			return api.Error("failed to parse page", http.StatusBadRequest)
+		}
+		req.Limit, err = api.QueryInt(r, "limit", defaultLimit)
+		if err != nil {
+			log.Error("failed to parse limit", logger.Err(err))
+			return api.Error("failed to parse limit", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate request", logger.Err(err))
+			return api.Error("failed to validate request", http.StatusBadRequest)
+		}
+
+		users, total, err := userSearcher.SearchUsers(ctx, req)
+		if err != nil {
+			log.Error("failed to search users", logger.Err(err))
+			return api.Error("failed to search users", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToSearchUsersResponse(users, total, req.Page, req.Limit))
+
+		return nil
+	}
+}
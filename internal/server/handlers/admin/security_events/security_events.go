@@ -0,0 +1,100 @@
+package security_events
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 20
+)
+
+type EventsSearcher interface {
+	SearchEvents(ctx context.Context, req dtos.SearchAuditEventsRequest) ([]entities.AuditEvent, int64, error)
+}
+
+// @Summary		search security events
+// @Description	search security audit events of all users, newest first
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			user_id		query		string	false	"subject user id"
+// @Param			actor_id	query		string	false	"actor user id"
+// @Param			type		query		string	false	"event type"
+// @Param			from		query		string	false	"RFC 3339 lower bound, inclusive"
+// @Param			to			query		string	false	"RFC 3339 upper bound, exclusive"
+// @Param			page		query		int		false	"page number, starts from 1"
+// @Param			limit		query		int		false	"page size, max 100"
+// @Success		200			{object}	dtos.AuditEventsResponse
+// @Failure		400			{object}	api.ErrorResponse
+// @Failure		401			{object}	api.ErrorResponse
+// @Failure		403			{object}	api.ErrorResponse
+// @Failure		500			{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/security-events [get]
+func New(eventsSearcher EventsSearcher) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.security_events.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		query := r.URL.Query()
+		req := dtos.SearchAuditEventsRequest{
+			UserId:  query.Get("user_id"),
+			ActorId: query.Get("actor_id"),
+			Type:    query.Get("type"),
+		}
+
+		var err error
+		req.Page, err = api.QueryInt(r, "page", defaultPage)
+		if err != nil {
+			log.Error("failed to parse page", logger.Err(err))
+			return api.Error("failed to parse page", http.StatusBadRequest)
+		}
+		req.Limit, err = api.QueryInt(r, "limit", defaultLimit)
+		if err != nil {
+			log.Error("failed to parse limit", logger.Err(err))
+			return api.Error("failed to parse limit", http.StatusBadRequest)
+		}
+		if from := query.Get("from"); from != "" {
+			t, err := time.Parse(time.RFC3339, from)
+			if err != nil {
+				log.Error("failed to parse from", logger.Err(err))
+				return api.Error("failed to parse from", http.StatusBadRequest)
+			}
+			req.From = &t
+		}
+		if to := query.Get("to"); to != "" {
+			t, err := time.Parse(time.RFC3339, to)
+			if err != nil {
+				log.Error("failed to parse to", logger.Err(err))
+				return api.Error("failed to parse to", http.StatusBadRequest)
+			}
+			req.To = &t
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate request", logger.Err(err))
+			return api.Error("failed to validate request", http.StatusBadRequest)
+		}
+
+		events, total, err := eventsSearcher.SearchEvents(ctx, req)
+		if err != nil {
+			log.Error("failed to search security events", logger.Err(err))
+			return api.Error("failed to search security events", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToAuditEventsResponse(events, total, req.Page, req.Limit))
+
+		return nil
+	}
+}
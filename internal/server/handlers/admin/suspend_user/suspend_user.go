@@ -0,0 +1,86 @@
+package suspend_user
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type UserSuspender interface {
+	SuspendUser(ctx context.Context, userId, adminId uuid.UUID, req dtos.SuspendUserRequest) error
+}
+
+// @Summary		suspend or ban user
+// @Description	suspend or ban user with a reason and optional expiry, revokes all user sessions
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path	string					true	"user id"
+// @Param			req	body	dtos.SuspendUserRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id}/suspension [post]
+func New(userSuspender UserSuspender) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.suspend_user.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		adminId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get admin id")
+			return api.Error("failed to get admin id", http.StatusUnauthorized)
+		}
+
+		var req dtos.SuspendUserRequest
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = userSuspender.SuspendUser(ctx, userId, adminId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrForbidden) {
+				log.Error("admin tried to suspend own account", logger.Err(err))
+				return api.Error("you can not suspend yourself", http.StatusForbidden)
+			}
+
+			log.Error("failed to suspend user", logger.Err(err))
+			return api.Error("failed to suspend user", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package suspend_user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuspendUser_New(t *testing.T) {
+	cases := []struct {
+		name             string
+		userId           string
+		body             string
+		respStatus       int
+		respMessage      string
+		wantSuspendError error
+	}{
+		{
+			name:             "good case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "suspend", "reason": "spam"}`,
+			respStatus:       http.StatusNoContent,
+			respMessage:      "",
+			wantSuspendError: nil,
+		},
+		{
+			name:             "invalid user id case",
+			userId:           "not uuid",
+			body:             `{"type": "suspend", "reason": "spam"}`,
+			respStatus:       http.StatusBadRequest,
+			respMessage:      "failed to parse user id",
+			wantSuspendError: nil,
+		},
+		{
+			name:             "invalid type case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "mute", "reason": "spam"}`,
+			respStatus:       http.StatusBadRequest,
+			respMessage:      "failed to validate body",
+			wantSuspendError: nil,
+		},
+		{
+			name:             "expiry in the past case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "ban", "reason": "spam", "expires_at": "2000-01-01T00:00:00Z"}`,
+			respStatus:       http.StatusBadRequest,
+			respMessage:      "failed to validate body",
+			wantSuspendError: nil,
+		},
+		{
+			name:             "user not found case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "ban", "reason": "spam"}`,
+			respStatus:       http.StatusNotFound,
+			respMessage:      errs.ErrUserNotFound.Error(),
+			wantSuspendError: errs.ErrUserNotFound,
+		},
+		{
+			name:             "suspend yourself case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "ban", "reason": "spam"}`,
+			respStatus:       http.StatusForbidden,
+			respMessage:      "you can not suspend yourself",
+			wantSuspendError: errs.ErrForbidden,
+		},
+		{
+			name:             "suspend error case",
+			userId:           uuid.NewString(),
+			body:             `{"type": "ban", "reason": "spam"}`,
+			respStatus:       http.StatusInternalServerError,
+			respMessage:      "failed to suspend user",
+			wantSuspendError: errors.New("some error"),
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mSuspender := NewMockUserSuspender(t)
+
+			mSuspender.EXPECT().SuspendUser(
+				mock.AnythingOfType("*context.valueCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("dtos.SuspendUserRequest"),
+			).Return(tt.wantSuspendError).Maybe()
+
+			handler := api.ErrorWrapper(New(mSuspender))
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/users/"+tt.userId+"/suspension", bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+			req.SetPathValue("id", tt.userId)
+			//nolint:staticcheck
+			req = req.WithContext(context.WithValue(req.Context(), consts.ContextUserId, uuid.New()))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.respStatus, rr.Code)
+
+			if tt.respStatus >= 400 {
+				var resp api.ErrorResponse
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tt.respMessage, resp.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package user_details
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type UserDetailsProvider interface {
+	UserDetails(ctx context.Context, userId uuid.UUID) (entities.User, []entities.Session, error)
+}
+
+// @Summary		get user details
+// @Description	get user with verification status, suspension and sessions
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path		string	true	"user id"
+// @Success		200	{object}	dtos.AdminUserDetailsResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id} [get]
+func New(userDetailsProvider UserDetailsProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.user_details.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		user, sessions, err := userDetailsProvider.UserDetails(ctx, userId)
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to get user details", logger.Err(err))
+			return api.Error("failed to get user details", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToAdminUserDetailsResponse(user, sessions))
+
+		return nil
+	}
+}
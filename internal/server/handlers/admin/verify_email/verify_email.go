@@ -0,0 +1,65 @@
+package verify_email
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type EmailVerifier interface {
+	ForceVerifyEmail(ctx context.Context, userId uuid.UUID) error
+}
+
+// @Summary		force verify user email
+// @Description	force verify user email
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			id	path	string	true	"user id"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/admin/users/{id}/verify-email [post]
+func New(emailVerifier EmailVerifier) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.verify_email.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			log.Error("failed to parse user id", logger.Err(err))
+			return api.Error("failed to parse user id", http.StatusBadRequest)
+		}
+
+		err = emailVerifier.ForceVerifyEmail(ctx, userId)
+		if err != nil {
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrChannelLoginTaken) {
+				log.Error("channel login taken", logger.Err(err))
+				return api.Error(errs.ErrChannelLoginTaken.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to verify email", logger.Err(err))
+			return api.Error("failed to verify email", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
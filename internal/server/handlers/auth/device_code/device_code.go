@@ -0,0 +1,66 @@
+package device_code
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type CodeRequester interface {
+	RequestCode(ctx context.Context, req dtos.DeviceCodeRequest) (entities.DeviceAuthorization, error)
+	VerificationURI() string
+	VerificationURIComplete(userCode string) string
+}
+
+// @Summary		request device code
+// @Description	start the device authorization flow (RFC 8628) for TV and console apps
+// @Tags			auth
+// @Accept			x-www-form-urlencoded
+// @Produce		json
+// @Param			client_id	formData	string	true	"client id"
+// @Success		200			{object}	dtos.DeviceCodeResponse
+// @Failure		400			{object}	api.ErrorResponse
+// @Failure		500			{object}	api.ErrorResponse
+// @Router			/auth/device/code [post]
+func New(codeRequester CodeRequester) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.device_code.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		err := r.ParseForm()
+		if err != nil {
+			log.Error("failed to parse form", logger.Err(err))
+			return api.Error("invalid_request", http.StatusBadRequest)
+		}
+
+		req := dtos.DeviceCodeRequest{
+			ClientId: r.PostForm.Get("client_id"),
+		}
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("invalid_request", http.StatusBadRequest)
+		}
+
+		auth, err := codeRequester.RequestCode(ctx, req)
+		if err != nil {
+			log.Error("failed to request device code", logger.Err(err))
+			return api.Error("failed to request device code", http.StatusInternalServerError)
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		render.JSON(w, r, dtos.ToDeviceCodeResponse(
+			auth,
+			codeRequester.VerificationURI(),
+			codeRequester.VerificationURIComplete(auth.UserCode),
+		))
+
+		return nil
+	}
+}
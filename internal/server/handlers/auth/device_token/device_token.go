@@ -0,0 +1,103 @@
+package device_token
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type TokenPoller interface {
+	PollToken(ctx context.Context, req dtos.DeviceTokenRequest, userAgent string) (uuid.UUID, error)
+}
+
+// oauthErrors are reported to the device as is, see RFC 8628 section 3.5.
+var oauthErrors = []error{
+	errs.ErrAuthorizationPending,
+	errs.ErrSlowDown,
+	errs.ErrAccessDenied,
+	errs.ErrExpiredToken,
+	errs.ErrInvalidGrant,
+	errs.ErrUnsupportedGrantType,
+}
+
+// @Summary		poll device token
+// @Description	poll until the user approves the device code, the device then gets a session of its own
+// @Tags			auth
+// @Accept			x-www-form-urlencoded
+// @Produce		json
+// @Param			grant_type	formData	string	true	"urn:ietf:params:oauth:grant-type:device_code"
+// @Param			device_code	formData	string	true	"device code"
+// @Param			client_id	formData	string	true	"client id"
+// @Success		200			{object}	dtos.DeviceTokenResponse
+// @Failure		400			{object}	api.ErrorResponse
+// @Failure		409			{object}	api.ErrorResponse
+// @Failure		500			{object}	api.ErrorResponse
+// @Router			/auth/device/token [post]
+func New(tokenPoller TokenPoller, sessionCfg config.SessionConfig) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.device_token.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		err := r.ParseForm()
+		if err != nil {
+			log.Error("failed to parse form", logger.Err(err))
+			return api.Error("invalid_request", http.StatusBadRequest)
+		}
+
+		req := dtos.DeviceTokenRequest{
+			GrantType:  r.PostForm.Get("grant_type"),
+			DeviceCode: r.PostForm.Get("device_code"),
+			ClientId:   r.PostForm.Get("client_id"),
+		}
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("invalid_request", http.StatusBadRequest)
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		sessionId, err := tokenPoller.PollToken(ctx, req, r.UserAgent())
+		if err != nil {
+			for _, oauthErr := range oauthErrors {
+				if errors.Is(err, oauthErr) {
+					log.Info("device token not issued", logger.Err(err))
+					return api.Error(oauthErr.Error(), http.StatusBadRequest)
+				}
+			}
+			if errors.Is(err, errs.ErrSessionLimit) {
+				log.Error("session limit exceeded", logger.Err(err))
+				return api.Error(errs.ErrSessionLimit.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to issue device token", logger.Err(err))
+			return api.Error("failed to issue device token", http.StatusInternalServerError)
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCfg.Name,
+			Value:    sessionId.String(),
+			Path:     "/",
+			HttpOnly: sessionCfg.HttpOnly,
+			Secure:   sessionCfg.Secure,
+			SameSite: http.SameSiteStrictMode,
+			MaxAge:   sessionCfg.MaxAge,
+		})
+		render.JSON(w, r, dtos.DeviceTokenResponse{
+			AccessToken: sessionId.String(),
+			TokenType:   "session",
+			ExpiresIn:   sessionCfg.MaxAge,
+		})
+
+		return nil
+	}
+}
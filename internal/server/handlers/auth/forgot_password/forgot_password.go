@@ -0,0 +1,55 @@
+package forgot_password
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type PasswordForgetter interface {
+	ForgotPassword(ctx context.Context, req dtos.ForgotPasswordRequest) error
+}
+
+// @Summary		forgot password
+// @Description	send a password reset email, the answer is the same whether or not the account exists
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.ForgotPasswordRequest	true	"request"
+// @Success		202
+// @Failure		400	{object}	api.ErrorResponse
+// @Router			/auth/forgot-password [post]
+func New(passwordForgetter PasswordForgetter) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.forgot_password.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		var req dtos.ForgotPasswordRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		// the service hides whether the account exists, an error here is an internal one only
+		err = passwordForgetter.ForgotPassword(ctx, req)
+		if err != nil {
+			log.Error("failed to handle forgot password", logger.Err(err))
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/AlexMickh/twitch-clone/internal/config"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
 	"github.com/AlexMickh/twitch-clone/pkg/api"
 	"github.com/AlexMickh/twitch-clone/pkg/logger"
@@ -15,19 +16,21 @@ import (
 )
 
 type Loginer interface {
-	Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, error)
+	Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, entities.User, error)
 }
 
 // @Summary		login user
-// @Description	login user
+// @Description	login user. email_update_required asks the client to prompt for a new email: the current one bounced or complained
 // @Tags			auth
 // @Accept			json
 // @Produce		json
-// @Param			req	body	dtos.LoginRequest	true	"request"
-// @Success		201
+// @Param			req	body		dtos.LoginRequest	true	"request"
+// @Success		201	{object}	dtos.LoginResponse
 // @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
 // @Failure		403	{object}	api.ErrorResponse
 // @Failure		404	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
 // @Failure		500	{object}	api.ErrorResponse
 // @Router			/auth/login [post]
 func New(loginer Loginer, sessionCfg config.SessionConfig) api.HandlerFunc {
@@ -48,8 +51,12 @@ func New(loginer Loginer, sessionCfg config.SessionConfig) api.HandlerFunc {
 			return api.Error("failed to validate body", http.StatusBadRequest)
 		}
 
-		sessionId, err := loginer.Login(ctx, req, r.UserAgent())
+		sessionId, user, err := loginer.Login(ctx, req, r.UserAgent())
 		if err != nil {
+			if errors.Is(err, errs.ErrInvalidCredentials) {
+				log.Error("invalid credentials", logger.Err(err))
+				return api.Error(errs.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+			}
 			if errors.Is(err, errs.ErrUserNotFound) {
 				log.Error("user not found", logger.Err(err))
 				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
@@ -58,6 +65,14 @@ func New(loginer Loginer, sessionCfg config.SessionConfig) api.HandlerFunc {
 				log.Error("email not verify", logger.Err(err))
 				return api.Error(errs.ErrUserEmailNotVerify.Error(), http.StatusForbidden)
 			}
+			if errors.Is(err, errs.ErrUserSuspended) {
+				log.Error("user suspended", logger.Err(err))
+				return api.Error(errs.ErrUserSuspended.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrSessionLimit) {
+				log.Error("session limit exceeded", logger.Err(err))
+				return api.Error(errs.ErrSessionLimit.Error(), http.StatusConflict)
+			}
 
 			log.Error("failed to login user", logger.Err(err))
 			return api.Error("failed to login user", http.StatusInternalServerError)
@@ -73,7 +88,8 @@ func New(loginer Loginer, sessionCfg config.SessionConfig) api.HandlerFunc {
 			MaxAge:   sessionCfg.MaxAge,
 		}
 		http.SetCookie(w, cookie)
-		w.WriteHeader(http.StatusCreated)
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dtos.ToLoginResponse(user))
 
 		return nil
 	}
@@ -10,6 +10,9 @@ import (
 	"testing"
 
 	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
 	"github.com/AlexMickh/twitch-clone/pkg/api"
 	mock "github.com/stretchr/testify/mock"
@@ -23,6 +26,7 @@ func TestLogin_New(t *testing.T) {
 		password       string
 		respStatus     int
 		respMessage    string
+		suppression    *entities.EmailSuppression
 		wantLoginError error
 	}{
 		{
@@ -33,6 +37,13 @@ func TestLogin_New(t *testing.T) {
 			respMessage:    "",
 			wantLoginError: nil,
 		},
+		{
+			name:        "bounced email case",
+			email:       "test@test.com",
+			password:    "qwerty",
+			respStatus:  http.StatusCreated,
+			suppression: &entities.EmailSuppression{Type: consts.MailEventHardBounce},
+		},
 		{
 			name:           "invalid request case",
 			email:          "test@test.com",
@@ -73,6 +84,22 @@ func TestLogin_New(t *testing.T) {
 			respMessage:    errs.ErrUserEmailNotVerify.Error(),
 			wantLoginError: errs.ErrUserEmailNotVerify,
 		},
+		{
+			name:           "user suspended case",
+			email:          "test@test.com",
+			password:       "qwerty",
+			respStatus:     http.StatusForbidden,
+			respMessage:    errs.ErrUserSuspended.Error(),
+			wantLoginError: errs.ErrUserSuspended,
+		},
+		{
+			name:           "invalid credentials case",
+			email:          "test@test.com",
+			password:       "qwerty",
+			respStatus:     http.StatusUnauthorized,
+			respMessage:    errs.ErrInvalidCredentials.Error(),
+			wantLoginError: errs.ErrInvalidCredentials,
+		},
 		{
 			name:           "login error case",
 			email:          "test@test.com",
@@ -93,7 +120,7 @@ func TestLogin_New(t *testing.T) {
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("dtos.LoginRequest"),
 				mock.AnythingOfType("string"),
-			).Return("some id", tt.wantLoginError).Maybe()
+			).Return("some id", entities.User{EmailSuppression: tt.suppression}, tt.wantLoginError).Maybe()
 
 			handler := api.ErrorWrapper(New(mLogin, config.SessionConfig{
 				Name:     "session",
@@ -118,7 +145,13 @@ func TestLogin_New(t *testing.T) {
 				require.NoError(t, err)
 
 				require.Equal(t, tt.respMessage, resp.Error)
+				return
 			}
+
+			var resp dtos.LoginResponse
+			err = json.NewDecoder(rr.Body).Decode(&resp)
+			require.NoError(t, err)
+			require.Equal(t, tt.suppression != nil, resp.EmailUpdateRequired)
 		})
 	}
 }
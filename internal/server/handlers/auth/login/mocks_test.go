@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -39,7 +40,7 @@ func (_m *MockLoginer) EXPECT() *MockLoginer_Expecter {
 }
 
 // Login provides a mock function for the type MockLoginer
-func (_mock *MockLoginer) Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, error) {
+func (_mock *MockLoginer) Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, entities.User, error) {
 	ret := _mock.Called(ctx, req, userAgent)
 
 	if len(ret) == 0 {
@@ -47,8 +48,9 @@ func (_mock *MockLoginer) Login(ctx context.Context, req dtos.LoginRequest, user
 	}
 
 	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, dtos.LoginRequest, string) (string, error)); ok {
+	var r1 entities.User
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dtos.LoginRequest, string) (string, entities.User, error)); ok {
 		return returnFunc(ctx, req, userAgent)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, dtos.LoginRequest, string) string); ok {
@@ -56,12 +58,17 @@ func (_mock *MockLoginer) Login(ctx context.Context, req dtos.LoginRequest, user
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, dtos.LoginRequest, string) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, dtos.LoginRequest, string) entities.User); ok {
 		r1 = returnFunc(ctx, req, userAgent)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(entities.User)
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, dtos.LoginRequest, string) error); ok {
+		r2 = returnFunc(ctx, req, userAgent)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
 // MockLoginer_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
@@ -100,12 +107,12 @@ func (_c *MockLoginer_Login_Call) Run(run func(ctx context.Context, req dtos.Log
 	return _c
 }
 
-func (_c *MockLoginer_Login_Call) Return(s string, err error) *MockLoginer_Login_Call {
-	_c.Call.Return(s, err)
+func (_c *MockLoginer_Login_Call) Return(s string, user entities.User, err error) *MockLoginer_Login_Call {
+	_c.Call.Return(s, user, err)
 	return _c
 }
 
-func (_c *MockLoginer_Login_Call) RunAndReturn(run func(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, error)) *MockLoginer_Login_Call {
+func (_c *MockLoginer_Login_Call) RunAndReturn(run func(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, entities.User, error)) *MockLoginer_Login_Call {
 	_c.Call.Return(run)
 	return _c
 }
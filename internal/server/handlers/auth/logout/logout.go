@@ -0,0 +1,67 @@
+package logout
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+type Logouter interface {
+	Logout(ctx context.Context, sessionId string) error
+}
+
+// @Summary		logout user
+// @Description	delete current session
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Success		204
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/auth/logout [post]
+func New(logouter Logouter, sessionCfg config.SessionConfig) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.logout.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		sessionId, ok := ctx.Value(consts.ContextSessionId).(string)
+		if !ok {
+			log.Error("failed to get session id")
+			return api.Error("failed to get session", http.StatusUnauthorized)
+		}
+
+		err := logouter.Logout(ctx, sessionId)
+		if err != nil {
+			if errors.Is(err, errs.ErrSessionNotFound) {
+				log.Error("session not found", logger.Err(err))
+				return api.Error(errs.ErrSessionNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to logout user", logger.Err(err))
+			return api.Error("failed to logout user", http.StatusInternalServerError)
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCfg.Name,
+			Value:    "",
+			Path:     "/",
+			HttpOnly: sessionCfg.HttpOnly,
+			Secure:   sessionCfg.Secure,
+			SameSite: http.SameSiteStrictMode,
+			MaxAge:   -1,
+		})
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
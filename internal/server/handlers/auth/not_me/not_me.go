@@ -0,0 +1,56 @@
+package not_me
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+type NotMeReporter interface {
+	NotMe(ctx context.Context, token string) error
+}
+
+// @Summary		report unknown sign-in
+// @Description	revoke the session from a new device alert and send a password reset email
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			token	path	string	true	"token from the new device alert"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Router			/auth/not-me/{token} [post]
+func New(reporter NotMeReporter) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.not_me.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		token := r.PathValue("token")
+		if token == "" {
+			log.Error("token is empty")
+			return api.Error("token is required", http.StatusBadRequest)
+		}
+
+		err := reporter.NotMe(ctx, token)
+		if err != nil {
+			if errors.Is(err, errs.ErrTokenNotFound) {
+				log.Error("token not found", logger.Err(err))
+				return api.Error(errs.ErrTokenNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to revoke session", logger.Err(err))
+			return api.Error("failed to revoke session", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
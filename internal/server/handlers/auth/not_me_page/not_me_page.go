@@ -0,0 +1,92 @@
+package not_me_page
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+type Localizer interface {
+	Translate(locale, key string, args ...any) string
+}
+
+// page posts back to its own URL, so the token never has to be put into it
+var page = template.Must(template.New("not_me").Parse(`<!doctype html>
+<html lang="{{.Locale}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p id="text">{{.Text}}</p>
+<form id="form" method="post">
+<button type="submit">{{.Button}}</button>
+</form>
+<script>
+document.getElementById("form").addEventListener("submit", function (e) {
+	e.preventDefault();
+	e.target.hidden = true;
+	fetch(location.href, {method: "POST"}).then(function (resp) {
+		document.getElementById("text").textContent = resp.ok ? {{.Done}} : {{.Failed}};
+	});
+});
+</script>
+</body>
+</html>
+`))
+
+type pageData struct {
+	Locale string
+	Title  string
+	Text   string
+	Button string
+	Done   string
+	Failed string
+}
+
+// @Summary		confirm unknown sign-in
+// @Description	the page the "it wasn't me" link of a new device alert opens, it changes nothing.
+// @Description	Confirming posts to the same URL, so link scanners of mail providers can not revoke the session.
+// @Tags			auth
+// @Produce		html
+// @Param			token	path	string	true	"token from the new device alert"
+// @Success		200
+// @Router			/auth/not-me/{token} [get]
+func New(localizer Localizer) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.not_me_page.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		locale, _ := ctx.Value(consts.ContextLocale).(string)
+		data := pageData{
+			Locale: locale,
+			Title:  localizer.Translate(locale, "page.not_me.title"),
+			Text:   localizer.Translate(locale, "page.not_me.text"),
+			Button: localizer.Translate(locale, "page.not_me.button"),
+			Done:   localizer.Translate(locale, "page.not_me.done"),
+			Failed: localizer.Translate(locale, "page.not_me.failed"),
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		// framing would let another site trick the user into confirming
+		w.Header().Set(
+			"Content-Security-Policy",
+			"default-src 'none'; script-src 'unsafe-inline'; connect-src 'self'; form-action 'self'; frame-ancestors 'none'",
+		)
+
+		if err := page.Execute(w, data); err != nil {
+			log.Error("failed to render page", logger.Err(err))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package not_me_page
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+// testLocalizer returns the key with characters that have to be escaped.
+type testLocalizer struct{}
+
+func (testLocalizer) Translate(locale, key string, _ ...any) string {
+	return locale + ":" + key + ` <"x">`
+}
+
+func TestNotMePage_New(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/auth/not-me/token", nil)
+	//nolint:staticcheck
+	req = req.WithContext(context.WithValue(req.Context(), consts.ContextLocale, "ru"))
+	w := httptest.NewRecorder()
+	api.ErrorWrapper(New(testLocalizer{})).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'")
+
+	body := w.Body.String()
+	require.Contains(t, body, `<html lang="ru">`)
+	require.Contains(t, body, `<form id="form" method="post">`)
+	require.Contains(t, body, "ru:page.not_me.button &lt;&#34;x&#34;&gt;")
+	// the token is not repeated in the page
+	require.NotContains(t, body, "token")
+}
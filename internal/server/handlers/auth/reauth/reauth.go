@@ -0,0 +1,86 @@
+package reauth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Reauthenticator interface {
+	Reauthenticate(ctx context.Context, userId uuid.UUID, sessionId string, req dtos.ReauthRequest) (time.Time, error)
+}
+
+// @Summary		re-authenticate user
+// @Description	check the password again and unlock sensitive actions for the current session for a short time
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			req	body		dtos.ReauthRequest	true	"request"
+// @Success		200	{object}	dtos.ReauthResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/auth/reauth [post]
+func New(reauthenticator Reauthenticator) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.auth.reauth.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+		sessionId, ok := ctx.Value(consts.ContextSessionId).(string)
+		if !ok {
+			log.Error("failed to get session id")
+			return api.Error("failed to get session", http.StatusUnauthorized)
+		}
+
+		var req dtos.ReauthRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		until, err := reauthenticator.Reauthenticate(ctx, userId, sessionId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrInvalidCredentials) {
+				log.Error("invalid credentials", logger.Err(err))
+				return api.Error(errs.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+			}
+			if errors.Is(err, errs.ErrSessionNotFound) {
+				log.Error("session not found", logger.Err(err))
+				return api.Error(errs.ErrSessionNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to re-authenticate user", logger.Err(err))
+			return api.Error("failed to re-authenticate user", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ReauthResponse{
+			ElevatedUntil: until,
+		})
+
+		return nil
+	}
+}
@@ -25,6 +25,9 @@ type Registerer interface {
 // @Param			req	body		dtos.RegisterRequest	true	"request"
 // @Success		201	{object}	dtos.RegisterResponse
 // @Failure		400	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		429	{object}	api.ErrorResponse
 // @Failure		500	{object}	api.ErrorResponse
 // @Router			/auth/register [post]
 func New(registerer Registerer) api.HandlerFunc {
@@ -51,6 +54,34 @@ func New(registerer Registerer) api.HandlerFunc {
 				log.Error("user already exists", logger.Err(err))
 				return api.Error("user already exists", http.StatusBadRequest)
 			}
+			if errors.Is(err, errs.ErrLoginTaken) {
+				log.Error("login taken", logger.Err(err))
+				return api.Error(errs.ErrLoginTaken.Error(), http.StatusConflict)
+			}
+			if errors.Is(err, errs.ErrRegistrationClosed) {
+				log.Error("registration closed", logger.Err(err))
+				return api.Error(errs.ErrRegistrationClosed.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrInviteInvalid) {
+				log.Error("invalid invite", logger.Err(err))
+				return api.Error(errs.ErrInviteInvalid.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrDisposableEmail) {
+				log.Error("disposable email", logger.Err(err))
+				return api.Error(errs.ErrDisposableEmail.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrReservedLogin) {
+				log.Error("reserved login", logger.Err(err))
+				return api.Error(errs.ErrReservedLogin.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrCaptchaInvalid) {
+				log.Error("captcha invalid", logger.Err(err))
+				return api.Error(errs.ErrCaptchaInvalid.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrSignupVelocity) {
+				log.Error("too many signups", logger.Err(err))
+				return api.Error(errs.ErrSignupVelocity.Error(), http.StatusTooManyRequests)
+			}
 
 			log.Error("failed to register user", logger.Err(err))
 			return api.Error("failed to register user", http.StatusInternalServerError)
@@ -100,6 +100,42 @@ func TestRegister_New(t *testing.T) {
 			wantRegisterError:  errs.ErrUserAlreadyExists,
 			wantRegisterReturn: "",
 		},
+		{
+			name: "login taken case",
+			req: dtos.RegisterRequest{
+				Login:    "Test",
+				Email:    "other@test.com",
+				Password: "test",
+			},
+			respStatus:         http.StatusConflict,
+			respMessage:        errs.ErrLoginTaken.Error(),
+			wantRegisterError:  errs.ErrLoginTaken,
+			wantRegisterReturn: "",
+		},
+		{
+			name: "reserved login case",
+			req: dtos.RegisterRequest{
+				Login:    "admin",
+				Email:    "test@test.com",
+				Password: "test",
+			},
+			respStatus:         http.StatusBadRequest,
+			respMessage:        errs.ErrReservedLogin.Error(),
+			wantRegisterError:  errs.ErrReservedLogin,
+			wantRegisterReturn: "",
+		},
+		{
+			name: "signup velocity case",
+			req: dtos.RegisterRequest{
+				Login:    "test",
+				Email:    "test@test.com",
+				Password: "test",
+			},
+			respStatus:         http.StatusTooManyRequests,
+			respMessage:        errs.ErrSignupVelocity.Error(),
+			wantRegisterError:  errs.ErrSignupVelocity,
+			wantRegisterReturn: "",
+		},
 		{
 			name: "register error case",
 			req: dtos.RegisterRequest{
@@ -0,0 +1,58 @@
+package channel_by_login
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type ChannelProvider interface {
+	ChannelByLogin(ctx context.Context, login string) (entities.Channel, error)
+}
+
+// @Summary		get channel
+// @Description	get the public channel of a user by login, case insensitive
+// @Tags			channel
+// @Produce		json
+// @Param			login	path		string	true	"channel login"
+// @Success		200		{object}	dtos.ChannelResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		404		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Router			/channels/{login} [get]
+func New(channelProvider ChannelProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.channel.channel_by_login.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		login := r.PathValue("login")
+		if login == "" {
+			log.Error("login is empty")
+			return api.Error("login is required", http.StatusBadRequest)
+		}
+
+		channel, err := channelProvider.ChannelByLogin(ctx, login)
+		if err != nil {
+			if errors.Is(err, errs.ErrChannelNotFound) {
+				log.Error("channel not found", logger.Err(err))
+				return api.Error(errs.ErrChannelNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to get channel", logger.Err(err))
+			return api.Error("failed to get channel", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToChannelResponse(channel))
+
+		return nil
+	}
+}
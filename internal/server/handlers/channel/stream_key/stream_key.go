@@ -0,0 +1,63 @@
+package stream_key
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type StreamKeyRevealer interface {
+	StreamKey(ctx context.Context, userId uuid.UUID) (string, error)
+}
+
+// @Summary		reveal my stream key
+// @Description	show the stream key of the current user's channel, needs a recent re-authentication
+// @Tags			channel
+// @Produce		json
+// @Success		200	{object}	dtos.StreamKeyResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/channels/me/stream-key [get]
+func New(streamKeyRevealer StreamKeyRevealer) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.channel.stream_key.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		key, err := streamKeyRevealer.StreamKey(ctx, userId)
+		if err != nil {
+			if errors.Is(err, errs.ErrChannelNotFound) {
+				log.Error("channel not found", logger.Err(err))
+				return api.Error(errs.ErrChannelNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to get stream key", logger.Err(err))
+			return api.Error("failed to get stream key", http.StatusInternalServerError)
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		render.JSON(w, r, dtos.StreamKeyResponse{
+			StreamKey: key,
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package update_channel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type ChannelUpdater interface {
+	UpdateChannel(ctx context.Context, userId uuid.UUID, req dtos.UpdateChannelRequest) (entities.Channel, error)
+}
+
+// @Summary		update my channel
+// @Description	change the settings of the channel of the current user, omitted fields keep their values
+// @Tags			channel
+// @Accept			json
+// @Produce		json
+// @Param			req	body		dtos.UpdateChannelRequest	true	"request"
+// @Success		200	{object}	dtos.ChannelResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/channels/me [patch]
+func New(channelUpdater ChannelUpdater) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.channel.update_channel.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.UpdateChannelRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		channel, err := channelUpdater.UpdateChannel(ctx, userId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrChannelNotFound) {
+				log.Error("channel not found", logger.Err(err))
+				return api.Error(errs.ErrChannelNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to update channel", logger.Err(err))
+			return api.Error("failed to update channel", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToChannelResponse(channel))
+
+		return nil
+	}
+}
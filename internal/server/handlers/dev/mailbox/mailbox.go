@@ -0,0 +1,32 @@
+package mailbox
+
+import (
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/go-chi/render"
+)
+
+type Mailbox interface {
+	Messages(to string) []email.CapturedMessage
+}
+
+// @Summary		list captured emails
+// @Description	list emails captured by the memory mail transport, newest first. Only served in local and dev envs
+// @Tags			dev
+// @Accept			json
+// @Produce		json
+// @Param			to	query		string	false	"recipient filter"
+// @Success		200	{object}	dtos.MailboxResponse
+// @Router			/dev/mailbox [get]
+func New(mailbox Mailbox) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		messages := mailbox.Messages(r.URL.Query().Get("to"))
+
+		render.JSON(w, r, dtos.ToMailboxResponse(messages))
+
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package activate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Activator interface {
+	Activate(ctx context.Context, userId uuid.UUID, req dtos.ActivateDeviceRequest) error
+}
+
+// @Summary		activate device
+// @Description	approve or deny the TV or console showing the user code, needs a recent re-authentication
+// @Tags			device
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.ActivateDeviceRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/activate [post]
+func New(activator Activator) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.device.activate.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.ActivateDeviceRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = activator.Activate(ctx, userId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrDeviceCodeNotFound) {
+				log.Error("device code not found", logger.Err(err))
+				return api.Error(errs.ErrDeviceCodeNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to activate device", logger.Err(err))
+			return api.Error("failed to activate device", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
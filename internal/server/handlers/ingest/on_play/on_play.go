@@ -0,0 +1,70 @@
+package on_play
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/ingesthook"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type Player interface {
+	Play(ctx context.Context, req dtos.IngestCallbackRequest) error
+}
+
+// @Summary		ingest play callback
+// @Description	nginx-rtmp on_play (form) and SRS on_play and on_stop (json, shown below). The stream name is the
+// @Description	channel login, only live channels can be played. Pass the shared secret as a bearer token or as the token query parameter.
+// @Tags			ingest
+// @Accept			json,x-www-form-urlencoded
+// @Produce		json
+// @Param			token	query		string					false	"shared secret"
+// @Param			req		body		dtos.SRSCallbackRequest	false	"SRS format"
+// @Success		200		{object}	dtos.IngestCallbackResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		401		{object}	api.ErrorResponse
+// @Failure		403		{object}	api.ErrorResponse
+// @Failure		404		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Router			/ingest/callbacks/on_play [post]
+func New(player Player) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.ingest.on_play.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		req, err := ingesthook.Parse(r)
+		if err != nil {
+			log.Error("invalid callback", logger.Err(err))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+		if req.Call != "play" && req.Call != "play_done" {
+			log.Error("unexpected callback", slog.String("call", req.Call))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+
+		if err := player.Play(ctx, req); err != nil {
+			if errors.Is(err, errs.ErrChannelNotFound) {
+				log.Error("channel not found", logger.Err(err))
+				return api.Error(errs.ErrChannelNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrChannelOffline) {
+				log.Error("channel offline", logger.Err(err))
+				return api.Error(errs.ErrChannelOffline.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to check playback", logger.Err(err))
+			return api.Error("failed to check playback", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.IngestCallbackResponse{})
+
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package on_publish
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/ingesthook"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, req dtos.IngestCallbackRequest) (string, error)
+}
+
+// @Summary		ingest publish callback
+// @Description	nginx-rtmp on_publish (form) and SRS on_publish (json, shown below). The stream key is the
+// @Description	stream name or the key query parameter of the publish URL. nginx-rtmp streams published under the
+// @Description	key are redirected to the channel login. Pass the shared secret as a bearer token or as the token query parameter.
+// @Tags			ingest
+// @Accept			json,x-www-form-urlencoded
+// @Produce		json
+// @Param			token	query		string					false	"shared secret"
+// @Param			req		body		dtos.SRSCallbackRequest	false	"SRS format"
+// @Success		200		{object}	dtos.IngestCallbackResponse
+// @Success		302
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Router			/ingest/callbacks/on_publish [post]
+func New(publisher Publisher) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.ingest.on_publish.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		req, err := ingesthook.Parse(r)
+		if err != nil {
+			log.Error("invalid callback", logger.Err(err))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+		if req.Call != "publish" {
+			log.Error("unexpected callback", slog.String("call", req.Call))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+
+		login, err := publisher.Publish(ctx, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrStreamKeyInvalid) {
+				log.Error("invalid stream key", logger.Err(err))
+				return api.Error(errs.ErrStreamKeyInvalid.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrUserSuspended) {
+				log.Error("user suspended", logger.Err(err))
+				return api.Error(errs.ErrUserSuspended.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrStreamNameInvalid) {
+				log.Error("invalid stream name", logger.Err(err))
+				return api.Error(errs.ErrStreamNameInvalid.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrAlreadyLive) {
+				log.Error("channel already live", logger.Err(err))
+				return api.Error(errs.ErrAlreadyLive.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to start broadcast", logger.Err(err))
+			return api.Error("failed to start broadcast", http.StatusInternalServerError)
+		}
+
+		// nginx-rtmp renames the stream to a Location that is not an rtmp:// URL,
+		// http.Redirect would make it relative to the callback path
+		if req.Server == consts.IngestServerNginx && req.Name != login {
+			w.Header().Set("Location", login)
+			w.WriteHeader(http.StatusFound)
+			return nil
+		}
+
+		render.JSON(w, r, dtos.IngestCallbackResponse{})
+
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package on_publish
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnPublish_New(t *testing.T) {
+	const (
+		nginxBody = "call=publish&addr=10.0.0.5&clientid=7&app=live&name=live_key&type=live"
+		srsBody   = `{"action":"on_publish","client_id":"7","ip":"10.0.0.5","app":"live","stream":"streamer","param":"?key=live_key"}`
+	)
+
+	tests := []struct {
+		name         string
+		contentType  string
+		body         string
+		wantCall     bool
+		wantErr      error
+		wantStatus   int
+		wantLocation string
+		respMessage  string
+	}{
+		{
+			name:         "nginx redirect case",
+			contentType:  "application/x-www-form-urlencoded",
+			body:         nginxBody,
+			wantCall:     true,
+			wantStatus:   http.StatusFound,
+			wantLocation: "streamer",
+		},
+		{
+			name:        "srs case",
+			contentType: "application/json",
+			body:        srsBody,
+			wantCall:    true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "invalid key case",
+			contentType: "application/x-www-form-urlencoded",
+			body:        nginxBody,
+			wantCall:    true,
+			wantErr:     errs.ErrStreamKeyInvalid,
+			wantStatus:  http.StatusForbidden,
+			respMessage: errs.ErrStreamKeyInvalid.Error(),
+		},
+		{
+			name:        "already live case",
+			contentType: "application/json",
+			body:        srsBody,
+			wantCall:    true,
+			wantErr:     errs.ErrAlreadyLive,
+			wantStatus:  http.StatusConflict,
+			respMessage: errs.ErrAlreadyLive.Error(),
+		},
+		{
+			name:        "wrong call case",
+			contentType: "application/x-www-form-urlencoded",
+			body:        strings.Replace(nginxBody, "call=publish", "call=play", 1),
+			wantStatus:  http.StatusBadRequest,
+			respMessage: errs.ErrIngestCallback.Error(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mPublisher := NewMockPublisher(t)
+			if tt.wantCall {
+				mPublisher.EXPECT().Publish(
+					mock.Anything,
+					mock.AnythingOfType("dtos.IngestCallbackRequest"),
+				).Return("streamer", tt.wantErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/ingest/callbacks/on_publish", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			api.ErrorWrapper(New(mPublisher))(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			require.Equal(t, tt.wantLocation, rec.Header().Get("Location"))
+			if tt.respMessage != "" {
+				require.Contains(t, rec.Body.String(), tt.respMessage)
+			}
+			if tt.wantStatus == http.StatusOK {
+				require.JSONEq(t, `{"code": 0}`, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package on_publish_done
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/ingesthook"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type PublishEnder interface {
+	PublishDone(ctx context.Context, req dtos.IngestCallbackRequest) error
+}
+
+// @Summary		ingest publish done callback
+// @Description	nginx-rtmp on_publish_done (form) and SRS on_unpublish (json, shown below), marks the channel offline.
+// @Description	Pass the shared secret as a bearer token or as the token query parameter.
+// @Tags			ingest
+// @Accept			json,x-www-form-urlencoded
+// @Produce		json
+// @Param			token	query		string					false	"shared secret"
+// @Param			req		body		dtos.SRSCallbackRequest	false	"SRS format"
+// @Success		200		{object}	dtos.IngestCallbackResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		401		{object}	api.ErrorResponse
+// @Failure		403		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Router			/ingest/callbacks/on_publish_done [post]
+func New(publishEnder PublishEnder) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.ingest.on_publish_done.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		req, err := ingesthook.Parse(r)
+		if err != nil {
+			log.Error("invalid callback", logger.Err(err))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+		if req.Call != "publish_done" {
+			log.Error("unexpected callback", slog.String("call", req.Call))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+
+		if err := publishEnder.PublishDone(ctx, req); err != nil {
+			log.Error("failed to end broadcast", logger.Err(err))
+			return api.Error("failed to end broadcast", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.IngestCallbackResponse{})
+
+		return nil
+	}
+}
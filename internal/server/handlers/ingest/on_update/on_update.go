@@ -0,0 +1,82 @@
+package on_update
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/ingesthook"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type Updater interface {
+	Update(ctx context.Context, req dtos.IngestCallbackRequest) error
+}
+
+// @Summary		ingest update callback
+// @Description	nginx-rtmp on_update, sent every notify_update_timeout while a stream is published or played.
+// @Description	A failure drops the client, this is how broadcasts end after a stream key reset or a suspension.
+// @Description	Pass the shared secret as a bearer token or as the token query parameter.
+// @Tags			ingest
+// @Accept			x-www-form-urlencoded
+// @Produce		json
+// @Param			token	query		string	false	"shared secret"
+// @Success		200		{object}	dtos.IngestCallbackResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		401		{object}	api.ErrorResponse
+// @Failure		403		{object}	api.ErrorResponse
+// @Failure		404		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Router			/ingest/callbacks/on_update [post]
+func New(updater Updater) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.ingest.on_update.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		req, err := ingesthook.Parse(r)
+		if err != nil {
+			log.Error("invalid callback", logger.Err(err))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+		if req.Call != "update_publish" && req.Call != "update_play" {
+			log.Error("unexpected callback", slog.String("call", req.Call))
+			return api.Error(errs.ErrIngestCallback.Error(), http.StatusBadRequest)
+		}
+
+		if err := updater.Update(ctx, req); err != nil {
+			if errors.Is(err, errs.ErrStreamKeyInvalid) {
+				log.Error("stream key was reset", logger.Err(err))
+				return api.Error(errs.ErrStreamKeyInvalid.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrUserSuspended) {
+				log.Error("user suspended", logger.Err(err))
+				return api.Error(errs.ErrUserSuspended.Error(), http.StatusForbidden)
+			}
+			if errors.Is(err, errs.ErrBroadcastNotFound) {
+				log.Error("broadcast not found", logger.Err(err))
+				return api.Error(errs.ErrBroadcastNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrChannelNotFound) {
+				log.Error("channel not found", logger.Err(err))
+				return api.Error(errs.ErrChannelNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrChannelOffline) {
+				log.Error("channel offline", logger.Err(err))
+				return api.Error(errs.ErrChannelOffline.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to update broadcast", logger.Err(err))
+			return api.Error("failed to update broadcast", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.IngestCallbackResponse{})
+
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package create_invite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type InviteCreator interface {
+	CreateInvite(ctx context.Context, userId uuid.UUID, role string, req dtos.CreateInviteRequest) (entities.Invite, error)
+}
+
+// @Summary		create invite code
+// @Description	create invite code for the invite-only registration, regular users are limited by quota.
+// @Description	Needs a recent re-authentication.
+// @Tags			invite
+// @Accept			json
+// @Produce		json
+// @Param			req	body		dtos.CreateInviteRequest	true	"request"
+// @Success		201	{object}	dtos.InviteResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/invites [post]
+func New(inviteCreator InviteCreator) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.invite.create_invite.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+		role, _ := ctx.Value(consts.ContextUserRole).(string)
+
+		var req dtos.CreateInviteRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		invite, err := inviteCreator.CreateInvite(ctx, userId, role, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrInviteQuota) {
+				log.Error("invite quota exceeded", logger.Err(err))
+				return api.Error(errs.ErrInviteQuota.Error(), http.StatusForbidden)
+			}
+
+			log.Error("failed to create invite", logger.Err(err))
+			return api.Error("failed to create invite", http.StatusInternalServerError)
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dtos.ToInviteResponse(invite))
+
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package my_invites
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type InviteProvider interface {
+	InvitesByCreator(ctx context.Context, userId uuid.UUID) ([]entities.Invite, error)
+}
+
+// @Summary		list my invite codes
+// @Description	list invite codes created by the current user with their redemptions
+// @Tags			invite
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	dtos.InvitesResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/invites [get]
+func New(inviteProvider InviteProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.invite.my_invites.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		invites, err := inviteProvider.InvitesByCreator(ctx, userId)
+		if err != nil {
+			log.Error("failed to get invites", logger.Err(err))
+			return api.Error("failed to get invites", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToInvitesResponse(invites))
+
+		return nil
+	}
+}
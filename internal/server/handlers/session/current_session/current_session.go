@@ -53,10 +53,13 @@ func New(sessionProvider SessionProvider, sessionCfg config.SessionConfig) api.H
 			return api.Error("failed to get session", http.StatusInternalServerError)
 		}
 
-		cookie.MaxAge = sessionCfg.MaxAge
-		http.SetCookie(w, cookie)
+		// impersonation sessions keep their short lifetime
+		if session.ImpersonatorId == "" {
+			cookie.MaxAge = sessionCfg.MaxAge
+			http.SetCookie(w, cookie)
+		}
 
-		render.JSON(w, r, dtos.ToCurrentSessionResponse(session.ID, session.UserId, session.UserAgent))
+		render.JSON(w, r, dtos.ToCurrentSessionResponse(session))
 
 		return nil
 	}
@@ -0,0 +1,67 @@
+package change_email
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type EmailChanger interface {
+	RequestEmailChange(ctx context.Context, userId uuid.UUID, req dtos.ChangeEmailRequest) error
+}
+
+// @Summary		change email
+// @Description	send a verification link to the new address of the logged in user, requires a recent re-authentication. The email is changed once the link is followed
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.ChangeEmailRequest	true	"request"
+// @Success		202
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/email [post]
+func New(emailChanger EmailChanger) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.change_email.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.ChangeEmailRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = emailChanger.RequestEmailChange(ctx, userId, req)
+		if err != nil {
+			log.Error("failed to request email change", logger.Err(err))
+			return api.Error("failed to request email change", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package change_password
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type PasswordChanger interface {
+	ChangePassword(ctx context.Context, userId uuid.UUID, req dtos.ChangePasswordRequest) error
+}
+
+// @Summary		change password
+// @Description	change password of the logged in user, requires a recent re-authentication and signs out all sessions
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.ChangePasswordRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/change-password [post]
+func New(passwordChanger PasswordChanger, sessionCfg config.SessionConfig) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.change_password.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.ChangePasswordRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = passwordChanger.ChangePassword(ctx, userId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrImpersonation) {
+				log.Error("password change while impersonating", logger.Err(err))
+				return api.Error(errs.ErrImpersonation.Error(), http.StatusForbidden)
+			}
+
+			log.Error("failed to change password", logger.Err(err))
+			return api.Error("failed to change password", http.StatusInternalServerError)
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCfg.Name,
+			Value:    "",
+			Path:     "/",
+			HttpOnly: sessionCfg.HttpOnly,
+			Secure:   sessionCfg.Secure,
+			SameSite: http.SameSiteStrictMode,
+			MaxAge:   -1,
+		})
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
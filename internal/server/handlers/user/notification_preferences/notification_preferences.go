@@ -0,0 +1,51 @@
+package notification_preferences
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type PreferencesProvider interface {
+	Preferences(ctx context.Context, userId uuid.UUID) (dtos.NotificationPreferencesResponse, error)
+}
+
+// @Summary		get notification preferences
+// @Description	get which email categories the user receives, security email is always on
+// @Tags			user
+// @Produce		json
+// @Success		200	{object}	dtos.NotificationPreferencesResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/notifications [get]
+func New(preferencesProvider PreferencesProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.notification_preferences.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		preferences, err := preferencesProvider.Preferences(ctx, userId)
+		if err != nil {
+			log.Error("failed to get notification preferences", logger.Err(err))
+			return api.Error("failed to get notification preferences", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, preferences)
+
+		return nil
+	}
+}
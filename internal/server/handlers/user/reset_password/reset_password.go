@@ -0,0 +1,68 @@
+package reset_password
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type PasswordResetter interface {
+	ResetPassword(ctx context.Context, req dtos.ResetPasswordRequest) error
+}
+
+// @Summary		reset password
+// @Description	set a new password using the token from the password reset email, revokes all user sessions
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.ResetPasswordRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Router			/user/reset-password [post]
+func New(passwordResetter PasswordResetter) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.reset_password.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		var req dtos.ResetPasswordRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = passwordResetter.ResetPassword(ctx, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrTokenNotFound) {
+				log.Error("token not found", logger.Err(err))
+				return api.Error(errs.ErrTokenNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+
+			log.Error("failed to reset password", logger.Err(err))
+			return api.Error("failed to reset password", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
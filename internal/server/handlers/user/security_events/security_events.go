@@ -0,0 +1,81 @@
+package security_events
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 20
+)
+
+type EventsProvider interface {
+	UserEvents(ctx context.Context, userId uuid.UUID, req dtos.PageRequest) ([]entities.AuditEvent, int64, error)
+}
+
+// @Summary		get own security events
+// @Description	get security audit events of the current user, newest first
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			page	query		int	false	"page number, starts from 1"
+// @Param			limit	query		int	false	"page size, max 100"
+// @Success		200		{object}	dtos.AuditEventsResponse
+// @Failure		400		{object}	api.ErrorResponse
+// @Failure		401		{object}	api.ErrorResponse
+// @Failure		500		{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/security-events [get]
+func New(eventsProvider EventsProvider) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.security_events.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var (
+			req dtos.PageRequest
+			err error
+		)
+		req.Page, err = api.QueryInt(r, "page", defaultPage)
+		if err != nil {
+			log.Error("failed to parse page", logger.Err(err))
+			return api.Error("failed to parse page", http.StatusBadRequest)
+		}
+		req.Limit, err = api.QueryInt(r, "limit", defaultLimit)
+		if err != nil {
+			log.Error("failed to parse limit", logger.Err(err))
+			return api.Error("failed to parse limit", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate request", logger.Err(err))
+			return api.Error("failed to validate request", http.StatusBadRequest)
+		}
+
+		events, total, err := eventsProvider.UserEvents(ctx, userId, req)
+		if err != nil {
+			log.Error("failed to get security events", logger.Err(err))
+			return api.Error("failed to get security events", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.ToAuditEventsResponse(events, total, req.Page, req.Limit))
+
+		return nil
+	}
+}
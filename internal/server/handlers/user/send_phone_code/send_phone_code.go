@@ -0,0 +1,80 @@
+package send_phone_code
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type PhoneCodeSender interface {
+	SendCode(ctx context.Context, userId uuid.UUID, req dtos.SendPhoneCodeRequest) error
+}
+
+// @Summary		send phone verification code
+// @Description	text a six-digit verification code to the phone number in E.164 format
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.SendPhoneCodeRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		429	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/phone [post]
+func New(phoneCodeSender PhoneCodeSender) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.send_phone_code.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.SendPhoneCodeRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = phoneCodeSender.SendCode(ctx, userId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrPhoneRateLimited) {
+				log.Error("phone code rate limited", logger.Err(err))
+				return api.Error(errs.ErrPhoneRateLimited.Error(), http.StatusTooManyRequests)
+			}
+			if errors.Is(err, errs.ErrPhoneTaken) {
+				log.Error("phone number taken", logger.Err(err))
+				return api.Error(errs.ErrPhoneTaken.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to send phone code", logger.Err(err))
+			return api.Error("failed to send phone code", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
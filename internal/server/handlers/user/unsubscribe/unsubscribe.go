@@ -0,0 +1,55 @@
+package unsubscribe
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+)
+
+type Unsubscriber interface {
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+// @Summary		unsubscribe from an email category
+// @Description	RFC 8058 one-click unsubscribe, the token comes from the List-Unsubscribe header and needs no session
+// @Tags			user
+// @Accept			x-www-form-urlencoded
+// @Produce		json
+// @Param			token	path	string	true	"token from the List-Unsubscribe header"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Router			/unsubscribe/{token} [post]
+func New(unsubscriber Unsubscriber) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.unsubscribe.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		token := r.PathValue("token")
+		if token == "" {
+			log.Error("token is empty")
+			return api.Error("token is required", http.StatusBadRequest)
+		}
+
+		err := unsubscriber.Unsubscribe(ctx, token)
+		if err != nil {
+			if errors.Is(err, errs.ErrUnsubscribeToken) {
+				log.Error("invalid unsubscribe token", logger.Err(err))
+				return api.Error(errs.ErrUnsubscribeToken.Error(), http.StatusBadRequest)
+			}
+
+			log.Error("failed to unsubscribe", logger.Err(err))
+			return api.Error("failed to unsubscribe", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package update_notification_preferences
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type PreferencesUpdater interface {
+	UpdatePreferences(
+		ctx context.Context,
+		userId uuid.UUID,
+		req dtos.UpdateNotificationPreferencesRequest,
+	) (dtos.NotificationPreferencesResponse, error)
+}
+
+// @Summary		update notification preferences
+// @Description	turn email categories on or off, omitted categories keep their values
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body		dtos.UpdateNotificationPreferencesRequest	true	"request"
+// @Success		200	{object}	dtos.NotificationPreferencesResponse
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/notifications [put]
+func New(preferencesUpdater PreferencesUpdater) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.update_notification_preferences.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.UpdateNotificationPreferencesRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		preferences, err := preferencesUpdater.UpdatePreferences(ctx, userId, req)
+		if err != nil {
+			log.Error("failed to update notification preferences", logger.Err(err))
+			return api.Error("failed to update notification preferences", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, preferences)
+
+		return nil
+	}
+}
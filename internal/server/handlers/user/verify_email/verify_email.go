@@ -16,7 +16,7 @@ type EmailVerifier interface {
 }
 
 // @Summary		verify user email
-// @Description	verify user email
+// @Description	verify user email, a link sent on an email change switches the account to the new address
 // @Tags			user
 // @Accept			json
 // @Produce		json
@@ -24,6 +24,7 @@ type EmailVerifier interface {
 // @Success		204
 // @Failure		400	{object}	api.ErrorResponse
 // @Failure		404	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
 // @Failure		500	{object}	api.ErrorResponse
 // @Router			/user/verify-email/{token} [get]
 func New(emailVerifier EmailVerifier) api.HandlerFunc {
@@ -48,12 +49,23 @@ func New(emailVerifier EmailVerifier) api.HandlerFunc {
 				log.Error("token not found", logger.Err(err))
 				return api.Error(errs.ErrTokenNotFound.Error(), http.StatusNotFound)
 			}
-		}
-		if err != nil {
 			if errors.Is(err, errs.ErrUserNotFound) {
 				log.Error("user not found", logger.Err(err))
 				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
 			}
+			if errors.Is(err, errs.ErrChannelLoginTaken) {
+				log.Error("channel login taken", logger.Err(err))
+				return api.Error(errs.ErrChannelLoginTaken.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to verify email", logger.Err(err))
+			return api.Error("failed to verify email", http.StatusInternalServerError)
+		}
+		if err != nil {
+			if errors.Is(err, errs.ErrUserAlreadyExists) {
+				log.Error("new email is taken", logger.Err(err))
+				return api.Error(errs.ErrUserAlreadyExists.Error(), http.StatusConflict)
+			}
 		}
 
 		w.WriteHeader(http.StatusNoContent)
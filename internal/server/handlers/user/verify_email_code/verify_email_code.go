@@ -0,0 +1,78 @@
+package verify_email_code
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+type EmailCodeVerifier interface {
+	VerifyEmailCode(ctx context.Context, req dtos.VerifyEmailCodeRequest) error
+}
+
+// @Summary		verify user email with code
+// @Description	verify user email with the six-digit code sent at registration
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.VerifyEmailCodeRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		404	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		429	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Router			/user/verify-email/code [post]
+func New(emailCodeVerifier EmailCodeVerifier) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.verify_email_code.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		var req dtos.VerifyEmailCodeRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = emailCodeVerifier.VerifyEmailCode(ctx, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrCodeInvalid) {
+				log.Error("invalid code", logger.Err(err))
+				return api.Error(errs.ErrCodeInvalid.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrCodeAttempts) {
+				log.Error("code attempts exceeded", logger.Err(err))
+				return api.Error(errs.ErrCodeAttempts.Error(), http.StatusTooManyRequests)
+			}
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Error("user not found", logger.Err(err))
+				return api.Error(errs.ErrUserNotFound.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrChannelLoginTaken) {
+				log.Error("channel login taken", logger.Err(err))
+				return api.Error(errs.ErrChannelLoginTaken.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to verify email", logger.Err(err))
+			return api.Error("failed to verify email", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package verify_phone
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type PhoneVerifier interface {
+	VerifyCode(ctx context.Context, userId uuid.UUID, req dtos.VerifyPhoneRequest) error
+}
+
+// @Summary		verify phone number
+// @Description	verify the phone number with the code sent by SMS
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			req	body	dtos.VerifyPhoneRequest	true	"request"
+// @Success		204
+// @Failure		400	{object}	api.ErrorResponse
+// @Failure		401	{object}	api.ErrorResponse
+// @Failure		403	{object}	api.ErrorResponse
+// @Failure		409	{object}	api.ErrorResponse
+// @Failure		429	{object}	api.ErrorResponse
+// @Failure		500	{object}	api.ErrorResponse
+// @Security		SessionAuth
+// @Router			/user/phone/verify [post]
+func New(phoneVerifier PhoneVerifier) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.user.verify_phone.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		userId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			log.Error("failed to get user id")
+			return api.Error("failed to get user id", http.StatusUnauthorized)
+		}
+
+		var req dtos.VerifyPhoneRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode body", logger.Err(err))
+			return api.Error("failed to decode body", http.StatusBadRequest)
+		}
+
+		if err = req.Validate(); err != nil {
+			log.Error("failed to validate body", logger.Err(err))
+			return api.Error("failed to validate body", http.StatusBadRequest)
+		}
+
+		err = phoneVerifier.VerifyCode(ctx, userId, req)
+		if err != nil {
+			if errors.Is(err, errs.ErrCodeInvalid) {
+				log.Error("invalid code", logger.Err(err))
+				return api.Error(errs.ErrCodeInvalid.Error(), http.StatusBadRequest)
+			}
+			if errors.Is(err, errs.ErrCodeAttempts) {
+				log.Error("code attempts exceeded", logger.Err(err))
+				return api.Error(errs.ErrCodeAttempts.Error(), http.StatusTooManyRequests)
+			}
+			if errors.Is(err, errs.ErrPhoneTaken) {
+				log.Error("phone number taken", logger.Err(err))
+				return api.Error(errs.ErrPhoneTaken.Error(), http.StatusConflict)
+			}
+
+			log.Error("failed to verify phone", logger.Err(err))
+			return api.Error("failed to verify phone", http.StatusInternalServerError)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package mail_events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/render"
+)
+
+// maxBodySize caps a webhook batch, providers send at most a few hundred events at once
+const maxBodySize = 1 << 20
+
+type EventHandler interface {
+	HandleEvents(ctx context.Context, provider string, body []byte) (int, int, error)
+}
+
+// @Summary		mail provider events
+// @Description	bounce and complaint webhook. provider is generic, ses, sendgrid or mailgun, the body is the provider format;
+// @Description	the generic one is shown below. Hard-bounced and complained addresses only get critical mail afterwards.
+// @Description	Pass the shared secret as a bearer token or as the token query parameter.
+// @Tags			webhook
+// @Accept			json
+// @Produce		json
+// @Param			provider	path		string					true	"provider"
+// @Param			token		query		string					false	"shared secret"
+// @Param			req			body		dtos.MailEventsRequest	false	"generic format"
+// @Success		200			{object}	dtos.MailEventsResponse
+// @Failure		400			{object}	api.ErrorResponse
+// @Failure		401			{object}	api.ErrorResponse
+// @Failure		404			{object}	api.ErrorResponse
+// @Failure		500			{object}	api.ErrorResponse
+// @Router			/webhooks/mail/{provider} [post]
+func New(eventHandler EventHandler) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhook.mail_events.New"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			log.Error("failed to read body", logger.Err(err))
+			return api.Error("failed to read body", http.StatusBadRequest)
+		}
+
+		received, suppressed, err := eventHandler.HandleEvents(ctx, r.PathValue("provider"), body)
+		if err != nil {
+			if errors.Is(err, errs.ErrMailProvider) {
+				log.Error("unknown mail provider", logger.Err(err))
+				return api.Error(errs.ErrMailProvider.Error(), http.StatusNotFound)
+			}
+			if errors.Is(err, errs.ErrMailEventsInvalid) {
+				log.Error("invalid mail events", logger.Err(err))
+				return api.Error(errs.ErrMailEventsInvalid.Error(), http.StatusBadRequest)
+			}
+
+			log.Error("failed to handle mail events", logger.Err(err))
+			return api.Error("failed to handle mail events", http.StatusInternalServerError)
+		}
+
+		render.JSON(w, r, dtos.MailEventsResponse{
+			Received:   received,
+			Suppressed: suppressed,
+		})
+
+		return nil
+	}
+}
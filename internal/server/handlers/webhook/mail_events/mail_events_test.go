@@ -0,0 +1,80 @@
+package mail_events
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/mailevents"
+	"github.com/AlexMickh/twitch-clone/internal/server/middlewares"
+	"github.com/AlexMickh/twitch-clone/pkg/api"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMailEvents_New drives the webhook with the fake provider, as a real one would call it.
+func TestMailEvents_New(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		provider   string
+		wantCall   bool
+		wantErr    error
+		wantStatus int
+	}{
+		{
+			name:     "good case",
+			secret:   "secret",
+			provider: consts.MailProviderSES,
+			wantCall: true,
+		},
+		{
+			name:       "wrong secret case",
+			secret:     "wrong",
+			provider:   consts.MailProviderSES,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid events case",
+			secret:     "secret",
+			provider:   consts.MailProviderGeneric,
+			wantCall:   true,
+			wantErr:    errs.ErrMailEventsInvalid,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mHandler := NewMockEventHandler(t)
+			if tt.wantCall {
+				mHandler.EXPECT().HandleEvents(
+					mock.Anything,
+					tt.provider,
+					mock.AnythingOfType("[]uint8"),
+				).Return(1, 1, tt.wantErr).Once()
+			}
+
+			r := chi.NewRouter()
+			r.With(middlewares.RequireSecret("secret")).
+				Post("/webhooks/mail/{provider}", api.ErrorWrapper(New(mHandler)))
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			body, err := mailevents.NewFake(srv.URL, tt.secret).
+				Post(t.Context(), tt.provider, consts.MailEventComplaint, "user@test.com")
+			if tt.wantStatus != 0 {
+				require.ErrorContains(t, err, fmt.Sprintf("answered %d", tt.wantStatus))
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, `{"received": 1, "suppressed": 1}`, string(body))
+		})
+	}
+}
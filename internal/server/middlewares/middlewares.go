@@ -2,24 +2,42 @@ package middlewares
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/config"
 	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/clientip"
 	"github.com/AlexMickh/twitch-clone/pkg/api"
 	"github.com/AlexMickh/twitch-clone/pkg/logger"
-	"github.com/go-chi/render"
 	"github.com/google/uuid"
 )
 
 type SessionValidator interface {
-	ValidateSession(ctx context.Context, sessionId string) (uuid.UUID, error)
+	ValidateSession(ctx context.Context, sessionId string) (entities.Session, error)
 }
 
-func Auth(sessionCfg config.SessionConfig, sessionValidator SessionValidator) func(next http.Handler) http.Handler {
+type UserProvider interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+}
+
+type Localizer interface {
+	Negotiate(preferences ...string) string
+	Translate(locale, key string, args ...any) string
+}
+
+func Auth(
+	sessionCfg config.SessionConfig,
+	sessionValidator SessionValidator,
+	userProvider UserProvider,
+) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			const op = "middlewares.Auth"
@@ -29,35 +47,222 @@ func Auth(sessionCfg config.SessionConfig, sessionValidator SessionValidator) fu
 			cookie, err := r.Cookie(sessionCfg.Name)
 			if err != nil {
 				log.Error("failed to get session", logger.Err(err))
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, api.ErrorResponse{
-					Error: "failed to get session",
-				})
+				api.WriteError(w, r, "failed to get session", http.StatusUnauthorized)
 				return
 			}
 
-			userId, err := sessionValidator.ValidateSession(ctx, cookie.Value)
+			session, err := sessionValidator.ValidateSession(ctx, cookie.Value)
 			if err != nil {
+				if errors.Is(err, errs.ErrSessionRevoked) {
+					log.Error("session revoked", logger.Err(err))
+					api.WriteError(w, r, errs.ErrSessionRevoked.Error(), http.StatusUnauthorized)
+					return
+				}
+				if errors.Is(err, errs.ErrSessionHijack) {
+					log.Error("session user agent changed", logger.Err(err))
+					api.WriteError(w, r, errs.ErrSessionHijack.Error(), http.StatusUnauthorized)
+					return
+				}
 				if errors.Is(err, errs.ErrSessionNotFound) {
 					log.Error("session not found", logger.Err(err))
-					render.Status(r, http.StatusUnauthorized)
-					render.JSON(w, r, api.ErrorResponse{
-						Error: errs.ErrSessionNotFound.Error(),
-					})
+					api.WriteError(w, r, errs.ErrSessionNotFound.Error(), http.StatusUnauthorized)
 					return
 				}
 				log.Error("failed to validate session", logger.Err(err))
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, api.ErrorResponse{
-					Error: "failed to validate session",
-				})
+				api.WriteError(w, r, "failed to validate session", http.StatusUnauthorized)
+				return
+			}
+
+			userId := session.UserId
+
+			user, err := userProvider.UserById(ctx, userId)
+			if err != nil {
+				log.Error("failed to get session user", logger.Err(err))
+				api.WriteError(w, r, "failed to validate session", http.StatusUnauthorized)
+				return
+			}
+			if user.IsSuspended(time.Now()) {
+				log.Error("user suspended", slog.String("user_id", userId.String()))
+				api.WriteError(w, r, errs.ErrUserSuspended.Error(), http.StatusForbidden)
 				return
 			}
 
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextSessionId, cookie.Value)
 			//nolint:staticcheck
 			ctx = context.WithValue(ctx, consts.ContextUserId, userId)
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextUserRole, user.Role)
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextElevatedUntil, session.ElevatedUntil)
+			if user.Locale != "" {
+				// the profile locale wins over the negotiated one
+				//nolint:staticcheck
+				ctx = context.WithValue(ctx, consts.ContextLocale, user.Locale)
+			}
+			if session.ImpersonatorId != "" {
+				impersonatorId, err := uuid.Parse(session.ImpersonatorId)
+				if err != nil {
+					log.Error("failed to parse impersonator id", logger.Err(err))
+					api.WriteError(w, r, "failed to validate session", http.StatusUnauthorized)
+					return
+				}
+
+				//nolint:staticcheck
+				ctx = context.WithValue(ctx, consts.ContextImpersonatorId, impersonatorId)
+				ctx = logger.ContextWithLogger(ctx, logger.FromCtx(ctx).With(
+					slog.String("impersonator_id", impersonatorId.String()),
+					slog.String("impersonated_user_id", userId.String()),
+				))
+			}
 			r = r.WithContext(ctx)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// ClientInfo puts the client address and user agent into the request context,
+// so services can attach them to audit events and sessions.
+// Forwarding headers are honored only for requests coming from trustedProxies.
+func ClientInfo(trustedProxies []string) func(next http.Handler) http.Handler {
+	resolver := clientip.MustNew(trustedProxies)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			//nolint:staticcheck
+			ctx := context.WithValue(r.Context(), consts.ContextClientIP, resolver.ClientIP(r))
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextUserAgent, r.UserAgent())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Locale negotiates the request locale from Accept-Language and makes error responses
+// carry a message translated from the "error.<code>" catalog keys.
+// Auth replaces the locale with the one from the user profile.
+func Locale(localizer Localizer) func(next http.Handler) http.Handler {
+	translator := func(ctx context.Context, msg string) string {
+		locale, _ := ctx.Value(consts.ContextLocale).(string)
+		key := "error." + msg
+		message := localizer.Translate(locale, key)
+		if message == key {
+			// unexpected errors have no catalog entry, the message is left out
+			return ""
+		}
+
+		return message
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := localizer.Negotiate(r.Header.Get("Accept-Language"))
+
+			//nolint:staticcheck
+			ctx := context.WithValue(r.Context(), consts.ContextLocale, locale)
+			ctx = api.ContextWithTranslator(ctx, translator)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole must be mounted after Auth. It rejects users whose role is not in roles.
+func RequireRole(roles ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middlewares.RequireRole"
+			ctx := r.Context()
+			log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+			role, _ := ctx.Value(consts.ContextUserRole).(string)
+			if !slices.Contains(roles, role) {
+				log.Error("role not allowed", slog.String("role", role))
+				api.WriteError(w, r, errs.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyImpersonation must be mounted after Auth. It keeps impersonation sessions
+// away from account security settings: password, email and 2FA.
+func DenyImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "middlewares.DenyImpersonation"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		if _, ok := ctx.Value(consts.ContextImpersonatorId).(uuid.UUID); ok {
+			log.Error("action not allowed while impersonating")
+			api.WriteError(w, r, errs.ErrImpersonation.Error(), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireReauth must be mounted after Auth. It guards sensitive actions behind
+// a recent password check, see POST /auth/reauth.
+func RequireReauth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "middlewares.RequireReauth"
+		ctx := r.Context()
+		log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+		elevatedUntil, _ := ctx.Value(consts.ContextElevatedUntil).(time.Time)
+		if !time.Now().Before(elevatedUntil) {
+			log.Error("session is not elevated", slog.Time("elevated_until", elevatedUntil))
+			api.WriteError(w, r, errs.ErrReauthRequired.Error(), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSecret guards machine to machine endpoints such as provider webhooks. The caller passes
+// the shared secret as a bearer token or, when it can not set headers, as the token query parameter.
+func RequireSecret(secret string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middlewares.RequireSecret"
+			log := logger.FromCtx(r.Context()).With(slog.String("op", op))
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				token = r.URL.Query().Get("token")
+			}
+			if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+				log.Error("invalid shared secret")
+				api.WriteError(w, r, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireIP must be mounted after ClientInfo. It lets in only clients from allowed, addresses or CIDRs.
+func RequireIP(allowed []string) func(next http.Handler) http.Handler {
+	allowlist := clientip.MustNewAllowlist(allowed)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middlewares.RequireIP"
+			log := logger.FromCtx(r.Context()).With(slog.String("op", op))
+
+			ip, _ := r.Context().Value(consts.ContextClientIP).(string)
+			if !allowlist.Allows(ip) {
+				log.Error("client address is not allowed", slog.String("ip", ip))
+				api.WriteError(w, r, errs.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
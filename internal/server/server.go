@@ -4,15 +4,58 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	_ "github.com/AlexMickh/twitch-clone/docs"
 	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/impersonate"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/mail_queue"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/reset_password"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/revoke_sessions"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/search_users"
+	admin_security_events "github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/security_events"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/suspend_user"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/unsuspend_user"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/user_details"
+	admin_verify_email "github.com/AlexMickh/twitch-clone/internal/server/handlers/admin/verify_email"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/device_code"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/device_token"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/forgot_password"
 	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/login"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/logout"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/not_me"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/not_me_page"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/reauth"
 	"github.com/AlexMickh/twitch-clone/internal/server/handlers/auth/register"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/channel/channel_by_login"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/channel/reset_stream_key"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/channel/stream_key"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/channel/update_channel"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/dev/mailbox"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/device/activate"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/ingest/on_play"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/ingest/on_publish"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/ingest/on_publish_done"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/ingest/on_update"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/invite/create_invite"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/invite/my_invites"
 	"github.com/AlexMickh/twitch-clone/internal/server/handlers/session/current_session"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/change_email"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/change_password"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/notification_preferences"
+	user_reset_password "github.com/AlexMickh/twitch-clone/internal/server/handlers/user/reset_password"
+	user_security_events "github.com/AlexMickh/twitch-clone/internal/server/handlers/user/security_events"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/send_phone_code"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/unsubscribe"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/update_notification_preferences"
 	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/verify_email"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/verify_email_code"
+	"github.com/AlexMickh/twitch-clone/internal/server/handlers/user/verify_phone"
+	webhook_mail_events "github.com/AlexMickh/twitch-clone/internal/server/handlers/webhook/mail_events"
 	"github.com/AlexMickh/twitch-clone/internal/server/middlewares"
 	"github.com/AlexMickh/twitch-clone/pkg/api"
 	"github.com/AlexMickh/twitch-clone/pkg/logger"
@@ -29,16 +72,95 @@ type Server struct {
 
 type AuthService interface {
 	Register(ctx context.Context, req dtos.RegisterRequest) (string, error)
-	Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, error)
+	Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, entities.User, error)
+	ResetPassword(ctx context.Context, req dtos.ResetPasswordRequest) error
+	Logout(ctx context.Context, sessionId string) error
+	NotMe(ctx context.Context, token string) error
+	ForgotPassword(ctx context.Context, req dtos.ForgotPasswordRequest) error
+	Reauthenticate(ctx context.Context, userId uuid.UUID, sessionId string, req dtos.ReauthRequest) (time.Time, error)
+	ChangePassword(ctx context.Context, userId uuid.UUID, req dtos.ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, userId uuid.UUID, req dtos.ChangeEmailRequest) error
 }
 
 type UserService interface {
 	VerifyEmail(ctx context.Context, req dtos.ValidateEmailRequest) error
+	VerifyEmailCode(ctx context.Context, req dtos.VerifyEmailCodeRequest) error
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
 }
 
 type SessionService interface {
 	SessionById(ctx context.Context, sessionId string) (entities.Session, error)
-	ValidateSession(ctx context.Context, sessionId string) (uuid.UUID, error)
+	ValidateSession(ctx context.Context, sessionId string) (entities.Session, error)
+}
+
+type AdminService interface {
+	SearchUsers(ctx context.Context, req dtos.SearchUsersRequest) ([]entities.User, int64, error)
+	UserDetails(ctx context.Context, userId uuid.UUID) (entities.User, []entities.Session, error)
+	ForceVerifyEmail(ctx context.Context, userId uuid.UUID) error
+	TriggerPasswordReset(ctx context.Context, userId uuid.UUID) error
+	SuspendUser(ctx context.Context, userId, adminId uuid.UUID, req dtos.SuspendUserRequest) error
+	UnsuspendUser(ctx context.Context, userId uuid.UUID) error
+	RevokeSessions(ctx context.Context, userId uuid.UUID) error
+	Impersonate(ctx context.Context, userId, adminId uuid.UUID, userAgent string) (uuid.UUID, error)
+}
+
+type AuditService interface {
+	UserEvents(ctx context.Context, userId uuid.UUID, req dtos.PageRequest) ([]entities.AuditEvent, int64, error)
+	SearchEvents(ctx context.Context, req dtos.SearchAuditEventsRequest) ([]entities.AuditEvent, int64, error)
+}
+
+type InviteService interface {
+	CreateInvite(ctx context.Context, userId uuid.UUID, role string, req dtos.CreateInviteRequest) (entities.Invite, error)
+	InvitesByCreator(ctx context.Context, userId uuid.UUID) ([]entities.Invite, error)
+}
+
+type DeviceAuthService interface {
+	RequestCode(ctx context.Context, req dtos.DeviceCodeRequest) (entities.DeviceAuthorization, error)
+	VerificationURI() string
+	VerificationURIComplete(userCode string) string
+	PollToken(ctx context.Context, req dtos.DeviceTokenRequest, userAgent string) (uuid.UUID, error)
+	Activate(ctx context.Context, userId uuid.UUID, req dtos.ActivateDeviceRequest) error
+}
+
+type PhoneService interface {
+	SendCode(ctx context.Context, userId uuid.UUID, req dtos.SendPhoneCodeRequest) error
+	VerifyCode(ctx context.Context, userId uuid.UUID, req dtos.VerifyPhoneRequest) error
+}
+
+type MailQueueService interface {
+	Stats(ctx context.Context) (entities.MailQueueStats, error)
+}
+
+type MailEventsService interface {
+	HandleEvents(ctx context.Context, provider string, body []byte) (int, int, error)
+}
+
+type NotificationService interface {
+	Preferences(ctx context.Context, userId uuid.UUID) (dtos.NotificationPreferencesResponse, error)
+	UpdatePreferences(
+		ctx context.Context,
+		userId uuid.UUID,
+		req dtos.UpdateNotificationPreferencesRequest,
+	) (dtos.NotificationPreferencesResponse, error)
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type ChannelService interface {
+	ChannelByLogin(ctx context.Context, login string) (entities.Channel, error)
+	UpdateChannel(ctx context.Context, userId uuid.UUID, req dtos.UpdateChannelRequest) (entities.Channel, error)
+	StreamKey(ctx context.Context, userId uuid.UUID) (string, error)
+	ResetStreamKey(ctx context.Context, userId uuid.UUID) (string, error)
+}
+
+type BroadcastService interface {
+	Publish(ctx context.Context, req dtos.IngestCallbackRequest) (string, error)
+	Update(ctx context.Context, req dtos.IngestCallbackRequest) error
+	PublishDone(ctx context.Context, req dtos.IngestCallbackRequest) error
+	Play(ctx context.Context, req dtos.IngestCallbackRequest) error
+}
+
+type Mailbox interface {
+	Messages(to string) []email.CapturedMessage
 }
 
 // @title						Your API
@@ -50,15 +172,32 @@ type SessionService interface {
 func New(
 	ctx context.Context,
 	cfg config.ServerConfig,
+	sessionSecurity config.SessionSecurityConfig,
+	mailEventsCfg config.MailEventsConfig,
+	ingestCallbacksCfg config.IngestCallbacksConfig,
+	localizer middlewares.Localizer,
 	authService AuthService,
 	userService UserService,
 	sessionService SessionService,
+	adminService AdminService,
+	auditService AuditService,
+	inviteService InviteService,
+	deviceAuthService DeviceAuthService,
+	phoneService PhoneService,
+	mailQueueService MailQueueService,
+	mailEventsService MailEventsService,
+	notificationService NotificationService,
+	channelService ChannelService,
+	broadcastService BroadcastService,
+	devMailbox Mailbox,
 ) *Server {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 	r.Use(logger.ChiMiddleware(ctx))
 	r.Use(middleware.Recoverer)
+	r.Use(middlewares.ClientInfo(cfg.TrustedProxies))
+	r.Use(middlewares.Locale(localizer))
 
 	r.Use(cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
@@ -76,20 +215,111 @@ func New(
 		return nil
 	}))
 
+	authMiddleware := middlewares.Auth(cfg.Session, sessionService, userService)
+
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", api.ErrorWrapper(register.New(authService)))
 		r.Post("/login", api.ErrorWrapper(login.New(authService, cfg.Session)))
+		r.With(authMiddleware).Post("/logout", api.ErrorWrapper(logout.New(authService, cfg.Session)))
+		// the link in the email only opens a page, link scanners of mail providers follow it too
+		r.Get("/not-me/{token}", api.ErrorWrapper(not_me_page.New(localizer)))
+		r.Post("/not-me/{token}", api.ErrorWrapper(not_me.New(authService)))
+		r.Post("/forgot-password", api.ErrorWrapper(forgot_password.New(authService)))
+		r.With(authMiddleware).Post("/reauth", api.ErrorWrapper(reauth.New(authService)))
+		r.Post("/device/code", api.ErrorWrapper(device_code.New(deviceAuthService)))
+		r.Post("/device/token", api.ErrorWrapper(device_token.New(deviceAuthService, cfg.Session)))
 	})
 
 	r.Route("/user", func(r chi.Router) {
 		r.Get("/verify-email/{token}", api.ErrorWrapper(verify_email.New(userService)))
+		r.Post("/verify-email/code", api.ErrorWrapper(verify_email_code.New(userService)))
+		r.Post("/reset-password", api.ErrorWrapper(user_reset_password.New(authService)))
+		r.With(authMiddleware).Get("/security-events", api.ErrorWrapper(user_security_events.New(auditService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation, middlewares.RequireReauth).
+			Post("/change-password", api.ErrorWrapper(change_password.New(authService, cfg.Session)))
+		r.With(authMiddleware, middlewares.DenyImpersonation, middlewares.RequireReauth).
+			Post("/email", api.ErrorWrapper(change_email.New(authService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation).
+			Post("/phone", api.ErrorWrapper(send_phone_code.New(phoneService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation).
+			Post("/phone/verify", api.ErrorWrapper(verify_phone.New(phoneService)))
+		r.With(authMiddleware).Get("/notifications", api.ErrorWrapper(notification_preferences.New(notificationService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation).
+			Put("/notifications", api.ErrorWrapper(update_notification_preferences.New(notificationService)))
+	})
+
+	// one-click unsubscribe from the List-Unsubscribe header, mail clients post here without a session
+	r.Post("/unsubscribe/{token}", api.ErrorWrapper(unsubscribe.New(notificationService)))
+
+	// approving a device mints a session for it
+	r.With(authMiddleware, middlewares.DenyImpersonation, middlewares.RequireReauth).
+		Post("/activate", api.ErrorWrapper(activate.New(deviceAuthService)))
+
+	r.Route("/invites", func(r chi.Router) {
+		r.Use(authMiddleware)
+		r.With(middlewares.RequireReauth).Post("/", api.ErrorWrapper(create_invite.New(inviteService)))
+		r.Get("/", api.ErrorWrapper(my_invites.New(inviteService)))
+	})
+
+	r.Route("/channels", func(r chi.Router) {
+		r.Get("/{login}", api.ErrorWrapper(channel_by_login.New(channelService)))
+		r.With(authMiddleware).Patch("/me", api.ErrorWrapper(update_channel.New(channelService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation, middlewares.RequireReauth).
+			Get("/me/stream-key", api.ErrorWrapper(stream_key.New(channelService)))
+		r.With(authMiddleware, middlewares.DenyImpersonation, middlewares.RequireReauth).
+			Post("/me/stream-key/reset", api.ErrorWrapper(reset_stream_key.New(channelService)))
 	})
 
 	r.Route("/session", func(r chi.Router) {
-		r.Use(middlewares.Auth(cfg.Session, sessionService))
+		r.Use(authMiddleware)
 		r.Get("/current", api.ErrorWrapper(current_session.New(sessionService, cfg.Session)))
 	})
 
+	// the webhook stays unreachable until a secret is configured
+	if mailEventsCfg.Secret != "" {
+		r.With(middlewares.RequireSecret(mailEventsCfg.Secret)).
+			Post("/webhooks/mail/{provider}", api.ErrorWrapper(webhook_mail_events.New(mailEventsService)))
+	}
+
+	// the callbacks of nginx-rtmp and SRS stay unreachable until a secret or an allowlist is configured
+	if ingestCallbacksCfg.Secret != "" || len(ingestCallbacksCfg.AllowedIPs) > 0 {
+		r.Route("/ingest/callbacks", func(r chi.Router) {
+			if ingestCallbacksCfg.Secret != "" {
+				r.Use(middlewares.RequireSecret(ingestCallbacksCfg.Secret))
+			}
+			if len(ingestCallbacksCfg.AllowedIPs) > 0 {
+				r.Use(middlewares.RequireIP(ingestCallbacksCfg.AllowedIPs))
+			}
+			r.Post("/on_publish", api.ErrorWrapper(on_publish.New(broadcastService)))
+			r.Post("/on_publish_done", api.ErrorWrapper(on_publish_done.New(broadcastService)))
+			r.Post("/on_play", api.ErrorWrapper(on_play.New(broadcastService)))
+			r.Post("/on_update", api.ErrorWrapper(on_update.New(broadcastService)))
+		})
+	}
+
+	// devMailbox is only passed outside of prod, see app.New
+	if devMailbox != nil {
+		r.Get("/dev/mailbox", api.ErrorWrapper(mailbox.New(devMailbox)))
+	}
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(authMiddleware)
+		r.Use(middlewares.RequireRole(consts.RoleAdmin))
+		r.Get("/users", api.ErrorWrapper(search_users.New(adminService)))
+		r.Get("/users/{id}", api.ErrorWrapper(user_details.New(adminService)))
+		r.Post("/users/{id}/verify-email", api.ErrorWrapper(admin_verify_email.New(adminService)))
+		r.Post("/users/{id}/reset-password", api.ErrorWrapper(reset_password.New(adminService)))
+		r.Post("/users/{id}/suspension", api.ErrorWrapper(suspend_user.New(adminService)))
+		r.Delete("/users/{id}/suspension", api.ErrorWrapper(unsuspend_user.New(adminService)))
+		r.Delete("/users/{id}/sessions", api.ErrorWrapper(revoke_sessions.New(adminService)))
+		r.Post(
+			"/users/{id}/impersonate",
+			api.ErrorWrapper(impersonate.New(adminService, cfg.Session, sessionSecurity.ImpersonationTTL)),
+		)
+		r.Get("/security-events", api.ErrorWrapper(admin_security_events.New(auditService)))
+		r.Get("/mail-queue", api.ErrorWrapper(mail_queue.New(mailQueueService)))
+	})
+
 	return &Server{
 		srv: &http.Server{
 			Addr:         cfg.Addr,
@@ -0,0 +1,207 @@
+package admin_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+	SearchUsers(ctx context.Context, req dtos.SearchUsersRequest) ([]entities.User, int64, error)
+	ForceVerifyEmail(ctx context.Context, id uuid.UUID) error
+	Suspend(ctx context.Context, id, issuedBy uuid.UUID, req dtos.SuspendUserRequest) error
+	Unsuspend(ctx context.Context, id uuid.UUID) error
+}
+
+type SessionService interface {
+	SessionsByUserId(ctx context.Context, userId uuid.UUID) ([]entities.Session, error)
+	RevokeUserSessions(ctx context.Context, userId uuid.UUID) error
+	CreateImpersonationSession(ctx context.Context, userId, impersonatorId uuid.UUID, userAgent string) (uuid.UUID, error)
+}
+
+type PasswordResetter interface {
+	SendPasswordReset(ctx context.Context, userId uuid.UUID) error
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type Service struct {
+	userService      UserService
+	sessionService   SessionService
+	passwordResetter PasswordResetter
+	auditor          Auditor
+}
+
+func New(
+	userService UserService,
+	sessionService SessionService,
+	passwordResetter PasswordResetter,
+	auditor Auditor,
+) *Service {
+	return &Service{
+		userService:      userService,
+		sessionService:   sessionService,
+		passwordResetter: passwordResetter,
+		auditor:          auditor,
+	}
+}
+
+func (s *Service) SearchUsers(ctx context.Context, req dtos.SearchUsersRequest) ([]entities.User, int64, error) {
+	const op = "services.admin.SearchUsers"
+
+	users, total, err := s.userService.SearchUsers(ctx, req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, total, nil
+}
+
+func (s *Service) UserDetails(ctx context.Context, userId uuid.UUID) (entities.User, []entities.Session, error) {
+	const op = "services.admin.UserDetails"
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return entities.User{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessions, err := s.sessionService.SessionsByUserId(ctx, userId)
+	if err != nil {
+		return entities.User{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, sessions, nil
+}
+
+func (s *Service) ForceVerifyEmail(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.admin.ForceVerifyEmail"
+
+	err := s.userService.ForceVerifyEmail(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "force_verify_email", nil)
+
+	return nil
+}
+
+func (s *Service) TriggerPasswordReset(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.admin.TriggerPasswordReset"
+
+	err := s.passwordResetter.SendPasswordReset(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "trigger_password_reset", nil)
+
+	return nil
+}
+
+// SuspendUser suspends or bans the user and drops all of their sessions,
+// so the suspension takes effect immediately rather than on next login.
+func (s *Service) SuspendUser(ctx context.Context, userId, adminId uuid.UUID, req dtos.SuspendUserRequest) error {
+	const op = "services.admin.SuspendUser"
+
+	if userId == adminId {
+		return fmt.Errorf("%s: %w", op, errs.ErrForbidden)
+	}
+
+	err := s.userService.Suspend(ctx, userId, adminId, req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.sessionService.RevokeUserSessions(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "suspend_user", map[string]string{"type": req.Type, "reason": req.Reason})
+
+	return nil
+}
+
+func (s *Service) UnsuspendUser(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.admin.UnsuspendUser"
+
+	err := s.userService.Unsuspend(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "unsuspend_user", nil)
+
+	return nil
+}
+
+func (s *Service) RevokeSessions(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.admin.RevokeSessions"
+
+	_, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.sessionService.RevokeUserSessions(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "revoke_sessions", nil)
+
+	return nil
+}
+
+// Impersonate opens a short-lived session as the user for support staff.
+// Staff cannot impersonate themselves, other admins or suspended users.
+func (s *Service) Impersonate(ctx context.Context, userId, adminId uuid.UUID, userAgent string) (uuid.UUID, error) {
+	const op = "services.admin.Impersonate"
+
+	if userId == adminId {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrForbidden)
+	}
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Role == consts.RoleAdmin {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrForbidden)
+	}
+	if user.IsSuspended(time.Now()) {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrUserSuspended)
+	}
+
+	sessionId, err := s.sessionService.CreateImpersonationSession(ctx, userId, adminId, userAgent)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordAdminAction(ctx, userId, "impersonate", map[string]string{"session_id": sessionId.String()})
+
+	return sessionId, nil
+}
+
+func (s *Service) recordAdminAction(ctx context.Context, userId uuid.UUID, action string, metadata map[string]string) {
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata["action"] = action
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventAdminAction,
+		UserId:   userId,
+		Metadata: metadata,
+	})
+}
@@ -0,0 +1,292 @@
+package admin_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_UserDetails(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		wantUserErr    error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:           "good case",
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        nil,
+		},
+		{
+			name:           "user not found case",
+			wantUserErr:    errs.ErrUserNotFound,
+			wantSessionErr: nil,
+			wantErr:        errs.ErrUserNotFound,
+		},
+		{
+			name:           "session error case",
+			wantUserErr:    nil,
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mSessionService := NewMockSessionService(t)
+
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId}, tt.wantUserErr).Once()
+
+			mSessionService.EXPECT().SessionsByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return([]entities.Session{{ID: uuid.New(), UserId: userId}}, tt.wantSessionErr).Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				sessionService: mSessionService,
+			}
+			user, sessions, err := s.UserDetails(context.Background(), userId)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, userId, user.ID)
+				require.Len(t, sessions, 1)
+			}
+		})
+	}
+}
+
+func TestService_SuspendUser(t *testing.T) {
+	adminId := uuid.New()
+
+	tests := []struct {
+		name           string
+		userId         uuid.UUID
+		wantUserErr    error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:           "good case",
+			userId:         uuid.New(),
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        nil,
+		},
+		{
+			name:           "suspend yourself case",
+			userId:         adminId,
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        errs.ErrForbidden,
+		},
+		{
+			name:           "user not found case",
+			userId:         uuid.New(),
+			wantUserErr:    errs.ErrUserNotFound,
+			wantSessionErr: nil,
+			wantErr:        errs.ErrUserNotFound,
+		},
+		{
+			name:           "session error case",
+			userId:         uuid.New(),
+			wantUserErr:    nil,
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mSessionService := NewMockSessionService(t)
+
+			mUserService.EXPECT().Suspend(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.userId,
+				adminId,
+				mock.AnythingOfType("dtos.SuspendUserRequest"),
+			).Return(tt.wantUserErr).Maybe()
+
+			mSessionService.EXPECT().RevokeUserSessions(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.userId,
+			).Return(tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			err := s.SuspendUser(context.Background(), tt.userId, adminId, dtos.SuspendUserRequest{
+				Type:   consts.SuspensionTypeBan,
+				Reason: "restreaming",
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_RevokeSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		wantUserErr    error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:           "good case",
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        nil,
+		},
+		{
+			name:           "user not found case",
+			wantUserErr:    errs.ErrUserNotFound,
+			wantSessionErr: nil,
+			wantErr:        errs.ErrUserNotFound,
+		},
+		{
+			name:           "session error case",
+			wantUserErr:    nil,
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mSessionService := NewMockSessionService(t)
+
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(entities.User{}, tt.wantUserErr).Once()
+
+			mSessionService.EXPECT().RevokeUserSessions(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			err := s.RevokeSessions(context.Background(), uuid.New())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_Impersonate(t *testing.T) {
+	adminId := uuid.New()
+
+	tests := []struct {
+		name           string
+		userId         uuid.UUID
+		user           entities.User
+		wantUserErr    error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:   "good case",
+			userId: uuid.New(),
+			user:   entities.User{Role: consts.RoleUser},
+		},
+		{
+			name:    "impersonate yourself case",
+			userId:  adminId,
+			wantErr: errs.ErrForbidden,
+		},
+		{
+			name:    "impersonate admin case",
+			userId:  uuid.New(),
+			user:    entities.User{Role: consts.RoleAdmin},
+			wantErr: errs.ErrForbidden,
+		},
+		{
+			name:        "user not found case",
+			userId:      uuid.New(),
+			wantUserErr: errs.ErrUserNotFound,
+			wantErr:     errs.ErrUserNotFound,
+		},
+		{
+			name:           "session error case",
+			userId:         uuid.New(),
+			user:           entities.User{Role: consts.RoleUser},
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mSessionService := NewMockSessionService(t)
+
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.userId,
+			).Return(tt.user, tt.wantUserErr).Maybe()
+
+			mSessionService.EXPECT().CreateImpersonationSession(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.userId,
+				adminId,
+				"chrome",
+			).Return(uuid.New(), tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			_, err := s.Impersonate(context.Background(), tt.userId, adminId, "chrome")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package audit_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	SaveEvent(ctx context.Context, event entities.AuditEvent) error
+	Events(ctx context.Context, filter entities.AuditEventFilter) ([]entities.AuditEvent, int64, error)
+}
+
+type Service struct {
+	repository Repository
+}
+
+func New(repository Repository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// Record stores event enriched with the request metadata found in ctx.
+// The actor defaults to the authenticated user, or to the subject for anonymous requests.
+// Under impersonation the staff member is the actor and the event is tagged with their id.
+// Failures are only logged: a broken audit log must not break authentication.
+func (s *Service) Record(ctx context.Context, event entities.AuditEvent) {
+	const op = "services.audit.Record"
+
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+	event.RequestId = middleware.GetReqID(ctx)
+	event.IP, _ = ctx.Value(consts.ContextClientIP).(string)
+	event.UserAgent, _ = ctx.Value(consts.ContextUserAgent).(string)
+	if impersonatorId, ok := ctx.Value(consts.ContextImpersonatorId).(uuid.UUID); ok {
+		if event.Metadata == nil {
+			event.Metadata = make(map[string]string, 1)
+		}
+		event.Metadata["impersonator_id"] = impersonatorId.String()
+		if event.ActorId == uuid.Nil {
+			event.ActorId = impersonatorId
+		}
+	}
+	if event.ActorId == uuid.Nil {
+		actorId, ok := ctx.Value(consts.ContextUserId).(uuid.UUID)
+		if !ok {
+			actorId = event.UserId
+		}
+		event.ActorId = actorId
+	}
+
+	err := s.repository.SaveEvent(ctx, event)
+	if err != nil {
+		logger.FromCtx(ctx).Error(
+			"failed to record audit event",
+			slog.String("op", op),
+			slog.String("type", event.Type),
+			logger.Err(err),
+		)
+	}
+}
+
+func (s *Service) UserEvents(ctx context.Context, userId uuid.UUID, req dtos.PageRequest) ([]entities.AuditEvent, int64, error) {
+	const op = "services.audit.UserEvents"
+
+	events, total, err := s.repository.Events(ctx, entities.AuditEventFilter{
+		UserId: &userId,
+		Offset: int64((req.Page - 1) * req.Limit),
+		Limit:  int64(req.Limit),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, total, nil
+}
+
+func (s *Service) SearchEvents(ctx context.Context, req dtos.SearchAuditEventsRequest) ([]entities.AuditEvent, int64, error) {
+	const op = "services.audit.SearchEvents"
+
+	filter := entities.AuditEventFilter{
+		Type:   req.Type,
+		From:   req.From,
+		To:     req.To,
+		Offset: int64((req.Page - 1) * req.Limit),
+		Limit:  int64(req.Limit),
+	}
+	if req.UserId != "" {
+		userId, err := uuid.Parse(req.UserId)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		filter.UserId = &userId
+	}
+	if req.ActorId != "" {
+		actorId, err := uuid.Parse(req.ActorId)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		filter.ActorId = &actorId
+	}
+
+	events, total, err := s.repository.Events(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, total, nil
+}
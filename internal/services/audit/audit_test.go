@@ -0,0 +1,160 @@
+package audit_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Record(t *testing.T) {
+	subjectId := uuid.New()
+	adminId := uuid.New()
+	staffId := uuid.New()
+
+	tests := []struct {
+		name            string
+		ctxUserId       *uuid.UUID
+		ctxImpersonator *uuid.UUID
+		wantActorId     uuid.UUID
+		wantMockErr     error
+	}{
+		{
+			name:        "anonymous request case",
+			ctxUserId:   nil,
+			wantActorId: subjectId,
+			wantMockErr: nil,
+		},
+		{
+			name:        "authenticated request case",
+			ctxUserId:   &adminId,
+			wantActorId: adminId,
+			wantMockErr: nil,
+		},
+		{
+			name:            "impersonated request case",
+			ctxUserId:       &subjectId,
+			ctxImpersonator: &staffId,
+			wantActorId:     staffId,
+			wantMockErr:     nil,
+		},
+		{
+			name:        "repository error case",
+			ctxUserId:   nil,
+			wantActorId: subjectId,
+			wantMockErr: errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+
+			//nolint:staticcheck
+			ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "request id")
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextClientIP, "10.0.0.1")
+			//nolint:staticcheck
+			ctx = context.WithValue(ctx, consts.ContextUserAgent, "firefox")
+			if tt.ctxUserId != nil {
+				//nolint:staticcheck
+				ctx = context.WithValue(ctx, consts.ContextUserId, *tt.ctxUserId)
+			}
+			wantImpersonator := ""
+			if tt.ctxImpersonator != nil {
+				//nolint:staticcheck
+				ctx = context.WithValue(ctx, consts.ContextImpersonatorId, *tt.ctxImpersonator)
+				wantImpersonator = tt.ctxImpersonator.String()
+			}
+
+			m.EXPECT().SaveEvent(
+				mock.Anything,
+				mock.MatchedBy(func(event entities.AuditEvent) bool {
+					return event.ID != uuid.Nil &&
+						event.Type == consts.AuditEventLogout &&
+						event.UserId == subjectId &&
+						event.ActorId == tt.wantActorId &&
+						event.IP == "10.0.0.1" &&
+						event.UserAgent == "firefox" &&
+						event.RequestId == "request id" &&
+						event.Metadata["impersonator_id"] == wantImpersonator &&
+						!event.CreatedAt.IsZero()
+				}),
+			).Return(tt.wantMockErr).Once()
+
+			s := &Service{
+				repository: m,
+			}
+			s.Record(ctx, entities.AuditEvent{
+				Type:   consts.AuditEventLogout,
+				UserId: subjectId,
+			})
+		})
+	}
+}
+
+func TestService_SearchEvents(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name        string
+		req         dtos.SearchAuditEventsRequest
+		wantFilter  entities.AuditEventFilter
+		wantMockErr error
+	}{
+		{
+			name: "user filter case",
+			req: dtos.SearchAuditEventsRequest{
+				PageRequest: dtos.PageRequest{Page: 3, Limit: 10},
+				UserId:      userId.String(),
+				Type:        consts.AuditEventLoginFailure,
+			},
+			wantFilter: entities.AuditEventFilter{
+				UserId: &userId,
+				Type:   consts.AuditEventLoginFailure,
+				Offset: 20,
+				Limit:  10,
+			},
+			wantMockErr: nil,
+		},
+		{
+			name: "repository error case",
+			req: dtos.SearchAuditEventsRequest{
+				PageRequest: dtos.PageRequest{Page: 1, Limit: 10},
+			},
+			wantFilter: entities.AuditEventFilter{
+				Offset: 0,
+				Limit:  10,
+			},
+			wantMockErr: errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+
+			m.EXPECT().Events(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.wantFilter,
+			).Return(nil, 0, tt.wantMockErr).Once()
+
+			s := &Service{
+				repository: m,
+			}
+			_, _, err := s.SearchEvents(context.Background(), tt.req)
+			require.ErrorIs(t, err, tt.wantMockErr)
+		})
+	}
+}
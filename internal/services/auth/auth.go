@@ -2,97 +2,599 @@ package auth_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyHash is compared against on unknown emails in the hardened mode,
+// so a login for a missing account takes as long as one with a wrong password.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
 type UserService interface {
 	CreateUser(ctx context.Context, login, email, password string) (uuid.UUID, error)
 	UserByEmail(ctx context.Context, email string) (entities.User, error)
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
 }
 
 type VerificationSender interface {
-	SendVerification(to string, token, login string) error
+	// SendVerification sends the verification link, the code or both; an empty token or code is left out
+	SendVerification(to, locale string, token, code, login string) error
+}
+
+type PasswordResetSender interface {
+	SendPasswordReset(to, locale string, token, login string) error
+}
+
+type AccountExistsSender interface {
+	SendAccountExists(to, locale string) error
+}
+
+type NewDeviceAlertSender interface {
+	SendNewDeviceAlert(to, locale string, token, login string, device entities.Device) error
 }
 
 type TokenService interface {
 	CreateToken(ctx context.Context, userId uuid.UUID, tokenType string) (string, error)
+	CreateTokenWithPayload(ctx context.Context, userId uuid.UUID, tokenType string, payload string) (string, error)
+	Token(ctx context.Context, token string) (entities.Token, error)
+	DeleteToken(ctx context.Context, token string) error
+	DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error
 }
 
 type SessionService interface {
-	CreateSession(ctx context.Context, userId uuid.UUID, userAgent string) (uuid.UUID, error)
+	CreateSession(ctx context.Context, userId uuid.UUID, role string, userAgent string) (uuid.UUID, error)
+	RevokeUserSessions(ctx context.Context, userId uuid.UUID) error
+	DeleteSession(ctx context.Context, sessionId string) (entities.Session, error)
+	RevokeSession(ctx context.Context, sessionId string) (entities.Session, error)
+	ElevateSession(ctx context.Context, sessionId string) (time.Time, error)
+}
+
+type DeviceService interface {
+	RememberDevice(ctx context.Context, userId uuid.UUID, userAgent string, ip string) (entities.Device, bool, error)
+}
+
+type VerificationCodeService interface {
+	CreateCode(ctx context.Context, userId uuid.UUID, email string) (string, error)
+}
+
+type RegistrationGuard interface {
+	Check(ctx context.Context, req dtos.RegisterRequest) error
+}
+
+type InviteService interface {
+	ConsumeInvite(ctx context.Context, code string) error
+	ReleaseInvite(ctx context.Context, code string) error
+	RecordRedemption(ctx context.Context, code string, userId uuid.UUID) error
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
 }
 
 type Service struct {
-	userService        UserService
-	verificationSender VerificationSender
-	tokenService       TokenService
-	sessionService     SessionService
+	userService          UserService
+	verificationSender   VerificationSender
+	passwordResetSender  PasswordResetSender
+	newDeviceAlertSender NewDeviceAlertSender
+	accountExistsSender  AccountExistsSender
+	tokenService         TokenService
+	verificationCodes    VerificationCodeService
+	sessionService       SessionService
+	deviceService        DeviceService
+	registrationGuard    RegistrationGuard
+	inviteService        InviteService
+	auditor              Auditor
+	registrationMode     string
+	hardened             bool
 }
 
 func New(
 	userService UserService,
 	verificationSender VerificationSender,
+	passwordResetSender PasswordResetSender,
+	newDeviceAlertSender NewDeviceAlertSender,
+	accountExistsSender AccountExistsSender,
 	tokenService TokenService,
+	verificationCodes VerificationCodeService,
 	sessionService SessionService,
+	deviceService DeviceService,
+	registrationGuard RegistrationGuard,
+	inviteService InviteService,
+	auditor Auditor,
+	registrationMode string,
+	hardened bool,
 ) *Service {
 	return &Service{
-		userService:        userService,
-		verificationSender: verificationSender,
-		tokenService:       tokenService,
-		sessionService:     sessionService,
+		userService:          userService,
+		verificationSender:   verificationSender,
+		passwordResetSender:  passwordResetSender,
+		newDeviceAlertSender: newDeviceAlertSender,
+		accountExistsSender:  accountExistsSender,
+		tokenService:         tokenService,
+		verificationCodes:    verificationCodes,
+		sessionService:       sessionService,
+		deviceService:        deviceService,
+		registrationGuard:    registrationGuard,
+		inviteService:        inviteService,
+		auditor:              auditor,
+		registrationMode:     registrationMode,
+		hardened:             hardened,
 	}
 }
 
 func (s *Service) Register(ctx context.Context, req dtos.RegisterRequest) (string, error) {
 	const op = "services.auth.Register"
 
-	hashPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	inviteOnly := s.registrationMode == consts.RegistrationModeInviteOnly
+	switch {
+	case s.registrationMode == consts.RegistrationModeClosed:
+		return "", fmt.Errorf("%s: %w", op, errs.ErrRegistrationClosed)
+	case inviteOnly && req.InviteCode == "":
+		return "", fmt.Errorf("%s: %w", op, errs.ErrInviteInvalid)
+	}
+
+	err := s.registrationGuard.Check(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	id, err := s.userService.CreateUser(ctx, req.Login, req.Email, string(hashPassword))
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	token, err := s.tokenService.CreateToken(ctx, id, consts.TokenTypeVerifyEmail)
+	if inviteOnly {
+		err = s.inviteService.ConsumeInvite(ctx, req.InviteCode)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	id, err := s.userService.CreateUser(ctx, req.Login, req.Email, string(hashPassword))
 	if err != nil {
+		if inviteOnly {
+			s.releaseInvite(ctx, req.InviteCode)
+		}
+		if s.hardened && errors.Is(err, errs.ErrUserAlreadyExists) {
+			return s.registerExisting(ctx, req.Email), nil
+		}
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.verificationSender.SendVerification(req.Email, token, req.Login)
+	if inviteOnly {
+		err = s.inviteService.RecordRedemption(ctx, req.InviteCode, id)
+		if err != nil {
+			logger.FromCtx(ctx).Error(
+				"failed to record invite redemption",
+				slog.String("op", op),
+				logger.Err(err),
+			)
+		}
+	}
+
+	err = s.sendVerification(ctx, id, req)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventRegister,
+		UserId: id,
+	})
+
 	return id.String(), nil
 }
 
-func (s *Service) Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, error) {
+// Login opens a session and returns the user, so the client can prompt for a new email
+// when the current one bounced or complained.
+func (s *Service) Login(ctx context.Context, req dtos.LoginRequest, userAgent string) (string, entities.User, error) {
 	const op = "services.auth.Login"
 
 	user, err := s.userService.UserByEmail(ctx, req.Email)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		reason := consts.AuditReasonUserNotFound
+		if errors.Is(err, errs.ErrUserEmailNotVerify) {
+			reason = consts.AuditReasonEmailNotVerify
+		}
+		s.recordLoginFailure(ctx, uuid.Nil, req.Email, reason)
+		if s.hardened && (errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrUserEmailNotVerify)) {
+			_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(req.Password))
+			return "", entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrInvalidCredentials)
+		}
+		return "", entities.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+		s.recordLoginFailure(ctx, user.ID, req.Email, consts.AuditReasonInvalidPassword)
+		if s.hardened {
+			return "", entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrInvalidCredentials)
+		}
+		return "", entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+	if user.IsSuspended(time.Now()) {
+		s.recordLoginFailure(ctx, user.ID, req.Email, consts.AuditReasonUserSuspended)
+		return "", entities.User{}, fmt.Errorf("%s: %w", op, errs.ErrUserSuspended)
 	}
 
-	sessionId, err := s.sessionService.CreateSession(ctx, user.ID, userAgent)
+	sessionId, err := s.sessionService.CreateSession(ctx, user.ID, user.Role, userAgent)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return "", entities.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventLoginSuccess,
+		UserId:   user.ID,
+		Metadata: map[string]string{"session_id": sessionId.String()},
+	})
+
+	s.checkDevice(ctx, user, sessionId, userAgent)
+
+	return sessionId.String(), user, nil
+}
+
+// NotMe handles the "this wasn't me" link from a new device alert:
+// it revokes the reported session and starts a password reset.
+func (s *Service) NotMe(ctx context.Context, token string) error {
+	const op = "services.auth.NotMe"
+
+	tokenEntity, err := s.tokenService.Token(ctx, token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tokenEntity.Type != consts.TokenTypeNotMe {
+		return fmt.Errorf("%s: %w", op, errs.ErrTokenNotFound)
+	}
+
+	// the session may be already gone (logout or expiration), that's fine
+	_, err = s.sessionService.RevokeSession(ctx, tokenEntity.Payload)
+	if err != nil && !errors.Is(err, errs.ErrSessionNotFound) && !errors.Is(err, errs.ErrSessionRevoked) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventSessionRevoked,
+		UserId:   tokenEntity.UserId,
+		Reason:   consts.AuditReasonNotMe,
+		Metadata: map[string]string{"session_id": tokenEntity.Payload},
+	})
+
+	err = s.tokenService.DeleteToken(ctx, token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.SendPasswordReset(ctx, tokenEntity.UserId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ForgotPassword sends a password reset email if the account exists.
+// It never reports whether it does, so the endpoint can not be used to probe emails.
+func (s *Service) ForgotPassword(ctx context.Context, req dtos.ForgotPasswordRequest) error {
+	const op = "services.auth.ForgotPassword"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	user, err := s.userService.UserByEmail(ctx, req.Email)
+	if err != nil {
+		log.Info("password reset requested for unknown account", logger.Err(err))
+		return nil
+	}
+
+	err = s.SendPasswordReset(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to send password reset", logger.Err(err))
+	}
+
+	return nil
+}
+
+func (s *Service) SendPasswordReset(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.auth.SendPasswordReset"
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.tokenService.CreateToken(ctx, user.ID, consts.TokenTypeResetPassword)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.passwordResetSender.SendPasswordReset(user.Email, userLocale(ctx, user), token, user.Login)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventPasswordReset,
+		UserId: user.ID,
+	})
+
+	return nil
+}
+
+func (s *Service) ResetPassword(ctx context.Context, req dtos.ResetPasswordRequest) error {
+	const op = "services.auth.ResetPassword"
+
+	token, err := s.tokenService.Token(ctx, req.Token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if token.Type != consts.TokenTypeResetPassword {
+		return fmt.Errorf("%s: %w", op, errs.ErrTokenNotFound)
+	}
+
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.userService.UpdatePassword(ctx, token.UserId, string(hashPassword))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventPasswordChange,
+		UserId: token.UserId,
+	})
+
+	// every reset link sent to the user stops working, not only the used one
+	err = s.tokenService.DeleteUserTokens(ctx, token.UserId, consts.TokenTypeResetPassword)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.sessionService.RevokeUserSessions(ctx, token.UserId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Reauthenticate re-checks the password of the logged in user and unlocks
+// sensitive actions for the current session for a short time.
+func (s *Service) Reauthenticate(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId string,
+	req dtos.ReauthRequest,
+) (time.Time, error) {
+	const op = "services.auth.Reauthenticate"
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		s.auditor.Record(ctx, entities.AuditEvent{
+			Type:     consts.AuditEventReauthFailure,
+			UserId:   userId,
+			Reason:   consts.AuditReasonInvalidPassword,
+			Metadata: map[string]string{"session_id": sessionId},
+		})
+		return time.Time{}, fmt.Errorf("%s: %w", op, errs.ErrInvalidCredentials)
+	}
+
+	until, err := s.sessionService.ElevateSession(ctx, sessionId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventReauth,
+		UserId:   userId,
+		Metadata: map[string]string{"session_id": sessionId},
+	})
+
+	return until, nil
+}
+
+// ChangePassword sets a new password for the logged in user and signs out all of their sessions.
+func (s *Service) ChangePassword(ctx context.Context, userId uuid.UUID, req dtos.ChangePasswordRequest) error {
+	const op = "services.auth.ChangePassword"
+
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.userService.UpdatePassword(ctx, userId, string(hashPassword))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventPasswordChange,
+		UserId: userId,
+	})
+
+	err = s.sessionService.RevokeUserSessions(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RequestEmailChange mails a verification link to the new address. The email of the
+// account is switched only when the link is followed, see the user service VerifyEmail.
+func (s *Service) RequestEmailChange(ctx context.Context, userId uuid.UUID, req dtos.ChangeEmailRequest) error {
+	const op = "services.auth.RequestEmailChange"
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.tokenService.CreateTokenWithPayload(ctx, user.ID, consts.TokenTypeChangeEmail, req.Email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.verificationSender.SendVerification(req.Email, userLocale(ctx, user), token, "", user.Login)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) Logout(ctx context.Context, sessionId string) error {
+	const op = "services.auth.Logout"
+
+	session, err := s.sessionService.DeleteSession(ctx, sessionId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventLogout,
+		UserId:   session.UserId,
+		Metadata: map[string]string{"session_id": session.ID.String()},
+	})
+
+	return nil
+}
+
+// sendVerification sends the link, the six-digit code or both, as the client asked at registration.
+func (s *Service) sendVerification(ctx context.Context, userId uuid.UUID, req dtos.RegisterRequest) error {
+	method := req.VerificationMethod
+	if method == "" {
+		method = consts.VerificationMethodLink
+	}
+
+	var token, code string
+	var err error
+	if method != consts.VerificationMethodCode {
+		token, err = s.tokenService.CreateToken(ctx, userId, consts.TokenTypeVerifyEmail)
+		if err != nil {
+			return err
+		}
+	}
+	if method != consts.VerificationMethodLink {
+		code, err = s.verificationCodes.CreateCode(ctx, userId, req.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.verificationSender.SendVerification(req.Email, requestLocale(ctx), token, code, req.Login)
+}
+
+func (s *Service) recordLoginFailure(ctx context.Context, userId uuid.UUID, email, reason string) {
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventLoginFailure,
+		UserId:   userId,
+		Reason:   reason,
+		Metadata: map[string]string{"email": email},
+	})
+}
+
+// checkDevice remembers the login device and alerts the user if it was not seen before.
+// Errors are only logged: device tracking must not block the login.
+func (s *Service) checkDevice(ctx context.Context, user entities.User, sessionId uuid.UUID, userAgent string) {
+	const op = "services.auth.checkDevice"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	ip, _ := ctx.Value(consts.ContextClientIP).(string)
+	device, isNew, err := s.deviceService.RememberDevice(ctx, user.ID, userAgent, ip)
+	if err != nil {
+		log.Error("failed to remember device", logger.Err(err))
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventNewDevice,
+		UserId: user.ID,
+		Metadata: map[string]string{
+			"session_id": sessionId.String(),
+			"browser":    device.Browser,
+			"os":         device.OS,
+			"ip_prefix":  device.IPPrefix,
+		},
+	})
+
+	token, err := s.tokenService.CreateTokenWithPayload(ctx, user.ID, consts.TokenTypeNotMe, sessionId.String())
+	if err != nil {
+		log.Error("failed to create not me token", logger.Err(err))
+		return
+	}
+
+	err = s.newDeviceAlertSender.SendNewDeviceAlert(user.Email, userLocale(ctx, user), token, user.Login, device)
+	if err != nil {
+		log.Error("failed to send new device alert", logger.Err(err))
+	}
+}
+
+// releaseInvite gives the invite use back after a failed registration.
+func (s *Service) releaseInvite(ctx context.Context, code string) {
+	const op = "services.auth.releaseInvite"
+
+	err := s.inviteService.ReleaseInvite(ctx, code)
+	if err != nil {
+		logger.FromCtx(ctx).Error(
+			"failed to release invite",
+			slog.String("op", op),
+			logger.Err(err),
+		)
+	}
+}
+
+// registerExisting answers a sign up for a taken email like a successful one
+// and lets the owner know about the attempt instead.
+func (s *Service) registerExisting(ctx context.Context, email string) string {
+	const op = "services.auth.registerExisting"
+
+	err := s.accountExistsSender.SendAccountExists(email, requestLocale(ctx))
+	if err != nil {
+		logger.FromCtx(ctx).Error(
+			"failed to send account exists email",
+			slog.String("op", op),
+			logger.Err(err),
+		)
+	}
+
+	return uuid.NewString()
+}
+
+// requestLocale is the locale negotiated for the current request, empty means the default one.
+func requestLocale(ctx context.Context) string {
+	locale, _ := ctx.Value(consts.ContextLocale).(string)
+	return locale
+}
+
+// userLocale prefers the locale from the user profile, mail may be triggered by someone else, e.g. an admin.
+func userLocale(ctx context.Context, user entities.User) string {
+	if user.Locale != "" {
+		return user.Locale
 	}
 
-	return sessionId.String(), nil
+	return requestLocale(ctx)
 }
@@ -2,8 +2,11 @@ package auth_service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
@@ -23,6 +26,7 @@ func TestService_Register(t *testing.T) {
 	tests := []struct {
 		name                string
 		args                args
+		wantGuardErr        error
 		wantUserErr         error
 		wantVerificationErr error
 		wantTokenErr        error
@@ -103,6 +107,19 @@ func TestService_Register(t *testing.T) {
 			wantTokenErr:        nil,
 			wantErr:             errs.ErrTokenNotFound,
 		},
+		{
+			name: "guard rejection case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.RegisterRequest{
+					Login:    "admin",
+					Email:    "test@test.com",
+					Password: "test",
+				},
+			},
+			wantGuardErr: errs.ErrReservedLogin,
+			wantErr:      errs.ErrReservedLogin,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -112,6 +129,12 @@ func TestService_Register(t *testing.T) {
 			mUserService := NewMockUserService(t)
 			mVerificationSender := NewMockVerificationSender(t)
 			mTokenService := NewMockTokenService(t)
+			mGuard := NewMockRegistrationGuard(t)
+
+			mGuard.EXPECT().Check(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("dtos.RegisterRequest"),
+			).Return(tt.wantGuardErr).Once()
 
 			mUserService.EXPECT().CreateUser(
 				mock.AnythingOfType("context.backgroundCtx"),
@@ -130,12 +153,22 @@ func TestService_Register(t *testing.T) {
 				mock.AnythingOfType("string"),
 				mock.AnythingOfType("string"),
 				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
 			).Return(tt.wantVerificationErr).Maybe()
 
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
 			s := &Service{
 				userService:        mUserService,
 				verificationSender: mVerificationSender,
 				tokenService:       mTokenService,
+				registrationGuard:  mGuard,
+				auditor:            mAuditor,
 			}
 			_, err := s.Register(tt.args.ctx, tt.args.req)
 			require.ErrorIs(t, err, tt.wantErr)
@@ -143,6 +176,132 @@ func TestService_Register(t *testing.T) {
 	}
 }
 
+func TestService_Register_Modes(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		inviteCode     string
+		wantConsumeErr error
+		wantUserErr    error
+		wantRelease    bool
+		wantRedeem     bool
+		wantErr        error
+	}{
+		{
+			name:    "closed case",
+			mode:    consts.RegistrationModeClosed,
+			wantErr: errs.ErrRegistrationClosed,
+		},
+		{
+			name:    "missing invite case",
+			mode:    consts.RegistrationModeInviteOnly,
+			wantErr: errs.ErrInviteInvalid,
+		},
+		{
+			name:           "invalid invite case",
+			mode:           consts.RegistrationModeInviteOnly,
+			inviteCode:     "CODE",
+			wantConsumeErr: errs.ErrInviteInvalid,
+			wantErr:        errs.ErrInviteInvalid,
+		},
+		{
+			name:        "user error releases invite case",
+			mode:        consts.RegistrationModeInviteOnly,
+			inviteCode:  "CODE",
+			wantUserErr: errs.ErrUserAlreadyExists,
+			wantRelease: true,
+			wantErr:     errs.ErrUserAlreadyExists,
+		},
+		{
+			name:       "invite redeemed case",
+			mode:       consts.RegistrationModeInviteOnly,
+			inviteCode: "CODE",
+			wantRedeem: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mVerificationSender := NewMockVerificationSender(t)
+			mTokenService := NewMockTokenService(t)
+			mGuard := NewMockRegistrationGuard(t)
+			mInviteService := NewMockInviteService(t)
+
+			mGuard.EXPECT().Check(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("dtos.RegisterRequest"),
+			).Return(nil).Maybe()
+
+			mInviteService.EXPECT().ConsumeInvite(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.inviteCode,
+			).Return(tt.wantConsumeErr).Maybe()
+
+			userId := uuid.New()
+			mUserService.EXPECT().CreateUser(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+			).Return(userId, tt.wantUserErr).Maybe()
+
+			if tt.wantRelease {
+				mInviteService.EXPECT().ReleaseInvite(
+					mock.AnythingOfType("context.backgroundCtx"),
+					tt.inviteCode,
+				).Return(nil).Once()
+			}
+			if tt.wantRedeem {
+				mInviteService.EXPECT().RecordRedemption(
+					mock.AnythingOfType("context.backgroundCtx"),
+					tt.inviteCode,
+					userId,
+				).Return(nil).Once()
+			}
+
+			mTokenService.EXPECT().CreateToken(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("string"),
+			).Return("token", nil).Maybe()
+
+			mVerificationSender.EXPECT().SendVerification(
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+			).Return(nil).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:        mUserService,
+				verificationSender: mVerificationSender,
+				tokenService:       mTokenService,
+				registrationGuard:  mGuard,
+				inviteService:      mInviteService,
+				auditor:            mAuditor,
+				registrationMode:   tt.mode,
+			}
+			_, err := s.Register(context.Background(), dtos.RegisterRequest{
+				Login:      "some login",
+				Email:      "test@test.com",
+				Password:   "test",
+				InviteCode: tt.inviteCode,
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestService_Login(t *testing.T) {
 	type args struct {
 		ctx       context.Context
@@ -152,12 +311,17 @@ func TestService_Login(t *testing.T) {
 
 	password := "test"
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	expired := time.Now().Add(-time.Hour)
 
 	tests := []struct {
 		name           string
 		args           args
+		suspension     *entities.Suspension
+		newDevice      bool
 		wantUserErr    error
 		wantSessionErr error
+		wantDeviceErr  error
+		wantAlertErr   error
 		wantErr        error
 	}{
 		{
@@ -202,6 +366,43 @@ func TestService_Login(t *testing.T) {
 			wantSessionErr: nil,
 			wantErr:        errs.ErrUserNotFound,
 		},
+		{
+			name: "suspended user case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.LoginRequest{
+					Email:    "test@test.com",
+					Password: password,
+				},
+				userAgent: "firefox",
+			},
+			suspension: &entities.Suspension{
+				Type:   consts.SuspensionTypeBan,
+				Reason: "spam",
+			},
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        errs.ErrUserSuspended,
+		},
+		{
+			name: "expired suspension case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.LoginRequest{
+					Email:    "test@test.com",
+					Password: password,
+				},
+				userAgent: "firefox",
+			},
+			suspension: &entities.Suspension{
+				Type:      consts.SuspensionTypeSuspend,
+				Reason:    "spam",
+				ExpiresAt: &expired,
+			},
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        nil,
+		},
 		{
 			name: "session error case",
 			args: args{
@@ -216,6 +417,52 @@ func TestService_Login(t *testing.T) {
 			wantSessionErr: errs.ErrSessionNotFound,
 			wantErr:        errs.ErrSessionNotFound,
 		},
+		{
+			name: "new device case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.LoginRequest{
+					Email:    "test@test.com",
+					Password: password,
+				},
+				userAgent: "firefox",
+			},
+			newDevice:      true,
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantErr:        nil,
+		},
+		{
+			name: "device error case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.LoginRequest{
+					Email:    "test@test.com",
+					Password: password,
+				},
+				userAgent: "firefox",
+			},
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantDeviceErr:  errors.New("device error"),
+			wantErr:        nil,
+		},
+		{
+			name: "alert error case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.LoginRequest{
+					Email:    "test@test.com",
+					Password: password,
+				},
+				userAgent: "firefox",
+			},
+			newDevice:      true,
+			wantUserErr:    nil,
+			wantSessionErr: nil,
+			wantAlertErr:   errors.New("smtp error"),
+			wantErr:        nil,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -229,21 +476,725 @@ func TestService_Login(t *testing.T) {
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("string"),
 			).Return(entities.User{
-				Password: string(hash),
+				Password:   string(hash),
+				Suspension: tt.suspension,
 			}, tt.wantUserErr).Once()
 
 			mSessionService.EXPECT().CreateSession(
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("uuid.UUID"),
 				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
 			).Return(uuid.New(), tt.wantSessionErr).Maybe()
 
+			mDeviceService := NewMockDeviceService(t)
+			mDeviceService.EXPECT().RememberDevice(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+			).Return(entities.Device{}, tt.newDevice, tt.wantDeviceErr).Maybe()
+
+			mTokenService := NewMockTokenService(t)
+			mAlertSender := NewMockNewDeviceAlertSender(t)
+			if tt.newDevice {
+				mTokenService.EXPECT().CreateTokenWithPayload(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("uuid.UUID"),
+					consts.TokenTypeNotMe,
+					mock.AnythingOfType("string"),
+				).Return(uuid.NewString(), nil).Once()
+				mAlertSender.EXPECT().SendNewDeviceAlert(
+					mock.AnythingOfType("string"),
+					mock.AnythingOfType("string"),
+					mock.AnythingOfType("string"),
+					mock.AnythingOfType("string"),
+					mock.AnythingOfType("entities.Device"),
+				).Return(tt.wantAlertErr).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:          mUserService,
+				newDeviceAlertSender: mAlertSender,
+				tokenService:         mTokenService,
+				sessionService:       mSessionService,
+				deviceService:        mDeviceService,
+				auditor:              mAuditor,
+			}
+			_, _, err := s.Login(tt.args.ctx, tt.args.req, tt.args.userAgent)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name               string
+		tokenType          string
+		wantTokenErr       error
+		wantUserErr        error
+		wantDeleteTokenErr error
+		wantSessionErr     error
+		wantErr            error
+	}{
+		{
+			name:      "good case",
+			tokenType: consts.TokenTypeResetPassword,
+			wantErr:   nil,
+		},
+		{
+			name:         "token not found case",
+			tokenType:    consts.TokenTypeResetPassword,
+			wantTokenErr: errs.ErrTokenNotFound,
+			wantErr:      errs.ErrTokenNotFound,
+		},
+		{
+			name:      "wrong token type case",
+			tokenType: consts.TokenTypeVerifyEmail,
+			wantErr:   errs.ErrTokenNotFound,
+		},
+		{
+			name:        "user error case",
+			tokenType:   consts.TokenTypeResetPassword,
+			wantUserErr: errs.ErrUserNotFound,
+			wantErr:     errs.ErrUserNotFound,
+		},
+		{
+			name:               "delete token error case",
+			tokenType:          consts.TokenTypeResetPassword,
+			wantDeleteTokenErr: errs.ErrTokenNotFound,
+			wantErr:            errs.ErrTokenNotFound,
+		},
+		{
+			name:           "session error case",
+			tokenType:      consts.TokenTypeResetPassword,
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mTokenService := NewMockTokenService(t)
+			mSessionService := NewMockSessionService(t)
+
+			userId := uuid.New()
+
+			mTokenService.EXPECT().Token(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+			).Return(entities.Token{UserId: userId, Type: tt.tokenType}, tt.wantTokenErr).Once()
+
+			mUserService.EXPECT().UpdatePassword(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				mock.AnythingOfType("string"),
+			).Return(tt.wantUserErr).Maybe()
+
+			mTokenService.EXPECT().DeleteUserTokens(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				consts.TokenTypeResetPassword,
+			).Return(tt.wantDeleteTokenErr).Maybe()
+
+			mSessionService.EXPECT().RevokeUserSessions(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				tokenService:   mTokenService,
+				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			err := s.ResetPassword(context.Background(), dtos.ResetPasswordRequest{
+				Token:    uuid.NewString(),
+				Password: "new password",
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_NotMe(t *testing.T) {
+	sessionId := uuid.NewString()
+
+	tests := []struct {
+		name           string
+		tokenType      string
+		wantTokenErr   error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:      "good case",
+			tokenType: consts.TokenTypeNotMe,
+		},
+		{
+			name:           "session already gone case",
+			tokenType:      consts.TokenTypeNotMe,
+			wantSessionErr: errs.ErrSessionNotFound,
+		},
+		{
+			name:           "session already revoked case",
+			tokenType:      consts.TokenTypeNotMe,
+			wantSessionErr: errs.ErrSessionRevoked,
+		},
+		{
+			name:         "token not found case",
+			tokenType:    consts.TokenTypeNotMe,
+			wantTokenErr: errs.ErrTokenNotFound,
+			wantErr:      errs.ErrTokenNotFound,
+		},
+		{
+			name:      "wrong token type case",
+			tokenType: consts.TokenTypeResetPassword,
+			wantErr:   errs.ErrTokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			mTokenService := NewMockTokenService(t)
+			mSessionService := NewMockSessionService(t)
+			mUserService := NewMockUserService(t)
+			mResetSender := NewMockPasswordResetSender(t)
+
+			mTokenService.EXPECT().Token(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+			).Return(entities.Token{
+				UserId:  userId,
+				Type:    tt.tokenType,
+				Payload: sessionId,
+			}, tt.wantTokenErr).Once()
+
+			mSessionService.EXPECT().RevokeSession(
+				mock.AnythingOfType("context.backgroundCtx"),
+				sessionId,
+			).Return(entities.Session{}, tt.wantSessionErr).Maybe()
+
+			mTokenService.EXPECT().DeleteToken(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+			).Return(nil).Maybe()
+
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId}, nil).Maybe()
+
+			mTokenService.EXPECT().CreateToken(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				consts.TokenTypeResetPassword,
+			).Return(uuid.NewString(), nil).Maybe()
+
+			mResetSender.EXPECT().SendPasswordReset(
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+			).Return(nil).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:         mUserService,
+				passwordResetSender: mResetSender,
+				tokenService:        mTokenService,
+				sessionService:      mSessionService,
+				auditor:             mAuditor,
+			}
+			err := s.NotMe(context.Background(), uuid.NewString())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_Hardened(t *testing.T) {
+	password := "test"
+	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	loginTests := []struct {
+		name        string
+		password    string
+		wantUserErr error
+	}{
+		{
+			name:        "unknown email case",
+			password:    password,
+			wantUserErr: errs.ErrUserNotFound,
+		},
+		{
+			name:        "email not verify case",
+			password:    password,
+			wantUserErr: errs.ErrUserEmailNotVerify,
+		},
+		{
+			name:     "invalid password case",
+			password: "invalid",
+		},
+	}
+	for _, tt := range loginTests {
+		tt := tt
+		t.Run("login "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().UserByEmail(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+			).Return(entities.User{Password: string(hash)}, tt.wantUserErr).Once()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService: mUserService,
+				auditor:     mAuditor,
+				hardened:    true,
+			}
+			_, _, err := s.Login(context.Background(), dtos.LoginRequest{
+				Email:    "test@test.com",
+				Password: tt.password,
+			}, "firefox")
+			require.ErrorIs(t, err, errs.ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("register existing email case", func(t *testing.T) {
+		t.Parallel()
+
+		mUserService := NewMockUserService(t)
+		mUserService.EXPECT().CreateUser(
+			mock.AnythingOfType("context.backgroundCtx"),
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"),
+		).Return(uuid.Nil, errs.ErrUserAlreadyExists).Once()
+
+		mGuard := NewMockRegistrationGuard(t)
+		mGuard.EXPECT().Check(
+			mock.AnythingOfType("context.backgroundCtx"),
+			mock.AnythingOfType("dtos.RegisterRequest"),
+		).Return(nil).Once()
+
+		mAccountExistsSender := NewMockAccountExistsSender(t)
+		mAccountExistsSender.EXPECT().SendAccountExists("test@test.com", "").Return(nil).Once()
+
+		s := &Service{
+			userService:         mUserService,
+			registrationGuard:   mGuard,
+			accountExistsSender: mAccountExistsSender,
+			hardened:            true,
+		}
+		id, err := s.Register(context.Background(), dtos.RegisterRequest{
+			Login:    "some login",
+			Email:    "test@test.com",
+			Password: "test",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+	})
+}
+
+func TestService_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantUserErr error
+		wantSend    bool
+	}{
+		{
+			name:     "existing account case",
+			wantSend: true,
+		},
+		{
+			name:        "unknown account case",
+			wantUserErr: errs.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			mUserService := NewMockUserService(t)
+			mTokenService := NewMockTokenService(t)
+			mResetSender := NewMockPasswordResetSender(t)
+
+			mUserService.EXPECT().UserByEmail(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"test@test.com",
+			).Return(entities.User{ID: userId, Email: "test@test.com"}, tt.wantUserErr).Once()
+
+			if tt.wantSend {
+				mUserService.EXPECT().UserById(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+				).Return(entities.User{ID: userId, Email: "test@test.com", Locale: "ru"}, nil).Once()
+				mTokenService.EXPECT().CreateToken(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					consts.TokenTypeResetPassword,
+				).Return(uuid.NewString(), nil).Once()
+				mResetSender.EXPECT().SendPasswordReset(
+					"test@test.com",
+					"ru",
+					mock.AnythingOfType("string"),
+					mock.AnythingOfType("string"),
+				).Return(nil).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:         mUserService,
+				passwordResetSender: mResetSender,
+				tokenService:        mTokenService,
+				auditor:             mAuditor,
+			}
+			err := s.ForgotPassword(context.Background(), dtos.ForgotPasswordRequest{Email: "test@test.com"})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Reauthenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("qwerty123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		password       string
+		wantUserErr    error
+		wantSessionErr error
+		wantAudit      string
+		wantErr        error
+	}{
+		{
+			name:      "good case",
+			password:  "qwerty123",
+			wantAudit: consts.AuditEventReauth,
+			wantErr:   nil,
+		},
+		{
+			name:      "wrong password case",
+			password:  "wrong password",
+			wantAudit: consts.AuditEventReauthFailure,
+			wantErr:   errs.ErrInvalidCredentials,
+		},
+		{
+			name:        "user not found case",
+			password:    "qwerty123",
+			wantUserErr: errs.ErrUserNotFound,
+			wantErr:     errs.ErrUserNotFound,
+		},
+		{
+			name:           "session not found case",
+			password:       "qwerty123",
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			sessionId := uuid.NewString()
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId, Password: string(hash)}, tt.wantUserErr).Once()
+
+			mSessionService := NewMockSessionService(t)
+			mSessionService.EXPECT().ElevateSession(
+				mock.AnythingOfType("context.backgroundCtx"),
+				sessionId,
+			).Return(time.Now().Add(10*time.Minute), tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantAudit != "" {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.MatchedBy(func(event entities.AuditEvent) bool {
+						return event.Type == tt.wantAudit && event.UserId == userId
+					}),
+				).Return().Once()
+			}
+
 			s := &Service{
 				userService:    mUserService,
 				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			_, err := s.Reauthenticate(context.Background(), userId, sessionId, dtos.ReauthRequest{
+				Password: tt.password,
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		wantUserErr    error
+		wantSessionErr error
+		wantErr        error
+	}{
+		{
+			name:    "good case",
+			wantErr: nil,
+		},
+		{
+			name:        "impersonation case",
+			wantUserErr: errs.ErrImpersonation,
+			wantErr:     errs.ErrImpersonation,
+		},
+		{
+			name:           "session error case",
+			wantSessionErr: errs.ErrSessionNotFound,
+			wantErr:        errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().UpdatePassword(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				mock.AnythingOfType("string"),
+			).Return(tt.wantUserErr).Once()
+
+			mSessionService := NewMockSessionService(t)
+			mSessionService.EXPECT().RevokeUserSessions(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(tt.wantSessionErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:    mUserService,
+				sessionService: mSessionService,
+				auditor:        mAuditor,
+			}
+			err := s.ChangePassword(context.Background(), userId, dtos.ChangePasswordRequest{
+				Password: "new password",
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_RequestEmailChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantUserErr error
+		wantSendErr error
+		wantErr     error
+	}{
+		{
+			name:    "good case",
+			wantErr: nil,
+		},
+		{
+			name:        "user not found case",
+			wantUserErr: errs.ErrUserNotFound,
+			wantErr:     errs.ErrUserNotFound,
+		},
+		{
+			name:        "send error case",
+			wantSendErr: errs.ErrTokenNotFound,
+			wantErr:     errs.ErrTokenNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			newEmail := "new@test.com"
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId, Login: "login", Email: "old@test.com"}, tt.wantUserErr).Once()
+
+			mTokenService := NewMockTokenService(t)
+			mTokenService.EXPECT().CreateTokenWithPayload(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				consts.TokenTypeChangeEmail,
+				newEmail,
+			).Return("token", nil).Maybe()
+
+			// the link goes to the new address, not to the current one
+			mSender := NewMockVerificationSender(t)
+			mSender.EXPECT().SendVerification(
+				newEmail,
+				mock.AnythingOfType("string"),
+				"token",
+				"",
+				"login",
+			).Return(tt.wantSendErr).Maybe()
+
+			s := &Service{
+				userService:        mUserService,
+				tokenService:       mTokenService,
+				verificationSender: mSender,
 			}
-			_, err := s.Login(tt.args.ctx, tt.args.req, tt.args.userAgent)
+			err := s.RequestEmailChange(context.Background(), userId, dtos.ChangeEmailRequest{Email: newEmail})
 			require.ErrorIs(t, err, tt.wantErr)
 		})
 	}
 }
+
+func TestService_Register_VerificationMethod(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		wantToken string
+		wantCode  string
+	}{
+		{
+			name:      "default case",
+			method:    "",
+			wantToken: "token",
+		},
+		{
+			name:      "link case",
+			method:    consts.VerificationMethodLink,
+			wantToken: "token",
+		},
+		{
+			name:     "code case",
+			method:   consts.VerificationMethodCode,
+			wantCode: "123456",
+		},
+		{
+			name:      "both case",
+			method:    consts.VerificationMethodBoth,
+			wantToken: "token",
+			wantCode:  "123456",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mGuard := NewMockRegistrationGuard(t)
+			mGuard.EXPECT().Check(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("dtos.RegisterRequest"),
+			).Return(nil).Once()
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().CreateUser(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+			).Return(userId, nil).Once()
+
+			mTokenService := NewMockTokenService(t)
+			if tt.wantToken != "" {
+				mTokenService.EXPECT().CreateToken(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					consts.TokenTypeVerifyEmail,
+				).Return(tt.wantToken, nil).Once()
+			}
+
+			mCodes := NewMockVerificationCodeService(t)
+			if tt.wantCode != "" {
+				mCodes.EXPECT().CreateCode(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					"test@test.com",
+				).Return(tt.wantCode, nil).Once()
+			}
+
+			mVerificationSender := NewMockVerificationSender(t)
+			mVerificationSender.EXPECT().SendVerification(
+				"test@test.com",
+				"",
+				tt.wantToken,
+				tt.wantCode,
+				"some login",
+			).Return(nil).Once()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				userService:        mUserService,
+				verificationSender: mVerificationSender,
+				tokenService:       mTokenService,
+				verificationCodes:  mCodes,
+				registrationGuard:  mGuard,
+				auditor:            mAuditor,
+			}
+			_, err := s.Register(context.Background(), dtos.RegisterRequest{
+				Login:              "some login",
+				Email:              "test@test.com",
+				Password:           "test",
+				VerificationMethod: tt.method,
+			})
+			require.NoError(t, err)
+		})
+	}
+}
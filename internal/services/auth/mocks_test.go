@@ -6,7 +6,9 @@ package auth_service
 
 import (
 	"context"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -119,6 +121,69 @@ func (_c *MockUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// UpdatePassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	ret := _mock.Called(ctx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type MockUserService_UpdatePassword_Call struct {
+	*mock.Call
+}
+
+// UpdatePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - password string
+func (_e *MockUserService_Expecter) UpdatePassword(ctx interface{}, id interface{}, password interface{}) *MockUserService_UpdatePassword_Call {
+	return &MockUserService_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, id, password)}
+}
+
+func (_c *MockUserService_UpdatePassword_Call) Run(run func(ctx context.Context, id uuid.UUID, password string)) *MockUserService_UpdatePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_UpdatePassword_Call) Return(err error) *MockUserService_UpdatePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_UpdatePassword_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, password string) error) *MockUserService_UpdatePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UserByEmail provides a mock function for the type MockUserService
 func (_mock *MockUserService) UserByEmail(ctx context.Context, email string) (entities.User, error) {
 	ret := _mock.Called(ctx, email)
@@ -185,6 +250,72 @@ func (_c *MockUserService_UserByEmail_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
+// UserById provides a mock function for the type MockUserService
+func (_mock *MockUserService) UserById(ctx context.Context, id uuid.UUID) (entities.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserById")
+	}
+
+	var r0 entities.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (entities.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) entities.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_UserById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserById'
+type MockUserService_UserById_Call struct {
+	*mock.Call
+}
+
+// UserById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockUserService_Expecter) UserById(ctx interface{}, id interface{}) *MockUserService_UserById_Call {
+	return &MockUserService_UserById_Call{Call: _e.mock.On("UserById", ctx, id)}
+}
+
+func (_c *MockUserService_UserById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockUserService_UserById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_UserById_Call) Return(user entities.User, err error) *MockUserService_UserById_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserService_UserById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (entities.User, error)) *MockUserService_UserById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockVerificationSender creates a new instance of MockVerificationSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockVerificationSender(t interface {
@@ -213,16 +344,16 @@ func (_m *MockVerificationSender) EXPECT() *MockVerificationSender_Expecter {
 }
 
 // SendVerification provides a mock function for the type MockVerificationSender
-func (_mock *MockVerificationSender) SendVerification(to string, token string, login string) error {
-	ret := _mock.Called(to, token, login)
+func (_mock *MockVerificationSender) SendVerification(to string, locale string, token string, code string, login string) error {
+	ret := _mock.Called(to, locale, token, code, login)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendVerification")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = returnFunc(to, token, login)
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
+		r0 = returnFunc(to, locale, token, code, login)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -236,13 +367,15 @@ type MockVerificationSender_SendVerification_Call struct {
 
 // SendVerification is a helper method to define mock.On call
 //   - to string
+//   - locale string
 //   - token string
+//   - code string
 //   - login string
-func (_e *MockVerificationSender_Expecter) SendVerification(to interface{}, token interface{}, login interface{}) *MockVerificationSender_SendVerification_Call {
-	return &MockVerificationSender_SendVerification_Call{Call: _e.mock.On("SendVerification", to, token, login)}
+func (_e *MockVerificationSender_Expecter) SendVerification(to interface{}, locale interface{}, token interface{}, code interface{}, login interface{}) *MockVerificationSender_SendVerification_Call {
+	return &MockVerificationSender_SendVerification_Call{Call: _e.mock.On("SendVerification", to, locale, token, code, login)}
 }
 
-func (_c *MockVerificationSender_SendVerification_Call) Run(run func(to string, token string, login string)) *MockVerificationSender_SendVerification_Call {
+func (_c *MockVerificationSender_SendVerification_Call) Run(run func(to string, locale string, token string, code string, login string)) *MockVerificationSender_SendVerification_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
@@ -256,10 +389,20 @@ func (_c *MockVerificationSender_SendVerification_Call) Run(run func(to string,
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
@@ -270,18 +413,18 @@ func (_c *MockVerificationSender_SendVerification_Call) Return(err error) *MockV
 	return _c
 }
 
-func (_c *MockVerificationSender_SendVerification_Call) RunAndReturn(run func(to string, token string, login string) error) *MockVerificationSender_SendVerification_Call {
+func (_c *MockVerificationSender_SendVerification_Call) RunAndReturn(run func(to string, locale string, token string, code string, login string) error) *MockVerificationSender_SendVerification_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockTokenService creates a new instance of MockTokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockPasswordResetSender creates a new instance of MockPasswordResetSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockTokenService(t interface {
+func NewMockPasswordResetSender(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockTokenService {
-	mock := &MockTokenService{}
+}) *MockPasswordResetSender {
+	mock := &MockPasswordResetSender{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -289,98 +432,95 @@ func NewMockTokenService(t interface {
 	return mock
 }
 
-// MockTokenService is an autogenerated mock type for the TokenService type
-type MockTokenService struct {
+// MockPasswordResetSender is an autogenerated mock type for the PasswordResetSender type
+type MockPasswordResetSender struct {
 	mock.Mock
 }
 
-type MockTokenService_Expecter struct {
+type MockPasswordResetSender_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockTokenService) EXPECT() *MockTokenService_Expecter {
-	return &MockTokenService_Expecter{mock: &_m.Mock}
+func (_m *MockPasswordResetSender) EXPECT() *MockPasswordResetSender_Expecter {
+	return &MockPasswordResetSender_Expecter{mock: &_m.Mock}
 }
 
-// CreateToken provides a mock function for the type MockTokenService
-func (_mock *MockTokenService) CreateToken(ctx context.Context, userId uuid.UUID, tokenType string) (string, error) {
-	ret := _mock.Called(ctx, userId, tokenType)
+// SendPasswordReset provides a mock function for the type MockPasswordResetSender
+func (_mock *MockPasswordResetSender) SendPasswordReset(to string, locale string, token string, login string) error {
+	ret := _mock.Called(to, locale, token, login)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateToken")
+		panic("no return value specified for SendPasswordReset")
 	}
 
-	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (string, error)); ok {
-		return returnFunc(ctx, userId, tokenType)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) string); ok {
-		r0 = returnFunc(ctx, userId, tokenType)
-	} else {
-		r0 = ret.Get(0).(string)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
-		r1 = returnFunc(ctx, userId, tokenType)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = returnFunc(to, locale, token, login)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockTokenService_CreateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateToken'
-type MockTokenService_CreateToken_Call struct {
+// MockPasswordResetSender_SendPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendPasswordReset'
+type MockPasswordResetSender_SendPasswordReset_Call struct {
 	*mock.Call
 }
 
-// CreateToken is a helper method to define mock.On call
-//   - ctx context.Context
-//   - userId uuid.UUID
-//   - tokenType string
-func (_e *MockTokenService_Expecter) CreateToken(ctx interface{}, userId interface{}, tokenType interface{}) *MockTokenService_CreateToken_Call {
-	return &MockTokenService_CreateToken_Call{Call: _e.mock.On("CreateToken", ctx, userId, tokenType)}
+// SendPasswordReset is a helper method to define mock.On call
+//   - to string
+//   - locale string
+//   - token string
+//   - login string
+func (_e *MockPasswordResetSender_Expecter) SendPasswordReset(to interface{}, locale interface{}, token interface{}, login interface{}) *MockPasswordResetSender_SendPasswordReset_Call {
+	return &MockPasswordResetSender_SendPasswordReset_Call{Call: _e.mock.On("SendPasswordReset", to, locale, token, login)}
 }
 
-func (_c *MockTokenService_CreateToken_Call) Run(run func(ctx context.Context, userId uuid.UUID, tokenType string)) *MockTokenService_CreateToken_Call {
+func (_c *MockPasswordResetSender_SendPasswordReset_Call) Run(run func(to string, locale string, token string, login string)) *MockPasswordResetSender_SendPasswordReset_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 context.Context
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(context.Context)
+			arg0 = args[0].(string)
 		}
-		var arg1 uuid.UUID
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(string)
 		}
 		var arg2 string
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTokenService_CreateToken_Call) Return(s string, err error) *MockTokenService_CreateToken_Call {
-	_c.Call.Return(s, err)
+func (_c *MockPasswordResetSender_SendPasswordReset_Call) Return(err error) *MockPasswordResetSender_SendPasswordReset_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockTokenService_CreateToken_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, tokenType string) (string, error)) *MockTokenService_CreateToken_Call {
+func (_c *MockPasswordResetSender_SendPasswordReset_Call) RunAndReturn(run func(to string, locale string, token string, login string) error) *MockPasswordResetSender_SendPasswordReset_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockSessionService creates a new instance of MockSessionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockNewDeviceAlertSender creates a new instance of MockNewDeviceAlertSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockSessionService(t interface {
+func NewMockNewDeviceAlertSender(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockSessionService {
-	mock := &MockSessionService{}
+}) *MockNewDeviceAlertSender {
+	mock := &MockNewDeviceAlertSender{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -388,89 +528,1470 @@ func NewMockSessionService(t interface {
 	return mock
 }
 
-// MockSessionService is an autogenerated mock type for the SessionService type
-type MockSessionService struct {
+// MockNewDeviceAlertSender is an autogenerated mock type for the NewDeviceAlertSender type
+type MockNewDeviceAlertSender struct {
 	mock.Mock
 }
 
-type MockSessionService_Expecter struct {
+type MockNewDeviceAlertSender_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockSessionService) EXPECT() *MockSessionService_Expecter {
-	return &MockSessionService_Expecter{mock: &_m.Mock}
+func (_m *MockNewDeviceAlertSender) EXPECT() *MockNewDeviceAlertSender_Expecter {
+	return &MockNewDeviceAlertSender_Expecter{mock: &_m.Mock}
 }
 
-// CreateSession provides a mock function for the type MockSessionService
-func (_mock *MockSessionService) CreateSession(ctx context.Context, userId uuid.UUID, userAgent string) (uuid.UUID, error) {
-	ret := _mock.Called(ctx, userId, userAgent)
+// SendNewDeviceAlert provides a mock function for the type MockNewDeviceAlertSender
+func (_mock *MockNewDeviceAlertSender) SendNewDeviceAlert(to string, locale string, token string, login string, device entities.Device) error {
+	ret := _mock.Called(to, locale, token, login, device)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateSession")
+		panic("no return value specified for SendNewDeviceAlert")
 	}
 
-	var r0 uuid.UUID
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (uuid.UUID, error)); ok {
-		return returnFunc(ctx, userId, userAgent)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) uuid.UUID); ok {
-		r0 = returnFunc(ctx, userId, userAgent)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(uuid.UUID)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
-		r1 = returnFunc(ctx, userId, userAgent)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, string, entities.Device) error); ok {
+		r0 = returnFunc(to, locale, token, login, device)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockSessionService_CreateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSession'
-type MockSessionService_CreateSession_Call struct {
+// MockNewDeviceAlertSender_SendNewDeviceAlert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendNewDeviceAlert'
+type MockNewDeviceAlertSender_SendNewDeviceAlert_Call struct {
 	*mock.Call
 }
 
-// CreateSession is a helper method to define mock.On call
-//   - ctx context.Context
-//   - userId uuid.UUID
-//   - userAgent string
-func (_e *MockSessionService_Expecter) CreateSession(ctx interface{}, userId interface{}, userAgent interface{}) *MockSessionService_CreateSession_Call {
-	return &MockSessionService_CreateSession_Call{Call: _e.mock.On("CreateSession", ctx, userId, userAgent)}
+// SendNewDeviceAlert is a helper method to define mock.On call
+//   - to string
+//   - locale string
+//   - token string
+//   - login string
+//   - device entities.Device
+func (_e *MockNewDeviceAlertSender_Expecter) SendNewDeviceAlert(to interface{}, locale interface{}, token interface{}, login interface{}, device interface{}) *MockNewDeviceAlertSender_SendNewDeviceAlert_Call {
+	return &MockNewDeviceAlertSender_SendNewDeviceAlert_Call{Call: _e.mock.On("SendNewDeviceAlert", to, locale, token, login, device)}
 }
 
-func (_c *MockSessionService_CreateSession_Call) Run(run func(ctx context.Context, userId uuid.UUID, userAgent string)) *MockSessionService_CreateSession_Call {
+func (_c *MockNewDeviceAlertSender_SendNewDeviceAlert_Call) Run(run func(to string, locale string, token string, login string, device entities.Device)) *MockNewDeviceAlertSender_SendNewDeviceAlert_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 context.Context
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(context.Context)
+			arg0 = args[0].(string)
 		}
-		var arg1 uuid.UUID
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(string)
 		}
 		var arg2 string
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 entities.Device
+		if args[4] != nil {
+			arg4 = args[4].(entities.Device)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockSessionService_CreateSession_Call) Return(uUID uuid.UUID, err error) *MockSessionService_CreateSession_Call {
-	_c.Call.Return(uUID, err)
+func (_c *MockNewDeviceAlertSender_SendNewDeviceAlert_Call) Return(err error) *MockNewDeviceAlertSender_SendNewDeviceAlert_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSessionService_CreateSession_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, userAgent string) (uuid.UUID, error)) *MockSessionService_CreateSession_Call {
+func (_c *MockNewDeviceAlertSender_SendNewDeviceAlert_Call) RunAndReturn(run func(to string, locale string, token string, login string, device entities.Device) error) *MockNewDeviceAlertSender_SendNewDeviceAlert_Call {
 	_c.Call.Return(run)
 	return _c
 }
+
+// NewMockAccountExistsSender creates a new instance of MockAccountExistsSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAccountExistsSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAccountExistsSender {
+	mock := &MockAccountExistsSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAccountExistsSender is an autogenerated mock type for the AccountExistsSender type
+type MockAccountExistsSender struct {
+	mock.Mock
+}
+
+type MockAccountExistsSender_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAccountExistsSender) EXPECT() *MockAccountExistsSender_Expecter {
+	return &MockAccountExistsSender_Expecter{mock: &_m.Mock}
+}
+
+// SendAccountExists provides a mock function for the type MockAccountExistsSender
+func (_mock *MockAccountExistsSender) SendAccountExists(to string, locale string) error {
+	ret := _mock.Called(to, locale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAccountExists")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(to, locale)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAccountExistsSender_SendAccountExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendAccountExists'
+type MockAccountExistsSender_SendAccountExists_Call struct {
+	*mock.Call
+}
+
+// SendAccountExists is a helper method to define mock.On call
+//   - to string
+//   - locale string
+func (_e *MockAccountExistsSender_Expecter) SendAccountExists(to interface{}, locale interface{}) *MockAccountExistsSender_SendAccountExists_Call {
+	return &MockAccountExistsSender_SendAccountExists_Call{Call: _e.mock.On("SendAccountExists", to, locale)}
+}
+
+func (_c *MockAccountExistsSender_SendAccountExists_Call) Run(run func(to string, locale string)) *MockAccountExistsSender_SendAccountExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAccountExistsSender_SendAccountExists_Call) Return(err error) *MockAccountExistsSender_SendAccountExists_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAccountExistsSender_SendAccountExists_Call) RunAndReturn(run func(to string, locale string) error) *MockAccountExistsSender_SendAccountExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTokenService creates a new instance of MockTokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenService {
+	mock := &MockTokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTokenService is an autogenerated mock type for the TokenService type
+type MockTokenService struct {
+	mock.Mock
+}
+
+type MockTokenService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenService) EXPECT() *MockTokenService_Expecter {
+	return &MockTokenService_Expecter{mock: &_m.Mock}
+}
+
+// CreateToken provides a mock function for the type MockTokenService
+func (_mock *MockTokenService) CreateToken(ctx context.Context, userId uuid.UUID, tokenType string) (string, error) {
+	ret := _mock.Called(ctx, userId, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (string, error)); ok {
+		return returnFunc(ctx, userId, tokenType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) string); ok {
+		r0 = returnFunc(ctx, userId, tokenType)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = returnFunc(ctx, userId, tokenType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTokenService_CreateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateToken'
+type MockTokenService_CreateToken_Call struct {
+	*mock.Call
+}
+
+// CreateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - tokenType string
+func (_e *MockTokenService_Expecter) CreateToken(ctx interface{}, userId interface{}, tokenType interface{}) *MockTokenService_CreateToken_Call {
+	return &MockTokenService_CreateToken_Call{Call: _e.mock.On("CreateToken", ctx, userId, tokenType)}
+}
+
+func (_c *MockTokenService_CreateToken_Call) Run(run func(ctx context.Context, userId uuid.UUID, tokenType string)) *MockTokenService_CreateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenService_CreateToken_Call) Return(s string, err error) *MockTokenService_CreateToken_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockTokenService_CreateToken_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, tokenType string) (string, error)) *MockTokenService_CreateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTokenWithPayload provides a mock function for the type MockTokenService
+func (_mock *MockTokenService) CreateTokenWithPayload(ctx context.Context, userId uuid.UUID, tokenType string, payload string) (string, error) {
+	ret := _mock.Called(ctx, userId, tokenType, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTokenWithPayload")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (string, error)); ok {
+		return returnFunc(ctx, userId, tokenType, payload)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) string); ok {
+		r0 = returnFunc(ctx, userId, tokenType, payload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = returnFunc(ctx, userId, tokenType, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTokenService_CreateTokenWithPayload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTokenWithPayload'
+type MockTokenService_CreateTokenWithPayload_Call struct {
+	*mock.Call
+}
+
+// CreateTokenWithPayload is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - tokenType string
+//   - payload string
+func (_e *MockTokenService_Expecter) CreateTokenWithPayload(ctx interface{}, userId interface{}, tokenType interface{}, payload interface{}) *MockTokenService_CreateTokenWithPayload_Call {
+	return &MockTokenService_CreateTokenWithPayload_Call{Call: _e.mock.On("CreateTokenWithPayload", ctx, userId, tokenType, payload)}
+}
+
+func (_c *MockTokenService_CreateTokenWithPayload_Call) Run(run func(ctx context.Context, userId uuid.UUID, tokenType string, payload string)) *MockTokenService_CreateTokenWithPayload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenService_CreateTokenWithPayload_Call) Return(s string, err error) *MockTokenService_CreateTokenWithPayload_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockTokenService_CreateTokenWithPayload_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, tokenType string, payload string) (string, error)) *MockTokenService_CreateTokenWithPayload_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteToken provides a mock function for the type MockTokenService
+func (_mock *MockTokenService) DeleteToken(ctx context.Context, token string) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTokenService_DeleteToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteToken'
+type MockTokenService_DeleteToken_Call struct {
+	*mock.Call
+}
+
+// DeleteToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockTokenService_Expecter) DeleteToken(ctx interface{}, token interface{}) *MockTokenService_DeleteToken_Call {
+	return &MockTokenService_DeleteToken_Call{Call: _e.mock.On("DeleteToken", ctx, token)}
+}
+
+func (_c *MockTokenService_DeleteToken_Call) Run(run func(ctx context.Context, token string)) *MockTokenService_DeleteToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenService_DeleteToken_Call) Return(err error) *MockTokenService_DeleteToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTokenService_DeleteToken_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockTokenService_DeleteToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUserTokens provides a mock function for the type MockTokenService
+func (_mock *MockTokenService) DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error {
+	ret := _mock.Called(ctx, userId, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserTokens")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, userId, tokenType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockTokenService_DeleteUserTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserTokens'
+type MockTokenService_DeleteUserTokens_Call struct {
+	*mock.Call
+}
+
+// DeleteUserTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - tokenType string
+func (_e *MockTokenService_Expecter) DeleteUserTokens(ctx interface{}, userId interface{}, tokenType interface{}) *MockTokenService_DeleteUserTokens_Call {
+	return &MockTokenService_DeleteUserTokens_Call{Call: _e.mock.On("DeleteUserTokens", ctx, userId, tokenType)}
+}
+
+func (_c *MockTokenService_DeleteUserTokens_Call) Run(run func(ctx context.Context, userId uuid.UUID, tokenType string)) *MockTokenService_DeleteUserTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenService_DeleteUserTokens_Call) Return(err error) *MockTokenService_DeleteUserTokens_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockTokenService_DeleteUserTokens_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, tokenType string) error) *MockTokenService_DeleteUserTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Token provides a mock function for the type MockTokenService
+func (_mock *MockTokenService) Token(ctx context.Context, token string) (entities.Token, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Token")
+	}
+
+	var r0 entities.Token
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (entities.Token, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) entities.Token); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Get(0).(entities.Token)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTokenService_Token_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Token'
+type MockTokenService_Token_Call struct {
+	*mock.Call
+}
+
+// Token is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockTokenService_Expecter) Token(ctx interface{}, token interface{}) *MockTokenService_Token_Call {
+	return &MockTokenService_Token_Call{Call: _e.mock.On("Token", ctx, token)}
+}
+
+func (_c *MockTokenService_Token_Call) Run(run func(ctx context.Context, token string)) *MockTokenService_Token_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenService_Token_Call) Return(token1 entities.Token, err error) *MockTokenService_Token_Call {
+	_c.Call.Return(token1, err)
+	return _c
+}
+
+func (_c *MockTokenService_Token_Call) RunAndReturn(run func(ctx context.Context, token string) (entities.Token, error)) *MockTokenService_Token_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockVerificationCodeService creates a new instance of MockVerificationCodeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockVerificationCodeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockVerificationCodeService {
+	mock := &MockVerificationCodeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockVerificationCodeService is an autogenerated mock type for the VerificationCodeService type
+type MockVerificationCodeService struct {
+	mock.Mock
+}
+
+type MockVerificationCodeService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockVerificationCodeService) EXPECT() *MockVerificationCodeService_Expecter {
+	return &MockVerificationCodeService_Expecter{mock: &_m.Mock}
+}
+
+// CreateCode provides a mock function for the type MockVerificationCodeService
+func (_mock *MockVerificationCodeService) CreateCode(ctx context.Context, userId uuid.UUID, email string) (string, error) {
+	ret := _mock.Called(ctx, userId, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCode")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (string, error)); ok {
+		return returnFunc(ctx, userId, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) string); ok {
+		r0 = returnFunc(ctx, userId, email)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = returnFunc(ctx, userId, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockVerificationCodeService_CreateCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCode'
+type MockVerificationCodeService_CreateCode_Call struct {
+	*mock.Call
+}
+
+// CreateCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - email string
+func (_e *MockVerificationCodeService_Expecter) CreateCode(ctx interface{}, userId interface{}, email interface{}) *MockVerificationCodeService_CreateCode_Call {
+	return &MockVerificationCodeService_CreateCode_Call{Call: _e.mock.On("CreateCode", ctx, userId, email)}
+}
+
+func (_c *MockVerificationCodeService_CreateCode_Call) Run(run func(ctx context.Context, userId uuid.UUID, email string)) *MockVerificationCodeService_CreateCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockVerificationCodeService_CreateCode_Call) Return(s string, err error) *MockVerificationCodeService_CreateCode_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockVerificationCodeService_CreateCode_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, email string) (string, error)) *MockVerificationCodeService_CreateCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSessionService creates a new instance of MockSessionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSessionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSessionService {
+	mock := &MockSessionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSessionService is an autogenerated mock type for the SessionService type
+type MockSessionService struct {
+	mock.Mock
+}
+
+type MockSessionService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSessionService) EXPECT() *MockSessionService_Expecter {
+	return &MockSessionService_Expecter{mock: &_m.Mock}
+}
+
+// CreateSession provides a mock function for the type MockSessionService
+func (_mock *MockSessionService) CreateSession(ctx context.Context, userId uuid.UUID, role string, userAgent string) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, userId, role, userAgent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, userId, role, userAgent)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) uuid.UUID); ok {
+		r0 = returnFunc(ctx, userId, role, userAgent)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = returnFunc(ctx, userId, role, userAgent)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSessionService_CreateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSession'
+type MockSessionService_CreateSession_Call struct {
+	*mock.Call
+}
+
+// CreateSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - role string
+//   - userAgent string
+func (_e *MockSessionService_Expecter) CreateSession(ctx interface{}, userId interface{}, role interface{}, userAgent interface{}) *MockSessionService_CreateSession_Call {
+	return &MockSessionService_CreateSession_Call{Call: _e.mock.On("CreateSession", ctx, userId, role, userAgent)}
+}
+
+func (_c *MockSessionService_CreateSession_Call) Run(run func(ctx context.Context, userId uuid.UUID, role string, userAgent string)) *MockSessionService_CreateSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSessionService_CreateSession_Call) Return(uUID uuid.UUID, err error) *MockSessionService_CreateSession_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockSessionService_CreateSession_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, role string, userAgent string) (uuid.UUID, error)) *MockSessionService_CreateSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSession provides a mock function for the type MockSessionService
+func (_mock *MockSessionService) DeleteSession(ctx context.Context, sessionId string) (entities.Session, error) {
+	ret := _mock.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 entities.Session
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (entities.Session, error)); ok {
+		return returnFunc(ctx, sessionId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) entities.Session); ok {
+		r0 = returnFunc(ctx, sessionId)
+	} else {
+		r0 = ret.Get(0).(entities.Session)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, sessionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSessionService_DeleteSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSession'
+type MockSessionService_DeleteSession_Call struct {
+	*mock.Call
+}
+
+// DeleteSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionId string
+func (_e *MockSessionService_Expecter) DeleteSession(ctx interface{}, sessionId interface{}) *MockSessionService_DeleteSession_Call {
+	return &MockSessionService_DeleteSession_Call{Call: _e.mock.On("DeleteSession", ctx, sessionId)}
+}
+
+func (_c *MockSessionService_DeleteSession_Call) Run(run func(ctx context.Context, sessionId string)) *MockSessionService_DeleteSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSessionService_DeleteSession_Call) Return(session entities.Session, err error) *MockSessionService_DeleteSession_Call {
+	_c.Call.Return(session, err)
+	return _c
+}
+
+func (_c *MockSessionService_DeleteSession_Call) RunAndReturn(run func(ctx context.Context, sessionId string) (entities.Session, error)) *MockSessionService_DeleteSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ElevateSession provides a mock function for the type MockSessionService
+func (_mock *MockSessionService) ElevateSession(ctx context.Context, sessionId string) (time.Time, error) {
+	ret := _mock.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ElevateSession")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (time.Time, error)); ok {
+		return returnFunc(ctx, sessionId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) time.Time); ok {
+		r0 = returnFunc(ctx, sessionId)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, sessionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSessionService_ElevateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ElevateSession'
+type MockSessionService_ElevateSession_Call struct {
+	*mock.Call
+}
+
+// ElevateSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionId string
+func (_e *MockSessionService_Expecter) ElevateSession(ctx interface{}, sessionId interface{}) *MockSessionService_ElevateSession_Call {
+	return &MockSessionService_ElevateSession_Call{Call: _e.mock.On("ElevateSession", ctx, sessionId)}
+}
+
+func (_c *MockSessionService_ElevateSession_Call) Run(run func(ctx context.Context, sessionId string)) *MockSessionService_ElevateSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSessionService_ElevateSession_Call) Return(time time.Time, err error) *MockSessionService_ElevateSession_Call {
+	_c.Call.Return(time, err)
+	return _c
+}
+
+func (_c *MockSessionService_ElevateSession_Call) RunAndReturn(run func(ctx context.Context, sessionId string) (time.Time, error)) *MockSessionService_ElevateSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function for the type MockSessionService
+func (_mock *MockSessionService) RevokeSession(ctx context.Context, sessionId string) (entities.Session, error) {
+	ret := _mock.Called(ctx, sessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 entities.Session
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (entities.Session, error)); ok {
+		return returnFunc(ctx, sessionId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) entities.Session); ok {
+		r0 = returnFunc(ctx, sessionId)
+	} else {
+		r0 = ret.Get(0).(entities.Session)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, sessionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSessionService_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockSessionService_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionId string
+func (_e *MockSessionService_Expecter) RevokeSession(ctx interface{}, sessionId interface{}) *MockSessionService_RevokeSession_Call {
+	return &MockSessionService_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, sessionId)}
+}
+
+func (_c *MockSessionService_RevokeSession_Call) Run(run func(ctx context.Context, sessionId string)) *MockSessionService_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSessionService_RevokeSession_Call) Return(session entities.Session, err error) *MockSessionService_RevokeSession_Call {
+	_c.Call.Return(session, err)
+	return _c
+}
+
+func (_c *MockSessionService_RevokeSession_Call) RunAndReturn(run func(ctx context.Context, sessionId string) (entities.Session, error)) *MockSessionService_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function for the type MockSessionService
+func (_mock *MockSessionService) RevokeUserSessions(ctx context.Context, userId uuid.UUID) error {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSessionService_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type MockSessionService_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+func (_e *MockSessionService_Expecter) RevokeUserSessions(ctx interface{}, userId interface{}) *MockSessionService_RevokeUserSessions_Call {
+	return &MockSessionService_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userId)}
+}
+
+func (_c *MockSessionService_RevokeUserSessions_Call) Run(run func(ctx context.Context, userId uuid.UUID)) *MockSessionService_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSessionService_RevokeUserSessions_Call) Return(err error) *MockSessionService_RevokeUserSessions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSessionService_RevokeUserSessions_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID) error) *MockSessionService_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDeviceService creates a new instance of MockDeviceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDeviceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDeviceService {
+	mock := &MockDeviceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockDeviceService is an autogenerated mock type for the DeviceService type
+type MockDeviceService struct {
+	mock.Mock
+}
+
+type MockDeviceService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDeviceService) EXPECT() *MockDeviceService_Expecter {
+	return &MockDeviceService_Expecter{mock: &_m.Mock}
+}
+
+// RememberDevice provides a mock function for the type MockDeviceService
+func (_mock *MockDeviceService) RememberDevice(ctx context.Context, userId uuid.UUID, userAgent string, ip string) (entities.Device, bool, error) {
+	ret := _mock.Called(ctx, userId, userAgent, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RememberDevice")
+	}
+
+	var r0 entities.Device
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) (entities.Device, bool, error)); ok {
+		return returnFunc(ctx, userId, userAgent, ip)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) entities.Device); ok {
+		r0 = returnFunc(ctx, userId, userAgent, ip)
+	} else {
+		r0 = ret.Get(0).(entities.Device)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) bool); ok {
+		r1 = returnFunc(ctx, userId, userAgent, ip)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r2 = returnFunc(ctx, userId, userAgent, ip)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockDeviceService_RememberDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RememberDevice'
+type MockDeviceService_RememberDevice_Call struct {
+	*mock.Call
+}
+
+// RememberDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - userAgent string
+//   - ip string
+func (_e *MockDeviceService_Expecter) RememberDevice(ctx interface{}, userId interface{}, userAgent interface{}, ip interface{}) *MockDeviceService_RememberDevice_Call {
+	return &MockDeviceService_RememberDevice_Call{Call: _e.mock.On("RememberDevice", ctx, userId, userAgent, ip)}
+}
+
+func (_c *MockDeviceService_RememberDevice_Call) Run(run func(ctx context.Context, userId uuid.UUID, userAgent string, ip string)) *MockDeviceService_RememberDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceService_RememberDevice_Call) Return(device entities.Device, b bool, err error) *MockDeviceService_RememberDevice_Call {
+	_c.Call.Return(device, b, err)
+	return _c
+}
+
+func (_c *MockDeviceService_RememberDevice_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, userAgent string, ip string) (entities.Device, bool, error)) *MockDeviceService_RememberDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRegistrationGuard creates a new instance of MockRegistrationGuard. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRegistrationGuard(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRegistrationGuard {
+	mock := &MockRegistrationGuard{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRegistrationGuard is an autogenerated mock type for the RegistrationGuard type
+type MockRegistrationGuard struct {
+	mock.Mock
+}
+
+type MockRegistrationGuard_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRegistrationGuard) EXPECT() *MockRegistrationGuard_Expecter {
+	return &MockRegistrationGuard_Expecter{mock: &_m.Mock}
+}
+
+// Check provides a mock function for the type MockRegistrationGuard
+func (_mock *MockRegistrationGuard) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dtos.RegisterRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRegistrationGuard_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type MockRegistrationGuard_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req dtos.RegisterRequest
+func (_e *MockRegistrationGuard_Expecter) Check(ctx interface{}, req interface{}) *MockRegistrationGuard_Check_Call {
+	return &MockRegistrationGuard_Check_Call{Call: _e.mock.On("Check", ctx, req)}
+}
+
+func (_c *MockRegistrationGuard_Check_Call) Run(run func(ctx context.Context, req dtos.RegisterRequest)) *MockRegistrationGuard_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 dtos.RegisterRequest
+		if args[1] != nil {
+			arg1 = args[1].(dtos.RegisterRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRegistrationGuard_Check_Call) Return(err error) *MockRegistrationGuard_Check_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRegistrationGuard_Check_Call) RunAndReturn(run func(ctx context.Context, req dtos.RegisterRequest) error) *MockRegistrationGuard_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockInviteService creates a new instance of MockInviteService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInviteService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInviteService {
+	mock := &MockInviteService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockInviteService is an autogenerated mock type for the InviteService type
+type MockInviteService struct {
+	mock.Mock
+}
+
+type MockInviteService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockInviteService) EXPECT() *MockInviteService_Expecter {
+	return &MockInviteService_Expecter{mock: &_m.Mock}
+}
+
+// ConsumeInvite provides a mock function for the type MockInviteService
+func (_mock *MockInviteService) ConsumeInvite(ctx context.Context, code string) error {
+	ret := _mock.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeInvite")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInviteService_ConsumeInvite_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeInvite'
+type MockInviteService_ConsumeInvite_Call struct {
+	*mock.Call
+}
+
+// ConsumeInvite is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+func (_e *MockInviteService_Expecter) ConsumeInvite(ctx interface{}, code interface{}) *MockInviteService_ConsumeInvite_Call {
+	return &MockInviteService_ConsumeInvite_Call{Call: _e.mock.On("ConsumeInvite", ctx, code)}
+}
+
+func (_c *MockInviteService_ConsumeInvite_Call) Run(run func(ctx context.Context, code string)) *MockInviteService_ConsumeInvite_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInviteService_ConsumeInvite_Call) Return(err error) *MockInviteService_ConsumeInvite_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInviteService_ConsumeInvite_Call) RunAndReturn(run func(ctx context.Context, code string) error) *MockInviteService_ConsumeInvite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordRedemption provides a mock function for the type MockInviteService
+func (_mock *MockInviteService) RecordRedemption(ctx context.Context, code string, userId uuid.UUID) error {
+	ret := _mock.Called(ctx, code, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordRedemption")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, code, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInviteService_RecordRedemption_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordRedemption'
+type MockInviteService_RecordRedemption_Call struct {
+	*mock.Call
+}
+
+// RecordRedemption is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - userId uuid.UUID
+func (_e *MockInviteService_Expecter) RecordRedemption(ctx interface{}, code interface{}, userId interface{}) *MockInviteService_RecordRedemption_Call {
+	return &MockInviteService_RecordRedemption_Call{Call: _e.mock.On("RecordRedemption", ctx, code, userId)}
+}
+
+func (_c *MockInviteService_RecordRedemption_Call) Run(run func(ctx context.Context, code string, userId uuid.UUID)) *MockInviteService_RecordRedemption_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInviteService_RecordRedemption_Call) Return(err error) *MockInviteService_RecordRedemption_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInviteService_RecordRedemption_Call) RunAndReturn(run func(ctx context.Context, code string, userId uuid.UUID) error) *MockInviteService_RecordRedemption_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseInvite provides a mock function for the type MockInviteService
+func (_mock *MockInviteService) ReleaseInvite(ctx context.Context, code string) error {
+	ret := _mock.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseInvite")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInviteService_ReleaseInvite_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseInvite'
+type MockInviteService_ReleaseInvite_Call struct {
+	*mock.Call
+}
+
+// ReleaseInvite is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+func (_e *MockInviteService_Expecter) ReleaseInvite(ctx interface{}, code interface{}) *MockInviteService_ReleaseInvite_Call {
+	return &MockInviteService_ReleaseInvite_Call{Call: _e.mock.On("ReleaseInvite", ctx, code)}
+}
+
+func (_c *MockInviteService_ReleaseInvite_Call) Run(run func(ctx context.Context, code string)) *MockInviteService_ReleaseInvite_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInviteService_ReleaseInvite_Call) Return(err error) *MockInviteService_ReleaseInvite_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInviteService_ReleaseInvite_Call) RunAndReturn(run func(ctx context.Context, code string) error) *MockInviteService_ReleaseInvite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditor creates a new instance of MockAuditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditor {
+	mock := &MockAuditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditor is an autogenerated mock type for the Auditor type
+type MockAuditor struct {
+	mock.Mock
+}
+
+type MockAuditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditor) EXPECT() *MockAuditor_Expecter {
+	return &MockAuditor_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) Record(ctx context.Context, event entities.AuditEvent) {
+	_mock.Called(ctx, event)
+	return
+}
+
+// MockAuditor_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditor_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event entities.AuditEvent
+func (_e *MockAuditor_Expecter) Record(ctx interface{}, event interface{}) *MockAuditor_Record_Call {
+	return &MockAuditor_Record_Call{Call: _e.mock.On("Record", ctx, event)}
+}
+
+func (_c *MockAuditor_Record_Call) Run(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.AuditEvent
+		if args[1] != nil {
+			arg1 = args[1].(entities.AuditEvent)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) Return() *MockAuditor_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) RunAndReturn(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Run(run)
+	return _c
+}
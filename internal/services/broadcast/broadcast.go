@@ -0,0 +1,322 @@
+package broadcast_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type ChannelService interface {
+	ChannelByStreamKey(ctx context.Context, key string) (entities.Channel, error)
+	ChannelByLogin(ctx context.Context, login string) (entities.Channel, error)
+}
+
+type Repository interface {
+	ChannelByBroadcastKeyHash(ctx context.Context, hash string) (entities.Channel, error)
+	StartBroadcast(ctx context.Context, channelId uuid.UUID, broadcast entities.Broadcast, staleBefore time.Time) error
+	TouchBroadcast(ctx context.Context, channelId uuid.UUID, clientId string, now time.Time) error
+	EndBroadcast(ctx context.Context, channelId uuid.UUID, clientId string) error
+}
+
+type UserService interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+}
+
+type Keyring interface {
+	Hash(key string) string
+}
+
+// Service answers the callbacks of nginx-rtmp and SRS, so broadcasters can go through them
+// instead of cmd/ingest. It tracks which channels are live, cmd/ingest reports its broadcasts
+// with Start, Touch and End.
+//
+// With nginx-rtmp broadcasters publish under their stream key and get renamed to their login.
+// SRS can't rename streams, so there the name is the login and the key is a query parameter.
+type Service struct {
+	channelService ChannelService
+	repository     Repository
+	userService    UserService
+	keyring        Keyring
+	cfg            config.IngestCallbacksConfig
+}
+
+func New(
+	channelService ChannelService,
+	repository Repository,
+	userService UserService,
+	keyring Keyring,
+	cfg config.IngestCallbacksConfig,
+) *Service {
+	return &Service{
+		channelService: channelService,
+		repository:     repository,
+		userService:    userService,
+		keyring:        keyring,
+		cfg:            cfg,
+	}
+}
+
+// Publish lets the broadcaster in and marks the channel live. It returns the login of the
+// channel, the name the stream has to be published under.
+func (s *Service) Publish(ctx context.Context, req dtos.IngestCallbackRequest) (string, error) {
+	const op = "services.broadcast.Publish"
+
+	key := req.Key
+	if key == "" {
+		key = req.Name
+	}
+
+	channel, err := s.channelService.ChannelByStreamKey(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// only nginx-rtmp can rename a stream published under the key
+	if req.Name != channel.Login && (req.Key != "" || req.Server != consts.IngestServerNginx) {
+		return "", fmt.Errorf("%s: %w", op, errs.ErrStreamNameInvalid)
+	}
+
+	if err := s.checkOwner(ctx, channel); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	err = s.repository.StartBroadcast(ctx, channel.ID, entities.Broadcast{
+		Ingest:    req.Server,
+		ClientId:  req.ClientId,
+		KeyHash:   channel.StreamKey.Hash,
+		StartedAt: now,
+		UpdatedAt: now,
+	}, now.Add(-s.cfg.StaleAfter))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.FromCtx(ctx).Info(
+		"broadcast started",
+		slog.String("op", op),
+		slog.String("channel_id", channel.ID.String()),
+		slog.String("login", channel.Login),
+		slog.String("ingest", req.Server),
+	)
+
+	return channel.Login, nil
+}
+
+// Update keeps the broadcast going. It fails once the stream key was reset or the owner got
+// suspended, which makes nginx-rtmp drop the broadcaster.
+func (s *Service) Update(ctx context.Context, req dtos.IngestCallbackRequest) error {
+	const op = "services.broadcast.Update"
+
+	if req.Call == "update_play" {
+		if err := s.Play(ctx, req); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	channel, err := s.broadcastChannel(ctx, req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.refresh(ctx, channel, req.ClientId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PublishDone marks the channel offline. Broadcasts that are already gone are fine, the
+// ingest servers call it for refused publishes too.
+func (s *Service) PublishDone(ctx context.Context, req dtos.IngestCallbackRequest) error {
+	const op = "services.broadcast.PublishDone"
+
+	channel, err := s.broadcastChannel(ctx, req)
+	if errors.Is(err, errs.ErrBroadcastNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repository.EndBroadcast(ctx, channel.ID, req.ClientId)
+	if err != nil && !errors.Is(err, errs.ErrBroadcastNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.FromCtx(ctx).Info(
+		"broadcast ended",
+		slog.String("op", op),
+		slog.String("channel_id", channel.ID.String()),
+		slog.String("ingest", req.Server),
+		slog.Duration("duration", time.Since(channel.Broadcast.StartedAt)),
+	)
+
+	return nil
+}
+
+// Play lets viewers watch live channels only, the name is the login.
+func (s *Service) Play(ctx context.Context, req dtos.IngestCallbackRequest) error {
+	const op = "services.broadcast.Play"
+
+	// viewers leaving, SRS sends on_stop as a play call too
+	if req.Call == "play_done" {
+		return nil
+	}
+
+	channel, err := s.channelService.ChannelByLogin(ctx, req.Name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if channel.Broadcast == nil {
+		return fmt.Errorf("%s: %w", op, errs.ErrChannelOffline)
+	}
+
+	return nil
+}
+
+// Start lets in a broadcaster of cmd/ingest and marks the channel live. clientId names the
+// connection, Touch and End have to be called with it.
+func (s *Service) Start(ctx context.Context, key, clientId string) (entities.Channel, error) {
+	const op = "services.broadcast.Start"
+
+	channel, err := s.channelService.ChannelByStreamKey(ctx, key)
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkOwner(ctx, channel); err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	broadcast := entities.Broadcast{
+		Ingest:    consts.IngestServerNative,
+		ClientId:  clientId,
+		KeyHash:   channel.StreamKey.Hash,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	err = s.repository.StartBroadcast(ctx, channel.ID, broadcast, now.Add(-s.cfg.StaleAfter))
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+	channel.Broadcast = &broadcast
+
+	return channel, nil
+}
+
+// Touch is the on_update of cmd/ingest. Like Update it fails once the broadcast was taken over,
+// the stream key was reset or the owner got suspended.
+func (s *Service) Touch(ctx context.Context, channel entities.Channel, clientId string) error {
+	const op = "services.broadcast.Touch"
+
+	if channel.Broadcast == nil {
+		return fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+
+	channel, err := s.repository.ChannelByBroadcastKeyHash(ctx, channel.Broadcast.KeyHash)
+	if errors.Is(err, errs.ErrChannelNotFound) {
+		return fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if channel.Broadcast == nil || channel.Broadcast.ClientId != clientId {
+		return fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+
+	if err := s.refresh(ctx, channel, clientId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// End marks the channel offline unless another connection took the broadcast over.
+func (s *Service) End(ctx context.Context, channelId uuid.UUID, clientId string) error {
+	const op = "services.broadcast.End"
+
+	err := s.repository.EndBroadcast(ctx, channelId, clientId)
+	if err != nil && !errors.Is(err, errs.ErrBroadcastNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// refresh checks that the broadcast of the channel may go on and keeps it from going stale.
+func (s *Service) refresh(ctx context.Context, channel entities.Channel, clientId string) error {
+	const op = "services.broadcast.refresh"
+
+	if channel.StreamKey == nil || channel.Broadcast.KeyHash != channel.StreamKey.Hash {
+		return fmt.Errorf("%s: %w", op, errs.ErrStreamKeyInvalid)
+	}
+
+	if err := s.checkOwner(ctx, channel); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repository.TouchBroadcast(ctx, channel.ID, clientId, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// broadcastChannel finds the channel the client broadcasts to. Streams published under the key
+// are found by the key they started with, it may have been reset since.
+func (s *Service) broadcastChannel(ctx context.Context, req dtos.IngestCallbackRequest) (entities.Channel, error) {
+	const op = "services.broadcast.broadcastChannel"
+
+	var (
+		channel entities.Channel
+		err     error
+	)
+	switch {
+	case req.Key != "":
+		channel, err = s.repository.ChannelByBroadcastKeyHash(ctx, s.keyring.Hash(req.Key))
+	case streamkey.Valid(req.Name):
+		channel, err = s.repository.ChannelByBroadcastKeyHash(ctx, s.keyring.Hash(req.Name))
+	default:
+		channel, err = s.channelService.ChannelByLogin(ctx, req.Name)
+	}
+	if errors.Is(err, errs.ErrChannelNotFound) {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if channel.Broadcast == nil || channel.Broadcast.ClientId != req.ClientId {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, errs.ErrBroadcastNotFound)
+	}
+
+	return channel, nil
+}
+
+func (s *Service) checkOwner(ctx context.Context, channel entities.Channel) error {
+	const op = "services.broadcast.checkOwner"
+
+	user, err := s.userService.UserById(ctx, channel.UserId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if user.IsSuspended(time.Now()) {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserSuspended)
+	}
+
+	return nil
+}
@@ -0,0 +1,511 @@
+package broadcast_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Publish(t *testing.T) {
+	keyring := testKeyring(t)
+	key, err := keyring.Generate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		req       dtos.IngestCallbackRequest
+		lookupErr error
+		suspended bool
+		wantStart bool
+		startErr  error
+		wantErr   error
+	}{
+		{
+			name:      "nginx key as name case",
+			req:       dtos.IngestCallbackRequest{Server: "nginx-rtmp", ClientId: "1", Name: key},
+			wantStart: true,
+		},
+		{
+			name:      "srs key as param case",
+			req:       dtos.IngestCallbackRequest{Server: "srs", ClientId: "1", Name: "streamer", Key: key},
+			wantStart: true,
+		},
+		{
+			name:    "srs key as name case",
+			req:     dtos.IngestCallbackRequest{Server: "srs", ClientId: "1", Name: key},
+			wantErr: errs.ErrStreamNameInvalid,
+		},
+		{
+			name:    "someone else's login case",
+			req:     dtos.IngestCallbackRequest{Server: "nginx-rtmp", ClientId: "1", Name: "other", Key: key},
+			wantErr: errs.ErrStreamNameInvalid,
+		},
+		{
+			name:      "invalid key case",
+			req:       dtos.IngestCallbackRequest{Server: "nginx-rtmp", ClientId: "1", Name: key},
+			lookupErr: errs.ErrStreamKeyInvalid,
+			wantErr:   errs.ErrStreamKeyInvalid,
+		},
+		{
+			name:      "suspended user case",
+			req:       dtos.IngestCallbackRequest{Server: "nginx-rtmp", ClientId: "1", Name: key},
+			suspended: true,
+			wantErr:   errs.ErrUserSuspended,
+		},
+		{
+			name:      "already live case",
+			req:       dtos.IngestCallbackRequest{Server: "nginx-rtmp", ClientId: "1", Name: key},
+			wantStart: true,
+			startErr:  errs.ErrAlreadyLive,
+			wantErr:   errs.ErrAlreadyLive,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{
+				ID:        uuid.New(),
+				UserId:    uuid.New(),
+				Login:     "streamer",
+				StreamKey: &entities.StreamKey{Hash: keyring.Hash(key)},
+			}
+			user := entities.User{ID: channel.UserId}
+			if tt.suspended {
+				user.Suspension = &entities.Suspension{}
+			}
+
+			mChannels := NewMockChannelService(t)
+			mChannels.EXPECT().ChannelByStreamKey(
+				mock.AnythingOfType("context.backgroundCtx"),
+				key,
+			).Return(channel, tt.lookupErr).Once()
+
+			mUsers := NewMockUserService(t)
+			mUsers.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				channel.UserId,
+			).Return(user, nil).Maybe()
+
+			mRepo := NewMockRepository(t)
+			if tt.wantStart {
+				mRepo.EXPECT().StartBroadcast(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+					mock.MatchedBy(func(broadcast entities.Broadcast) bool {
+						return broadcast.ClientId == tt.req.ClientId &&
+							broadcast.Ingest == tt.req.Server &&
+							broadcast.KeyHash == channel.StreamKey.Hash
+					}),
+					mock.AnythingOfType("time.Time"),
+				).Return(tt.startErr).Once()
+			}
+
+			s := New(mChannels, mRepo, mUsers, keyring, config.IngestCallbacksConfig{StaleAfter: time.Minute})
+
+			login, err := s.Publish(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, channel.Login, login)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	keyring := testKeyring(t)
+	key, err := keyring.Generate()
+	require.NoError(t, err)
+	resetKey, err := keyring.Generate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		currentKey string
+		clientId   string
+		lookupErr  error
+		wantTouch  bool
+		wantErr    error
+	}{
+		{
+			name:       "good case",
+			currentKey: key,
+			clientId:   "1",
+			wantTouch:  true,
+		},
+		{
+			name:       "key reset case",
+			currentKey: resetKey,
+			clientId:   "1",
+			wantErr:    errs.ErrStreamKeyInvalid,
+		},
+		{
+			name:       "other client case",
+			currentKey: key,
+			clientId:   "2",
+			wantErr:    errs.ErrBroadcastNotFound,
+		},
+		{
+			name:      "not live case",
+			clientId:  "1",
+			lookupErr: errs.ErrChannelNotFound,
+			wantErr:   errs.ErrBroadcastNotFound,
+		},
+		{
+			name:      "lookup error case",
+			clientId:  "1",
+			lookupErr: errors.New("some error"),
+			wantErr:   errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{
+				ID:        uuid.New(),
+				UserId:    uuid.New(),
+				Login:     "streamer",
+				StreamKey: &entities.StreamKey{Hash: keyring.Hash(tt.currentKey)},
+				Broadcast: &entities.Broadcast{ClientId: "1", KeyHash: keyring.Hash(key)},
+			}
+
+			mRepo := NewMockRepository(t)
+			mRepo.EXPECT().ChannelByBroadcastKeyHash(
+				mock.AnythingOfType("context.backgroundCtx"),
+				keyring.Hash(key),
+			).Return(channel, tt.lookupErr).Once()
+			if tt.wantTouch {
+				mRepo.EXPECT().TouchBroadcast(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+					tt.clientId,
+					mock.AnythingOfType("time.Time"),
+				).Return(nil).Once()
+			}
+
+			mUsers := NewMockUserService(t)
+			mUsers.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				channel.UserId,
+			).Return(entities.User{ID: channel.UserId}, nil).Maybe()
+
+			s := New(NewMockChannelService(t), mRepo, mUsers, keyring, config.IngestCallbacksConfig{})
+
+			err := s.Update(context.Background(), dtos.IngestCallbackRequest{
+				Server:   "nginx-rtmp",
+				Call:     "update_publish",
+				ClientId: tt.clientId,
+				Name:     key,
+			})
+			if tt.wantErr != nil {
+				require.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_PublishDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientId string
+		wantEnd  bool
+	}{
+		{
+			name:     "good case",
+			clientId: "1",
+			wantEnd:  true,
+		},
+		{
+			name:     "replaced broadcast case",
+			clientId: "2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{
+				ID:        uuid.New(),
+				Login:     "streamer",
+				Broadcast: &entities.Broadcast{ClientId: "1", StartedAt: time.Now()},
+			}
+
+			mChannels := NewMockChannelService(t)
+			mChannels.EXPECT().ChannelByLogin(
+				mock.AnythingOfType("context.backgroundCtx"),
+				channel.Login,
+			).Return(channel, nil).Once()
+
+			mRepo := NewMockRepository(t)
+			if tt.wantEnd {
+				mRepo.EXPECT().EndBroadcast(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+					tt.clientId,
+				).Return(nil).Once()
+			}
+
+			s := New(mChannels, mRepo, NewMockUserService(t), testKeyring(t), config.IngestCallbacksConfig{})
+
+			err := s.PublishDone(context.Background(), dtos.IngestCallbackRequest{
+				Server:   "srs",
+				Call:     "publish_done",
+				ClientId: tt.clientId,
+				Name:     channel.Login,
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Play(t *testing.T) {
+	tests := []struct {
+		name      string
+		broadcast *entities.Broadcast
+		lookupErr error
+		wantErr   error
+	}{
+		{
+			name:      "good case",
+			broadcast: &entities.Broadcast{ClientId: "1"},
+		},
+		{
+			name:    "offline case",
+			wantErr: errs.ErrChannelOffline,
+		},
+		{
+			name:      "unknown channel case",
+			lookupErr: errs.ErrChannelNotFound,
+			wantErr:   errs.ErrChannelNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mChannels := NewMockChannelService(t)
+			mChannels.EXPECT().ChannelByLogin(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"streamer",
+			).Return(entities.Channel{Login: "streamer", Broadcast: tt.broadcast}, tt.lookupErr).Once()
+
+			s := New(mChannels, NewMockRepository(t), NewMockUserService(t), testKeyring(t), config.IngestCallbacksConfig{})
+
+			err := s.Play(context.Background(), dtos.IngestCallbackRequest{
+				Server:   "nginx-rtmp",
+				Call:     "play",
+				ClientId: "9",
+				Name:     "streamer",
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Start(t *testing.T) {
+	tests := []struct {
+		name      string
+		lookupErr error
+		suspended bool
+		wantStart bool
+		startErr  error
+		wantErr   error
+	}{
+		{
+			name:      "good case",
+			wantStart: true,
+		},
+		{
+			name:      "invalid key case",
+			lookupErr: errs.ErrStreamKeyInvalid,
+			wantErr:   errs.ErrStreamKeyInvalid,
+		},
+		{
+			name:      "suspended user case",
+			suspended: true,
+			wantErr:   errs.ErrUserSuspended,
+		},
+		{
+			name:      "already live case",
+			wantStart: true,
+			startErr:  errs.ErrAlreadyLive,
+			wantErr:   errs.ErrAlreadyLive,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{
+				ID:        uuid.New(),
+				UserId:    uuid.New(),
+				Login:     "streamer",
+				StreamKey: &entities.StreamKey{Hash: "hash"},
+			}
+			user := entities.User{ID: channel.UserId}
+			if tt.suspended {
+				user.Suspension = &entities.Suspension{}
+			}
+
+			mChannels := NewMockChannelService(t)
+			mChannels.EXPECT().ChannelByStreamKey(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"live_key",
+			).Return(channel, tt.lookupErr).Once()
+
+			mUsers := NewMockUserService(t)
+			mUsers.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				channel.UserId,
+			).Return(user, nil).Maybe()
+
+			mRepo := NewMockRepository(t)
+			if tt.wantStart {
+				mRepo.EXPECT().StartBroadcast(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+					mock.MatchedBy(func(broadcast entities.Broadcast) bool {
+						return broadcast.ClientId == "client" &&
+							broadcast.Ingest == consts.IngestServerNative &&
+							broadcast.KeyHash == channel.StreamKey.Hash
+					}),
+					mock.AnythingOfType("time.Time"),
+				).Return(tt.startErr).Once()
+			}
+
+			s := New(mChannels, mRepo, mUsers, testKeyring(t), config.IngestCallbacksConfig{StaleAfter: time.Minute})
+
+			got, err := s.Start(context.Background(), "live_key", "client")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, channel.ID, got.ID)
+			require.Equal(t, "client", got.Broadcast.ClientId)
+		})
+	}
+}
+
+func TestService_Touch(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyHash   string
+		clientId  string
+		suspended bool
+		lookupErr error
+		wantTouch bool
+		wantErr   error
+	}{
+		{
+			name:      "good case",
+			keyHash:   "hash",
+			clientId:  "client",
+			wantTouch: true,
+		},
+		{
+			name:     "key reset case",
+			keyHash:  "reset",
+			clientId: "client",
+			wantErr:  errs.ErrStreamKeyInvalid,
+		},
+		{
+			name:      "suspended user case",
+			keyHash:   "hash",
+			clientId:  "client",
+			suspended: true,
+			wantErr:   errs.ErrUserSuspended,
+		},
+		{
+			name:     "taken over case",
+			keyHash:  "hash",
+			clientId: "other",
+			wantErr:  errs.ErrBroadcastNotFound,
+		},
+		{
+			name:      "ended case",
+			clientId:  "client",
+			lookupErr: errs.ErrChannelNotFound,
+			wantErr:   errs.ErrBroadcastNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{
+				ID:        uuid.New(),
+				UserId:    uuid.New(),
+				Login:     "streamer",
+				StreamKey: &entities.StreamKey{Hash: tt.keyHash},
+				Broadcast: &entities.Broadcast{ClientId: tt.clientId, KeyHash: "hash"},
+			}
+			user := entities.User{ID: channel.UserId}
+			if tt.suspended {
+				user.Suspension = &entities.Suspension{}
+			}
+
+			mRepo := NewMockRepository(t)
+			mRepo.EXPECT().ChannelByBroadcastKeyHash(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"hash",
+			).Return(channel, tt.lookupErr).Once()
+			if tt.wantTouch {
+				mRepo.EXPECT().TouchBroadcast(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+					"client",
+					mock.AnythingOfType("time.Time"),
+				).Return(nil).Once()
+			}
+
+			mUsers := NewMockUserService(t)
+			mUsers.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				channel.UserId,
+			).Return(user, nil).Maybe()
+
+			s := New(NewMockChannelService(t), mRepo, mUsers, testKeyring(t), config.IngestCallbacksConfig{})
+
+			started := channel
+			started.Broadcast = &entities.Broadcast{ClientId: "client", KeyHash: "hash"}
+			err := s.Touch(context.Background(), started, "client")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func testKeyring(t *testing.T) *streamkey.Keyring {
+	t.Helper()
+
+	keyring, err := streamkey.NewKeyring("secret")
+	require.NoError(t, err)
+
+	return keyring
+}
@@ -0,0 +1,262 @@
+package channel_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// defaultLanguage is used for users without a locale
+const defaultLanguage = "en"
+
+type ChannelRepository interface {
+	SaveChannel(ctx context.Context, channel entities.Channel) error
+	ChannelByLogin(ctx context.Context, login string) (entities.Channel, error)
+	ChannelByUserId(ctx context.Context, userId uuid.UUID) (entities.Channel, error)
+	UpdateSettings(ctx context.Context, channel entities.Channel) error
+	ChannelByStreamKeyHash(ctx context.Context, hash string) (entities.Channel, error)
+	SaveStreamKey(ctx context.Context, userId uuid.UUID, key entities.StreamKey, previousHash string) (entities.Channel, error)
+}
+
+type Keyring interface {
+	Generate() (string, error)
+	Hash(key string) string
+	Seal(key string, owner []byte) ([]byte, error)
+	Open(sealed []byte, owner []byte) (string, error)
+}
+
+type BroadcastKicker interface {
+	Kick(ctx context.Context, channelId uuid.UUID) error
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type Service struct {
+	channelRepository ChannelRepository
+	keyring           Keyring
+	kicker            BroadcastKicker
+	auditor           Auditor
+}
+
+func New(channelRepository ChannelRepository, keyring Keyring, kicker BroadcastKicker, auditor Auditor) *Service {
+	return &Service{
+		channelRepository: channelRepository,
+		keyring:           keyring,
+		kicker:            kicker,
+		auditor:           auditor,
+	}
+}
+
+// CreateChannel creates the channel of a user who just verified their email.
+// A user who already has a channel keeps it.
+func (s *Service) CreateChannel(ctx context.Context, user entities.User) error {
+	const op = "services.channel.CreateChannel"
+
+	language := user.Locale
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	now := time.Now()
+	err := s.channelRepository.SaveChannel(ctx, entities.Channel{
+		ID:          uuid.New(),
+		UserId:      user.ID,
+		Login:       strings.ToLower(user.Login),
+		DisplayName: user.Login,
+		Language:    language,
+		Tags:        []string{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ChannelByLogin(ctx context.Context, login string) (entities.Channel, error) {
+	const op = "services.channel.ChannelByLogin"
+
+	channel, err := s.channelRepository.ChannelByLogin(ctx, login)
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+func (s *Service) UpdateChannel(ctx context.Context, userId uuid.UUID, req dtos.UpdateChannelRequest) (entities.Channel, error) {
+	const op = "services.channel.UpdateChannel"
+
+	channel, err := s.channelRepository.ChannelByUserId(ctx, userId)
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if req.Title != nil {
+		channel.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Category != nil {
+		channel.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.Language != nil {
+		channel.Language = *req.Language
+	}
+	if req.Tags != nil {
+		channel.Tags = *req.Tags
+	}
+	if req.Mature != nil {
+		channel.Mature = *req.Mature
+	}
+	if req.Description != nil {
+		channel.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.OfflineBanner != nil {
+		channel.OfflineBanner = *req.OfflineBanner
+	}
+	channel.UpdatedAt = time.Now()
+
+	err = s.channelRepository.UpdateSettings(ctx, channel)
+	if err != nil {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// StreamKey reveals the stream key of the user's channel. Channels that never had
+// a key get one here.
+func (s *Service) StreamKey(ctx context.Context, userId uuid.UUID) (string, error) {
+	const op = "services.channel.StreamKey"
+
+	channel, err := s.channelRepository.ChannelByUserId(ctx, userId)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if channel.StreamKey == nil {
+		key, err := s.replaceStreamKey(ctx, channel)
+		if err == nil {
+			s.recordStreamKeyEvent(ctx, userId, consts.AuditEventStreamKeyReveal)
+			return key, nil
+		}
+		if !errors.Is(err, errs.ErrStreamKeyChanged) {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		// a concurrent request created the key first, reveal that one
+		channel, err = s.channelRepository.ChannelByUserId(ctx, userId)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		if channel.StreamKey == nil {
+			return "", fmt.Errorf("%s: %w", op, errs.ErrStreamKeyChanged)
+		}
+	}
+
+	key, err := s.keyring.Open(channel.StreamKey.Sealed, channel.ID[:])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordStreamKeyEvent(ctx, userId, consts.AuditEventStreamKeyReveal)
+
+	return key, nil
+}
+
+// ResetStreamKey replaces the stream key and drops the broadcast running with the old one.
+func (s *Service) ResetStreamKey(ctx context.Context, userId uuid.UUID) (string, error) {
+	const op = "services.channel.ResetStreamKey"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	channel, err := s.channelRepository.ChannelByUserId(ctx, userId)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := s.replaceStreamKey(ctx, channel)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordStreamKeyEvent(ctx, userId, consts.AuditEventStreamKeyReset)
+
+	if channel.StreamKey != nil {
+		// the old key is gone already, ingest servers that miss the kick
+		// still refuse it on the next check
+		if err = s.kicker.Kick(ctx, channel.ID); err != nil {
+			log.Error("failed to kick broadcast", logger.Err(err))
+		}
+	}
+
+	return key, nil
+}
+
+// ChannelByStreamKey resolves a stream key to its channel, it is what the ingest
+// checks a broadcaster with. Any unknown key is ErrStreamKeyInvalid.
+func (s *Service) ChannelByStreamKey(ctx context.Context, key string) (entities.Channel, error) {
+	const op = "services.channel.ChannelByStreamKey"
+
+	if !streamkey.Valid(key) {
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, errs.ErrStreamKeyInvalid)
+	}
+
+	channel, err := s.channelRepository.ChannelByStreamKeyHash(ctx, s.keyring.Hash(key))
+	if err != nil {
+		if errors.Is(err, errs.ErrChannelNotFound) {
+			return entities.Channel{}, fmt.Errorf("%s: %w", op, errs.ErrStreamKeyInvalid)
+		}
+		return entities.Channel{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return channel, nil
+}
+
+// replaceStreamKey stores a new key in place of the one the channel was read with.
+func (s *Service) replaceStreamKey(ctx context.Context, channel entities.Channel) (string, error) {
+	key, err := s.keyring.Generate()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := s.keyring.Seal(key, channel.ID[:])
+	if err != nil {
+		return "", err
+	}
+
+	var previousHash string
+	if channel.StreamKey != nil {
+		previousHash = channel.StreamKey.Hash
+	}
+
+	_, err = s.channelRepository.SaveStreamKey(ctx, channel.UserId, entities.StreamKey{
+		Hash:      s.keyring.Hash(key),
+		Sealed:    sealed,
+		CreatedAt: time.Now(),
+	}, previousHash)
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (s *Service) recordStreamKeyEvent(ctx context.Context, userId uuid.UUID, eventType string) {
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   eventType,
+		UserId: userId,
+	})
+}
@@ -0,0 +1,430 @@
+package channel_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/streamkey"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateChannel(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         entities.User
+		wantLanguage string
+		wantRepoErr  error
+		wantErr      error
+	}{
+		{
+			name:         "good case",
+			user:         entities.User{ID: uuid.New(), Login: "StreamerOne", Locale: "ru"},
+			wantLanguage: "ru",
+		},
+		{
+			name:         "no locale case",
+			user:         entities.User{ID: uuid.New(), Login: "StreamerOne"},
+			wantLanguage: defaultLanguage,
+		},
+		{
+			name:         "login taken case",
+			user:         entities.User{ID: uuid.New(), Login: "StreamerOne"},
+			wantLanguage: defaultLanguage,
+			wantRepoErr:  errs.ErrChannelLoginTaken,
+			wantErr:      errs.ErrChannelLoginTaken,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mRepo := NewMockChannelRepository(t)
+			mRepo.EXPECT().SaveChannel(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.MatchedBy(func(c entities.Channel) bool {
+					return c.UserId == tt.user.ID &&
+						c.Login == "streamerone" &&
+						c.DisplayName == "StreamerOne" &&
+						c.Language == tt.wantLanguage &&
+						c.ID != uuid.Nil
+				}),
+			).Return(tt.wantRepoErr).Once()
+
+			s := New(mRepo, testKeyring(t), NewMockBroadcastKicker(t), NewMockAuditor(t))
+
+			err := s.CreateChannel(context.Background(), tt.user)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_UpdateChannel(t *testing.T) {
+	title := "  speedrun  "
+	mature := true
+	banner := ""
+	tags := []string{"english"}
+
+	tests := []struct {
+		name        string
+		req         dtos.UpdateChannelRequest
+		wantGetErr  error
+		wantSaveErr error
+		want        entities.Channel
+		wantErr     error
+	}{
+		{
+			name: "good case",
+			req: dtos.UpdateChannelRequest{
+				Title:         &title,
+				Mature:        &mature,
+				OfflineBanner: &banner,
+				Tags:          &tags,
+			},
+			want: entities.Channel{
+				Title:       "speedrun",
+				Category:    "Just Chatting",
+				Language:    "en",
+				Tags:        []string{"english"},
+				Mature:      true,
+				Description: "about",
+			},
+		},
+		{
+			name: "empty request case",
+			want: entities.Channel{
+				Title:         "old",
+				Category:      "Just Chatting",
+				Language:      "en",
+				Tags:          []string{"old"},
+				Description:   "about",
+				OfflineBanner: "https://cdn.example.com/banner.png",
+			},
+		},
+		{
+			name:       "channel not found case",
+			req:        dtos.UpdateChannelRequest{Title: &title},
+			wantGetErr: errs.ErrChannelNotFound,
+			wantErr:    errs.ErrChannelNotFound,
+		},
+		{
+			name:        "repository error case",
+			req:         dtos.UpdateChannelRequest{Title: &title},
+			wantSaveErr: errors.New("some error"),
+			wantErr:     errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			stored := entities.Channel{
+				UserId:        userId,
+				Title:         "old",
+				Category:      "Just Chatting",
+				Language:      "en",
+				Tags:          []string{"old"},
+				Description:   "about",
+				OfflineBanner: "https://cdn.example.com/banner.png",
+			}
+
+			mRepo := NewMockChannelRepository(t)
+			mRepo.EXPECT().ChannelByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(stored, tt.wantGetErr).Once()
+			if tt.wantGetErr == nil {
+				mRepo.EXPECT().UpdateSettings(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.Channel"),
+				).Return(tt.wantSaveErr).Once()
+			}
+
+			s := New(mRepo, testKeyring(t), NewMockBroadcastKicker(t), NewMockAuditor(t))
+
+			got, err := s.UpdateChannel(context.Background(), userId, tt.req)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, errs.ErrChannelNotFound) {
+					require.ErrorIs(t, err, errs.ErrChannelNotFound)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, got.UpdatedAt.IsZero())
+
+			tt.want.UserId = userId
+			tt.want.UpdatedAt = got.UpdatedAt
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_StreamKey(t *testing.T) {
+	keyring := testKeyring(t)
+	channelId := uuid.New()
+	key, err := keyring.Generate()
+	require.NoError(t, err)
+	sealed, err := keyring.Seal(key, channelId[:])
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		stored      *entities.StreamKey
+		wantSaveErr error
+		reread      *entities.StreamKey
+		wantKey     string
+		wantErr     error
+	}{
+		{
+			name:    "stored key case",
+			stored:  &entities.StreamKey{Hash: keyring.Hash(key), Sealed: sealed},
+			wantKey: key,
+		},
+		{
+			name: "first reveal case",
+		},
+		{
+			name:        "concurrent first reveal case",
+			wantSaveErr: errs.ErrStreamKeyChanged,
+			reread:      &entities.StreamKey{Hash: keyring.Hash(key), Sealed: sealed},
+			wantKey:     key,
+		},
+		{
+			name:        "repository error case",
+			wantSaveErr: errors.New("some error"),
+			wantErr:     errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			channel := entities.Channel{ID: channelId, UserId: userId, StreamKey: tt.stored}
+
+			mRepo := NewMockChannelRepository(t)
+			mRepo.EXPECT().ChannelByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(channel, nil).Once()
+
+			var saved entities.StreamKey
+			if tt.stored == nil {
+				mRepo.EXPECT().SaveStreamKey(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					mock.AnythingOfType("entities.StreamKey"),
+					"",
+				).RunAndReturn(func(_ context.Context, _ uuid.UUID, key entities.StreamKey, _ string) (entities.Channel, error) {
+					saved = key
+					return entities.Channel{}, tt.wantSaveErr
+				}).Once()
+			}
+			if tt.reread != nil {
+				reread := channel
+				reread.StreamKey = tt.reread
+				mRepo.EXPECT().ChannelByUserId(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+				).Return(reread, nil).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantErr == nil {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.MatchedBy(func(e entities.AuditEvent) bool {
+						return e.Type == consts.AuditEventStreamKeyReveal && e.UserId == userId
+					}),
+				).Return().Once()
+			}
+
+			s := New(mRepo, keyring, NewMockBroadcastKicker(t), mAuditor)
+
+			got, err := s.StreamKey(context.Background(), userId)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantKey != "" {
+				require.Equal(t, tt.wantKey, got)
+				return
+			}
+
+			// a generated key is stored hashed and sealed, never in the clear
+			require.True(t, streamkey.Valid(got))
+			require.Equal(t, keyring.Hash(got), saved.Hash)
+			require.NotContains(t, string(saved.Sealed), got)
+			opened, err := keyring.Open(saved.Sealed, channelId[:])
+			require.NoError(t, err)
+			require.Equal(t, got, opened)
+		})
+	}
+}
+
+func TestService_ResetStreamKey(t *testing.T) {
+	keyring := testKeyring(t)
+
+	tests := []struct {
+		name        string
+		stored      *entities.StreamKey
+		wantSaveErr error
+		wantKick    bool
+		wantKickErr error
+		wantErr     error
+	}{
+		{
+			name:     "good case",
+			stored:   &entities.StreamKey{Hash: "old hash"},
+			wantKick: true,
+		},
+		{
+			name: "no key yet case",
+		},
+		{
+			name:        "kick error case",
+			stored:      &entities.StreamKey{Hash: "old hash"},
+			wantKick:    true,
+			wantKickErr: errors.New("some error"),
+		},
+		{
+			name:        "concurrent reset case",
+			stored:      &entities.StreamKey{Hash: "old hash"},
+			wantSaveErr: errs.ErrStreamKeyChanged,
+			wantErr:     errs.ErrStreamKeyChanged,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			channel := entities.Channel{ID: uuid.New(), UserId: userId, StreamKey: tt.stored}
+
+			mRepo := NewMockChannelRepository(t)
+			mRepo.EXPECT().ChannelByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(channel, nil).Once()
+
+			previousHash := ""
+			if tt.stored != nil {
+				previousHash = tt.stored.Hash
+			}
+			mRepo.EXPECT().SaveStreamKey(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+				mock.AnythingOfType("entities.StreamKey"),
+				previousHash,
+			).Return(entities.Channel{}, tt.wantSaveErr).Once()
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantErr == nil {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.MatchedBy(func(e entities.AuditEvent) bool {
+						return e.Type == consts.AuditEventStreamKeyReset && e.UserId == userId
+					}),
+				).Return().Once()
+			}
+
+			mKicker := NewMockBroadcastKicker(t)
+			if tt.wantKick {
+				mKicker.EXPECT().Kick(
+					mock.AnythingOfType("context.backgroundCtx"),
+					channel.ID,
+				).Return(tt.wantKickErr).Once()
+			}
+
+			s := New(mRepo, keyring, mKicker, mAuditor)
+
+			got, err := s.ResetStreamKey(context.Background(), userId)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, streamkey.Valid(got))
+		})
+	}
+}
+
+func TestService_ChannelByStreamKey(t *testing.T) {
+	keyring := testKeyring(t)
+	key, err := keyring.Generate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		key        string
+		wantLookup bool
+		wantRepo   error
+		wantErr    error
+	}{
+		{
+			name:       "good case",
+			key:        key,
+			wantLookup: true,
+		},
+		{
+			name:    "malformed key case",
+			key:     "not a key",
+			wantErr: errs.ErrStreamKeyInvalid,
+		},
+		{
+			name:       "unknown key case",
+			key:        key,
+			wantLookup: true,
+			wantRepo:   errs.ErrChannelNotFound,
+			wantErr:    errs.ErrStreamKeyInvalid,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{ID: uuid.New()}
+
+			mRepo := NewMockChannelRepository(t)
+			if tt.wantLookup {
+				mRepo.EXPECT().ChannelByStreamKeyHash(
+					mock.AnythingOfType("context.backgroundCtx"),
+					keyring.Hash(tt.key),
+				).Return(channel, tt.wantRepo).Once()
+			}
+
+			s := New(mRepo, keyring, NewMockBroadcastKicker(t), NewMockAuditor(t))
+
+			got, err := s.ChannelByStreamKey(context.Background(), tt.key)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, channel, got)
+		})
+	}
+}
+
+func testKeyring(t *testing.T) *streamkey.Keyring {
+	t.Helper()
+
+	keyring, err := streamkey.NewKeyring("secret")
+	require.NoError(t, err)
+
+	return keyring
+}
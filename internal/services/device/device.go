@@ -0,0 +1,62 @@
+package device_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/lib/device"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	TouchDevice(ctx context.Context, device entities.Device) (bool, error)
+	CountDevices(ctx context.Context, userId uuid.UUID) (int64, error)
+}
+
+type Service struct {
+	repository Repository
+}
+
+func New(repository Repository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// RememberDevice stores the device fingerprint in the user history.
+// isNew is true only when the user already had other devices,
+// so the very first login after registration is not reported as suspicious.
+func (s *Service) RememberDevice(
+	ctx context.Context,
+	userId uuid.UUID,
+	userAgent string,
+	ip string,
+) (entities.Device, bool, error) {
+	const op = "services.device.RememberDevice"
+
+	info := device.ParseUserAgent(userAgent)
+	now := time.Now()
+	knownDevice := entities.Device{
+		ID:        uuid.New(),
+		UserId:    userId,
+		Browser:   info.Browser,
+		OS:        info.OS,
+		IPPrefix:  device.IPPrefix(ip),
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+
+	count, err := s.repository.CountDevices(ctx, userId)
+	if err != nil {
+		return entities.Device{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	inserted, err := s.repository.TouchDevice(ctx, knownDevice)
+	if err != nil {
+		return entities.Device{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return knownDevice, inserted && count > 0, nil
+}
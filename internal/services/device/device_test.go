@@ -0,0 +1,85 @@
+package device_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RememberDevice(t *testing.T) {
+	repositoryErr := errors.New("repository error")
+
+	tests := []struct {
+		name         string
+		count        int64
+		inserted     bool
+		wantCountErr error
+		wantTouchErr error
+		wantNew      bool
+		wantErr      error
+	}{
+		{
+			name:     "known device case",
+			count:    2,
+			inserted: false,
+			wantNew:  false,
+		},
+		{
+			name:     "new device case",
+			count:    2,
+			inserted: true,
+			wantNew:  true,
+		},
+		{
+			name:     "first device case",
+			count:    0,
+			inserted: true,
+			wantNew:  false,
+		},
+		{
+			name:         "count error case",
+			wantCountErr: repositoryErr,
+			wantErr:      repositoryErr,
+		},
+		{
+			name:         "touch error case",
+			count:        1,
+			wantTouchErr: repositoryErr,
+			wantErr:      repositoryErr,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mr := NewMockRepository(t)
+			mr.EXPECT().CountDevices(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(tt.count, tt.wantCountErr).Once()
+			mr.EXPECT().TouchDevice(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.Device"),
+			).Return(tt.inserted, tt.wantTouchErr).Maybe()
+
+			s := New(mr)
+			device, isNew, err := s.RememberDevice(
+				context.Background(),
+				uuid.New(),
+				"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+				"203.0.113.7",
+			)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantNew, isNew)
+			if tt.wantErr == nil {
+				require.Equal(t, "Firefox", device.Browser)
+				require.Equal(t, "203.0.113.0/24", device.IPPrefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package device_auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+)
+
+// userCodeAlphabet has no vowels and no look-alike characters, as RFC 8628 section 6.1 suggests.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+const (
+	userCodeLength   = 8
+	userCodeAttempts = 3
+	slowDownStep     = 5 * time.Second
+)
+
+type Repository interface {
+	SaveAuthorization(ctx context.Context, auth entities.DeviceAuthorization) error
+	AuthorizationByDeviceCode(ctx context.Context, deviceCode string) (entities.DeviceAuthorization, error)
+	AuthorizationByUserCode(ctx context.Context, userCode string) (entities.DeviceAuthorization, error)
+	Decide(ctx context.Context, deviceCode, status, userId string) error
+	RecordPoll(ctx context.Context, deviceCode string, at time.Time, interval time.Duration) error
+	DeleteAuthorization(ctx context.Context, auth entities.DeviceAuthorization) error
+}
+
+type UserProvider interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+}
+
+type SessionCreator interface {
+	CreateSession(ctx context.Context, userId uuid.UUID, role string, userAgent string) (uuid.UUID, error)
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type Service struct {
+	repository     Repository
+	userProvider   UserProvider
+	sessionCreator SessionCreator
+	auditor        Auditor
+	cfg            config.DeviceAuthConfig
+}
+
+func New(
+	repository Repository,
+	userProvider UserProvider,
+	sessionCreator SessionCreator,
+	auditor Auditor,
+	cfg config.DeviceAuthConfig,
+) *Service {
+	return &Service{
+		repository:     repository,
+		userProvider:   userProvider,
+		sessionCreator: sessionCreator,
+		auditor:        auditor,
+		cfg:            cfg,
+	}
+}
+
+// RequestCode starts the device flow for clientId.
+func (s *Service) RequestCode(ctx context.Context, req dtos.DeviceCodeRequest) (entities.DeviceAuthorization, error) {
+	const op = "services.device_auth.RequestCode"
+
+	auth := entities.DeviceAuthorization{
+		DeviceCode: rand.Text(),
+		ClientId:   req.ClientId,
+		Status:     consts.DeviceAuthorizationPending,
+		Interval:   s.cfg.Interval,
+		ExpiresAt:  time.Now().Add(s.cfg.CodeTTL),
+	}
+
+	var err error
+	for range userCodeAttempts {
+		auth.UserCode, err = genUserCode()
+		if err != nil {
+			return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = s.repository.SaveAuthorization(ctx, auth)
+		if !errors.Is(err, errs.ErrUserCodeTaken) {
+			break
+		}
+	}
+	if err != nil {
+		return entities.DeviceAuthorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return auth, nil
+}
+
+// Activate lets the logged in user approve or deny the device showing req.UserCode.
+func (s *Service) Activate(ctx context.Context, userId uuid.UUID, req dtos.ActivateDeviceRequest) error {
+	const op = "services.device_auth.Activate"
+
+	auth, err := s.repository.AuthorizationByUserCode(ctx, NormalizeUserCode(req.UserCode))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	status, event := consts.DeviceAuthorizationApproved, consts.AuditEventDeviceAuthorized
+	if req.Deny {
+		status, event = consts.DeviceAuthorizationDenied, consts.AuditEventDeviceDenied
+	}
+
+	err = s.repository.Decide(ctx, auth.DeviceCode, status, userId.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     event,
+		UserId:   userId,
+		Metadata: map[string]string{"client_id": auth.ClientId},
+	})
+
+	return nil
+}
+
+// PollToken is called by the device until the user acts on the code.
+// Errors follow RFC 8628 section 3.5: ErrAuthorizationPending, ErrSlowDown, ErrAccessDenied, ErrExpiredToken
+// and ErrInvalidGrant for a device code issued to another client.
+// On approval the device gets a session of its own, the grant can be exchanged only once.
+func (s *Service) PollToken(ctx context.Context, req dtos.DeviceTokenRequest, userAgent string) (uuid.UUID, error) {
+	const op = "services.device_auth.PollToken"
+
+	if req.GrantType != consts.DeviceGrantType {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrUnsupportedGrantType)
+	}
+
+	auth, err := s.repository.AuthorizationByDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		if errors.Is(err, errs.ErrDeviceCodeNotFound) {
+			return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrExpiredToken)
+		}
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if auth.ClientId != req.ClientId {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrInvalidGrant)
+	}
+
+	now := time.Now()
+	interval := auth.Interval
+	tooFast := now.Sub(auth.LastPolledAt) < auth.Interval
+	if tooFast {
+		interval += slowDownStep
+	}
+	err = s.repository.RecordPoll(ctx, auth.DeviceCode, now, interval)
+	if err != nil {
+		if errors.Is(err, errs.ErrDeviceCodeNotFound) {
+			return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrExpiredToken)
+		}
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if tooFast {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrSlowDown)
+	}
+
+	switch auth.Status {
+	case consts.DeviceAuthorizationPending:
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrAuthorizationPending)
+	case consts.DeviceAuthorizationDenied:
+		_ = s.repository.DeleteAuthorization(ctx, auth)
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrAccessDenied)
+	}
+
+	err = s.repository.DeleteAuthorization(ctx, auth)
+	if err != nil {
+		if errors.Is(err, errs.ErrDeviceCodeNotFound) {
+			return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrExpiredToken)
+		}
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userId, err := uuid.Parse(auth.UserId)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+	user, err := s.userProvider.UserById(ctx, userId)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.IsSuspended(now) {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrAccessDenied)
+	}
+
+	sessionId, err := s.sessionCreator.CreateSession(ctx, user.ID, user.Role, userAgent)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventLoginSuccess,
+		UserId: user.ID,
+		Metadata: map[string]string{
+			"session_id": sessionId.String(),
+			"client_id":  auth.ClientId,
+			"method":     "device_code",
+		},
+	})
+
+	return sessionId, nil
+}
+
+// VerificationURIComplete is the activation link with the user code filled in, suitable for a QR code.
+func (s *Service) VerificationURIComplete(userCode string) string {
+	return s.cfg.VerificationURI + "?user_code=" + userCode
+}
+
+func (s *Service) VerificationURI() string {
+	return s.cfg.VerificationURI
+}
+
+// NormalizeUserCode makes the code typed by the user comparable to the stored one:
+// case and the dash or spaces between the two halves do not matter.
+func NormalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if len(code) != userCodeLength {
+		return code
+	}
+
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+func genUserCode() (string, error) {
+	alphabetLen := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, 0, userCodeLength+1)
+	for i := range userCodeLength {
+		if i == userCodeLength/2 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, userCodeAlphabet[n.Int64()])
+	}
+
+	return string(code), nil
+}
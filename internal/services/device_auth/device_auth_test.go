@@ -0,0 +1,307 @@
+package device_auth_service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.DeviceAuthConfig{
+	CodeTTL:         10 * time.Minute,
+	Interval:        5 * time.Second,
+	VerificationURI: "http://localhost/activate",
+}
+
+func TestService_RequestCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		saveErrs  []error
+		wantSaves int
+		wantErr   error
+	}{
+		{
+			name:      "good case",
+			saveErrs:  []error{nil},
+			wantSaves: 1,
+			wantErr:   nil,
+		},
+		{
+			name:      "user code collision case",
+			saveErrs:  []error{errs.ErrUserCodeTaken, nil},
+			wantSaves: 2,
+			wantErr:   nil,
+		},
+		{
+			name:      "user code space exhausted case",
+			saveErrs:  []error{errs.ErrUserCodeTaken, errs.ErrUserCodeTaken, errs.ErrUserCodeTaken},
+			wantSaves: 3,
+			wantErr:   errs.ErrUserCodeTaken,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+			for _, saveErr := range tt.saveErrs {
+				m.EXPECT().SaveAuthorization(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.DeviceAuthorization"),
+				).Return(saveErr).Once()
+			}
+
+			s := New(m, NewMockUserProvider(t), NewMockSessionCreator(t), NewMockAuditor(t), testCfg)
+			auth, err := s.RequestCode(context.Background(), dtos.DeviceCodeRequest{ClientId: "tv"})
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Regexp(t, regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), auth.UserCode)
+				require.NotEmpty(t, auth.DeviceCode)
+				require.Equal(t, consts.DeviceAuthorizationPending, auth.Status)
+				require.Equal(t, testCfg.Interval, auth.Interval)
+			}
+		})
+	}
+}
+
+func TestService_Activate(t *testing.T) {
+	tests := []struct {
+		name          string
+		deny          bool
+		wantStatus    string
+		wantLookupErr error
+		wantDecideErr error
+		wantErr       error
+	}{
+		{
+			name:       "approve case",
+			wantStatus: consts.DeviceAuthorizationApproved,
+		},
+		{
+			name:       "deny case",
+			deny:       true,
+			wantStatus: consts.DeviceAuthorizationDenied,
+		},
+		{
+			name:          "unknown code case",
+			wantLookupErr: errs.ErrDeviceCodeNotFound,
+			wantErr:       errs.ErrDeviceCodeNotFound,
+		},
+		{
+			name:          "already decided case",
+			wantStatus:    consts.DeviceAuthorizationApproved,
+			wantDecideErr: errs.ErrDeviceCodeNotFound,
+			wantErr:       errs.ErrDeviceCodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+			auth := entities.DeviceAuthorization{DeviceCode: "device code", UserCode: "BCDF-GHJK", ClientId: "tv"}
+
+			m := NewMockRepository(t)
+			m.EXPECT().AuthorizationByUserCode(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"BCDF-GHJK",
+			).Return(auth, tt.wantLookupErr).Once()
+			m.EXPECT().Decide(
+				mock.AnythingOfType("context.backgroundCtx"),
+				auth.DeviceCode,
+				tt.wantStatus,
+				userId.String(),
+			).Return(tt.wantDecideErr).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := New(m, NewMockUserProvider(t), NewMockSessionCreator(t), mAuditor, testCfg)
+			err := s.Activate(context.Background(), userId, dtos.ActivateDeviceRequest{
+				UserCode: "bcdf ghjk",
+				Deny:     tt.deny,
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_PollToken(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name          string
+		grantType     string
+		clientId      string
+		auth          entities.DeviceAuthorization
+		wantLookupErr error
+		wantDeleteErr error
+		wantSession   bool
+		wantErr       error
+	}{
+		{
+			name:      "pending case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "tv",
+			auth: entities.DeviceAuthorization{
+				ClientId: "tv",
+				Status:   consts.DeviceAuthorizationPending,
+				Interval: 5 * time.Second,
+			},
+			wantErr: errs.ErrAuthorizationPending,
+		},
+		{
+			name:      "polling too fast case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "tv",
+			auth: entities.DeviceAuthorization{
+				ClientId:     "tv",
+				Status:       consts.DeviceAuthorizationApproved,
+				Interval:     5 * time.Second,
+				LastPolledAt: time.Now(),
+			},
+			wantErr: errs.ErrSlowDown,
+		},
+		{
+			name:      "denied case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "tv",
+			auth: entities.DeviceAuthorization{
+				ClientId: "tv",
+				Status:   consts.DeviceAuthorizationDenied,
+				Interval: 5 * time.Second,
+			},
+			wantErr: errs.ErrAccessDenied,
+		},
+		{
+			name:      "approved case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "tv",
+			auth: entities.DeviceAuthorization{
+				ClientId: "tv",
+				Status:   consts.DeviceAuthorizationApproved,
+				UserId:   userId.String(),
+				Interval: 5 * time.Second,
+			},
+			wantSession: true,
+			wantErr:     nil,
+		},
+		{
+			name:      "already exchanged case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "tv",
+			auth: entities.DeviceAuthorization{
+				ClientId: "tv",
+				Status:   consts.DeviceAuthorizationApproved,
+				UserId:   userId.String(),
+				Interval: 5 * time.Second,
+			},
+			wantDeleteErr: errs.ErrDeviceCodeNotFound,
+			wantErr:       errs.ErrExpiredToken,
+		},
+		{
+			name:          "expired case",
+			grantType:     consts.DeviceGrantType,
+			clientId:      "tv",
+			wantLookupErr: errs.ErrDeviceCodeNotFound,
+			wantErr:       errs.ErrExpiredToken,
+		},
+		{
+			name:      "other client case",
+			grantType: consts.DeviceGrantType,
+			clientId:  "console",
+			auth: entities.DeviceAuthorization{
+				ClientId: "tv",
+				Status:   consts.DeviceAuthorizationApproved,
+			},
+			wantErr: errs.ErrInvalidGrant,
+		},
+		{
+			name:      "wrong grant type case",
+			grantType: "password",
+			clientId:  "tv",
+			wantErr:   errs.ErrUnsupportedGrantType,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.auth.DeviceCode = "device code"
+
+			m := NewMockRepository(t)
+			m.EXPECT().AuthorizationByDeviceCode(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"device code",
+			).Return(tt.auth, tt.wantLookupErr).Maybe()
+			m.EXPECT().RecordPoll(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"device code",
+				mock.AnythingOfType("time.Time"),
+				mock.AnythingOfType("time.Duration"),
+			).Return(nil).Maybe()
+			m.EXPECT().DeleteAuthorization(
+				mock.AnythingOfType("context.backgroundCtx"),
+				tt.auth,
+			).Return(tt.wantDeleteErr).Maybe()
+
+			mUserProvider := NewMockUserProvider(t)
+			mSessionCreator := NewMockSessionCreator(t)
+			mAuditor := NewMockAuditor(t)
+			if tt.wantSession {
+				mUserProvider.EXPECT().UserById(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+				).Return(entities.User{ID: userId, Role: consts.RoleUser}, nil).Once()
+				mSessionCreator.EXPECT().CreateSession(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					consts.RoleUser,
+					"smart tv",
+				).Return(uuid.New(), nil).Once()
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.AuditEvent"),
+				).Return().Once()
+			}
+
+			s := New(m, mUserProvider, mSessionCreator, mAuditor, testCfg)
+			_, err := s.PollToken(context.Background(), dtos.DeviceTokenRequest{
+				GrantType:  tt.grantType,
+				DeviceCode: "device code",
+				ClientId:   tt.clientId,
+			}, "smart tv")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{code: "BCDF-GHJK", want: "BCDF-GHJK"},
+		{code: "bcdfghjk", want: "BCDF-GHJK"},
+		{code: " bcdf ghjk ", want: "BCDF-GHJK"},
+		{code: "bcd", want: "BCD"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, NormalizeUserCode(tt.code), tt.code)
+	}
+}
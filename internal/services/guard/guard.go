@@ -0,0 +1,240 @@
+package guard_service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+)
+
+// DefaultReservedLogins can not be taken by regular users, they could be used to impersonate the platform.
+var DefaultReservedLogins = []string{
+	"admin",
+	"administrator",
+	"moderator",
+	"mod",
+	"official",
+	"root",
+	"staff",
+	"support",
+	"system",
+	"twitch",
+	"twitchclone",
+}
+
+// Checker is one step of the registration pipeline. It returns a distinct errs.Err* on rejection.
+type Checker interface {
+	Check(ctx context.Context, req dtos.RegisterRequest) error
+}
+
+type Counter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// Service runs the checkers in order and stops at the first rejection,
+// so cheap local checks should go before network ones.
+type Service struct {
+	checkers []Checker
+}
+
+func New(checkers ...Checker) *Service {
+	return &Service{
+		checkers: checkers,
+	}
+}
+
+func (s *Service) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	const op = "services.guard.Check"
+
+	for _, checker := range s.checkers {
+		if err := checker.Check(ctx, req); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// DisposableDomains rejects emails of throwaway mail providers, subdomains included.
+type DisposableDomains struct {
+	domains map[string]struct{}
+}
+
+func NewDisposableDomains(domains []string) *DisposableDomains {
+	set := make(map[string]struct{}, len(domains))
+	for _, domain := range domains {
+		set[strings.ToLower(domain)] = struct{}{}
+	}
+
+	return &DisposableDomains{
+		domains: set,
+	}
+}
+
+func (d *DisposableDomains) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	at := strings.LastIndex(req.Email, "@")
+	domain := strings.ToLower(req.Email[at+1:])
+
+	for {
+		if _, ok := d.domains[domain]; ok {
+			return errs.ErrDisposableEmail
+		}
+
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return nil
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+// Logins rejects reserved logins (exact match) and offensive ones (substring match).
+// Both are compared after normalization, so "Adm1n" or "s_t_a_f_f" do not slip through.
+type Logins struct {
+	reserved  map[string]struct{}
+	offensive []string
+}
+
+func NewLogins(reserved, offensive []string) *Logins {
+	set := make(map[string]struct{}, len(reserved))
+	for _, login := range reserved {
+		set[normalizeLogin(login)] = struct{}{}
+	}
+
+	words := make([]string, 0, len(offensive))
+	for _, word := range offensive {
+		if word = normalizeLogin(word); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return &Logins{
+		reserved:  set,
+		offensive: words,
+	}
+}
+
+func (l *Logins) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	login := normalizeLogin(req.Login)
+
+	if _, ok := l.reserved[login]; ok {
+		return errs.ErrReservedLogin
+	}
+	for _, word := range l.offensive {
+		if strings.Contains(login, word) {
+			return errs.ErrReservedLogin
+		}
+	}
+
+	return nil
+}
+
+// SignupVelocity limits the number of registration attempts from one IP during window.
+type SignupVelocity struct {
+	counter Counter
+	limit   int64
+	window  time.Duration
+}
+
+func NewSignupVelocity(counter Counter, limit int64, window time.Duration) *SignupVelocity {
+	return &SignupVelocity{
+		counter: counter,
+		limit:   limit,
+		window:  window,
+	}
+}
+
+func (v *SignupVelocity) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	ip, _ := ctx.Value(consts.ContextClientIP).(string)
+	if ip == "" || v.limit <= 0 {
+		return nil
+	}
+
+	count, err := v.counter.Increment(ctx, "signup:"+ip, v.window)
+	if err != nil {
+		return err
+	}
+	if count > v.limit {
+		return errs.ErrSignupVelocity
+	}
+
+	return nil
+}
+
+type Captcha struct {
+	verifier CaptchaVerifier
+}
+
+func NewCaptcha(verifier CaptchaVerifier) *Captcha {
+	return &Captcha{
+		verifier: verifier,
+	}
+}
+
+func (c *Captcha) Check(ctx context.Context, req dtos.RegisterRequest) error {
+	ip, _ := ctx.Value(consts.ContextClientIP).(string)
+
+	return c.verifier.Verify(ctx, req.CaptchaToken, ip)
+}
+
+// ReadList reads a word list file: one entry per line, blank lines and # comments are skipped.
+func ReadList(path string) ([]string, error) {
+	const op = "services.guard.ReadList"
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var list []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list = append(list, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"@", "a",
+	"$", "s",
+)
+
+func normalizeLogin(login string) string {
+	login = leetReplacer.Replace(strings.ToLower(login))
+
+	var b strings.Builder
+	for _, r := range login {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
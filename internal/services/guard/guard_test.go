@@ -0,0 +1,169 @@
+package guard_service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposableDomains_Check(t *testing.T) {
+	checker := NewDisposableDomains([]string{"mailinator.com", "10minutemail.com"})
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{
+			name:  "good case",
+			email: "user@gmail.com",
+		},
+		{
+			name:    "disposable case",
+			email:   "user@Mailinator.com",
+			wantErr: errs.ErrDisposableEmail,
+		},
+		{
+			name:    "subdomain case",
+			email:   "user@eu.10minutemail.com",
+			wantErr: errs.ErrDisposableEmail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.Check(context.Background(), dtos.RegisterRequest{Email: tt.email})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLogins_Check(t *testing.T) {
+	checker := NewLogins(DefaultReservedLogins, []string{"badword"})
+
+	tests := []struct {
+		name    string
+		login   string
+		wantErr error
+	}{
+		{
+			name:  "good case",
+			login: "streamer42",
+		},
+		{
+			name:    "reserved case",
+			login:   "Admin",
+			wantErr: errs.ErrReservedLogin,
+		},
+		{
+			name:    "obfuscated reserved case",
+			login:   "st4_ff",
+			wantErr: errs.ErrReservedLogin,
+		},
+		{
+			name:    "offensive case",
+			login:   "the_b4dw0rd_guy",
+			wantErr: errs.ErrReservedLogin,
+		},
+		{
+			name:  "reserved word inside login case",
+			login: "adminfan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.Check(context.Background(), dtos.RegisterRequest{Login: tt.login})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSignupVelocity_Check(t *testing.T) {
+	counterErr := errors.New("counter error")
+
+	tests := []struct {
+		name           string
+		ip             string
+		count          int64
+		wantCounterErr error
+		wantErr        error
+	}{
+		{
+			name:  "under limit case",
+			ip:    "203.0.113.7",
+			count: 3,
+		},
+		{
+			name:    "over limit case",
+			ip:      "203.0.113.7",
+			count:   4,
+			wantErr: errs.ErrSignupVelocity,
+		},
+		{
+			name: "unknown ip case",
+			ip:   "",
+		},
+		{
+			name:           "counter error case",
+			ip:             "203.0.113.7",
+			wantCounterErr: counterErr,
+			wantErr:        counterErr,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockCounter(t)
+			m.EXPECT().Increment(
+				mock.AnythingOfType("*context.valueCtx"),
+				"signup:"+tt.ip,
+				time.Hour,
+			).Return(tt.count, tt.wantCounterErr).Maybe()
+
+			//nolint:staticcheck
+			ctx := context.WithValue(context.Background(), consts.ContextClientIP, tt.ip)
+			err := NewSignupVelocity(m, 3, time.Hour).Check(ctx, dtos.RegisterRequest{})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	mVerifier := NewMockCaptchaVerifier(t)
+	mVerifier.EXPECT().Verify(
+		mock.AnythingOfType("context.backgroundCtx"),
+		"token",
+		"",
+	).Return(errs.ErrCaptchaInvalid).Once()
+
+	s := New(
+		NewDisposableDomains([]string{"mailinator.com"}),
+		NewCaptcha(mVerifier),
+	)
+
+	err := s.Check(context.Background(), dtos.RegisterRequest{Email: "user@mailinator.com", CaptchaToken: "token"})
+	require.ErrorIs(t, err, errs.ErrDisposableEmail)
+
+	err = s.Check(context.Background(), dtos.RegisterRequest{Email: "user@gmail.com", CaptchaToken: "token"})
+	require.ErrorIs(t, err, errs.ErrCaptchaInvalid)
+}
+
+func TestReadList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.txt")
+	err := os.WriteFile(path, []byte("# comment\nmailinator.com\n\n  yopmail.com  \n"), 0o600)
+	require.NoError(t, err)
+
+	list, err := ReadList(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"mailinator.com", "yopmail.com"}, list)
+}
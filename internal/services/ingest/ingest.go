@@ -0,0 +1,261 @@
+package ingest_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type BroadcastService interface {
+	Start(ctx context.Context, key, clientId string) (entities.Channel, error)
+	Touch(ctx context.Context, channel entities.Channel, clientId string) error
+	End(ctx context.Context, channelId uuid.UUID, clientId string) error
+}
+
+type KickSubscriber interface {
+	Kicks(ctx context.Context) (<-chan uuid.UUID, error)
+}
+
+// Service runs in cmd/ingest. It hands the media of broadcasters to the sink and drops them when
+// their key is reset. Who may broadcast is up to the broadcast service, which keeps one
+// broadcast per channel across all ingest servers.
+type Service struct {
+	broadcasts BroadcastService
+	kicks      KickSubscriber
+	sink       media.Sink
+	cfg        config.IngestConfig
+
+	mu sync.Mutex
+	// sessions are the broadcasts of this server, to find the ones to kick
+	sessions map[uuid.UUID]*Session
+}
+
+func New(broadcasts BroadcastService, kicks KickSubscriber, sink media.Sink, cfg config.IngestConfig) *Service {
+	return &Service{
+		broadcasts: broadcasts,
+		kicks:      kicks,
+		sink:       sink,
+		cfg:        cfg,
+		sessions:   make(map[uuid.UUID]*Session),
+	}
+}
+
+// Session is a broadcast in progress.
+type Session struct {
+	service   *Service
+	channel   entities.Channel
+	clientId  string
+	protocol  string
+	startedAt time.Time
+	writer    media.Writer
+	stop      func()
+	// ctx outlives the request that started the session
+	ctx       context.Context
+	log       *slog.Logger
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Start lets in the broadcaster of key. stop is called if the broadcast is kicked, it has to
+// disconnect the broadcaster, which then closes the session like any other disconnect.
+func (s *Service) Start(ctx context.Context, key, protocol string, stop func()) (*Session, error) {
+	const op = "services.ingest.Start"
+
+	clientId := uuid.NewString()
+	channel, err := s.broadcasts.Start(ctx, key, clientId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	session := &Session{
+		service:   s,
+		channel:   channel,
+		clientId:  clientId,
+		protocol:  protocol,
+		startedAt: time.Now(),
+		stop:      stop,
+		ctx:       ctx,
+		log:       logger.FromCtx(ctx),
+		done:      make(chan struct{}),
+	}
+
+	writer, err := s.sink.Open(ctx, media.Stream{
+		ChannelId: channel.ID,
+		Login:     channel.Login,
+		Protocol:  protocol,
+		StartedAt: session.startedAt,
+	})
+	if err != nil {
+		session.end()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	session.writer = writer
+
+	// a broadcast another server took over is replaced here too, its updates fail and stop it
+	s.mu.Lock()
+	s.sessions[channel.ID] = session
+	s.mu.Unlock()
+
+	if s.cfg.UpdateInterval > 0 {
+		go session.keepAlive()
+	}
+
+	session.log.Info(
+		"broadcast started",
+		slog.String("op", op),
+		slog.String("channel_id", channel.ID.String()),
+		slog.String("login", channel.Login),
+		slog.String("protocol", protocol),
+	)
+
+	return session, nil
+}
+
+// Kick disconnects the broadcaster of the channel, it reports whether there was one.
+func (s *Service) Kick(channelId uuid.UUID) bool {
+	s.mu.Lock()
+	session, ok := s.sessions[channelId]
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	session.stop()
+	return true
+}
+
+// WatchKicks subscribes to the kick notices sent on stream key resets and drops the kicked
+// broadcasts until ctx is done.
+func (s *Service) WatchKicks(ctx context.Context) error {
+	const op = "services.ingest.WatchKicks"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	kicks, err := s.kicks.Kicks(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	go func() {
+		for channelId := range kicks {
+			if s.Kick(channelId) {
+				log.Info("broadcast kicked", slog.String("channel_id", channelId.String()))
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *Service) release(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[session.channel.ID] == session {
+		delete(s.sessions, session.channel.ID)
+	}
+}
+
+func (s *Session) Channel() entities.Channel {
+	return s.channel
+}
+
+func (s *Session) WritePacket(packet media.Packet) error {
+	const op = "services.ingest.Session.WritePacket"
+
+	if err := s.writer.WritePacket(packet); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Close ends the broadcast, calling it again does nothing.
+func (s *Session) Close() error {
+	const op = "services.ingest.Session.Close"
+
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.service.release(s)
+		err = s.writer.Close()
+		s.end()
+
+		s.log.Info(
+			"broadcast ended",
+			slog.String("op", op),
+			slog.String("channel_id", s.channel.ID.String()),
+			slog.String("protocol", s.protocol),
+			slog.Duration("duration", time.Since(s.startedAt)),
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// keepAlive confirms the broadcast every UpdateInterval until the session is closed. It stops
+// the broadcaster once the broadcast may not go on, errors of the database are only logged.
+func (s *Session) keepAlive() {
+	const op = "services.ingest.Session.keepAlive"
+
+	ticker := time.NewTicker(s.service.cfg.UpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		err := s.service.broadcasts.Touch(s.ctx, s.channel, s.clientId)
+		switch {
+		case errors.Is(err, errs.ErrBroadcastNotFound),
+			errors.Is(err, errs.ErrStreamKeyInvalid),
+			errors.Is(err, errs.ErrUserSuspended):
+			s.log.Info(
+				"broadcast stopped",
+				slog.String("op", op),
+				slog.String("channel_id", s.channel.ID.String()),
+				logger.Err(err),
+			)
+			s.stop()
+			return
+		case err != nil:
+			s.log.Error(
+				"failed to update broadcast",
+				slog.String("op", op),
+				slog.String("channel_id", s.channel.ID.String()),
+				logger.Err(err),
+			)
+		}
+	}
+}
+
+// end marks the channel offline.
+func (s *Session) end() {
+	const op = "services.ingest.Session.end"
+
+	err := s.service.broadcasts.End(s.ctx, s.channel.ID, s.clientId)
+	if err != nil {
+		s.log.Error(
+			"failed to end broadcast",
+			slog.String("op", op),
+			slog.String("channel_id", s.channel.ID.String()),
+			logger.Err(err),
+		)
+	}
+}
@@ -0,0 +1,517 @@
+package ingest_service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+	"github.com/AlexMickh/twitch-clone/internal/lib/media"
+	"github.com/AlexMickh/twitch-clone/internal/lib/rtmp"
+	"github.com/AlexMickh/twitch-clone/internal/lib/whip"
+	"github.com/google/uuid"
+	"github.com/pion/webrtc/v4"
+	pionmedia "github.com/pion/webrtc/v4/pkg/media"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "live_key"
+
+// testSink records what every broadcast wrote.
+type testSink struct {
+	mu      sync.Mutex
+	streams []media.Stream
+	packets chan media.Packet
+	closed  chan struct{}
+}
+
+func newTestSink() *testSink {
+	return &testSink{
+		packets: make(chan media.Packet, 16),
+		closed:  make(chan struct{}, 4),
+	}
+}
+
+func (s *testSink) Open(_ context.Context, stream media.Stream) (media.Writer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streams = append(s.streams, stream)
+	return s, nil
+}
+
+func (s *testSink) WritePacket(packet media.Packet) error {
+	s.packets <- packet
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.closed <- struct{}{}
+	return nil
+}
+
+// testBroadcasts lets in testKey and keeps one broadcast per channel, like the channel repository.
+type testBroadcasts struct {
+	channel  entities.Channel
+	startErr error
+	touchErr error
+
+	mu   sync.Mutex
+	live map[uuid.UUID]string
+}
+
+func newTestBroadcasts(channel entities.Channel) *testBroadcasts {
+	return &testBroadcasts{
+		channel: channel,
+		live:    make(map[uuid.UUID]string),
+	}
+}
+
+func (b *testBroadcasts) Start(_ context.Context, key, clientId string) (entities.Channel, error) {
+	if b.startErr != nil {
+		return entities.Channel{}, b.startErr
+	}
+	if key != testKey {
+		return entities.Channel{}, errs.ErrStreamKeyInvalid
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.live[b.channel.ID]; ok {
+		return entities.Channel{}, errs.ErrAlreadyLive
+	}
+	b.live[b.channel.ID] = clientId
+
+	return b.channel, nil
+}
+
+func (b *testBroadcasts) Touch(_ context.Context, channel entities.Channel, clientId string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.live[channel.ID] != clientId {
+		return errs.ErrBroadcastNotFound
+	}
+	return b.touchErr
+}
+
+func (b *testBroadcasts) End(_ context.Context, channelId uuid.UUID, clientId string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.live[channelId] == clientId {
+		delete(b.live, channelId)
+	}
+	return nil
+}
+
+func (b *testBroadcasts) isLive() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.live[b.channel.ID]
+	return ok
+}
+
+func TestService_Publish(t *testing.T) {
+	tests := []struct {
+		name      string
+		app       string
+		lookupErr error
+		live      bool
+		wantCode  string
+	}{
+		{
+			name: "good case",
+			app:  "live",
+		},
+		{
+			name:      "invalid key case",
+			app:       "live",
+			lookupErr: errs.ErrStreamKeyInvalid,
+			wantCode:  "NetStream.Publish.Denied",
+		},
+		{
+			name:      "suspended owner case",
+			app:       "live",
+			lookupErr: errs.ErrUserSuspended,
+			wantCode:  "NetStream.Publish.Denied",
+		},
+		{
+			name:     "already live case",
+			app:      "live",
+			live:     true,
+			wantCode: "NetStream.Publish.BadName",
+		},
+		{
+			name:     "unknown app case",
+			app:      "other",
+			wantCode: "NetStream.Publish.Denied",
+		},
+		{
+			name:      "lookup error case",
+			app:       "live",
+			lookupErr: errors.New("some error"),
+			wantCode:  "NetStream.Publish.Failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{ID: uuid.New(), Login: "streamer"}
+
+			broadcasts := newTestBroadcasts(channel)
+			broadcasts.startErr = tt.lookupErr
+
+			sink := newTestSink()
+			s := New(broadcasts, NewMockKickSubscriber(t), sink, config.IngestConfig{App: "live"})
+			if tt.live {
+				_, err := s.Start(context.Background(), testKey, consts.IngestProtocolWHIP, func() {})
+				require.NoError(t, err)
+			}
+			addr := startServer(t, s)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := rtmp.Dial(ctx, "rtmp://"+addr+"/"+tt.app)
+			require.NoError(t, err)
+			defer func() {
+				_ = client.Close()
+			}()
+
+			err = client.Publish(ctx, testKey)
+			if tt.wantCode != "" {
+				var status *rtmp.StatusError
+				require.ErrorAs(t, err, &status)
+				require.Equal(t, tt.wantCode, status.Code)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, client.WriteTag(flv.Tag{Type: flv.TagScript, Data: []byte{5}}))
+			require.NoError(t, client.WriteTag(flv.Tag{Type: flv.TagVideo, Data: []byte{0x17, 0, 0, 0, 0, 1}}))
+			require.NoError(t, client.WriteTag(flv.Tag{Type: flv.TagAudio, Timestamp: 21, Data: []byte{0xaf, 1, 'x'}}))
+
+			want := []media.Packet{
+				{Codec: media.CodecH264, Keyframe: true, Config: true, Data: []byte{1}},
+				{Codec: media.CodecAAC, Time: 21 * time.Millisecond, Data: []byte{'x'}},
+			}
+			for _, packet := range want {
+				select {
+				case got := <-sink.packets:
+					require.Equal(t, packet, got)
+				case <-ctx.Done():
+					t.Fatal("packet did not arrive")
+				}
+			}
+
+			sink.mu.Lock()
+			require.Len(t, sink.streams, 1)
+			require.Equal(t, channel.ID, sink.streams[0].ChannelId)
+			require.Equal(t, "rtmp", sink.streams[0].Protocol)
+			sink.mu.Unlock()
+
+			// a second publisher is refused while the first is live
+			second, err := rtmp.Dial(ctx, "rtmp://"+addr+"/live")
+			require.NoError(t, err)
+			defer func() {
+				_ = second.Close()
+			}()
+			var status *rtmp.StatusError
+			require.ErrorAs(t, second.Publish(ctx, testKey), &status)
+			require.Equal(t, "NetStream.Publish.BadName", status.Code)
+
+			// unpublishing frees the channel
+			require.NoError(t, client.Close())
+			select {
+			case <-sink.closed:
+			case <-ctx.Done():
+				t.Fatal("writer was not closed")
+			}
+			require.Eventually(t, func() bool {
+				return !s.Kick(channel.ID) && !broadcasts.isLive()
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestService_WatchKicks(t *testing.T) {
+	t.Parallel()
+
+	channel := entities.Channel{ID: uuid.New(), Login: "streamer"}
+
+	kicks := make(chan uuid.UUID)
+	mKicks := NewMockKickSubscriber(t)
+	mKicks.EXPECT().Kicks(
+		mock.AnythingOfType("*context.cancelCtx"),
+	).Return(kicks, nil).Once()
+
+	sink := newTestSink()
+	s := New(newTestBroadcasts(channel), mKicks, sink, config.IngestConfig{App: "live"})
+	addr := startServer(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	require.NoError(t, s.WatchKicks(watchCtx))
+
+	client, err := rtmp.Dial(ctx, "rtmp://"+addr+"/live")
+	require.NoError(t, err)
+	defer func() {
+		_ = client.Close()
+	}()
+	require.NoError(t, client.Publish(ctx, testKey))
+
+	// other channels are not affected
+	kicks <- uuid.New()
+	kicks <- channel.ID
+
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+		t.Fatal("broadcaster was not kicked")
+	}
+	select {
+	case <-sink.closed:
+	case <-ctx.Done():
+		t.Fatal("writer was not closed")
+	}
+	close(kicks)
+}
+
+func TestService_PublishWHIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		lookupErr  error
+		live       bool
+		wantStatus int
+	}{
+		{
+			name: "good case",
+		},
+		{
+			name:       "invalid key case",
+			lookupErr:  errs.ErrStreamKeyInvalid,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "suspended owner case",
+			lookupErr:  errs.ErrUserSuspended,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "already live case",
+			live:       true,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "lookup error case",
+			lookupErr:  errors.New("some error"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{ID: uuid.New(), Login: "streamer"}
+
+			broadcasts := newTestBroadcasts(channel)
+			broadcasts.startErr = tt.lookupErr
+
+			sink := newTestSink()
+			s := New(broadcasts, NewMockKickSubscriber(t), sink, config.IngestConfig{App: "live"})
+			if tt.live {
+				_, err := s.Start(context.Background(), testKey, consts.IngestProtocolRTMP, func() {})
+				require.NoError(t, err)
+			}
+			endpoint := startWHIPServer(t, s)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+
+			audio, err := webrtc.NewTrackLocalStaticSample(
+				webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+				"audio",
+				"broadcast",
+			)
+			require.NoError(t, err)
+
+			client, err := whip.Publish(ctx, endpoint, testKey, audio)
+			if tt.wantStatus != 0 {
+				var status *whip.StatusError
+				require.ErrorAs(t, err, &status)
+				require.Equal(t, tt.wantStatus, status.Status)
+				return
+			}
+			require.NoError(t, err)
+			defer func() {
+				_ = client.Close(context.Background())
+			}()
+
+			select {
+			case <-client.Connected():
+			case <-ctx.Done():
+				t.Fatal("client did not connect")
+			}
+
+			// the next sample completes the one before, so they are sent until one arrives
+			stop := make(chan struct{})
+			defer close(stop)
+			go func() {
+				ticker := time.NewTicker(20 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+						_ = audio.WriteSample(pionmedia.Sample{Data: []byte{0xfc, 'x'}, Duration: 20 * time.Millisecond})
+					}
+				}
+			}()
+
+			select {
+			case got := <-sink.packets:
+				require.Equal(t, media.CodecOpus, got.Codec)
+				require.Equal(t, []byte{0xfc, 'x'}, got.Data)
+			case <-ctx.Done():
+				t.Fatal("packet did not arrive")
+			}
+
+			sink.mu.Lock()
+			require.Len(t, sink.streams, 1)
+			require.Equal(t, channel.ID, sink.streams[0].ChannelId)
+			require.Equal(t, "whip", sink.streams[0].Protocol)
+			sink.mu.Unlock()
+			require.True(t, broadcasts.isLive())
+
+			// an RTMP broadcast of the same channel is refused while the first is live
+			addr := startServer(t, s)
+			second, err := rtmp.Dial(ctx, "rtmp://"+addr+"/live")
+			require.NoError(t, err)
+			defer func() {
+				_ = second.Close()
+			}()
+			var status *rtmp.StatusError
+			require.ErrorAs(t, second.Publish(ctx, testKey), &status)
+			require.Equal(t, "NetStream.Publish.BadName", status.Code)
+
+			// a kick ends the session
+			require.True(t, s.Kick(channel.ID))
+			select {
+			case <-sink.closed:
+			case <-ctx.Done():
+				t.Fatal("writer was not closed")
+			}
+			require.Eventually(t, func() bool {
+				return !s.Kick(channel.ID) && !broadcasts.isLive()
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestService_KeepAlive(t *testing.T) {
+	tests := []struct {
+		name     string
+		touchErr error
+		wantStop bool
+	}{
+		{
+			name: "good case",
+		},
+		{
+			name:     "suspended owner case",
+			touchErr: errs.ErrUserSuspended,
+			wantStop: true,
+		},
+		{
+			name:     "key reset case",
+			touchErr: errs.ErrStreamKeyInvalid,
+			wantStop: true,
+		},
+		{
+			name:     "database error case",
+			touchErr: errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channel := entities.Channel{ID: uuid.New(), Login: "streamer"}
+			broadcasts := newTestBroadcasts(channel)
+			broadcasts.touchErr = tt.touchErr
+
+			s := New(broadcasts, NewMockKickSubscriber(t), newTestSink(), config.IngestConfig{
+				App:            "live",
+				UpdateInterval: 10 * time.Millisecond,
+			})
+
+			stopped := make(chan struct{}, 1)
+			session, err := s.Start(context.Background(), testKey, consts.IngestProtocolRTMP, func() {
+				stopped <- struct{}{}
+			})
+			require.NoError(t, err)
+			defer func() {
+				_ = session.Close()
+			}()
+
+			select {
+			case <-stopped:
+				require.True(t, tt.wantStop, "broadcast was stopped")
+			case <-time.After(200 * time.Millisecond):
+				require.False(t, tt.wantStop, "broadcast was not stopped")
+			}
+
+			require.NoError(t, session.Close())
+			require.False(t, broadcasts.isLive())
+		})
+	}
+}
+
+func startWHIPServer(t *testing.T, s *Service) string {
+	t.Helper()
+
+	server, err := whip.NewServer(s.WHIP(), 0, nil, 5*time.Second)
+	require.NoError(t, err)
+	srv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		srv.Close()
+		_ = server.Shutdown(context.Background())
+	})
+
+	return srv.URL + whip.Path
+}
+
+func startServer(t *testing.T, handler rtmp.Handler) string {
+	t.Helper()
+
+	server := rtmp.NewServer(handler, 4096, 5*time.Second)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(context.Background(), l)
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	return l.Addr().String()
+}
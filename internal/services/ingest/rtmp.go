@@ -0,0 +1,64 @@
+package ingest_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/flv"
+	"github.com/AlexMickh/twitch-clone/internal/lib/rtmp"
+)
+
+// Publish makes the service an rtmp.Handler. Broadcasters publish under their stream key.
+func (s *Service) Publish(ctx context.Context, conn *rtmp.Conn, app, name string) (rtmp.Stream, error) {
+	const op = "services.ingest.Publish"
+
+	if app != s.cfg.App {
+		return nil, fmt.Errorf("%s: unknown app %q: %w", op, app, rtmp.ErrDenied)
+	}
+
+	// some clients append query parameters to the name
+	key, _, _ := strings.Cut(name, "?")
+	session, err := s.Start(ctx, key, consts.IngestProtocolRTMP, func() {
+		_ = conn.Close()
+	})
+	switch {
+	case errors.Is(err, errs.ErrStreamKeyInvalid), errors.Is(err, errs.ErrUserSuspended):
+		return nil, fmt.Errorf("%s: %w: %w", op, rtmp.ErrDenied, err)
+	case errors.Is(err, errs.ErrAlreadyLive):
+		return nil, fmt.Errorf("%s: %w: %w", op, rtmp.ErrBadName, err)
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &rtmpStream{
+		session: session,
+	}, nil
+}
+
+type rtmpStream struct {
+	session *Session
+}
+
+// WriteTag passes audio and video on and drops script data such as onMetaData. Codecs other
+// than H.264 and AAC end the broadcast.
+func (s *rtmpStream) WriteTag(tag flv.Tag) error {
+	const op = "services.ingest.rtmpStream.WriteTag"
+
+	packet, ok, err := flv.ParseTag(tag)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	return s.session.WritePacket(packet)
+}
+
+func (s *rtmpStream) Close() error {
+	return s.session.Close()
+}
@@ -0,0 +1,42 @@
+package ingest_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/whip"
+)
+
+// WHIP is the whip.Handler of the service. Browsers send the stream key as the bearer token.
+func (s *Service) WHIP() whip.Handler {
+	return whipHandler{
+		service: s,
+	}
+}
+
+type whipHandler struct {
+	service *Service
+}
+
+func (h whipHandler) Publish(ctx context.Context, session *whip.Session, token string) (whip.Stream, error) {
+	const op = "services.ingest.whipHandler.Publish"
+
+	stream, err := h.service.Start(ctx, token, consts.IngestProtocolWHIP, func() {
+		_ = session.Close()
+	})
+	switch {
+	case errors.Is(err, errs.ErrStreamKeyInvalid):
+		return nil, fmt.Errorf("%s: %w: %w", op, whip.ErrUnauthorized, err)
+	case errors.Is(err, errs.ErrUserSuspended):
+		return nil, fmt.Errorf("%s: %w: %w", op, whip.ErrForbidden, err)
+	case errors.Is(err, errs.ErrAlreadyLive):
+		return nil, fmt.Errorf("%s: %w: %w", op, whip.ErrConflict, err)
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stream, nil
+}
@@ -0,0 +1,133 @@
+package invite_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	SaveInvite(ctx context.Context, invite entities.Invite) error
+	InvitesByCreator(ctx context.Context, userId uuid.UUID) ([]entities.Invite, error)
+	CountInvitesByCreator(ctx context.Context, userId uuid.UUID) (int64, error)
+	ConsumeInvite(ctx context.Context, code string, now time.Time) error
+	ReleaseInvite(ctx context.Context, code string) error
+	SaveRedemption(ctx context.Context, code string, redemption entities.InviteRedemption) error
+}
+
+type Service struct {
+	repository Repository
+	quota      int64
+}
+
+// New creates the service. quota is the number of codes a regular user may create, admins are not limited.
+func New(repository Repository, quota int64) *Service {
+	return &Service{
+		repository: repository,
+		quota:      quota,
+	}
+}
+
+func (s *Service) CreateInvite(
+	ctx context.Context,
+	userId uuid.UUID,
+	role string,
+	req dtos.CreateInviteRequest,
+) (entities.Invite, error) {
+	const op = "services.invite.CreateInvite"
+
+	if role != consts.RoleAdmin {
+		count, err := s.repository.CountInvitesByCreator(ctx, userId)
+		if err != nil {
+			return entities.Invite{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if count >= s.quota {
+			return entities.Invite{}, fmt.Errorf("%s: %w", op, errs.ErrInviteQuota)
+		}
+	}
+
+	code, err := genCode()
+	if err != nil {
+		return entities.Invite{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invite := entities.Invite{
+		Code:        code,
+		CreatedBy:   userId,
+		MaxUses:     req.MaxUses,
+		Redemptions: []entities.InviteRedemption{},
+		ExpiresAt:   req.ExpiresAt,
+		CreatedAt:   time.Now(),
+	}
+
+	err = s.repository.SaveInvite(ctx, invite)
+	if err != nil {
+		return entities.Invite{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invite, nil
+}
+
+func (s *Service) InvitesByCreator(ctx context.Context, userId uuid.UUID) ([]entities.Invite, error) {
+	const op = "services.invite.InvitesByCreator"
+
+	invites, err := s.repository.InvitesByCreator(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invites, nil
+}
+
+func (s *Service) ConsumeInvite(ctx context.Context, code string) error {
+	const op = "services.invite.ConsumeInvite"
+
+	err := s.repository.ConsumeInvite(ctx, code, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ReleaseInvite(ctx context.Context, code string) error {
+	const op = "services.invite.ReleaseInvite"
+
+	err := s.repository.ReleaseInvite(ctx, code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) RecordRedemption(ctx context.Context, code string, userId uuid.UUID) error {
+	const op = "services.invite.RecordRedemption"
+
+	err := s.repository.SaveRedemption(ctx, code, entities.InviteRedemption{
+		UserId:     userId,
+		RedeemedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func genCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
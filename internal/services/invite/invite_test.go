@@ -0,0 +1,84 @@
+package invite_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateInvite(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		count    int64
+		wantSave bool
+		wantErr  error
+	}{
+		{
+			name:     "user under quota case",
+			role:     consts.RoleUser,
+			count:    2,
+			wantSave: true,
+		},
+		{
+			name:    "user over quota case",
+			role:    consts.RoleUser,
+			count:   3,
+			wantErr: errs.ErrInviteQuota,
+		},
+		{
+			name:     "admin without quota case",
+			role:     consts.RoleAdmin,
+			count:    100,
+			wantSave: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+			m.EXPECT().CountInvitesByCreator(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(tt.count, nil).Maybe()
+			if tt.wantSave {
+				m.EXPECT().SaveInvite(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.Invite"),
+				).Return(nil).Once()
+			}
+
+			s := New(m, 3)
+			invite, err := s.CreateInvite(context.Background(), uuid.New(), tt.role, dtos.CreateInviteRequest{
+				MaxUses:   5,
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.NotEmpty(t, invite.Code)
+				require.Equal(t, 5, invite.MaxUses)
+			}
+		})
+	}
+}
+
+func TestService_ConsumeInvite(t *testing.T) {
+	m := NewMockRepository(t)
+	m.EXPECT().ConsumeInvite(
+		mock.AnythingOfType("context.backgroundCtx"),
+		"CODE",
+		mock.AnythingOfType("time.Time"),
+	).Return(errs.ErrInviteInvalid).Once()
+
+	err := New(m, 3).ConsumeInvite(context.Background(), "CODE")
+	require.ErrorIs(t, err, errs.ErrInviteInvalid)
+}
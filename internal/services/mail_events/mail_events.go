@@ -0,0 +1,89 @@
+package mail_events_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/mailevents"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	SuppressEmail(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error)
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type Service struct {
+	userService UserService
+	auditor     Auditor
+}
+
+func New(userService UserService, auditor Auditor) *Service {
+	return &Service{
+		userService: userService,
+		auditor:     auditor,
+	}
+}
+
+// HandleEvents parses a provider webhook and suppresses the addresses that hard-bounced
+// or complained. Soft bounces are left to the mail queue retries.
+// It returns how many events were received and how many addresses got suppressed.
+func (s *Service) HandleEvents(ctx context.Context, provider string, body []byte) (int, int, error) {
+	const op = "services.mail_events.HandleEvents"
+	log := logger.FromCtx(ctx).With(slog.String("op", op), slog.String("provider", provider))
+
+	events, err := mailevents.Parse(provider, body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	suppressed := 0
+	for _, event := range events {
+		if event.Type == consts.MailEventSoftBounce {
+			log.Info("soft bounce", slog.String("reason", event.Reason))
+			continue
+		}
+
+		occurredAt := event.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = time.Now()
+		}
+		userId, err := s.userService.SuppressEmail(ctx, strings.TrimSpace(event.Email), entities.EmailSuppression{
+			Type:   event.Type,
+			Reason: event.Reason,
+			At:     occurredAt.UTC(),
+		})
+		if err != nil {
+			// mail also goes to addresses without an account, e.g. sign up attempts
+			if errors.Is(err, errs.ErrUserNotFound) {
+				log.Info("mail event for an unknown address", slog.String("type", event.Type))
+				continue
+			}
+			return len(events), suppressed, fmt.Errorf("%s: %w", op, err)
+		}
+		suppressed++
+
+		s.auditor.Record(ctx, entities.AuditEvent{
+			Type:   consts.AuditEventEmailSuppressed,
+			UserId: userId,
+			Reason: event.Type,
+			Metadata: map[string]string{
+				"provider": provider,
+				"reason":   event.Reason,
+			},
+		})
+	}
+
+	return len(events), suppressed, nil
+}
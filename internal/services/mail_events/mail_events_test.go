@@ -0,0 +1,110 @@
+package mail_events_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/mailevents"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_HandleEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		provider       string
+		eventType      string
+		wantSuppress   bool
+		wantUserErr    error
+		wantSuppressed int
+		wantErr        error
+	}{
+		{
+			name:           "hard bounce case",
+			provider:       consts.MailProviderSES,
+			eventType:      consts.MailEventHardBounce,
+			wantSuppress:   true,
+			wantSuppressed: 1,
+		},
+		{
+			name:           "complaint case",
+			provider:       consts.MailProviderSendGrid,
+			eventType:      consts.MailEventComplaint,
+			wantSuppress:   true,
+			wantSuppressed: 1,
+		},
+		{
+			name:      "soft bounce case",
+			provider:  consts.MailProviderMailgun,
+			eventType: consts.MailEventSoftBounce,
+		},
+		{
+			name:         "unknown address case",
+			provider:     consts.MailProviderGeneric,
+			eventType:    consts.MailEventHardBounce,
+			wantSuppress: true,
+			wantUserErr:  errs.ErrUserNotFound,
+		},
+		{
+			name:         "user service error case",
+			provider:     consts.MailProviderGeneric,
+			eventType:    consts.MailEventComplaint,
+			wantSuppress: true,
+			wantUserErr:  errors.New("some error"),
+			wantErr:      errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			if tt.wantSuppress {
+				mUserService.EXPECT().SuppressEmail(
+					mock.AnythingOfType("context.backgroundCtx"),
+					"user@test.com",
+					mock.MatchedBy(func(s entities.EmailSuppression) bool {
+						return s.Type == tt.eventType && !s.At.IsZero()
+					}),
+				).Return(uuid.New(), tt.wantUserErr).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantSuppressed > 0 {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.AuditEvent"),
+				).Return().Once()
+			}
+
+			body, err := mailevents.Sample(tt.provider, tt.eventType, "user@test.com")
+			require.NoError(t, err)
+
+			s := New(mUserService, mAuditor)
+			received, suppressed, err := s.HandleEvents(context.Background(), tt.provider, body)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, received)
+			require.Equal(t, tt.wantSuppressed, suppressed)
+		})
+	}
+}
+
+func TestService_HandleEvents_Invalid(t *testing.T) {
+	s := New(NewMockUserService(t), NewMockAuditor(t))
+
+	_, _, err := s.HandleEvents(context.Background(), "postmark", []byte(`{}`))
+	require.ErrorIs(t, err, errs.ErrMailProvider)
+
+	_, _, err = s.HandleEvents(context.Background(), consts.MailProviderGeneric, []byte(`{"events": [{}]}`))
+	require.ErrorIs(t, err, errs.ErrMailEventsInvalid)
+}
@@ -0,0 +1,196 @@
+package mail_queue_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// enqueueTimeout bounds Send, the email senders are called without a context.
+const enqueueTimeout = 5 * time.Second
+
+type Repository interface {
+	Enqueue(ctx context.Context, job entities.MailJob) error
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (entities.MailJob, error)
+	Complete(ctx context.Context, id uuid.UUID) error
+	Retry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastErr string) error
+	MoveToDeadLetter(ctx context.Context, job entities.MailJob) error
+	Stats(ctx context.Context) (entities.MailQueueStats, error)
+}
+
+type Transport interface {
+	Send(msg email.Message) error
+}
+
+// Service is a durable outbox in front of the real mail transport. It is an email.Transport
+// itself, so Send only stores the message and the workers started with Start deliver it.
+type Service struct {
+	repository Repository
+	transport  Transport
+	cfg        config.MailQueueConfig
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func New(repository Repository, transport Transport, cfg config.MailQueueConfig) *Service {
+	return &Service{
+		repository: repository,
+		transport:  transport,
+		cfg:        cfg,
+	}
+}
+
+func (s *Service) Send(msg email.Message) error {
+	const op = "services.mail_queue.Send"
+
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+
+	now := time.Now()
+	err := s.repository.Enqueue(ctx, entities.MailJob{
+		ID:              uuid.New(),
+		MessageId:       msg.ID,
+		Date:            msg.Date,
+		From:            msg.From,
+		FromName:        msg.FromName,
+		To:              msg.To,
+		Subject:         msg.Subject,
+		Text:            msg.Text,
+		HTML:            msg.HTML,
+		ListUnsubscribe: msg.ListUnsubscribe,
+		NextAttemptAt:   now,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) Stats(ctx context.Context) (entities.MailQueueStats, error) {
+	const op = "services.mail_queue.Stats"
+
+	stats, err := s.repository.Stats(ctx)
+	if err != nil {
+		return entities.MailQueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// Start runs the worker pool until Stop is called.
+func (s *Service) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for range s.cfg.Workers {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.work(ctx)
+		}()
+	}
+}
+
+// Stop waits for the workers to finish the messages they are sending.
+func (s *Service) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Service) work(ctx context.Context) {
+	const op = "services.mail_queue.work"
+	log := logger.FromCtx(ctx).With(slog.String("op", op))
+
+	for {
+		job, err := s.repository.Claim(ctx, time.Now(), s.cfg.Lease)
+		if err == nil {
+			s.process(context.WithoutCancel(ctx), job)
+			continue
+		}
+		if !errors.Is(err, errs.ErrMailQueueEmpty) && ctx.Err() == nil {
+			log.Error("failed to claim mail job", logger.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+// process delivers the job and then completes, retries or dead-letters it.
+// Rejections the server reports as permanent are not retried.
+func (s *Service) process(ctx context.Context, job entities.MailJob) {
+	const op = "services.mail_queue.process"
+	log := logger.FromCtx(ctx).With(slog.String("op", op), slog.String("job_id", job.ID.String()))
+
+	sendErr := s.transport.Send(email.Message{
+		ID:              job.MessageId,
+		Date:            job.Date,
+		From:            job.From,
+		FromName:        job.FromName,
+		To:              job.To,
+		Subject:         job.Subject,
+		Text:            job.Text,
+		HTML:            job.HTML,
+		ListUnsubscribe: job.ListUnsubscribe,
+	})
+	if sendErr == nil {
+		if err := s.repository.Complete(ctx, job.ID); err != nil {
+			log.Error("failed to complete mail job", logger.Err(err))
+		}
+		return
+	}
+
+	attempts := job.Attempts + 1
+	if isPermanent(sendErr) || attempts >= s.cfg.MaxAttempts {
+		now := time.Now()
+		job.Attempts = attempts
+		job.LastError = sendErr.Error()
+		job.FailedAt = &now
+		if err := s.repository.MoveToDeadLetter(ctx, job); err != nil {
+			log.Error("failed to dead-letter mail job", logger.Err(err))
+			return
+		}
+		log.Error("mail job dead-lettered", slog.Int("attempts", attempts), logger.Err(sendErr))
+		return
+	}
+
+	err := s.repository.Retry(ctx, job.ID, time.Now().Add(s.backoff(attempts)), sendErr.Error())
+	if err != nil {
+		log.Error("failed to reschedule mail job", logger.Err(err))
+		return
+	}
+	log.Warn("mail job failed, will retry", slog.Int("attempts", attempts), logger.Err(sendErr))
+}
+
+// backoff doubles the delay from BaseBackoff with every failed attempt, up to MaxBackoff.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.cfg.MaxBackoff)
+}
+
+// isPermanent reports SMTP 5xx replies, such as an unknown recipient.
+func isPermanent(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
@@ -0,0 +1,149 @@
+package mail_queue_service
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/email"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.MailQueueConfig{
+	Workers:      1,
+	PollInterval: 10 * time.Millisecond,
+	Lease:        time.Minute,
+	MaxAttempts:  3,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   2 * time.Minute,
+}
+
+func TestService_Send(t *testing.T) {
+	m := NewMockRepository(t)
+	m.EXPECT().Enqueue(
+		mock.AnythingOfType("*context.timerCtx"),
+		mock.MatchedBy(func(job entities.MailJob) bool {
+			return job.To == "user@test.com" && job.Attempts == 0 && !job.NextAttemptAt.IsZero()
+		}),
+	).Return(nil).Once()
+
+	s := New(m, NewMockTransport(t), testCfg)
+	err := s.Send(email.Message{To: "user@test.com", Subject: "subject"})
+	require.NoError(t, err)
+}
+
+func TestService_process(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempts    int
+		sendErr     error
+		wantRetry   bool
+		wantDead    bool
+		wantBackoff time.Duration
+	}{
+		{
+			name: "good case",
+		},
+		{
+			name:        "temporary error case",
+			attempts:    1,
+			sendErr:     &textproto.Error{Code: 421, Msg: "try again later"},
+			wantRetry:   true,
+			wantBackoff: time.Minute,
+		},
+		{
+			name:     "permanent error case",
+			sendErr:  &textproto.Error{Code: 550, Msg: "no such user"},
+			wantDead: true,
+		},
+		{
+			name:     "max attempts case",
+			attempts: 2,
+			sendErr:  errors.New("connection refused"),
+			wantDead: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := entities.MailJob{
+				ID:       uuid.New(),
+				To:       "user@test.com",
+				Attempts: tt.attempts,
+			}
+
+			mTransport := NewMockTransport(t)
+			mTransport.EXPECT().Send(mock.AnythingOfType("email.Message")).Return(tt.sendErr).Once()
+
+			mRepo := NewMockRepository(t)
+			switch {
+			case tt.wantRetry:
+				mRepo.EXPECT().Retry(
+					mock.AnythingOfType("context.backgroundCtx"),
+					job.ID,
+					mock.MatchedBy(func(at time.Time) bool {
+						return time.Until(at).Round(time.Second) == tt.wantBackoff
+					}),
+					tt.sendErr.Error(),
+				).Return(nil).Once()
+			case tt.wantDead:
+				mRepo.EXPECT().MoveToDeadLetter(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.MatchedBy(func(dead entities.MailJob) bool {
+						return dead.ID == job.ID && dead.Attempts == tt.attempts+1 && dead.FailedAt != nil
+					}),
+				).Return(nil).Once()
+			default:
+				mRepo.EXPECT().Complete(mock.AnythingOfType("context.backgroundCtx"), job.ID).Return(nil).Once()
+			}
+
+			s := New(mRepo, mTransport, testCfg)
+			s.process(context.Background(), job)
+		})
+	}
+}
+
+func TestService_backoff(t *testing.T) {
+	s := New(nil, nil, testCfg)
+
+	require.Equal(t, 30*time.Second, s.backoff(1))
+	require.Equal(t, time.Minute, s.backoff(2))
+	require.Equal(t, 2*time.Minute, s.backoff(3))
+	require.Equal(t, 2*time.Minute, s.backoff(10))
+}
+
+func TestService_StartStop(t *testing.T) {
+	job := entities.MailJob{ID: uuid.New(), To: "user@test.com"}
+	sent := make(chan struct{})
+
+	mRepo := NewMockRepository(t)
+	mRepo.EXPECT().Claim(mock.Anything, mock.AnythingOfType("time.Time"), testCfg.Lease).
+		Return(job, nil).Once()
+	mRepo.EXPECT().Claim(mock.Anything, mock.AnythingOfType("time.Time"), testCfg.Lease).
+		Return(entities.MailJob{}, errs.ErrMailQueueEmpty).Maybe()
+	mRepo.EXPECT().Complete(mock.Anything, job.ID).Return(nil).Once()
+
+	mTransport := NewMockTransport(t)
+	mTransport.EXPECT().Send(mock.AnythingOfType("email.Message")).
+		Run(func(email.Message) { close(sent) }).
+		Return(nil).Once()
+
+	s := New(mRepo, mTransport, testCfg)
+	s.Start(context.Background())
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("mail job was not sent")
+	}
+	s.Stop()
+}
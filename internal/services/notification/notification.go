@@ -0,0 +1,142 @@
+package notification_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+	UserByEmail(ctx context.Context, email string) (entities.User, error)
+	SetNotificationPreferences(ctx context.Context, id uuid.UUID, preferences map[string]bool) error
+}
+
+type TokenParser interface {
+	Parse(token string) (string, string, error)
+}
+
+type Service struct {
+	userService UserService
+	tokenParser TokenParser
+}
+
+func New(userService UserService, tokenParser TokenParser) *Service {
+	return &Service{
+		userService: userService,
+		tokenParser: tokenParser,
+	}
+}
+
+// AcceptsEmail reports whether mail of the category may be sent to the address.
+// Addresses without an account get only what they asked for, e.g. sign up mail,
+// suppressed addresses get only security mail.
+func (s *Service) AcceptsEmail(ctx context.Context, email, category string) (bool, error) {
+	const op = "services.notification.AcceptsEmail"
+
+	user, err := s.userService.UserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return true, nil
+		}
+		if errors.Is(err, errs.ErrUserEmailNotVerify) {
+			return category == consts.EmailCategorySecurity, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.EmailSuppression != nil {
+		return category == consts.EmailCategorySecurity, nil
+	}
+
+	return user.WantsEmail(category), nil
+}
+
+func (s *Service) Preferences(ctx context.Context, userId uuid.UUID) (dtos.NotificationPreferencesResponse, error) {
+	const op = "services.notification.Preferences"
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return dtos.NotificationPreferencesResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return toResponse(user), nil
+}
+
+func (s *Service) UpdatePreferences(
+	ctx context.Context,
+	userId uuid.UUID,
+	req dtos.UpdateNotificationPreferencesRequest,
+) (dtos.NotificationPreferencesResponse, error) {
+	const op = "services.notification.UpdatePreferences"
+
+	preferences := make(map[string]bool, 3)
+	if req.Marketing != nil {
+		preferences[consts.EmailCategoryMarketing] = *req.Marketing
+	}
+	if req.GoLive != nil {
+		preferences[consts.EmailCategoryGoLive] = *req.GoLive
+	}
+	if req.Digest != nil {
+		preferences[consts.EmailCategoryDigest] = *req.Digest
+	}
+
+	err := s.userService.SetNotificationPreferences(ctx, userId, preferences)
+	if err != nil {
+		return dtos.NotificationPreferencesResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return dtos.NotificationPreferencesResponse{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return toResponse(user), nil
+}
+
+// Unsubscribe turns off the category signed into a List-Unsubscribe token.
+// Tokens of addresses that no longer have an account are accepted and ignored.
+func (s *Service) Unsubscribe(ctx context.Context, token string) error {
+	const op = "services.notification.Unsubscribe"
+
+	email, category, err := s.tokenParser.Parse(token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, errs.ErrUnsubscribeToken)
+	}
+	if category == consts.EmailCategorySecurity {
+		return fmt.Errorf("%s: %w", op, errs.ErrUnsubscribeToken)
+	}
+	if _, ok := entities.DefaultNotificationPreferences[category]; !ok {
+		return fmt.Errorf("%s: %w", op, errs.ErrUnsubscribeToken)
+	}
+
+	user, err := s.userService.UserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrUserEmailNotVerify) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.userService.SetNotificationPreferences(ctx, user.ID, map[string]bool{category: false})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func toResponse(user entities.User) dtos.NotificationPreferencesResponse {
+	return dtos.NotificationPreferencesResponse{
+		Security:  user.WantsEmail(consts.EmailCategorySecurity),
+		Marketing: user.WantsEmail(consts.EmailCategoryMarketing),
+		GoLive:    user.WantsEmail(consts.EmailCategoryGoLive),
+		Digest:    user.WantsEmail(consts.EmailCategoryDigest),
+	}
+}
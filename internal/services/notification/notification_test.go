@@ -0,0 +1,213 @@
+package notification_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_AcceptsEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		category    string
+		user        entities.User
+		wantUserErr error
+		want        bool
+		wantErr     error
+	}{
+		{
+			name:     "default go live case",
+			category: consts.EmailCategoryGoLive,
+			want:     true,
+		},
+		{
+			name:     "default marketing case",
+			category: consts.EmailCategoryMarketing,
+			want:     false,
+		},
+		{
+			name:     "turned off case",
+			category: consts.EmailCategoryDigest,
+			user: entities.User{NotificationPreferences: map[string]bool{
+				consts.EmailCategoryDigest: false,
+			}},
+			want: false,
+		},
+		{
+			name:     "security can't be turned off case",
+			category: consts.EmailCategorySecurity,
+			user: entities.User{NotificationPreferences: map[string]bool{
+				consts.EmailCategorySecurity: false,
+			}},
+			want: true,
+		},
+		{
+			name:     "suppressed case",
+			category: consts.EmailCategoryGoLive,
+			user:     entities.User{EmailSuppression: &entities.EmailSuppression{Type: consts.MailEventComplaint}},
+			want:     false,
+		},
+		{
+			name:        "unknown address case",
+			category:    consts.EmailCategoryMarketing,
+			wantUserErr: errs.ErrUserNotFound,
+			want:        true,
+		},
+		{
+			name:        "email not verified case",
+			category:    consts.EmailCategoryDigest,
+			wantUserErr: errs.ErrUserEmailNotVerify,
+			want:        false,
+		},
+		{
+			name:        "user service error case",
+			category:    consts.EmailCategoryDigest,
+			wantUserErr: errors.New("some error"),
+			wantErr:     errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mUserService := NewMockUserService(t)
+			mUserService.EXPECT().UserByEmail(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"user@test.com",
+			).Return(tt.user, tt.wantUserErr).Once()
+
+			s := New(mUserService, NewMockTokenParser(t))
+
+			got, err := s.AcceptsEmail(context.Background(), "user@test.com", tt.category)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_UpdatePreferences(t *testing.T) {
+	userId := uuid.New()
+	off := false
+
+	mUserService := NewMockUserService(t)
+	mUserService.EXPECT().SetNotificationPreferences(
+		mock.AnythingOfType("context.backgroundCtx"),
+		userId,
+		map[string]bool{consts.EmailCategoryGoLive: false},
+	).Return(nil).Once()
+	mUserService.EXPECT().UserById(
+		mock.AnythingOfType("context.backgroundCtx"),
+		userId,
+	).Return(entities.User{NotificationPreferences: map[string]bool{
+		consts.EmailCategoryGoLive: false,
+	}}, nil).Once()
+
+	s := New(mUserService, NewMockTokenParser(t))
+
+	got, err := s.UpdatePreferences(context.Background(), userId, dtos.UpdateNotificationPreferencesRequest{GoLive: &off})
+	require.NoError(t, err)
+	require.Equal(t, dtos.NotificationPreferencesResponse{
+		Security:  true,
+		Marketing: false,
+		GoLive:    false,
+		Digest:    true,
+	}, got)
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		category    string
+		parseErr    error
+		wantLookup  bool
+		wantUserErr error
+		wantSet     bool
+		wantErr     error
+	}{
+		{
+			name:       "good case",
+			category:   consts.EmailCategoryMarketing,
+			wantLookup: true,
+			wantSet:    true,
+		},
+		{
+			name:     "invalid token case",
+			parseErr: errs.ErrUnsubscribeToken,
+			wantErr:  errs.ErrUnsubscribeToken,
+		},
+		{
+			name:     "security category case",
+			category: consts.EmailCategorySecurity,
+			wantErr:  errs.ErrUnsubscribeToken,
+		},
+		{
+			name:     "unknown category case",
+			category: "weekly",
+			wantErr:  errs.ErrUnsubscribeToken,
+		},
+		{
+			name:        "deleted account case",
+			category:    consts.EmailCategoryDigest,
+			wantLookup:  true,
+			wantUserErr: errs.ErrUserNotFound,
+		},
+		{
+			name:        "user service error case",
+			category:    consts.EmailCategoryDigest,
+			wantLookup:  true,
+			wantUserErr: errors.New("some error"),
+			wantErr:     errors.New("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mTokenParser := NewMockTokenParser(t)
+			mTokenParser.EXPECT().Parse("token").Return("user@test.com", tt.category, tt.parseErr).Once()
+
+			mUserService := NewMockUserService(t)
+			if tt.wantLookup {
+				mUserService.EXPECT().UserByEmail(
+					mock.AnythingOfType("context.backgroundCtx"),
+					"user@test.com",
+				).Return(entities.User{ID: userId}, tt.wantUserErr).Once()
+			}
+			if tt.wantSet {
+				mUserService.EXPECT().SetNotificationPreferences(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					map[string]bool{tt.category: false},
+				).Return(nil).Once()
+			}
+
+			s := New(mUserService, mTokenParser)
+
+			err := s.Unsubscribe(context.Background(), "token")
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, errs.ErrUnsubscribeToken) {
+					require.ErrorIs(t, err, errs.ErrUnsubscribeToken)
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
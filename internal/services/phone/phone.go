@@ -0,0 +1,165 @@
+package phone_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+	SetPhone(ctx context.Context, id uuid.UUID, phone string) error
+}
+
+// Reservations tracks which accounts hold a phone number.
+type Reservations interface {
+	Reserve(ctx context.Context, phone string, userId uuid.UUID, limit int64) error
+	Release(ctx context.Context, phone string, userId uuid.UUID) error
+	CountHolders(ctx context.Context, phone string, exceptId uuid.UUID) (int64, error)
+}
+
+type CodeService interface {
+	CreateCode(ctx context.Context, userId uuid.UUID, recipient string) (string, error)
+	VerifyCode(ctx context.Context, recipient, code string) (uuid.UUID, error)
+}
+
+type Counter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, text string) error
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type Service struct {
+	userService  UserService
+	codeService  CodeService
+	reservations Reservations
+	counter      Counter
+	smsSender    SMSSender
+	auditor      Auditor
+	cfg          config.PhoneConfig
+}
+
+func New(
+	userService UserService,
+	codeService CodeService,
+	reservations Reservations,
+	counter Counter,
+	smsSender SMSSender,
+	auditor Auditor,
+	cfg config.PhoneConfig,
+) *Service {
+	return &Service{
+		userService:  userService,
+		codeService:  codeService,
+		reservations: reservations,
+		counter:      counter,
+		smsSender:    smsSender,
+		auditor:      auditor,
+		cfg:          cfg,
+	}
+}
+
+// SendCode texts a verification code to the phone number. Sends are limited per user
+// and per number, so the endpoint can not be used to flood someone with messages.
+func (s *Service) SendCode(ctx context.Context, userId uuid.UUID, req dtos.SendPhoneCodeRequest) error {
+	const op = "services.phone.SendCode"
+
+	count, err := s.counter.Increment(ctx, "sms_user:"+userId.String()+":count", s.cfg.SendWindow)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count > s.cfg.MaxSendsPerUser {
+		return fmt.Errorf("%s: %w", op, errs.ErrPhoneRateLimited)
+	}
+
+	count, err = s.counter.Increment(ctx, "sms_phone:"+req.Phone+":count", s.cfg.SendWindow)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count > s.cfg.MaxSendsPerNumber {
+		return fmt.Errorf("%s: %w", op, errs.ErrPhoneRateLimited)
+	}
+
+	// an early check only, the limit is enforced when the number is reserved on verification
+	taken, err := s.reservations.CountHolders(ctx, req.Phone, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if taken >= s.cfg.MaxAccountsPerNumber {
+		return fmt.Errorf("%s: %w", op, errs.ErrPhoneTaken)
+	}
+
+	code, err := s.codeService.CreateCode(ctx, userId, codeRecipient(userId, req.Phone))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.smsSender.SendSMS(ctx, req.Phone, fmt.Sprintf("Your verification code is %s", code))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyCode checks the code and stores the number as the user's verified phone.
+// The number is reserved first, so concurrent verifications can not exceed the per-number limit.
+func (s *Service) VerifyCode(ctx context.Context, userId uuid.UUID, req dtos.VerifyPhoneRequest) error {
+	const op = "services.phone.VerifyCode"
+
+	_, err := s.codeService.VerifyCode(ctx, codeRecipient(userId, req.Phone), req.Code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userService.UserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.reservations.Reserve(ctx, req.Phone, userId, s.cfg.MaxAccountsPerNumber)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.userService.SetPhone(ctx, userId, req.Phone)
+	if err != nil {
+		if user.Phone != req.Phone {
+			_ = s.reservations.Release(ctx, req.Phone, userId)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Phone != "" && user.Phone != req.Phone {
+		err = s.reservations.Release(ctx, user.Phone, userId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventPhoneVerified,
+		UserId: userId,
+	})
+
+	return nil
+}
+
+// codeRecipient scopes codes to the user, so requests for the same number
+// from different accounts do not overwrite each other.
+func codeRecipient(userId uuid.UUID, phone string) string {
+	return userId.String() + ":" + phone
+}
@@ -0,0 +1,214 @@
+package phone_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/dtos"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/sms"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.PhoneConfig{
+	MaxSendsPerNumber:    3,
+	MaxSendsPerUser:      5,
+	SendWindow:           time.Hour,
+	MaxAccountsPerNumber: 1,
+}
+
+const testPhone = "+15550001234"
+
+func TestService_SendCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		userCount  int64
+		phoneCount int64
+		takenCount int64
+		wantCreate bool
+		wantErr    error
+	}{
+		{
+			name:       "good case",
+			userCount:  1,
+			phoneCount: 1,
+			wantCreate: true,
+			wantErr:    nil,
+		},
+		{
+			name:      "user rate limited case",
+			userCount: 6,
+			wantErr:   errs.ErrPhoneRateLimited,
+		},
+		{
+			name:       "phone rate limited case",
+			userCount:  1,
+			phoneCount: 4,
+			wantErr:    errs.ErrPhoneRateLimited,
+		},
+		{
+			name:       "phone taken case",
+			userCount:  1,
+			phoneCount: 1,
+			takenCount: 1,
+			wantErr:    errs.ErrPhoneTaken,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mCounter := NewMockCounter(t)
+			mCounter.EXPECT().Increment(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"sms_user:"+userId.String()+":count",
+				testCfg.SendWindow,
+			).Return(tt.userCount, nil).Once()
+			mCounter.EXPECT().Increment(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"sms_phone:"+testPhone+":count",
+				testCfg.SendWindow,
+			).Return(tt.phoneCount, nil).Maybe()
+
+			mReservations := NewMockReservations(t)
+			mReservations.EXPECT().CountHolders(
+				mock.AnythingOfType("context.backgroundCtx"),
+				testPhone,
+				userId,
+			).Return(tt.takenCount, nil).Maybe()
+
+			mCodes := NewMockCodeService(t)
+			if tt.wantCreate {
+				mCodes.EXPECT().CreateCode(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					userId.String()+":"+testPhone,
+				).Return("123456", nil).Once()
+			}
+
+			sender := sms.NewMemory()
+			s := New(NewMockUserService(t), mCodes, mReservations, mCounter, sender, NewMockAuditor(t), testCfg)
+			err := s.SendCode(context.Background(), userId, dtos.SendPhoneCodeRequest{Phone: testPhone})
+			require.ErrorIs(t, err, tt.wantErr)
+
+			msg, ok := sender.Last(testPhone)
+			require.Equal(t, tt.wantCreate, ok)
+			if tt.wantCreate {
+				require.Contains(t, msg.Text, "123456")
+			}
+		})
+	}
+}
+
+func TestService_VerifyCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldPhone    string
+		codeErr     error
+		reserveErr  error
+		setErr      error
+		wantSet     bool
+		wantRelease string
+		wantErr     error
+	}{
+		{
+			name:    "good case",
+			wantSet: true,
+			wantErr: nil,
+		},
+		{
+			name:        "number changed case",
+			oldPhone:    "+15550009999",
+			wantSet:     true,
+			wantRelease: "+15550009999",
+			wantErr:     nil,
+		},
+		{
+			name:     "same number case",
+			oldPhone: testPhone,
+			wantSet:  true,
+			wantErr:  nil,
+		},
+		{
+			name:    "invalid code case",
+			codeErr: errs.ErrCodeInvalid,
+			wantErr: errs.ErrCodeInvalid,
+		},
+		{
+			name:       "phone taken meanwhile case",
+			reserveErr: errs.ErrPhoneTaken,
+			wantErr:    errs.ErrPhoneTaken,
+		},
+		{
+			name:        "set phone error case",
+			setErr:      errs.ErrUserNotFound,
+			wantSet:     true,
+			wantRelease: testPhone,
+			wantErr:     errs.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mCodes := NewMockCodeService(t)
+			mCodes.EXPECT().VerifyCode(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId.String()+":"+testPhone,
+				"123456",
+			).Return(userId, tt.codeErr).Once()
+
+			mUsers := NewMockUserService(t)
+			mUsers.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId, Phone: tt.oldPhone}, nil).Maybe()
+			if tt.wantSet {
+				mUsers.EXPECT().SetPhone(
+					mock.AnythingOfType("context.backgroundCtx"),
+					userId,
+					testPhone,
+				).Return(tt.setErr).Once()
+			}
+
+			mReservations := NewMockReservations(t)
+			mReservations.EXPECT().Reserve(
+				mock.AnythingOfType("context.backgroundCtx"),
+				testPhone,
+				userId,
+				testCfg.MaxAccountsPerNumber,
+			).Return(tt.reserveErr).Maybe()
+			if tt.wantRelease != "" {
+				mReservations.EXPECT().Release(
+					mock.AnythingOfType("context.backgroundCtx"),
+					tt.wantRelease,
+					userId,
+				).Return(nil).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := New(mUsers, mCodes, mReservations, NewMockCounter(t), sms.NewMemory(), mAuditor, testCfg)
+			err := s.VerifyCode(context.Background(), userId, dtos.VerifyPhoneRequest{
+				Phone: testPhone,
+				Code:  "123456",
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
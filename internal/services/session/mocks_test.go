@@ -6,8 +6,10 @@ package session_service
 
 import (
 	"context"
+	"time"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -38,6 +40,258 @@ func (_m *MockRepository) EXPECT() *MockRepository_Expecter {
 	return &MockRepository_Expecter{mock: &_m.Mock}
 }
 
+// DeleteSession provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteSession(ctx context.Context, id uuid.UUID, userId uuid.UUID) error {
+	ret := _mock.Called(ctx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_DeleteSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSession'
+type MockRepository_DeleteSession_Call struct {
+	*mock.Call
+}
+
+// DeleteSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - userId uuid.UUID
+func (_e *MockRepository_Expecter) DeleteSession(ctx interface{}, id interface{}, userId interface{}) *MockRepository_DeleteSession_Call {
+	return &MockRepository_DeleteSession_Call{Call: _e.mock.On("DeleteSession", ctx, id, userId)}
+}
+
+func (_c *MockRepository_DeleteSession_Call) Run(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID)) *MockRepository_DeleteSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_DeleteSession_Call) Return(err error) *MockRepository_DeleteSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_DeleteSession_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID) error) *MockRepository_DeleteSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSessionsByUserId provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteSessionsByUserId(ctx context.Context, userId uuid.UUID) error {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSessionsByUserId")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_DeleteSessionsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSessionsByUserId'
+type MockRepository_DeleteSessionsByUserId_Call struct {
+	*mock.Call
+}
+
+// DeleteSessionsByUserId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+func (_e *MockRepository_Expecter) DeleteSessionsByUserId(ctx interface{}, userId interface{}) *MockRepository_DeleteSessionsByUserId_Call {
+	return &MockRepository_DeleteSessionsByUserId_Call{Call: _e.mock.On("DeleteSessionsByUserId", ctx, userId)}
+}
+
+func (_c *MockRepository_DeleteSessionsByUserId_Call) Run(run func(ctx context.Context, userId uuid.UUID)) *MockRepository_DeleteSessionsByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_DeleteSessionsByUserId_Call) Return(err error) *MockRepository_DeleteSessionsByUserId_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_DeleteSessionsByUserId_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID) error) *MockRepository_DeleteSessionsByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ElevateSession provides a mock function for the type MockRepository
+func (_mock *MockRepository) ElevateSession(ctx context.Context, id uuid.UUID, userId uuid.UUID, until time.Time) error {
+	ret := _mock.Called(ctx, id, userId, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ElevateSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, userId, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_ElevateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ElevateSession'
+type MockRepository_ElevateSession_Call struct {
+	*mock.Call
+}
+
+// ElevateSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - userId uuid.UUID
+//   - until time.Time
+func (_e *MockRepository_Expecter) ElevateSession(ctx interface{}, id interface{}, userId interface{}, until interface{}) *MockRepository_ElevateSession_Call {
+	return &MockRepository_ElevateSession_Call{Call: _e.mock.On("ElevateSession", ctx, id, userId, until)}
+}
+
+func (_c *MockRepository_ElevateSession_Call) Run(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID, until time.Time)) *MockRepository_ElevateSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_ElevateSession_Call) Return(err error) *MockRepository_ElevateSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_ElevateSession_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID, until time.Time) error) *MockRepository_ElevateSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function for the type MockRepository
+func (_mock *MockRepository) RevokeSession(ctx context.Context, id uuid.UUID, userId uuid.UUID) error {
+	ret := _mock.Called(ctx, id, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockRepository_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - userId uuid.UUID
+func (_e *MockRepository_Expecter) RevokeSession(ctx interface{}, id interface{}, userId interface{}) *MockRepository_RevokeSession_Call {
+	return &MockRepository_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, id, userId)}
+}
+
+func (_c *MockRepository_RevokeSession_Call) Run(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID)) *MockRepository_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_RevokeSession_Call) Return(err error) *MockRepository_RevokeSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_RevokeSession_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID) error) *MockRepository_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveSession provides a mock function for the type MockRepository
 func (_mock *MockRepository) SaveSession(ctx context.Context, session entities.Session) error {
 	ret := _mock.Called(ctx, session)
@@ -95,6 +349,69 @@ func (_c *MockRepository_SaveSession_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// SaveSessionTTL provides a mock function for the type MockRepository
+func (_mock *MockRepository) SaveSessionTTL(ctx context.Context, session entities.Session, ttl time.Duration) error {
+	ret := _mock.Called(ctx, session, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSessionTTL")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.Session, time.Duration) error); ok {
+		r0 = returnFunc(ctx, session, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SaveSessionTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSessionTTL'
+type MockRepository_SaveSessionTTL_Call struct {
+	*mock.Call
+}
+
+// SaveSessionTTL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session entities.Session
+//   - ttl time.Duration
+func (_e *MockRepository_Expecter) SaveSessionTTL(ctx interface{}, session interface{}, ttl interface{}) *MockRepository_SaveSessionTTL_Call {
+	return &MockRepository_SaveSessionTTL_Call{Call: _e.mock.On("SaveSessionTTL", ctx, session, ttl)}
+}
+
+func (_c *MockRepository_SaveSessionTTL_Call) Run(run func(ctx context.Context, session entities.Session, ttl time.Duration)) *MockRepository_SaveSessionTTL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.Session
+		if args[1] != nil {
+			arg1 = args[1].(entities.Session)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SaveSessionTTL_Call) Return(err error) *MockRepository_SaveSessionTTL_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SaveSessionTTL_Call) RunAndReturn(run func(ctx context.Context, session entities.Session, ttl time.Duration) error) *MockRepository_SaveSessionTTL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SessionById provides a mock function for the type MockRepository
 func (_mock *MockRepository) SessionById(ctx context.Context, sessionId string) (entities.Session, error) {
 	ret := _mock.Called(ctx, sessionId)
@@ -160,3 +477,213 @@ func (_c *MockRepository_SessionById_Call) RunAndReturn(run func(ctx context.Con
 	_c.Call.Return(run)
 	return _c
 }
+
+// SessionsByUserId provides a mock function for the type MockRepository
+func (_mock *MockRepository) SessionsByUserId(ctx context.Context, userId uuid.UUID) ([]entities.Session, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SessionsByUserId")
+	}
+
+	var r0 []entities.Session
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.Session, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.Session); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Session)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_SessionsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SessionsByUserId'
+type MockRepository_SessionsByUserId_Call struct {
+	*mock.Call
+}
+
+// SessionsByUserId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+func (_e *MockRepository_Expecter) SessionsByUserId(ctx interface{}, userId interface{}) *MockRepository_SessionsByUserId_Call {
+	return &MockRepository_SessionsByUserId_Call{Call: _e.mock.On("SessionsByUserId", ctx, userId)}
+}
+
+func (_c *MockRepository_SessionsByUserId_Call) Run(run func(ctx context.Context, userId uuid.UUID)) *MockRepository_SessionsByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SessionsByUserId_Call) Return(sessions []entities.Session, err error) *MockRepository_SessionsByUserId_Call {
+	_c.Call.Return(sessions, err)
+	return _c
+}
+
+func (_c *MockRepository_SessionsByUserId_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID) ([]entities.Session, error)) *MockRepository_SessionsByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchSession provides a mock function for the type MockRepository
+func (_mock *MockRepository) TouchSession(ctx context.Context, id uuid.UUID, userId uuid.UUID, lastSeen time.Time) error {
+	ret := _mock.Called(ctx, id, userId, lastSeen)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, userId, lastSeen)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_TouchSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchSession'
+type MockRepository_TouchSession_Call struct {
+	*mock.Call
+}
+
+// TouchSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - userId uuid.UUID
+//   - lastSeen time.Time
+func (_e *MockRepository_Expecter) TouchSession(ctx interface{}, id interface{}, userId interface{}, lastSeen interface{}) *MockRepository_TouchSession_Call {
+	return &MockRepository_TouchSession_Call{Call: _e.mock.On("TouchSession", ctx, id, userId, lastSeen)}
+}
+
+func (_c *MockRepository_TouchSession_Call) Run(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID, lastSeen time.Time)) *MockRepository_TouchSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_TouchSession_Call) Return(err error) *MockRepository_TouchSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_TouchSession_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, userId uuid.UUID, lastSeen time.Time) error) *MockRepository_TouchSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditor creates a new instance of MockAuditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditor {
+	mock := &MockAuditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditor is an autogenerated mock type for the Auditor type
+type MockAuditor struct {
+	mock.Mock
+}
+
+type MockAuditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditor) EXPECT() *MockAuditor_Expecter {
+	return &MockAuditor_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) Record(ctx context.Context, event entities.AuditEvent) {
+	_mock.Called(ctx, event)
+	return
+}
+
+// MockAuditor_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditor_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event entities.AuditEvent
+func (_e *MockAuditor_Expecter) Record(ctx interface{}, event interface{}) *MockAuditor_Record_Call {
+	return &MockAuditor_Record_Call{Call: _e.mock.On("Record", ctx, event)}
+}
+
+func (_c *MockAuditor_Record_Call) Run(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.AuditEvent
+		if args[1] != nil {
+			arg1 = args[1].(entities.AuditEvent)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) Return() *MockAuditor_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) RunAndReturn(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Run(run)
+	return _c
+}
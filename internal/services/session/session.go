@@ -2,43 +2,125 @@ package session_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/internal/lib/device"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
 	"github.com/google/uuid"
 )
 
+// touchInterval limits how often last_seen is written back on validation.
+const touchInterval = time.Minute
+
 type Repository interface {
 	SaveSession(ctx context.Context, session entities.Session) error
+	SaveSessionTTL(ctx context.Context, session entities.Session, ttl time.Duration) error
 	SessionById(ctx context.Context, sessionId string) (entities.Session, error)
+	SessionsByUserId(ctx context.Context, userId uuid.UUID) ([]entities.Session, error)
+	DeleteSessionsByUserId(ctx context.Context, userId uuid.UUID) error
+	DeleteSession(ctx context.Context, id, userId uuid.UUID) error
+	RevokeSession(ctx context.Context, id, userId uuid.UUID) error
+	TouchSession(ctx context.Context, id, userId uuid.UUID, lastSeen time.Time) error
+	ElevateSession(ctx context.Context, id, userId uuid.UUID, until time.Time) error
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
 }
 
 type Service struct {
 	repository Repository
+	auditor    Auditor
+	limits     config.SessionLimitConfig
+	security   config.SessionSecurityConfig
 }
 
-func New(repository Repository) *Service {
+func New(
+	repository Repository,
+	auditor Auditor,
+	limits config.SessionLimitConfig,
+	security config.SessionSecurityConfig,
+) *Service {
 	return &Service{
 		repository: repository,
+		auditor:    auditor,
+		limits:     limits,
+		security:   security,
 	}
 }
 
-func (s *Service) CreateSession(ctx context.Context, userId uuid.UUID, userAgent string) (uuid.UUID, error) {
+func (s *Service) CreateSession(ctx context.Context, userId uuid.UUID, role string, userAgent string) (uuid.UUID, error) {
 	const op = "services.session.CreateSession"
 
+	err := s.enforceLimit(ctx, userId, role)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	info := device.ParseUserAgent(userAgent)
+	ip, _ := ctx.Value(consts.ContextClientIP).(string)
+	session := entities.Session{
+		ID:         uuid.New(),
+		UserId:     userId,
+		UserAgent:  userAgent,
+		Browser:    info.Browser,
+		OS:         info.OS,
+		DeviceType: info.DeviceType,
+		IP:         ip,
+		LastSeen:   time.Now(),
+	}
+
+	err = s.repository.SaveSession(ctx, session)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return session.ID, nil
+}
+
+// CreateImpersonationSession opens a short-lived session for userId on behalf of the staff member impersonatorId.
+// It does not count against the user's session limit and never evicts the user's own sessions.
+func (s *Service) CreateImpersonationSession(
+	ctx context.Context,
+	userId, impersonatorId uuid.UUID,
+	userAgent string,
+) (uuid.UUID, error) {
+	const op = "services.session.CreateImpersonationSession"
+
+	info := device.ParseUserAgent(userAgent)
+	ip, _ := ctx.Value(consts.ContextClientIP).(string)
 	session := entities.Session{
-		ID:        uuid.New(),
-		UserId:    userId,
-		UserAgent: userAgent,
-		LastSeen:  time.Now(),
+		ID:             uuid.New(),
+		UserId:         userId,
+		UserAgent:      userAgent,
+		Browser:        info.Browser,
+		OS:             info.OS,
+		DeviceType:     info.DeviceType,
+		IP:             ip,
+		LastSeen:       time.Now(),
+		ImpersonatorId: impersonatorId.String(),
 	}
 
-	err := s.repository.SaveSession(ctx, session)
+	err := s.repository.SaveSessionTTL(ctx, session, s.security.ImpersonationTTL)
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventImpersonationStart,
+		UserId:   userId,
+		ActorId:  impersonatorId,
+		Metadata: map[string]string{"session_id": session.ID.String()},
+	})
+
 	return session.ID, nil
 }
 
@@ -53,13 +135,190 @@ func (s *Service) SessionById(ctx context.Context, sessionId string) (entities.S
 	return session, nil
 }
 
-func (s *Service) ValidateSession(ctx context.Context, sessionId string) (uuid.UUID, error) {
+func (s *Service) ValidateSession(ctx context.Context, sessionId string) (entities.Session, error) {
 	const op = "services.session.ValidateSession"
 
 	session, err := s.SessionById(ctx, sessionId)
 	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if s.security.Strict {
+		err = s.checkUserAgent(ctx, session)
+		if err != nil {
+			return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeen) > touchInterval {
+		err = s.repository.TouchSession(ctx, session.ID, session.UserId, now)
+		if err != nil && !errors.Is(err, errs.ErrSessionNotFound) {
+			logger.FromCtx(ctx).Error(
+				"failed to touch session",
+				slog.String("op", op),
+				logger.Err(err),
+			)
+		}
+	}
+
+	return session, nil
+}
+
+func (s *Service) SessionsByUserId(ctx context.Context, userId uuid.UUID) ([]entities.Session, error) {
+	const op = "services.session.SessionsByUserId"
+
+	sessions, err := s.repository.SessionsByUserId(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+func (s *Service) RevokeUserSessions(ctx context.Context, userId uuid.UUID) error {
+	const op = "services.session.RevokeUserSessions"
+
+	err := s.repository.DeleteSessionsByUserId(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventSessionRevoked,
+		UserId: userId,
+	})
+
+	return nil
+}
+
+func (s *Service) DeleteSession(ctx context.Context, sessionId string) (entities.Session, error) {
+	const op = "services.session.DeleteSession"
+
+	session, err := s.SessionById(ctx, sessionId)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repository.DeleteSession(ctx, session.ID, session.UserId)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return session, nil
+}
+
+// RevokeSession deletes the session like DeleteSession, but its client is told
+// the session was revoked instead of getting a plain not found.
+func (s *Service) RevokeSession(ctx context.Context, sessionId string) (entities.Session, error) {
+	const op = "services.session.RevokeSession"
+
+	session, err := s.SessionById(ctx, sessionId)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repository.RevokeSession(ctx, session.ID, session.UserId)
+	if err != nil {
+		return entities.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return session, nil
+}
+
+// ElevateSession unlocks sensitive actions for the session for the configured re-auth window
+// and returns the time they stay unlocked until.
+func (s *Service) ElevateSession(ctx context.Context, sessionId string) (time.Time, error) {
+	const op = "services.session.ElevateSession"
+
+	session, err := s.SessionById(ctx, sessionId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return session.UserId, nil
+	until := time.Now().Add(s.security.ReauthTTL)
+	err = s.repository.ElevateSession(ctx, session.ID, session.UserId, until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return until, nil
+}
+
+// enforceLimit makes room for a new session of the user according to the configured policy:
+// either the least recently seen sessions are revoked, or the login is rejected.
+func (s *Service) enforceLimit(ctx context.Context, userId uuid.UUID, role string) error {
+	limit := s.limits.MaxSessions
+	if override, ok := s.limits.RoleOverrides[role]; ok {
+		limit = override
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	all, err := s.repository.SessionsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	// impersonation sessions belong to staff, they neither count nor get evicted
+	sessions := make([]entities.Session, 0, len(all))
+	for _, session := range all {
+		if session.ImpersonatorId == "" {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) < limit {
+		return nil
+	}
+	if s.limits.Policy == consts.SessionLimitPolicyReject {
+		return errs.ErrSessionLimit
+	}
+
+	slices.SortFunc(sessions, func(a, b entities.Session) int {
+		return a.LastSeen.Compare(b.LastSeen)
+	})
+	for _, session := range sessions[:len(sessions)-limit+1] {
+		err = s.repository.RevokeSession(ctx, session.ID, session.UserId)
+		if err != nil && !errors.Is(err, errs.ErrSessionNotFound) {
+			return err
+		}
+
+		s.auditor.Record(ctx, entities.AuditEvent{
+			Type:     consts.AuditEventSessionRevoked,
+			UserId:   userId,
+			Reason:   consts.AuditReasonSessionLimit,
+			Metadata: map[string]string{"session_id": session.ID.String()},
+		})
+	}
+
+	return nil
+}
+
+// checkUserAgent rejects the session if the browser or OS family of the request
+// differs from the one the session was created with. Sessions created before
+// the user agent was parsed are not checked.
+func (s *Service) checkUserAgent(ctx context.Context, session entities.Session) error {
+	if session.Browser == "" {
+		return nil
+	}
+
+	userAgent, _ := ctx.Value(consts.ContextUserAgent).(string)
+	info := device.ParseUserAgent(userAgent)
+	if info.Browser == session.Browser && info.OS == session.OS {
+		return nil
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   consts.AuditEventSessionHijack,
+		UserId: session.UserId,
+		Metadata: map[string]string{
+			"session_id":      session.ID.String(),
+			"session_browser": session.Browser,
+			"session_os":      session.OS,
+			"browser":         info.Browser,
+			"os":              info.OS,
+		},
+	})
+
+	return errs.ErrSessionHijack
 }
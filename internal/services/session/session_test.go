@@ -2,9 +2,12 @@ package session_service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
 	"github.com/google/uuid"
@@ -69,7 +72,102 @@ func TestService_CreateSession(t *testing.T) {
 			s := &Service{
 				repository: tt.fields.repository,
 			}
-			_, err := s.CreateSession(tt.args.ctx, tt.args.userId, tt.args.userAgent)
+			_, err := s.CreateSession(tt.args.ctx, tt.args.userId, consts.RoleUser, tt.args.userAgent)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_CreateSession_Limit(t *testing.T) {
+	now := time.Now()
+	userId := uuid.New()
+	oldest := entities.Session{ID: uuid.New(), UserId: userId, LastSeen: now.Add(-2 * time.Hour)}
+	// the impersonation session is seen least recently, but is neither counted nor evicted
+	sessions := []entities.Session{
+		{ID: uuid.New(), UserId: userId, LastSeen: now},
+		oldest,
+		{ID: uuid.New(), UserId: userId, LastSeen: now.Add(-3 * time.Hour), ImpersonatorId: uuid.NewString()},
+	}
+
+	tests := []struct {
+		name        string
+		limits      config.SessionLimitConfig
+		role        string
+		wantRevoked *uuid.UUID
+		wantSaved   bool
+		wantErr     error
+	}{
+		{
+			name: "under limit case",
+			limits: config.SessionLimitConfig{
+				MaxSessions: 3,
+				Policy:      consts.SessionLimitPolicyEvictOldest,
+			},
+			role:      consts.RoleUser,
+			wantSaved: true,
+		},
+		{
+			name: "evict oldest case",
+			limits: config.SessionLimitConfig{
+				MaxSessions: 2,
+				Policy:      consts.SessionLimitPolicyEvictOldest,
+			},
+			role:        consts.RoleUser,
+			wantRevoked: &oldest.ID,
+			wantSaved:   true,
+		},
+		{
+			name: "reject case",
+			limits: config.SessionLimitConfig{
+				MaxSessions: 2,
+				Policy:      consts.SessionLimitPolicyReject,
+			},
+			role:    consts.RoleUser,
+			wantErr: errs.ErrSessionLimit,
+		},
+		{
+			name: "role override case",
+			limits: config.SessionLimitConfig{
+				MaxSessions:   2,
+				Policy:        consts.SessionLimitPolicyReject,
+				RoleOverrides: map[string]int{consts.RoleAdmin: 5},
+			},
+			role:      consts.RoleAdmin,
+			wantSaved: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+			m.EXPECT().SessionsByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(sessions, nil).Once()
+			if tt.wantRevoked != nil {
+				m.EXPECT().RevokeSession(
+					mock.AnythingOfType("context.backgroundCtx"),
+					*tt.wantRevoked,
+					userId,
+				).Return(nil).Once()
+			}
+			if tt.wantSaved {
+				m.EXPECT().SaveSession(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("entities.Session"),
+				).Return(nil).Once()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := New(m, mAuditor, tt.limits, config.SessionSecurityConfig{})
+			_, err := s.CreateSession(context.Background(), userId, tt.role, "firefox")
 			require.ErrorIs(t, err, tt.wantErr)
 		})
 	}
@@ -196,6 +294,12 @@ func TestService_ValidateSession(t *testing.T) {
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("string"),
 			).Return(entities.Session{}, tt.wantMockErr).Once()
+			m.EXPECT().TouchSession(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("uuid.UUID"),
+				mock.AnythingOfType("time.Time"),
+			).Return(nil).Maybe()
 
 			s := &Service{
 				repository: tt.fields.repository,
@@ -205,3 +309,218 @@ func TestService_ValidateSession(t *testing.T) {
 		})
 	}
 }
+
+func TestService_RevokeUserSessions(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantMockErr error
+		wantErr     error
+	}{
+		{
+			name:        "good case",
+			wantMockErr: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "repository error case",
+			wantMockErr: errs.ErrSessionNotFound,
+			wantErr:     errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockRepository(t)
+
+			m.EXPECT().DeleteSessionsByUserId(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(tt.wantMockErr).Once()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := &Service{
+				repository: m,
+				auditor:    mAuditor,
+			}
+			err := s.RevokeUserSessions(context.Background(), uuid.New())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ValidateSession_Strict(t *testing.T) {
+	const (
+		firefox = "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0"
+		chrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	)
+
+	tests := []struct {
+		name      string
+		browser   string
+		os        string
+		userAgent string
+		wantAudit bool
+		wantErr   error
+	}{
+		{
+			name:      "same user agent case",
+			browser:   "Firefox",
+			os:        "Linux",
+			userAgent: firefox,
+		},
+		{
+			name:      "user agent changed case",
+			browser:   "Firefox",
+			os:        "Linux",
+			userAgent: chrome,
+			wantAudit: true,
+			wantErr:   errs.ErrSessionHijack,
+		},
+		{
+			name:      "legacy session case",
+			userAgent: chrome,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+			m.EXPECT().SessionById(
+				mock.AnythingOfType("*context.valueCtx"),
+				mock.AnythingOfType("string"),
+			).Return(entities.Session{
+				ID:       uuid.New(),
+				UserId:   uuid.New(),
+				Browser:  tt.browser,
+				OS:       tt.os,
+				LastSeen: time.Now(),
+			}, nil).Once()
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantAudit {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("*context.valueCtx"),
+					mock.AnythingOfType("entities.AuditEvent"),
+				).Return().Once()
+			}
+
+			s := New(m, mAuditor, config.SessionLimitConfig{}, config.SessionSecurityConfig{Strict: true})
+			//nolint:staticcheck
+			ctx := context.WithValue(context.Background(), consts.ContextUserAgent, tt.userAgent)
+			_, err := s.ValidateSession(ctx, uuid.NewString())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_CreateImpersonationSession(t *testing.T) {
+	errRedis := errors.New("redis down")
+
+	tests := []struct {
+		name        string
+		wantMockErr error
+		wantErr     error
+	}{
+		{
+			name:        "good case",
+			wantMockErr: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "repository error case",
+			wantMockErr: errRedis,
+			wantErr:     errRedis,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId, staffId := uuid.New(), uuid.New()
+			ttl := 15 * time.Minute
+
+			m := NewMockRepository(t)
+			m.EXPECT().SaveSessionTTL(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.MatchedBy(func(session entities.Session) bool {
+					return session.UserId == userId && session.ImpersonatorId == staffId.String()
+				}),
+				ttl,
+			).Return(tt.wantMockErr).Once()
+
+			mAuditor := NewMockAuditor(t)
+			if tt.wantMockErr == nil {
+				mAuditor.EXPECT().Record(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.MatchedBy(func(event entities.AuditEvent) bool {
+						return event.Type == consts.AuditEventImpersonationStart && event.ActorId == staffId
+					}),
+				).Return().Once()
+			}
+
+			s := New(m, mAuditor, config.SessionLimitConfig{}, config.SessionSecurityConfig{ImpersonationTTL: ttl})
+			_, err := s.CreateImpersonationSession(context.Background(), userId, staffId, "chrome")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ElevateSession(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantLookupErr error
+		wantMockErr   error
+		wantErr       error
+	}{
+		{
+			name:    "good case",
+			wantErr: nil,
+		},
+		{
+			name:          "session not found case",
+			wantLookupErr: errs.ErrSessionNotFound,
+			wantErr:       errs.ErrSessionNotFound,
+		},
+		{
+			name:        "session expired meanwhile case",
+			wantMockErr: errs.ErrSessionNotFound,
+			wantErr:     errs.ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			session := entities.Session{ID: uuid.New(), UserId: uuid.New()}
+			ttl := 10 * time.Minute
+
+			m := NewMockRepository(t)
+			m.EXPECT().SessionById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				session.ID.String(),
+			).Return(session, tt.wantLookupErr).Once()
+			m.EXPECT().ElevateSession(
+				mock.AnythingOfType("context.backgroundCtx"),
+				session.ID,
+				session.UserId,
+				mock.AnythingOfType("time.Time"),
+			).Return(tt.wantMockErr).Maybe()
+
+			s := New(m, NewMockAuditor(t), config.SessionLimitConfig{}, config.SessionSecurityConfig{ReauthTTL: ttl})
+			until, err := s.ElevateSession(context.Background(), session.ID.String())
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.WithinDuration(t, time.Now().Add(ttl), until, time.Second)
+			}
+		})
+	}
+}
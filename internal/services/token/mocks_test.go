@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -95,6 +96,69 @@ func (_c *MockRepository_DeleteToken_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// DeleteUserTokens provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error {
+	ret := _mock.Called(ctx, userId, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserTokens")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, userId, tokenType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_DeleteUserTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserTokens'
+type MockRepository_DeleteUserTokens_Call struct {
+	*mock.Call
+}
+
+// DeleteUserTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uuid.UUID
+//   - tokenType string
+func (_e *MockRepository_Expecter) DeleteUserTokens(ctx interface{}, userId interface{}, tokenType interface{}) *MockRepository_DeleteUserTokens_Call {
+	return &MockRepository_DeleteUserTokens_Call{Call: _e.mock.On("DeleteUserTokens", ctx, userId, tokenType)}
+}
+
+func (_c *MockRepository_DeleteUserTokens_Call) Run(run func(ctx context.Context, userId uuid.UUID, tokenType string)) *MockRepository_DeleteUserTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_DeleteUserTokens_Call) Return(err error) *MockRepository_DeleteUserTokens_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_DeleteUserTokens_Call) RunAndReturn(run func(ctx context.Context, userId uuid.UUID, tokenType string) error) *MockRepository_DeleteUserTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveToken provides a mock function for the type MockRepository
 func (_mock *MockRepository) SaveToken(ctx context.Context, token entities.Token) error {
 	ret := _mock.Called(ctx, token)
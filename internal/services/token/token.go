@@ -3,7 +3,10 @@ package token_service
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/google/uuid"
 )
@@ -12,15 +15,18 @@ type Repository interface {
 	SaveToken(ctx context.Context, token entities.Token) error
 	Token(ctx context.Context, token string) (entities.Token, error)
 	DeleteToken(ctx context.Context, token string) error
+	DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error
 }
 
 type Service struct {
 	repository Repository
+	cfg        config.TokenConfig
 }
 
-func New(repository Repository) *Service {
+func New(repository Repository, cfg config.TokenConfig) *Service {
 	return &Service{
 		repository: repository,
+		cfg:        cfg,
 	}
 }
 
@@ -28,9 +34,35 @@ func (s *Service) CreateToken(ctx context.Context, userId uuid.UUID, tokenType s
 	const op = "services.token.CreateToken"
 
 	token := entities.Token{
-		Token:  uuid.NewString(),
-		UserId: userId,
-		Type:   tokenType,
+		Token:     uuid.NewString(),
+		UserId:    userId,
+		Type:      tokenType,
+		ExpiresAt: s.expiresAt(tokenType),
+	}
+
+	err := s.repository.SaveToken(ctx, token)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token.Token, nil
+}
+
+// CreateTokenWithPayload creates a token carrying extra data, e.g. the id of the session it refers to.
+func (s *Service) CreateTokenWithPayload(
+	ctx context.Context,
+	userId uuid.UUID,
+	tokenType string,
+	payload string,
+) (string, error) {
+	const op = "services.token.CreateTokenWithPayload"
+
+	token := entities.Token{
+		Token:     uuid.NewString(),
+		UserId:    userId,
+		Type:      tokenType,
+		Payload:   payload,
+		ExpiresAt: s.expiresAt(tokenType),
 	}
 
 	err := s.repository.SaveToken(ctx, token)
@@ -62,3 +94,34 @@ func (s *Service) DeleteToken(ctx context.Context, token string) error {
 
 	return nil
 }
+
+// DeleteUserTokens deletes all tokens of the type issued to the user,
+// e.g. the older reset links once the password was reset with one of them.
+func (s *Service) DeleteUserTokens(ctx context.Context, userId uuid.UUID, tokenType string) error {
+	const op = "services.token.DeleteUserTokens"
+
+	err := s.repository.DeleteUserTokens(ctx, userId, tokenType)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// expiresAt returns when a new token of the type stops working, zero if it never does.
+func (s *Service) expiresAt(tokenType string) time.Time {
+	var ttl time.Duration
+	switch tokenType {
+	case consts.TokenTypeResetPassword:
+		ttl = s.cfg.ResetPasswordTTL
+	case consts.TokenTypeNotMe:
+		ttl = s.cfg.NotMeTTL
+	case consts.TokenTypeChangeEmail:
+		ttl = s.cfg.ChangeEmailTTL
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(ttl)
+}
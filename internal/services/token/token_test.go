@@ -3,7 +3,9 @@ package token_service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/config"
 	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
@@ -75,6 +77,63 @@ func TestService_CreateToken(t *testing.T) {
 	}
 }
 
+func TestService_CreateToken_Expiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenType string
+		wantTTL   time.Duration
+	}{
+		{
+			name:      "reset password case",
+			tokenType: consts.TokenTypeResetPassword,
+			wantTTL:   time.Hour,
+		},
+		{
+			name:      "not me case",
+			tokenType: consts.TokenTypeNotMe,
+			wantTTL:   72 * time.Hour,
+		},
+		{
+			name:      "change email case",
+			tokenType: consts.TokenTypeChangeEmail,
+			wantTTL:   24 * time.Hour,
+		},
+		{
+			name:      "verify email case",
+			tokenType: consts.TokenTypeVerifyEmail,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockRepository(t)
+
+			var saved entities.Token
+			m.EXPECT().SaveToken(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.Token"),
+			).RunAndReturn(func(_ context.Context, token entities.Token) error {
+				saved = token
+				return nil
+			}).Once()
+
+			s := New(m, config.TokenConfig{
+				ResetPasswordTTL: time.Hour,
+				NotMeTTL:         72 * time.Hour,
+				ChangeEmailTTL:   24 * time.Hour,
+			})
+			_, err := s.CreateToken(context.Background(), uuid.New(), tt.tokenType)
+			require.NoError(t, err)
+
+			if tt.wantTTL == 0 {
+				require.True(t, saved.ExpiresAt.IsZero())
+				return
+			}
+			require.WithinDuration(t, time.Now().Add(tt.wantTTL), saved.ExpiresAt, time.Minute)
+		})
+	}
+}
+
 func TestService_Token(t *testing.T) {
 	type fields struct {
 		repository Repository
@@ -39,59 +39,534 @@ func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
 	return &MockUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// ChangeEmail provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ChangeEmail(ctx context.Context, id uuid.UUID, email string) error {
+	ret := _mock.Called(ctx, id, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_ChangeEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeEmail'
+type MockUserRepository_ChangeEmail_Call struct {
+	*mock.Call
+}
+
+// ChangeEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - email string
+func (_e *MockUserRepository_Expecter) ChangeEmail(ctx interface{}, id interface{}, email interface{}) *MockUserRepository_ChangeEmail_Call {
+	return &MockUserRepository_ChangeEmail_Call{Call: _e.mock.On("ChangeEmail", ctx, id, email)}
+}
+
+func (_c *MockUserRepository_ChangeEmail_Call) Run(run func(ctx context.Context, id uuid.UUID, email string)) *MockUserRepository_ChangeEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ChangeEmail_Call) Return(err error) *MockUserRepository_ChangeEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_ChangeEmail_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, email string) error) *MockUserRepository_ChangeEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveUser provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) SaveUser(ctx context.Context, user entities.User) error {
 	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveUser")
+		panic("no return value specified for SaveUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
+type MockUserRepository_SaveUser_Call struct {
+	*mock.Call
+}
+
+// SaveUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user entities.User
+func (_e *MockUserRepository_Expecter) SaveUser(ctx interface{}, user interface{}) *MockUserRepository_SaveUser_Call {
+	return &MockUserRepository_SaveUser_Call{Call: _e.mock.On("SaveUser", ctx, user)}
+}
+
+func (_c *MockUserRepository_SaveUser_Call) Run(run func(ctx context.Context, user entities.User)) *MockUserRepository_SaveUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.User
+		if args[1] != nil {
+			arg1 = args[1].(entities.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SaveUser_Call) Return(err error) *MockUserRepository_SaveUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_SaveUser_Call) RunAndReturn(run func(ctx context.Context, user entities.User) error) *MockUserRepository_SaveUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchUsers provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SearchUsers(ctx context.Context, query string, offset int64, limit int64) ([]entities.User, int64, error) {
+	ret := _mock.Called(ctx, query, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUsers")
+	}
+
+	var r0 []entities.User
+	var r1 int64
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]entities.User, int64, error)); ok {
+		return returnFunc(ctx, query, offset, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64, int64) []entities.User); ok {
+		r0 = returnFunc(ctx, query, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = returnFunc(ctx, query, offset, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = returnFunc(ctx, query, offset, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockUserRepository_SearchUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchUsers'
+type MockUserRepository_SearchUsers_Call struct {
+	*mock.Call
+}
+
+// SearchUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - offset int64
+//   - limit int64
+func (_e *MockUserRepository_Expecter) SearchUsers(ctx interface{}, query interface{}, offset interface{}, limit interface{}) *MockUserRepository_SearchUsers_Call {
+	return &MockUserRepository_SearchUsers_Call{Call: _e.mock.On("SearchUsers", ctx, query, offset, limit)}
+}
+
+func (_c *MockUserRepository_SearchUsers_Call) Run(run func(ctx context.Context, query string, offset int64, limit int64)) *MockUserRepository_SearchUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SearchUsers_Call) Return(users []entities.User, n int64, err error) *MockUserRepository_SearchUsers_Call {
+	_c.Call.Return(users, n, err)
+	return _c
+}
+
+func (_c *MockUserRepository_SearchUsers_Call) RunAndReturn(run func(ctx context.Context, query string, offset int64, limit int64) ([]entities.User, int64, error)) *MockUserRepository_SearchUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetNotificationPreferences provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SetNotificationPreferences(ctx context.Context, id uuid.UUID, preferences map[string]bool) error {
+	ret := _mock.Called(ctx, id, preferences)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNotificationPreferences")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, map[string]bool) error); ok {
+		r0 = returnFunc(ctx, id, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_SetNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNotificationPreferences'
+type MockUserRepository_SetNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// SetNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - preferences map[string]bool
+func (_e *MockUserRepository_Expecter) SetNotificationPreferences(ctx interface{}, id interface{}, preferences interface{}) *MockUserRepository_SetNotificationPreferences_Call {
+	return &MockUserRepository_SetNotificationPreferences_Call{Call: _e.mock.On("SetNotificationPreferences", ctx, id, preferences)}
+}
+
+func (_c *MockUserRepository_SetNotificationPreferences_Call) Run(run func(ctx context.Context, id uuid.UUID, preferences map[string]bool)) *MockUserRepository_SetNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 map[string]bool
+		if args[2] != nil {
+			arg2 = args[2].(map[string]bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SetNotificationPreferences_Call) Return(err error) *MockUserRepository_SetNotificationPreferences_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_SetNotificationPreferences_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, preferences map[string]bool) error) *MockUserRepository_SetNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPhone provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SetPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	ret := _mock.Called(ctx, id, phone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPhone")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_SetPhone_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPhone'
+type MockUserRepository_SetPhone_Call struct {
+	*mock.Call
+}
+
+// SetPhone is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - phone string
+func (_e *MockUserRepository_Expecter) SetPhone(ctx interface{}, id interface{}, phone interface{}) *MockUserRepository_SetPhone_Call {
+	return &MockUserRepository_SetPhone_Call{Call: _e.mock.On("SetPhone", ctx, id, phone)}
+}
+
+func (_c *MockUserRepository_SetPhone_Call) Run(run func(ctx context.Context, id uuid.UUID, phone string)) *MockUserRepository_SetPhone_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SetPhone_Call) Return(err error) *MockUserRepository_SetPhone_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_SetPhone_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, phone string) error) *MockUserRepository_SetPhone_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetSuspension provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SetSuspension(ctx context.Context, id uuid.UUID, suspension *entities.Suspension) error {
+	ret := _mock.Called(ctx, id, suspension)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSuspension")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.Suspension) error); ok {
+		r0 = returnFunc(ctx, id, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_SetSuspension_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSuspension'
+type MockUserRepository_SetSuspension_Call struct {
+	*mock.Call
+}
+
+// SetSuspension is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - suspension *entities.Suspension
+func (_e *MockUserRepository_Expecter) SetSuspension(ctx interface{}, id interface{}, suspension interface{}) *MockUserRepository_SetSuspension_Call {
+	return &MockUserRepository_SetSuspension_Call{Call: _e.mock.On("SetSuspension", ctx, id, suspension)}
+}
+
+func (_c *MockUserRepository_SetSuspension_Call) Run(run func(ctx context.Context, id uuid.UUID, suspension *entities.Suspension)) *MockUserRepository_SetSuspension_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *entities.Suspension
+		if args[2] != nil {
+			arg2 = args[2].(*entities.Suspension)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SetSuspension_Call) Return(err error) *MockUserRepository_SetSuspension_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_SetSuspension_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, suspension *entities.Suspension) error) *MockUserRepository_SetSuspension_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SuppressEmail provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SuppressEmail(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, email, suppression)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuppressEmail")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, entities.EmailSuppression) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, email, suppression)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, entities.EmailSuppression) uuid.UUID); ok {
+		r0 = returnFunc(ctx, email, suppression)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, entities.EmailSuppression) error); ok {
+		r1 = returnFunc(ctx, email, suppression)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_SuppressEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SuppressEmail'
+type MockUserRepository_SuppressEmail_Call struct {
+	*mock.Call
+}
+
+// SuppressEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - suppression entities.EmailSuppression
+func (_e *MockUserRepository_Expecter) SuppressEmail(ctx interface{}, email interface{}, suppression interface{}) *MockUserRepository_SuppressEmail_Call {
+	return &MockUserRepository_SuppressEmail_Call{Call: _e.mock.On("SuppressEmail", ctx, email, suppression)}
+}
+
+func (_c *MockUserRepository_SuppressEmail_Call) Run(run func(ctx context.Context, email string, suppression entities.EmailSuppression)) *MockUserRepository_SuppressEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 entities.EmailSuppression
+		if args[2] != nil {
+			arg2 = args[2].(entities.EmailSuppression)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_SuppressEmail_Call) Return(uUID uuid.UUID, err error) *MockUserRepository_SuppressEmail_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockUserRepository_SuppressEmail_Call) RunAndReturn(run func(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error)) *MockUserRepository_SuppressEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePassword provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	ret := _mock.Called(ctx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.User) error); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = returnFunc(ctx, id, password)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
-type MockUserRepository_SaveUser_Call struct {
+// MockUserRepository_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type MockUserRepository_UpdatePassword_Call struct {
 	*mock.Call
 }
 
-// SaveUser is a helper method to define mock.On call
+// UpdatePassword is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user entities.User
-func (_e *MockUserRepository_Expecter) SaveUser(ctx interface{}, user interface{}) *MockUserRepository_SaveUser_Call {
-	return &MockUserRepository_SaveUser_Call{Call: _e.mock.On("SaveUser", ctx, user)}
+//   - id uuid.UUID
+//   - password string
+func (_e *MockUserRepository_Expecter) UpdatePassword(ctx interface{}, id interface{}, password interface{}) *MockUserRepository_UpdatePassword_Call {
+	return &MockUserRepository_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, id, password)}
 }
 
-func (_c *MockUserRepository_SaveUser_Call) Run(run func(ctx context.Context, user entities.User)) *MockUserRepository_SaveUser_Call {
+func (_c *MockUserRepository_UpdatePassword_Call) Run(run func(ctx context.Context, id uuid.UUID, password string)) *MockUserRepository_UpdatePassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 entities.User
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(entities.User)
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_SaveUser_Call) Return(err error) *MockUserRepository_SaveUser_Call {
+func (_c *MockUserRepository_UpdatePassword_Call) Return(err error) *MockUserRepository_UpdatePassword_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_SaveUser_Call) RunAndReturn(run func(ctx context.Context, user entities.User) error) *MockUserRepository_SaveUser_Call {
+func (_c *MockUserRepository_UpdatePassword_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, password string) error) *MockUserRepository_UpdatePassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -162,6 +637,72 @@ func (_c *MockUserRepository_UserByEmail_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// UserById provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UserById(ctx context.Context, id uuid.UUID) (entities.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserById")
+	}
+
+	var r0 entities.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (entities.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) entities.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_UserById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserById'
+type MockUserRepository_UserById_Call struct {
+	*mock.Call
+}
+
+// UserById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockUserRepository_Expecter) UserById(ctx interface{}, id interface{}) *MockUserRepository_UserById_Call {
+	return &MockUserRepository_UserById_Call{Call: _e.mock.On("UserById", ctx, id)}
+}
+
+func (_c *MockUserRepository_UserById_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockUserRepository_UserById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UserById_Call) Return(user entities.User, err error) *MockUserRepository_UserById_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_UserById_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (entities.User, error)) *MockUserRepository_UserById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ValidateEmail provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) ValidateEmail(ctx context.Context, id uuid.UUID) error {
 	ret := _mock.Called(ctx, id)
@@ -368,3 +909,261 @@ func (_c *MockTokenService_Token_Call) RunAndReturn(run func(ctx context.Context
 	_c.Call.Return(run)
 	return _c
 }
+
+// NewMockCodeVerifier creates a new instance of MockCodeVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCodeVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCodeVerifier {
+	mock := &MockCodeVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCodeVerifier is an autogenerated mock type for the CodeVerifier type
+type MockCodeVerifier struct {
+	mock.Mock
+}
+
+type MockCodeVerifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCodeVerifier) EXPECT() *MockCodeVerifier_Expecter {
+	return &MockCodeVerifier_Expecter{mock: &_m.Mock}
+}
+
+// VerifyCode provides a mock function for the type MockCodeVerifier
+func (_mock *MockCodeVerifier) VerifyCode(ctx context.Context, email string, code string) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, email, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCode")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, email, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) uuid.UUID); ok {
+		r0 = returnFunc(ctx, email, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, email, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCodeVerifier_VerifyCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyCode'
+type MockCodeVerifier_VerifyCode_Call struct {
+	*mock.Call
+}
+
+// VerifyCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - code string
+func (_e *MockCodeVerifier_Expecter) VerifyCode(ctx interface{}, email interface{}, code interface{}) *MockCodeVerifier_VerifyCode_Call {
+	return &MockCodeVerifier_VerifyCode_Call{Call: _e.mock.On("VerifyCode", ctx, email, code)}
+}
+
+func (_c *MockCodeVerifier_VerifyCode_Call) Run(run func(ctx context.Context, email string, code string)) *MockCodeVerifier_VerifyCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCodeVerifier_VerifyCode_Call) Return(uUID uuid.UUID, err error) *MockCodeVerifier_VerifyCode_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockCodeVerifier_VerifyCode_Call) RunAndReturn(run func(ctx context.Context, email string, code string) (uuid.UUID, error)) *MockCodeVerifier_VerifyCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditor creates a new instance of MockAuditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditor {
+	mock := &MockAuditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditor is an autogenerated mock type for the Auditor type
+type MockAuditor struct {
+	mock.Mock
+}
+
+type MockAuditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditor) EXPECT() *MockAuditor_Expecter {
+	return &MockAuditor_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) Record(ctx context.Context, event entities.AuditEvent) {
+	_mock.Called(ctx, event)
+	return
+}
+
+// MockAuditor_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditor_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event entities.AuditEvent
+func (_e *MockAuditor_Expecter) Record(ctx interface{}, event interface{}) *MockAuditor_Record_Call {
+	return &MockAuditor_Record_Call{Call: _e.mock.On("Record", ctx, event)}
+}
+
+func (_c *MockAuditor_Record_Call) Run(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.AuditEvent
+		if args[1] != nil {
+			arg1 = args[1].(entities.AuditEvent)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) Return() *MockAuditor_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditor_Record_Call) RunAndReturn(run func(ctx context.Context, event entities.AuditEvent)) *MockAuditor_Record_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockChannelCreator creates a new instance of MockChannelCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockChannelCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockChannelCreator {
+	mock := &MockChannelCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockChannelCreator is an autogenerated mock type for the ChannelCreator type
+type MockChannelCreator struct {
+	mock.Mock
+}
+
+type MockChannelCreator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockChannelCreator) EXPECT() *MockChannelCreator_Expecter {
+	return &MockChannelCreator_Expecter{mock: &_m.Mock}
+}
+
+// CreateChannel provides a mock function for the type MockChannelCreator
+func (_mock *MockChannelCreator) CreateChannel(ctx context.Context, user entities.User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateChannel")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entities.User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockChannelCreator_CreateChannel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateChannel'
+type MockChannelCreator_CreateChannel_Call struct {
+	*mock.Call
+}
+
+// CreateChannel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user entities.User
+func (_e *MockChannelCreator_Expecter) CreateChannel(ctx interface{}, user interface{}) *MockChannelCreator_CreateChannel_Call {
+	return &MockChannelCreator_CreateChannel_Call{Call: _e.mock.On("CreateChannel", ctx, user)}
+}
+
+func (_c *MockChannelCreator_CreateChannel_Call) Run(run func(ctx context.Context, user entities.User)) *MockChannelCreator_CreateChannel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entities.User
+		if args[1] != nil {
+			arg1 = args[1].(entities.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockChannelCreator_CreateChannel_Call) Return(err error) *MockChannelCreator_CreateChannel_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockChannelCreator_CreateChannel_Call) RunAndReturn(run func(ctx context.Context, user entities.User) error) *MockChannelCreator_CreateChannel_Call {
+	_c.Call.Return(run)
+	return _c
+}
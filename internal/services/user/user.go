@@ -3,7 +3,10 @@ package user_service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
@@ -14,6 +17,14 @@ type UserRepository interface {
 	SaveUser(ctx context.Context, user entities.User) error
 	UserByEmail(ctx context.Context, email string) (entities.User, error)
 	ValidateEmail(ctx context.Context, id uuid.UUID) error
+	ChangeEmail(ctx context.Context, id uuid.UUID, email string) error
+	UserById(ctx context.Context, id uuid.UUID) (entities.User, error)
+	SearchUsers(ctx context.Context, query string, offset, limit int64) ([]entities.User, int64, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	SetSuspension(ctx context.Context, id uuid.UUID, suspension *entities.Suspension) error
+	SetPhone(ctx context.Context, id uuid.UUID, phone string) error
+	SuppressEmail(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error)
+	SetNotificationPreferences(ctx context.Context, id uuid.UUID, preferences map[string]bool) error
 }
 
 type TokenService interface {
@@ -21,15 +32,39 @@ type TokenService interface {
 	DeleteToken(ctx context.Context, token string) error
 }
 
+type CodeVerifier interface {
+	VerifyCode(ctx context.Context, email, code string) (uuid.UUID, error)
+}
+
+type Auditor interface {
+	Record(ctx context.Context, event entities.AuditEvent)
+}
+
+type ChannelCreator interface {
+	CreateChannel(ctx context.Context, user entities.User) error
+}
+
 type Service struct {
 	userRepository UserRepository
 	tokenService   TokenService
+	codeVerifier   CodeVerifier
+	auditor        Auditor
+	channelCreator ChannelCreator
 }
 
-func New(userRepository UserRepository, tokenService TokenService) *Service {
+func New(
+	userRepository UserRepository,
+	tokenService TokenService,
+	codeVerifier CodeVerifier,
+	auditor Auditor,
+	channelCreator ChannelCreator,
+) *Service {
 	return &Service{
 		userRepository: userRepository,
 		tokenService:   tokenService,
+		codeVerifier:   codeVerifier,
+		auditor:        auditor,
+		channelCreator: channelCreator,
 	}
 }
 
@@ -37,13 +72,18 @@ func (s *Service) CreateUser(ctx context.Context, login, email, password string)
 	const op = "services.user.CreateUser"
 
 	id := uuid.New()
+	// the negotiated request locale becomes the preferred one
+	locale, _ := ctx.Value(consts.ContextLocale).(string)
 
 	user := entities.User{
 		ID:              id,
 		Login:           login,
+		LoginKey:        strings.ToLower(login),
 		Email:           email,
 		Password:        password,
 		IsEmailVerified: false,
+		Role:            consts.RoleUser,
+		Locale:          locale,
 	}
 
 	err := s.userRepository.SaveUser(ctx, user)
@@ -68,6 +108,8 @@ func (s *Service) UserByEmail(ctx context.Context, email string) (entities.User,
 	return user, nil
 }
 
+// VerifyEmail follows a verification link. Links sent on an email change
+// carry the new address, following one switches the account to it.
 func (s *Service) VerifyEmail(ctx context.Context, req dtos.ValidateEmailRequest) error {
 	const op = "services.user.VerifyEmail"
 
@@ -76,9 +118,27 @@ func (s *Service) VerifyEmail(ctx context.Context, req dtos.ValidateEmailRequest
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.userRepository.ValidateEmail(ctx, token.UserId)
+	event := consts.AuditEventVerifyEmail
+	switch token.Type {
+	case consts.TokenTypeVerifyEmail:
+		err = s.userRepository.ValidateEmail(ctx, token.UserId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+		}
+	case consts.TokenTypeChangeEmail:
+		err = s.userRepository.ChangeEmail(ctx, token.UserId, token.Payload)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		event = consts.AuditEventEmailChange
+	default:
+		return fmt.Errorf("%s: %w", op, errs.ErrTokenNotFound)
+	}
+
+	// the token is kept until the channel exists, so following the link again retries
+	err = s.createChannel(ctx, token.UserId)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	err = s.tokenService.DeleteToken(ctx, req.Token)
@@ -86,5 +146,177 @@ func (s *Service) VerifyEmail(ctx context.Context, req dtos.ValidateEmailRequest
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:   event,
+		UserId: token.UserId,
+	})
+
+	return nil
+}
+
+// VerifyEmailCode is the alternative to the verification link for mobile apps.
+func (s *Service) VerifyEmailCode(ctx context.Context, req dtos.VerifyEmailCodeRequest) error {
+	const op = "services.user.VerifyEmailCode"
+
+	userId, err := s.codeVerifier.VerifyCode(ctx, req.Email, req.Code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.userRepository.ValidateEmail(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, errs.ErrUserNotFound)
+	}
+
+	err = s.createChannel(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.auditor.Record(ctx, entities.AuditEvent{
+		Type:     consts.AuditEventVerifyEmail,
+		UserId:   userId,
+		Metadata: map[string]string{"method": consts.VerificationMethodCode},
+	})
+
+	return nil
+}
+
+func (s *Service) UserById(ctx context.Context, id uuid.UUID) (entities.User, error) {
+	const op = "services.user.UserById"
+
+	user, err := s.userRepository.UserById(ctx, id)
+	if err != nil {
+		return entities.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Service) SearchUsers(ctx context.Context, req dtos.SearchUsersRequest) ([]entities.User, int64, error) {
+	const op = "services.user.SearchUsers"
+
+	offset := int64((req.Page - 1) * req.Limit)
+	users, total, err := s.userRepository.SearchUsers(ctx, req.Query, offset, int64(req.Limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, total, nil
+}
+
+func (s *Service) ForceVerifyEmail(ctx context.Context, id uuid.UUID) error {
+	const op = "services.user.ForceVerifyEmail"
+
+	err := s.userRepository.ValidateEmail(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.createChannel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// createChannel gives a user with a verified email their channel. Logins are unique since
+// registration checks them, a clash is left only by users registered before and fails the call.
+func (s *Service) createChannel(ctx context.Context, id uuid.UUID) error {
+	const op = "services.user.createChannel"
+
+	user, err := s.userRepository.UserById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.channelCreator.CreateChannel(ctx, user)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdatePassword refuses to run under an impersonation session: support staff
+// may act as the user but never take over their credentials.
+func (s *Service) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	const op = "services.user.UpdatePassword"
+
+	if _, ok := ctx.Value(consts.ContextImpersonatorId).(uuid.UUID); ok {
+		return fmt.Errorf("%s: %w", op, errs.ErrImpersonation)
+	}
+
+	err := s.userRepository.UpdatePassword(ctx, id, password)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) Suspend(ctx context.Context, id, issuedBy uuid.UUID, req dtos.SuspendUserRequest) error {
+	const op = "services.user.Suspend"
+
+	suspension := &entities.Suspension{
+		Type:      req.Type,
+		Reason:    req.Reason,
+		ExpiresAt: req.ExpiresAt,
+		IssuedBy:  issuedBy,
+		IssuedAt:  time.Now(),
+	}
+
+	err := s.userRepository.SetSuspension(ctx, id, suspension)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	const op = "services.user.Unsuspend"
+
+	err := s.userRepository.SetSuspension(ctx, id, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Service) SetPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	const op = "services.user.SetPhone"
+
+	err := s.userRepository.SetPhone(ctx, id, phone)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SuppressEmail marks the address as undeliverable, returns the id of its owner.
+func (s *Service) SuppressEmail(ctx context.Context, email string, suppression entities.EmailSuppression) (uuid.UUID, error) {
+	const op = "services.user.SuppressEmail"
+
+	id, err := s.userRepository.SuppressEmail(ctx, email, suppression)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SetNotificationPreferences stores the given email categories, the others keep their values.
+func (s *Service) SetNotificationPreferences(ctx context.Context, id uuid.UUID, preferences map[string]bool) error {
+	const op = "services.user.SetNotificationPreferences"
+
+	err := s.userRepository.SetNotificationPreferences(ctx, id, preferences)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
@@ -2,8 +2,11 @@ package user_service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/AlexMickh/twitch-clone/internal/consts"
 	"github.com/AlexMickh/twitch-clone/internal/dtos"
 	"github.com/AlexMickh/twitch-clone/internal/entities"
 	"github.com/AlexMickh/twitch-clone/internal/errs"
@@ -64,13 +67,30 @@ func TestService_CreateUser(t *testing.T) {
 			wantRepositoryErr: errs.ErrUserAlreadyExists,
 			wantErr:           errs.ErrUserAlreadyExists,
 		},
+		{
+			name: "login taken case",
+			fields: fields{
+				userRepository: mr,
+				tokenService:   ms,
+			},
+			args: args{
+				ctx:      context.Background(),
+				login:    "Some Login",
+				email:    "example@test.com",
+				password: "qwerty123",
+			},
+			wantRepositoryErr: errs.ErrLoginTaken,
+			wantErr:           errs.ErrLoginTaken,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			mr.EXPECT().SaveUser(
 				mock.AnythingOfType("context.backgroundCtx"),
-				mock.AnythingOfType("entities.User"),
+				mock.MatchedBy(func(user entities.User) bool {
+					return user.LoginKey == strings.ToLower(tt.args.login)
+				}),
 			).Return(tt.wantRepositoryErr).Once()
 
 			s := &Service{
@@ -200,9 +220,11 @@ func TestService_VerifyEmail(t *testing.T) {
 	tests := []struct {
 		name                 string
 		args                 args
+		tokenType            string
 		wantRepositoryErr    error
 		wantServiceGetErr    error
 		wantServiceDeleteErr error
+		wantChannelErr       error
 		wantErr              error
 	}{
 		{
@@ -213,6 +235,7 @@ func TestService_VerifyEmail(t *testing.T) {
 					Token: uuid.NewString(),
 				},
 			},
+			tokenType:            consts.TokenTypeVerifyEmail,
 			wantRepositoryErr:    nil,
 			wantServiceGetErr:    nil,
 			wantServiceDeleteErr: nil,
@@ -226,6 +249,7 @@ func TestService_VerifyEmail(t *testing.T) {
 					Token: uuid.NewString(),
 				},
 			},
+			tokenType:            consts.TokenTypeVerifyEmail,
 			wantRepositoryErr:    nil,
 			wantServiceGetErr:    errs.ErrTokenNotFound,
 			wantServiceDeleteErr: nil,
@@ -239,11 +263,26 @@ func TestService_VerifyEmail(t *testing.T) {
 					Token: uuid.NewString(),
 				},
 			},
+			tokenType:            consts.TokenTypeVerifyEmail,
 			wantRepositoryErr:    errs.ErrUserNotFound,
 			wantServiceGetErr:    nil,
 			wantServiceDeleteErr: nil,
 			wantErr:              errs.ErrUserNotFound,
 		},
+		{
+			name: "wrong token type case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.ValidateEmailRequest{
+					Token: uuid.NewString(),
+				},
+			},
+			tokenType:            consts.TokenTypeResetPassword,
+			wantRepositoryErr:    nil,
+			wantServiceGetErr:    nil,
+			wantServiceDeleteErr: nil,
+			wantErr:              errs.ErrTokenNotFound,
+		},
 		{
 			name: "service delete case",
 			args: args{
@@ -252,11 +291,59 @@ func TestService_VerifyEmail(t *testing.T) {
 					Token: uuid.NewString(),
 				},
 			},
+			tokenType:            consts.TokenTypeVerifyEmail,
 			wantRepositoryErr:    nil,
 			wantServiceGetErr:    nil,
 			wantServiceDeleteErr: errs.ErrTokenNotFound,
 			wantErr:              errs.ErrTokenNotFound,
 		},
+		{
+			name: "channel error case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.ValidateEmailRequest{
+					Token: uuid.NewString(),
+				},
+			},
+			tokenType:      consts.TokenTypeVerifyEmail,
+			wantChannelErr: errors.New("some error"),
+			wantErr:        errors.New("some error"),
+		},
+		{
+			name: "channel login taken case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.ValidateEmailRequest{
+					Token: uuid.NewString(),
+				},
+			},
+			tokenType:      consts.TokenTypeVerifyEmail,
+			wantChannelErr: errs.ErrChannelLoginTaken,
+			wantErr:        errs.ErrChannelLoginTaken,
+		},
+		{
+			name: "change email case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.ValidateEmailRequest{
+					Token: uuid.NewString(),
+				},
+			},
+			tokenType: consts.TokenTypeChangeEmail,
+			wantErr:   nil,
+		},
+		{
+			name: "change email taken case",
+			args: args{
+				ctx: context.Background(),
+				req: dtos.ValidateEmailRequest{
+					Token: uuid.NewString(),
+				},
+			},
+			tokenType:         consts.TokenTypeChangeEmail,
+			wantRepositoryErr: errs.ErrUserAlreadyExists,
+			wantErr:           errs.ErrUserAlreadyExists,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -267,23 +354,173 @@ func TestService_VerifyEmail(t *testing.T) {
 			ms.EXPECT().Token(
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("string"),
-			).Return(entities.Token{}, tt.wantServiceGetErr).Once()
+			).Return(entities.Token{Type: tt.tokenType, Payload: "new@test.com"}, tt.wantServiceGetErr).Once()
 
 			mr.EXPECT().ValidateEmail(
 				mock.AnythingOfType("context.backgroundCtx"),
 				mock.AnythingOfType("uuid.UUID"),
 			).Return(tt.wantRepositoryErr).Maybe()
+			mr.EXPECT().ChangeEmail(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("uuid.UUID"),
+				"new@test.com",
+			).Return(tt.wantRepositoryErr).Maybe()
 
-			ms.EXPECT().DeleteToken(
+			mr.EXPECT().UserById(
 				mock.AnythingOfType("context.backgroundCtx"),
-				mock.AnythingOfType("string"),
-			).Return(tt.wantServiceDeleteErr).Maybe()
+				mock.AnythingOfType("uuid.UUID"),
+			).Return(entities.User{Login: "login"}, nil).Maybe()
+
+			mChannels := NewMockChannelCreator(t)
+			mChannels.EXPECT().CreateChannel(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.User"),
+			).Return(tt.wantChannelErr).Maybe()
+
+			// a failed channel keeps the token for the retry
+			if tt.wantChannelErr == nil {
+				ms.EXPECT().DeleteToken(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("string"),
+				).Return(tt.wantServiceDeleteErr).Maybe()
+			}
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
 
 			s := &Service{
 				userRepository: mr,
 				tokenService:   ms,
+				auditor:        mAuditor,
+				channelCreator: mChannels,
 			}
 			err := s.VerifyEmail(tt.args.ctx, tt.args.req)
+			if tt.wantChannelErr != nil && !errors.Is(tt.wantChannelErr, errs.ErrChannelLoginTaken) {
+				require.Error(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_UpdatePassword(t *testing.T) {
+	//nolint:staticcheck
+	impersonated := context.WithValue(context.Background(), consts.ContextImpersonatorId, uuid.New())
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantRepoCall bool
+		wantErr      error
+	}{
+		{
+			name:         "good case",
+			ctx:          context.Background(),
+			wantRepoCall: true,
+			wantErr:      nil,
+		},
+		{
+			name:         "impersonation case",
+			ctx:          impersonated,
+			wantRepoCall: false,
+			wantErr:      errs.ErrImpersonation,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mr := NewMockUserRepository(t)
+			if tt.wantRepoCall {
+				mr.EXPECT().UpdatePassword(
+					mock.AnythingOfType("context.backgroundCtx"),
+					mock.AnythingOfType("uuid.UUID"),
+					"hash",
+				).Return(nil).Once()
+			}
+
+			s := &Service{
+				userRepository: mr,
+			}
+			err := s.UpdatePassword(tt.ctx, uuid.New(), "hash")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_VerifyEmailCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantCodeErr error
+		wantRepoErr error
+		wantErr     error
+	}{
+		{
+			name:    "good case",
+			wantErr: nil,
+		},
+		{
+			name:        "invalid code case",
+			wantCodeErr: errs.ErrCodeInvalid,
+			wantErr:     errs.ErrCodeInvalid,
+		},
+		{
+			name:        "attempts exceeded case",
+			wantCodeErr: errs.ErrCodeAttempts,
+			wantErr:     errs.ErrCodeAttempts,
+		},
+		{
+			name:        "repository error case",
+			wantRepoErr: errs.ErrUserNotFound,
+			wantErr:     errs.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userId := uuid.New()
+
+			mCodes := NewMockCodeVerifier(t)
+			mCodes.EXPECT().VerifyCode(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"test@test.com",
+				"123456",
+			).Return(userId, tt.wantCodeErr).Once()
+
+			mr := NewMockUserRepository(t)
+			mr.EXPECT().ValidateEmail(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(tt.wantRepoErr).Maybe()
+			mr.EXPECT().UserById(
+				mock.AnythingOfType("context.backgroundCtx"),
+				userId,
+			).Return(entities.User{ID: userId, Login: "login"}, nil).Maybe()
+
+			mChannels := NewMockChannelCreator(t)
+			mChannels.EXPECT().CreateChannel(
+				mock.AnythingOfType("context.backgroundCtx"),
+				entities.User{ID: userId, Login: "login"},
+			).Return(nil).Maybe()
+
+			mAuditor := NewMockAuditor(t)
+			mAuditor.EXPECT().Record(
+				mock.AnythingOfType("context.backgroundCtx"),
+				mock.AnythingOfType("entities.AuditEvent"),
+			).Return().Maybe()
+
+			s := New(mr, NewMockTokenService(t), mCodes, mAuditor, mChannels)
+			err := s.VerifyEmailCode(context.Background(), dtos.VerifyEmailCodeRequest{
+				Email: "test@test.com",
+				Code:  "123456",
+			})
 			require.ErrorIs(t, err, tt.wantErr)
 		})
 	}
@@ -0,0 +1,118 @@
+package verification_code_service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/AlexMickh/twitch-clone/pkg/logger"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const codeDigits = 6
+
+var codeSpace = big.NewInt(1_000_000)
+
+type Repository interface {
+	SaveCode(ctx context.Context, code entities.VerificationCode, ttl time.Duration) error
+	RegisterAttempt(ctx context.Context, recipient string) (entities.VerificationCode, error)
+	DeleteCode(ctx context.Context, recipient string) error
+}
+
+type Service struct {
+	repository Repository
+	cfg        config.VerificationCodeConfig
+}
+
+func New(repository Repository, cfg config.VerificationCodeConfig) *Service {
+	return &Service{
+		repository: repository,
+		cfg:        cfg,
+	}
+}
+
+// CreateCode issues a new six-digit code for the recipient, replacing the previous one.
+func (s *Service) CreateCode(ctx context.Context, userId uuid.UUID, recipient string) (string, error) {
+	const op = "services.verification_code.CreateCode"
+
+	n, err := rand.Int(rand.Reader, codeSpace)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	code := fmt.Sprintf("%0*d", codeDigits, n.Int64())
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repository.SaveCode(ctx, entities.VerificationCode{
+		Recipient: normalize(recipient),
+		UserId:    userId.String(),
+		CodeHash:  string(hash),
+	}, s.cfg.TTL)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// VerifyCode checks the code and returns the user it was issued to. Every call uses up
+// an attempt, once they run out the code is dropped and a new one has to be requested.
+// A missing or expired code is reported as ErrCodeInvalid like a wrong one.
+func (s *Service) VerifyCode(ctx context.Context, recipient, code string) (uuid.UUID, error) {
+	const op = "services.verification_code.VerifyCode"
+
+	recipient = normalize(recipient)
+	stored, err := s.repository.RegisterAttempt(ctx, recipient)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if stored.Attempts > s.cfg.MaxAttempts {
+		s.deleteCode(ctx, recipient)
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrCodeAttempts)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(stored.CodeHash), []byte(code))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return uuid.UUID{}, fmt.Errorf("%s: %w", op, errs.ErrCodeInvalid)
+		}
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userId, err := uuid.Parse(stored.UserId)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.deleteCode(ctx, recipient)
+
+	return userId, nil
+}
+
+// deleteCode only logs failures: the code expires on its own anyway.
+func (s *Service) deleteCode(ctx context.Context, recipient string) {
+	const op = "services.verification_code.deleteCode"
+
+	err := s.repository.DeleteCode(ctx, recipient)
+	if err != nil {
+		logger.FromCtx(ctx).Error("failed to delete verification code", slog.String("op", op), logger.Err(err))
+	}
+}
+
+// normalize lowercases emails, E.164 phone numbers are left as they are.
+func normalize(recipient string) string {
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
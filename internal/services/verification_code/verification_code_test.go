@@ -0,0 +1,117 @@
+package verification_code_service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AlexMickh/twitch-clone/internal/config"
+	"github.com/AlexMickh/twitch-clone/internal/entities"
+	"github.com/AlexMickh/twitch-clone/internal/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testCfg = config.VerificationCodeConfig{
+	TTL:         15 * time.Minute,
+	MaxAttempts: 3,
+}
+
+func TestService_CreateCode(t *testing.T) {
+	userId := uuid.New()
+
+	m := NewMockRepository(t)
+	var saved entities.VerificationCode
+	m.EXPECT().SaveCode(
+		mock.AnythingOfType("context.backgroundCtx"),
+		mock.AnythingOfType("entities.VerificationCode"),
+		testCfg.TTL,
+	).Run(func(_ context.Context, code entities.VerificationCode, _ time.Duration) {
+		saved = code
+	}).Return(nil).Once()
+
+	s := New(m, testCfg)
+	code, err := s.CreateCode(context.Background(), userId, " User@Example.com")
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^\d{6}$`), code)
+
+	require.Equal(t, "user@example.com", saved.Recipient)
+	require.Equal(t, userId.String(), saved.UserId)
+	require.NotContains(t, saved.CodeHash, code)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(saved.CodeHash), []byte(code)))
+}
+
+func TestService_VerifyCode(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	require.NoError(t, err)
+	userId := uuid.New()
+
+	tests := []struct {
+		name        string
+		code        string
+		attempts    int64
+		wantMockErr error
+		wantDelete  bool
+		wantErr     error
+	}{
+		{
+			name:       "good case",
+			code:       "123456",
+			attempts:   1,
+			wantDelete: true,
+			wantErr:    nil,
+		},
+		{
+			name:     "wrong code case",
+			code:     "654321",
+			attempts: 1,
+			wantErr:  errs.ErrCodeInvalid,
+		},
+		{
+			name:       "attempts exceeded case",
+			code:       "123456",
+			attempts:   4,
+			wantDelete: true,
+			wantErr:    errs.ErrCodeAttempts,
+		},
+		{
+			name:        "expired code case",
+			code:        "123456",
+			wantMockErr: errs.ErrCodeInvalid,
+			wantErr:     errs.ErrCodeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMockRepository(t)
+			m.EXPECT().RegisterAttempt(
+				mock.AnythingOfType("context.backgroundCtx"),
+				"user@example.com",
+			).Return(entities.VerificationCode{
+				Recipient: "user@example.com",
+				UserId:    userId.String(),
+				CodeHash:  string(hash),
+				Attempts:  tt.attempts,
+			}, tt.wantMockErr).Once()
+			if tt.wantDelete {
+				m.EXPECT().DeleteCode(
+					mock.AnythingOfType("context.backgroundCtx"),
+					"user@example.com",
+				).Return(nil).Once()
+			}
+
+			s := New(m, testCfg)
+			got, err := s.VerifyCode(context.Background(), "User@example.com", tt.code)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				require.Equal(t, userId, got)
+			}
+		})
+	}
+}
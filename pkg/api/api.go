@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/render"
 )
@@ -12,12 +14,24 @@ type HttpError struct {
 	status int
 }
 
+// ErrorResponse.Error is stable and meant for clients to match on,
+// Message is its human readable form in the request locale.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
 }
 
 type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 
+// Translator turns an error message into the human readable form for the request in ctx.
+type Translator func(ctx context.Context, msg string) string
+
+type translatorKey struct{}
+
+func ContextWithTranslator(ctx context.Context, translator Translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, translator)
+}
+
 func ErrorWrapper(f func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := f(w, r)
@@ -25,19 +39,38 @@ func ErrorWrapper(f func(w http.ResponseWriter, r *http.Request) error) http.Han
 			w.Header().Add("Content-Type", "application/json")
 			httpErr, ok := err.(HttpError)
 			if ok {
-				render.Status(r, httpErr.status)
-				render.JSON(w, r, ErrorResponse{Error: httpErr.msg})
+				WriteError(w, r, httpErr.msg, httpErr.status)
 			} else {
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, ErrorResponse{Error: err.Error()})
+				WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			}
 		}
 	}
 }
 
+// WriteError renders an ErrorResponse, for middlewares that can not return an error.
+func WriteError(w http.ResponseWriter, r *http.Request, msg string, status int) {
+	resp := ErrorResponse{Error: msg}
+	if translator, ok := r.Context().Value(translatorKey{}).(Translator); ok {
+		resp.Message = translator(r.Context(), msg)
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, resp)
+}
+
 func Error(msg string, status int) HttpError {
 	return HttpError{
 		msg:    msg,
 		status: status,
 	}
 }
+
+// QueryInt reads an integer query parameter, falling back to def when it is absent.
+func QueryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(value)
+}